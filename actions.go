@@ -0,0 +1,644 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/slack-go/slack"
+)
+
+// actionItemModalCallbackID はアクションアイテム追加モーダルのコールバックID
+const actionItemModalCallbackID = "action_item_modal"
+
+// actionItemReminderCheckInterval はリマインド時刻に達したかを確認する間隔
+const actionItemReminderCheckInterval = 10 * time.Minute
+
+// defaultActionItemReminderTime はリマインドDMを送信するデフォルトの時刻
+const defaultActionItemReminderTime = "09:00"
+
+// actionItemHelp はアクションアイテムコマンドの書式の説明
+const actionItemHelp = "*アクションアイテムの指定方法:*\n" +
+	"• `@bot action add [#インシデントID] <内容> [owner:@user] [due:2025-01-31|7d] [link:URL]`\n" +
+	"  担当者を省略するとコマンドを実行した人、インシデントIDを省略するとこのチャンネルのインシデントが対象になります\n" +
+	"• `@bot action list [#インシデントID|mine]`\n" +
+	"• `@bot action done <アクションアイテムID>`"
+
+// ActionItemInput はアクションアイテム追加時の入力値
+type ActionItemInput struct {
+	IncidentID int64 // 0の場合はチャンネルのインシデント
+	Title      string
+	OwnerID    string
+	DueDate    string // "2006-01-02" 形式（空文字列の場合は期限なし）
+	Link       string
+}
+
+// actionRouter は `@bot action` のサブコマンドのルーター
+var actionRouter = newCommandRouter("@bot action")
+
+func init() {
+	actionRouter.register(&Command{
+		Name:        "add",
+		Aliases:     []string{"追加"},
+		Usage:       "[#id] <内容> [owner:@user] [due:日付] [link:URL]",
+		Description: "アクションアイテムを追加",
+		Handler:     addActionItemCommand,
+	})
+	actionRouter.register(&Command{
+		Name:        "list",
+		Aliases:     []string{"一覧"},
+		Usage:       "[#id|mine]",
+		Description: "アクションアイテムの一覧を表示（`mine` で自分が担当の未完了のもの）",
+		Handler:     listActionItemsCommand,
+	})
+	actionRouter.register(&Command{
+		Name:        "done",
+		Aliases:     []string{"完了"},
+		Usage:       "<アクションアイテムID>",
+		Description: "アクションアイテムを完了にする",
+		Handler:     completeActionItemCommand,
+	})
+	actionRouter.register(&Command{
+		Name:        "help",
+		Aliases:     []string{"ヘルプ"},
+		Description: "アクションアイテムコマンドのヘルプを表示",
+		Handler:     showActionItemHelp,
+	})
+}
+
+// handleActionCommand は `@bot action` コマンドをサブコマンドに振り分ける
+func handleActionCommand(ctx *CommandContext) {
+	if !actionRouter.dispatch(ctx, ctx.Args) {
+		showActionItemHelp(ctx)
+	}
+}
+
+// showActionItemHelp はアクションアイテムコマンドのヘルプと追加ボタンを表示
+func showActionItemHelp(ctx *CommandContext) {
+	text := "📌 *アクションアイテム*\n\n再発防止策などのフォローアップ作業を担当者・期限付きで管理します。\n\n" + actionRouter.helpText()
+	blocks := []slack.Block{
+		slack.NewSectionBlock(slack.NewTextBlockObject("mrkdwn", text, false, false), nil, nil),
+	}
+
+	// チャンネルにインシデントがある場合はモーダルから追加できるようにする
+	if incidentID, err := getLatestIncidentByChannelID(ctx.ChannelID); err == nil {
+		blocks = append(blocks, slack.NewActionBlock(
+			fmt.Sprintf("action_items_%d", incidentID),
+			newAddActionItemButton(incidentID),
+		))
+	}
+
+	ctx.replyBlocks(text, blocks, true)
+}
+
+// newAddActionItemButton はアクションアイテム追加モーダルを開くボタンを作成
+func newAddActionItemButton(incidentID int64) *slack.ButtonBlockElement {
+	return slack.NewButtonBlockElement(
+		"add_action_item",
+		fmt.Sprintf("incident_%d", incidentID),
+		slack.NewTextBlockObject("plain_text", "📌 アクションアイテムを追加", true, false),
+	)
+}
+
+// resolveActionItemIncident はアクションアイテムの対象インシデントを決定
+// 指定がない場合はチャンネルの最新のインシデント（復旧済みも含む）を対象とする
+func resolveActionItemIncident(ctx *CommandContext, incidentID int64) (int64, error) {
+	if incidentID != 0 {
+		return incidentID, nil
+	}
+
+	incidentID, err := getLatestIncidentByChannelID(ctx.ChannelID)
+	if err != nil {
+		return 0, fmt.Errorf("このチャンネルにはインシデントがありません。`#インシデントID` を指定してください")
+	}
+	return incidentID, nil
+}
+
+// addActionItemCommand はアクションアイテムを追加するコマンド
+func addActionItemCommand(ctx *CommandContext) {
+	input, err := parseActionItemArgs(ctx.Args, ctx.UserID, time.Now())
+	if err != nil {
+		ctx.reply(fmt.Sprintf("❌ %v\n\n%s", err, actionItemHelp), true)
+		return
+	}
+
+	incidentID, err := resolveActionItemIncident(ctx, input.IncidentID)
+	if err != nil {
+		ctx.reply(fmt.Sprintf("❌ %v", err), true)
+		return
+	}
+	input.IncidentID = incidentID
+
+	if err := createActionItem(ctx.API, input, ctx.UserID); err != nil {
+		ctx.reply(fmt.Sprintf("❌ %v", err), true)
+		return
+	}
+}
+
+// createActionItem はアクションアイテムを保存し、インシデントチャンネルに通知
+func createActionItem(api SlackAPI, input ActionItemInput, createdBy string) error {
+	incident, err := getIncidentDetails(input.IncidentID)
+	if err != nil {
+		log.Printf("インシデント詳細取得エラー: %v", err)
+		return fmt.Errorf("インシデント #%d が見つかりません", input.IncidentID)
+	}
+
+	ownerName := ""
+	if input.OwnerID != "" {
+		ownerName = newUserNameResolver(api).lookup(input.OwnerID)
+	}
+
+	itemID, err := addActionItem(input.IncidentID, input.Title, input.OwnerID, ownerName, input.DueDate, input.Link, createdBy)
+	if err != nil {
+		log.Printf("アクションアイテム追加エラー: %v", err)
+		return fmt.Errorf("アクションアイテムの追加に失敗しました: %v", err)
+	}
+
+	message := formatActionItemAdded(itemID, input, createdBy)
+	_, _, err = api.PostMessage(
+		incident.ChannelID,
+		slack.MsgOptionText(message, false),
+		slack.MsgOptionBlocks(
+			slack.NewSectionBlock(
+				slack.NewTextBlockObject("mrkdwn", message, false, false),
+				nil, nil,
+			),
+		),
+	)
+	if err != nil {
+		log.Printf("アクションアイテム追加通知の投稿エラー: %v", err)
+	}
+
+	return nil
+}
+
+// listActionItemsCommand はアクションアイテムの一覧を表示するコマンド
+func listActionItemsCommand(ctx *CommandContext) {
+	var items []ActionItem
+	var header string
+	var err error
+	showIncident := false
+
+	switch {
+	case len(ctx.Args) > 0 && isMineKeyword(ctx.Args[0]):
+		items, err = listActionItems(0, ctx.UserID, false)
+		header = "📌 *あなたが担当の未完了のアクションアイテム*"
+		showIncident = true
+	default:
+		var incidentID int64
+		if len(ctx.Args) > 0 {
+			if incidentID, err = parseIncidentID(ctx.Args[0]); err != nil {
+				ctx.reply(fmt.Sprintf("❌ %v\n\n%s", err, actionItemHelp), true)
+				return
+			}
+		}
+		if incidentID, err = resolveActionItemIncident(ctx, incidentID); err != nil {
+			ctx.reply(fmt.Sprintf("❌ %v", err), true)
+			return
+		}
+		items, err = listActionItems(incidentID, "", true)
+		header = fmt.Sprintf("📌 *インシデント #%d のアクションアイテム*", incidentID)
+	}
+
+	if err != nil {
+		log.Printf("アクションアイテム取得エラー: %v", err)
+		ctx.reply(fmt.Sprintf("❌ アクションアイテムの取得に失敗しました: %v", err), true)
+		return
+	}
+
+	ctx.reply(buildActionItemListMessage(header, items, time.Now(), showIncident), false)
+	log.Printf("アクションアイテム一覧を表示しました (%d件)", len(items))
+}
+
+// completeActionItemCommand はアクションアイテムを完了にするコマンド
+func completeActionItemCommand(ctx *CommandContext) {
+	if len(ctx.Args) == 0 {
+		ctx.reply("❌ アクションアイテムIDを指定してください（例: `@bot action done 12`）", true)
+		return
+	}
+
+	itemID, err := parseIncidentID(ctx.Args[0])
+	if err != nil {
+		ctx.reply(fmt.Sprintf("❌ アクションアイテムIDが不正です: %s", ctx.Args[0]), true)
+		return
+	}
+
+	incidentID, title, err := completeActionItem(itemID, ctx.UserID)
+	if err != nil {
+		ctx.reply(fmt.Sprintf("❌ %v", err), true)
+		return
+	}
+
+	message := fmt.Sprintf("✅ <@%s> がアクションアイテム #%d「%s」を完了しました（インシデント #%d）", ctx.UserID, itemID, title, incidentID)
+	ctx.reply(message, false)
+}
+
+// isMineKeyword は自分を表すキーワードかを判定
+func isMineKeyword(arg string) bool {
+	switch strings.ToLower(arg) {
+	case "mine", "me", "@me", "自分":
+		return true
+	}
+	return false
+}
+
+// parseActionItemArgs はアクションアイテム追加コマンドの引数を解析
+// `owner:`・`due:`・`link:` 以外のトークンは内容として扱う
+func parseActionItemArgs(args []string, userID string, now time.Time) (ActionItemInput, error) {
+	input := ActionItemInput{OwnerID: userID}
+	var titleParts []string
+
+	for i, arg := range args {
+		if i == 0 && strings.HasPrefix(arg, "#") {
+			incidentID, err := parseIncidentID(arg)
+			if err != nil {
+				return input, err
+			}
+			input.IncidentID = incidentID
+			continue
+		}
+
+		key, value, found := strings.Cut(arg, ":")
+		if !found {
+			titleParts = append(titleParts, arg)
+			continue
+		}
+
+		switch strings.ToLower(key) {
+		case "owner", "担当", "担当者":
+			ownerID, err := parseUserFilter(value, userID)
+			if err != nil {
+				return input, err
+			}
+			input.OwnerID = ownerID
+		case "due", "期限":
+			dueDate, err := parseDueDate(value, now)
+			if err != nil {
+				return input, err
+			}
+			input.DueDate = dueDate
+		case "link", "リンク":
+			input.Link = parseSlackLink(value)
+		default:
+			titleParts = append(titleParts, arg)
+		}
+	}
+
+	input.Title = strings.TrimSpace(strings.Join(titleParts, " "))
+	if input.Title == "" {
+		return input, fmt.Errorf("アクションアイテムの内容を指定してください")
+	}
+
+	return input, nil
+}
+
+// parseDueDate は期限を解析して "2006-01-02" 形式で返す
+// 日付（2025-01-31 / 2025/01/31）または今日からの期間（3d、2w）を指定できる
+func parseDueDate(value string, now time.Time) (string, error) {
+	for _, layout := range []string{"2006-01-02", "2006/01/02"} {
+		if date, err := time.ParseInLocation(layout, value, now.Location()); err == nil {
+			return date.Format("2006-01-02"), nil
+		}
+	}
+
+	if duration, err := parseSinceDuration(value); err == nil && duration >= 24*time.Hour {
+		return now.Add(duration).Format("2006-01-02"), nil
+	}
+
+	return "", fmt.Errorf("期限は `2025-01-31` のような日付か、`3d`・`2w` のような期間で指定してください: `%s`", value)
+}
+
+// parseSlackLink はSlackが自動リンクしたURL（<https://...|表示名>）からURLを取り出す
+func parseSlackLink(value string) string {
+	value = strings.TrimSuffix(strings.TrimPrefix(value, "<"), ">")
+	url, _, _ := strings.Cut(value, "|")
+	return url
+}
+
+// formatActionItemAdded はアクションアイテム追加の通知メッセージを生成
+func formatActionItemAdded(itemID int64, input ActionItemInput, createdBy string) string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "📌 *アクションアイテム #%d を追加しました*\n\n", itemID)
+	fmt.Fprintf(&sb, "*内容:* %s\n", input.Title)
+	if input.OwnerID != "" {
+		fmt.Fprintf(&sb, "*担当者:* <@%s>\n", input.OwnerID)
+	} else {
+		sb.WriteString("*担当者:* 未割り当て\n")
+	}
+	if input.DueDate != "" {
+		fmt.Fprintf(&sb, "*期限:* %s\n", input.DueDate)
+	}
+	if input.Link != "" {
+		fmt.Fprintf(&sb, "*リンク:* <%s>\n", input.Link)
+	}
+	fmt.Fprintf(&sb, "*追加者:* <@%s>\n\n", createdBy)
+	fmt.Fprintf(&sb, "完了したら `@bot action done %d` で完了にしてください。", itemID)
+	return sb.String()
+}
+
+// buildActionItemListMessage はアクションアイテム一覧のメッセージを生成
+func buildActionItemListMessage(header string, items []ActionItem, now time.Time, showIncident bool) string {
+	if len(items) == 0 {
+		return header + "\n\nℹ️ アクションアイテムはありません。`@bot action add <内容>` で追加できます。"
+	}
+
+	var sb strings.Builder
+	sb.WriteString(header + "\n\n")
+	for _, item := range items {
+		sb.WriteString(formatActionItemLine(item, now, showIncident) + "\n")
+	}
+	return sb.String()
+}
+
+// formatActionItemLine はアクションアイテム1件を1行に整形
+func formatActionItemLine(item ActionItem, now time.Time, showIncident bool) string {
+	var sb strings.Builder
+	if item.Status == actionItemStatusDone {
+		fmt.Fprintf(&sb, "✅ `#%d` ~%s~", item.ID, item.Title)
+	} else {
+		fmt.Fprintf(&sb, "⬜ `#%d` %s", item.ID, item.Title)
+	}
+
+	var attrs []string
+	if item.OwnerID != "" {
+		attrs = append(attrs, fmt.Sprintf("担当: <@%s>", item.OwnerID))
+	} else {
+		attrs = append(attrs, "担当: 未割り当て")
+	}
+	if item.DueDate != nil {
+		due := "期限: " + item.DueDate.Format("2006-01-02")
+		if item.Status != actionItemStatusDone {
+			if days := overdueDays(*item.DueDate, now); days > 0 {
+				due += fmt.Sprintf(" ⚠️ %d日超過", days)
+			}
+		}
+		attrs = append(attrs, due)
+	}
+	if item.Link != "" {
+		attrs = append(attrs, fmt.Sprintf("<%s|リンク>", item.Link))
+	}
+	sb.WriteString(" — " + strings.Join(attrs, " / "))
+
+	if showIncident {
+		fmt.Fprintf(&sb, "（インシデント #%d %s）", item.IncidentID, item.IncidentTitle)
+	}
+
+	return sb.String()
+}
+
+// overdueDays は期限を何日過ぎているかを返す（期限内の場合は0以下）
+// DATE型は日付のみを表すため、時刻とタイムゾーンを無視して日数を比較する
+func overdueDays(dueDate, now time.Time) int {
+	due := time.Date(dueDate.Year(), dueDate.Month(), dueDate.Day(), 0, 0, 0, 0, time.UTC)
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	return int(today.Sub(due).Hours() / 24)
+}
+
+// createActionItemModal はアクションアイテム追加用のモーダルを作成
+func createActionItemModal(incidentID int64, defaultOwnerID string) slack.ModalViewRequest {
+	// 内容入力
+	titleInput := slack.NewPlainTextInputBlockElement(
+		slack.NewTextBlockObject("plain_text", "例: 監視アラートの閾値を見直す", false, false),
+		"action_title",
+	)
+	titleBlock := slack.NewInputBlock(
+		"action_title_block",
+		slack.NewTextBlockObject("plain_text", "内容", false, false),
+		nil,
+		titleInput,
+	)
+
+	// 担当者選択（初期値は操作したユーザー）
+	ownerSelect := slack.NewOptionsSelectBlockElement(
+		slack.OptTypeUser,
+		slack.NewTextBlockObject("plain_text", "担当者を選択", false, false),
+		"action_owner",
+	)
+	ownerSelect.InitialUser = defaultOwnerID
+	ownerBlock := slack.NewInputBlock(
+		"action_owner_block",
+		slack.NewTextBlockObject("plain_text", "担当者", false, false),
+		nil,
+		ownerSelect,
+	)
+	ownerBlock.Optional = true
+
+	// 期限選択
+	dueDatePicker := slack.NewDatePickerBlockElement("action_due")
+	dueDateBlock := slack.NewInputBlock(
+		"action_due_block",
+		slack.NewTextBlockObject("plain_text", "期限", false, false),
+		nil,
+		dueDatePicker,
+	)
+	dueDateBlock.Optional = true
+
+	// リンク入力
+	linkInput := slack.NewPlainTextInputBlockElement(
+		slack.NewTextBlockObject("plain_text", "チケットやPRのURL", false, false),
+		"action_link",
+	)
+	linkBlock := slack.NewInputBlock(
+		"action_link_block",
+		slack.NewTextBlockObject("plain_text", "リンク", false, false),
+		nil,
+		linkInput,
+	)
+	linkBlock.Optional = true
+
+	blocks := slack.Blocks{
+		BlockSet: []slack.Block{
+			titleBlock,
+			ownerBlock,
+			dueDateBlock,
+			linkBlock,
+		},
+	}
+
+	return slack.ModalViewRequest{
+		Type:            slack.VTModal,
+		Title:           slack.NewTextBlockObject("plain_text", "アクションアイテム", false, false),
+		Close:           slack.NewTextBlockObject("plain_text", "キャンセル", false, false),
+		Submit:          slack.NewTextBlockObject("plain_text", "追加する", false, false),
+		Blocks:          blocks,
+		CallbackID:      actionItemModalCallbackID,
+		PrivateMetadata: fmt.Sprintf("%d", incidentID),
+	}
+}
+
+// handleOpenActionItemModal は「アクションアイテムを追加」ボタンがクリックされた時の処理
+func handleOpenActionItemModal(api SlackAPI, callback slack.InteractionCallback) {
+	log.Println("アクションアイテム追加ボタンがクリックされました")
+
+	incidentID, err := parseIncidentActionValue(callback.ActionCallback.BlockActions[0].Value)
+	if err != nil {
+		log.Printf("インシデントID解析エラー: %v", err)
+		return
+	}
+
+	_, err = api.OpenView(callback.TriggerID, createActionItemModal(incidentID, callback.User.ID))
+	if err != nil {
+		log.Printf("アクションアイテムモーダル表示エラー: %v", err)
+		return
+	}
+
+	log.Println("アクションアイテム追加モーダルを表示しました")
+}
+
+// handleActionItemModalSubmission はアクションアイテム追加モーダル送信時の処理
+func handleActionItemModalSubmission(api SlackAPI, callback slack.InteractionCallback) {
+	log.Println("アクションアイテム追加モーダル送信を受信しました")
+
+	// 同じモーダルの送信（連打や再配信）で二重に追加しない
+	if !markEventProcessed(viewSubmissionKey(callback.View.ID)) {
+		log.Printf("モーダル %s は処理済みのため、アクションアイテムを追加しません", callback.View.ID)
+		return
+	}
+
+	var incidentID int64
+	fmt.Sscanf(callback.View.PrivateMetadata, "%d", &incidentID)
+	incidentID = canonicalIncidentID(incidentID)
+
+	values := callback.View.State.Values
+	input := ActionItemInput{
+		IncidentID: incidentID,
+		Title:      strings.TrimSpace(values["action_title_block"]["action_title"].Value),
+		OwnerID:    values["action_owner_block"]["action_owner"].SelectedUser,
+		DueDate:    values["action_due_block"]["action_due"].SelectedDate,
+		Link:       strings.TrimSpace(values["action_link_block"]["action_link"].Value),
+	}
+
+	if err := createActionItem(api, input, callback.User.ID); err != nil {
+		log.Printf("アクションアイテム追加エラー: %v", err)
+		api.PostMessage(callback.User.ID, slack.MsgOptionText(fmt.Sprintf("❌ %v", err), false))
+	}
+}
+
+// startActionItemReminder は期限切れのアクションアイテムを担当者にDMで通知する処理を開始
+// 1日1回、設定された時刻以降に通知する（通知済みかどうかはインシデントの保存先に記録する）
+// ctx がキャンセルされると（リーダーでなくなると）終了する
+func startActionItemReminder(ctx context.Context, api SlackAPI) {
+	if config.ActionItems.DisableReminder {
+		log.Println("アクションアイテムのリマインドは無効です")
+		return
+	}
+
+	reminderTime := config.ActionItems.ReminderTime
+	if reminderTime == "" {
+		reminderTime = defaultActionItemReminderTime
+	}
+	hour, minute, err := parseReminderTime(reminderTime)
+	if err != nil {
+		log.Printf("リマインド時刻の設定が不正なため %s を使用します: %v", defaultActionItemReminderTime, err)
+		hour, minute, _ = parseReminderTime(defaultActionItemReminderTime)
+	}
+
+	log.Printf("アクションアイテムのリマインドを開始します (毎日 %02d:%02d)", hour, minute)
+
+	go func() {
+		ticker := time.NewTicker(actionItemReminderCheckInterval)
+		defer ticker.Stop()
+
+		for {
+			now := time.Now()
+			if isReminderDue(now, hour, minute) {
+				sendOverdueActionItemReminders(api, now)
+			}
+
+			select {
+			case <-ctx.Done():
+				log.Println("アクションアイテムのリマインドを停止しました")
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// parseReminderTime は "09:00" 形式の時刻を解析
+func parseReminderTime(value string) (int, int, error) {
+	t, err := time.Parse("15:04", value)
+	if err != nil {
+		return 0, 0, fmt.Errorf("時刻は `09:00` のように指定してください: %s", value)
+	}
+	return t.Hour(), t.Minute(), nil
+}
+
+// isReminderDue は今日のリマインド時刻を過ぎているかを判定
+func isReminderDue(now time.Time, hour, minute int) bool {
+	reminderAt := time.Date(now.Year(), now.Month(), now.Day(), hour, minute, 0, 0, now.Location())
+	return !now.Before(reminderAt)
+}
+
+// sendOverdueActionItemReminders は期限切れのアクションアイテムを担当者ごとにDMで通知
+func sendOverdueActionItemReminders(api SlackAPI, now time.Time) {
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	items, err := getOverdueActionItems(today)
+	if err != nil {
+		log.Printf("期限切れアクションアイテム取得エラー: %v", err)
+		return
+	}
+
+	owners, itemsByOwner := groupActionItemsByOwner(items)
+	for _, ownerID := range owners {
+		ownerItems := itemsByOwner[ownerID]
+		message := buildOverdueReminderMessage(ownerItems, now)
+
+		_, _, err := api.PostMessage(
+			ownerID,
+			slack.MsgOptionText(message, false),
+			slack.MsgOptionBlocks(
+				slack.NewSectionBlock(
+					slack.NewTextBlockObject("mrkdwn", message, false, false),
+					nil, nil,
+				),
+			),
+		)
+		if err != nil {
+			log.Printf("アクションアイテムのリマインド送信エラー (%s): %v", ownerID, err)
+			continue
+		}
+
+		itemIDs := make([]int64, 0, len(ownerItems))
+		for _, item := range ownerItems {
+			itemIDs = append(itemIDs, item.ID)
+		}
+		if err := markActionItemsReminded(itemIDs); err != nil {
+			log.Printf("リマインド日時の記録エラー: %v", err)
+		}
+		log.Printf("%s に期限切れのアクションアイテム %d 件をリマインドしました", ownerID, len(ownerItems))
+	}
+}
+
+// groupActionItemsByOwner はアクションアイテムを担当者ごとにまとめる（担当者はIDの昇順）
+func groupActionItemsByOwner(items []ActionItem) ([]string, map[string][]ActionItem) {
+	itemsByOwner := make(map[string][]ActionItem)
+	for _, item := range items {
+		if item.OwnerID == "" {
+			continue
+		}
+		itemsByOwner[item.OwnerID] = append(itemsByOwner[item.OwnerID], item)
+	}
+
+	owners := make([]string, 0, len(itemsByOwner))
+	for ownerID := range itemsByOwner {
+		owners = append(owners, ownerID)
+	}
+	sort.Strings(owners)
+
+	return owners, itemsByOwner
+}
+
+// buildOverdueReminderMessage は期限切れアクションアイテムのリマインドメッセージを生成
+func buildOverdueReminderMessage(items []ActionItem, now time.Time) string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "⏰ *期限を過ぎたアクションアイテムがあります（%d件）*\n\n", len(items))
+	for _, item := range items {
+		sb.WriteString(formatActionItemLine(item, now, true) + "\n")
+	}
+	sb.WriteString("\n完了したら `@bot action done <ID>` で完了にしてください。")
+	return sb.String()
+}
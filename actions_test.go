@@ -0,0 +1,195 @@
+package main
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseActionItemArgs(t *testing.T) {
+	now := time.Date(2025, 1, 8, 12, 0, 0, 0, time.UTC)
+
+	input, err := parseActionItemArgs(
+		[]string{"#12", "監視の閾値を", "見直す", "owner:<@U456|suzuki>", "due:3d", "link:<https://example.com/issue/1|issue>"},
+		"U123", now,
+	)
+	if err != nil {
+		t.Fatalf("予期しないエラー: %v", err)
+	}
+
+	expected := ActionItemInput{
+		IncidentID: 12,
+		Title:      "監視の閾値を 見直す",
+		OwnerID:    "U456",
+		DueDate:    "2025-01-11",
+		Link:       "https://example.com/issue/1",
+	}
+	if !reflect.DeepEqual(input, expected) {
+		t.Errorf("解析結果が間違っています:\n got: %+v\nwant: %+v", input, expected)
+	}
+}
+
+func TestParseActionItemArgsDefaults(t *testing.T) {
+	input, err := parseActionItemArgs([]string{"手順書を更新", "https://example.com"}, "U123", time.Now())
+	if err != nil {
+		t.Fatalf("予期しないエラー: %v", err)
+	}
+
+	if input.IncidentID != 0 {
+		t.Errorf("インシデントIDを省略した場合は0であるべきです: %d", input.IncidentID)
+	}
+	if input.OwnerID != "U123" {
+		t.Errorf("担当者を省略した場合は実行ユーザーであるべきです: %s", input.OwnerID)
+	}
+	// 認識できないキーを含むトークンは内容として扱う
+	if input.Title != "手順書を更新 https://example.com" {
+		t.Errorf("内容が間違っています: %s", input.Title)
+	}
+	if input.DueDate != "" || input.Link != "" {
+		t.Errorf("期限・リンクは空であるべきです: %+v", input)
+	}
+}
+
+func TestParseActionItemArgsErrors(t *testing.T) {
+	tests := [][]string{
+		nil,
+		{"#12"},
+		{"owner:@me"},
+		{"内容", "owner:someone"},
+		{"内容", "due:tomorrow"},
+		{"内容", "due:30m"},
+		{"#abc", "内容"},
+	}
+
+	for _, args := range tests {
+		if _, err := parseActionItemArgs(args, "U123", time.Now()); err == nil {
+			t.Errorf("parseActionItemArgs(%q) はエラーを返すべきです", args)
+		}
+	}
+}
+
+func TestParseDueDate(t *testing.T) {
+	now := time.Date(2025, 1, 31, 23, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		value    string
+		expected string
+	}{
+		{"2025-02-14", "2025-02-14"},
+		{"2025/02/14", "2025-02-14"},
+		{"1d", "2025-02-01"},
+		{"2w", "2025-02-14"},
+	}
+
+	for _, tt := range tests {
+		got, err := parseDueDate(tt.value, now)
+		if err != nil {
+			t.Errorf("parseDueDate(%q) で予期しないエラー: %v", tt.value, err)
+			continue
+		}
+		if got != tt.expected {
+			t.Errorf("parseDueDate(%q) = %q, want %q", tt.value, got, tt.expected)
+		}
+	}
+}
+
+func TestFormatActionItemLine(t *testing.T) {
+	now := time.Date(2025, 1, 13, 9, 0, 0, 0, time.Local)
+	dueDate := time.Date(2025, 1, 10, 0, 0, 0, 0, time.UTC)
+	item := ActionItem{
+		ID:            7,
+		IncidentID:    3,
+		IncidentTitle: "APIエラー率上昇",
+		Title:         "閾値を見直す",
+		Status:        actionItemStatusOpen,
+		OwnerID:       "U1",
+		DueDate:       &dueDate,
+		Link:          "https://example.com/7",
+	}
+
+	line := formatActionItemLine(item, now, true)
+	for _, expected := range []string{"⬜ `#7` 閾値を見直す", "担当: <@U1>", "期限: 2025-01-10 ⚠️ 3日超過", "<https://example.com/7|リンク>", "（インシデント #3 APIエラー率上昇）"} {
+		if !strings.Contains(line, expected) {
+			t.Errorf("%q が含まれていません: %s", expected, line)
+		}
+	}
+
+	// 完了済みのものは期限切れを表示しない
+	item.Status = actionItemStatusDone
+	line = formatActionItemLine(item, now, false)
+	if !strings.HasPrefix(line, "✅ `#7` ~閾値を見直す~") || strings.Contains(line, "超過") || strings.Contains(line, "インシデント #3") {
+		t.Errorf("完了済みの表示が間違っています: %s", line)
+	}
+}
+
+func TestBuildActionItemListMessageEmpty(t *testing.T) {
+	message := buildActionItemListMessage("📌 *一覧*", nil, time.Now(), false)
+	if !strings.Contains(message, "アクションアイテムはありません") {
+		t.Errorf("空の一覧のメッセージが間違っています: %s", message)
+	}
+}
+
+func TestGroupActionItemsByOwner(t *testing.T) {
+	items := []ActionItem{
+		{ID: 1, OwnerID: "U2"},
+		{ID: 2, OwnerID: "U1"},
+		{ID: 3, OwnerID: "U2"},
+		{ID: 4},
+	}
+
+	owners, itemsByOwner := groupActionItemsByOwner(items)
+	if !reflect.DeepEqual(owners, []string{"U1", "U2"}) {
+		t.Errorf("担当者が間違っています: %v", owners)
+	}
+	if len(itemsByOwner["U2"]) != 2 || len(itemsByOwner["U1"]) != 1 {
+		t.Errorf("担当者ごとの件数が間違っています: %v", itemsByOwner)
+	}
+}
+
+func TestReminderTime(t *testing.T) {
+	hour, minute, err := parseReminderTime("09:30")
+	if err != nil || hour != 9 || minute != 30 {
+		t.Fatalf("parseReminderTime(\"09:30\") = %d, %d, %v", hour, minute, err)
+	}
+	if _, _, err := parseReminderTime("9時"); err == nil {
+		t.Error("不正な時刻はエラーを返すべきです")
+	}
+
+	day := time.Date(2025, 1, 8, 0, 0, 0, 0, time.UTC)
+	if isReminderDue(day.Add(9*time.Hour+29*time.Minute), 9, 30) {
+		t.Error("リマインド時刻より前は送信しないべきです")
+	}
+	if !isReminderDue(day.Add(9*time.Hour+30*time.Minute), 9, 30) {
+		t.Error("リマインド時刻以降は送信するべきです")
+	}
+}
+
+func TestActionRouterHelpText(t *testing.T) {
+	help := actionRouter.helpText()
+	for _, expected := range []string{"`@bot action add", "`@bot action list", "`@bot action done"} {
+		if !strings.Contains(help, expected) {
+			t.Errorf("ヘルプに %q が含まれていません:\n%s", expected, help)
+		}
+	}
+}
+
+func TestE2EActionItemsWithMemoryStore(t *testing.T) {
+	fake, api := setupE2E(t)
+	fake.addChannel("CINC", "incident-1")
+	if _, err := saveIncident("決済APIの障害", "high", "説明", "決済", "CINC", "incident-1", "U001", "報告 太郎", ""); err != nil {
+		t.Fatalf("saveIncident() error = %v", err)
+	}
+
+	handleEventsAPIEvent(api, mentionEvent("CINC", "U001", "<@UBOT> action add 監視を追加 owner:<@U002> due:2025-01-31"))
+	handleEventsAPIEvent(api, mentionEvent("CINC", "U001", "<@UBOT> action list"))
+	if !fake.hasMessage("CINC", "監視を追加") || !fake.hasMessage("CINC", "<@U002>") {
+		t.Fatalf("アクションアイテムの一覧 = %+v", fake.messagesTo("CINC"))
+	}
+
+	handleEventsAPIEvent(api, mentionEvent("CINC", "U002", "<@UBOT> action done 1"))
+	items, err := listActionItems(0, "U002", true)
+	if err != nil || len(items) != 1 || items[0].Status != actionItemStatusDone {
+		t.Errorf("完了後のアクションアイテム = %+v, %v", items, err)
+	}
+}
@@ -0,0 +1,249 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/slack-go/slack"
+)
+
+// defaultAlertServiceWindow は同じサービスのアラートを同じインシデントにまとめるデフォルトの期間
+const defaultAlertServiceWindow = 30 * time.Minute
+
+// alertGroupingMu は同時に受信した Webhook から同じアラートのインシデントを二重に作成しないよう、アラートの処理を1件ずつ行う
+var alertGroupingMu sync.Mutex
+
+// alertGroup はまとめ先の対応中のインシデントと、インシデントチャンネルに投稿するアラート
+type alertGroup struct {
+	incident *Incident
+	posted   []Alert
+}
+
+// alertFingerprint はアラートのフィンガープリントを取得（送信元が付与しない場合はラベルから作成）
+func alertFingerprint(alert Alert) string {
+	if alert.Fingerprint != "" {
+		return alert.Fingerprint
+	}
+
+	names := make([]string, 0, len(alert.Labels))
+	for name := range alert.Labels {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	hash := sha256.New()
+	for _, name := range names {
+		fmt.Fprintf(hash, "%s=%s\n", name, alert.Labels[name])
+	}
+	return hex.EncodeToString(hash.Sum(nil))[:16]
+}
+
+// alertServiceWindow は同じサービスのアラートをまとめる期間を取得
+func alertServiceWindow() time.Duration {
+	if minutes := config.Alerts.Grouping.ServiceWindowMinutes; minutes > 0 {
+		return time.Duration(minutes) * time.Minute
+	}
+	return defaultAlertServiceWindow
+}
+
+// newIncidentAlert はアラートをインシデントに紐付ける形式に変換
+func newIncidentAlert(incidentID int64, alert Alert, service string) IncidentAlert {
+	return IncidentAlert{
+		IncidentID:   incidentID,
+		Fingerprint:  alert.Fingerprint,
+		Source:       alert.Source,
+		Status:       alert.Status,
+		Summary:      alert.summary(),
+		Service:      service,
+		Labels:       alert.Labels,
+		StartsAt:     alert.StartsAt,
+		EndsAt:       alert.EndsAt,
+		GeneratorURL: alert.GeneratorURL,
+	}
+}
+
+// findAlertIncident はアラートをまとめる対応中のインシデントを取得（ない場合は nil）
+// 同じフィンガープリントのアラートを紐付けたインシデントを優先し、発生中のアラートは同じサービスのインシデントにもまとめる
+func findAlertIncident(alert Alert, service string) (*Incident, error) {
+	incident, err := store.FindIncidentByAlert(alert.Fingerprint)
+	if err != nil || incident != nil {
+		return incident, err
+	}
+	if alert.Status != alertStatusFiring || service == "" || config.Alerts.Grouping.DisableService {
+		return nil, nil
+	}
+	return store.FindIncidentByAlertService(service, time.Now().Add(-alertServiceWindow()))
+}
+
+// handleAlerts は受信したアラートを対応中のインシデントにまとめ、まとめられない発生中のアラートからインシデントを作成
+// まとめたアラートのうち、新しいものと状態が変わったものだけをインシデントチャンネルに投稿する（再通知は投稿しない）
+// 解消したアラートによってインシデントのアラートがすべて解消した場合は、復旧を促すボタンを投稿する
+// 対応中のインシデントがない解消済みのアラートとルールで無視するアラートは何もしない
+func handleAlerts(api SlackAPI, alerts []Alert, reporterID string) error {
+	alertGroupingMu.Lock()
+	defer alertGroupingMu.Unlock()
+
+	groups := make(map[int64]*alertGroup)
+	var groupOrder []int64
+	var unmatched []Alert
+
+	for _, alert := range alerts {
+		alert.Fingerprint = alertFingerprint(alert)
+		rule := matchAlertRule(alert.Labels)
+		if rule != nil && rule.Ignore {
+			log.Printf("ルールに一致したため、アラート %s を無視します", alert.summary())
+			continue
+		}
+		service := alertService(alert.Labels, rule)
+
+		var incident *Incident
+		if store != nil {
+			var err error
+			if incident, err = findAlertIncident(alert, service); err != nil {
+				return err
+			}
+		}
+		if incident == nil {
+			if alert.Status == alertStatusFiring {
+				unmatched = append(unmatched, alert)
+			}
+			continue
+		}
+
+		previous, err := store.AttachAlert(newIncidentAlert(incident.ID, alert, service))
+		if err != nil {
+			return err
+		}
+		group, exists := groups[incident.ID]
+		if !exists {
+			group = &alertGroup{incident: incident}
+			groups[incident.ID] = group
+			groupOrder = append(groupOrder, incident.ID)
+		}
+		if previous != alert.Status {
+			group.posted = append(group.posted, alert)
+		}
+	}
+
+	for _, incidentID := range groupOrder {
+		group := groups[incidentID]
+		if len(group.posted) == 0 {
+			continue
+		}
+		log.Printf("アラート %d件をインシデント %d にまとめました", len(group.posted), incidentID)
+		details := formatAlertDetails(fmt.Sprintf("インシデント #%d にまとめたアラート", incidentID), group.posted)
+		if _, _, err := api.PostMessage(group.incident.ChannelID, slack.MsgOptionText(details, false)); err != nil {
+			log.Printf("アラートの詳細の投稿エラー: %v", err)
+		}
+		if len(firingAlerts(group.posted)) < len(group.posted) {
+			notifyIfAlertsResolved(api, group.incident)
+		}
+	}
+
+	if len(unmatched) == 0 {
+		return nil
+	}
+	_, err := openAlertIncident(api, unmatched, reporterID)
+	return err
+}
+
+// openAlertIncident は発生中のアラートからインシデントを作成し、アラートを紐付けて詳細をインシデントチャンネルに投稿
+func openAlertIncident(api SlackAPI, firing []Alert, reporterID string) (int64, error) {
+	report, ok := alertIncidentReport(firing, reporterID)
+	if !ok {
+		return 0, nil
+	}
+
+	log.Printf("アラートからインシデントを作成します: タイトル=%s, 重要度=%s", report.Title, report.Severity)
+	incidentID, channelID, err := openIncident(api, report)
+	if err != nil {
+		return 0, err
+	}
+
+	if incidentID != 0 {
+		for _, alert := range firing {
+			service := alertService(alert.Labels, matchAlertRule(alert.Labels))
+			if _, err := store.AttachAlert(newIncidentAlert(incidentID, alert, service)); err != nil {
+				log.Printf("アラートの紐付けエラー: %v", err)
+			}
+		}
+	}
+
+	if _, _, err := api.PostMessage(channelID, slack.MsgOptionText(formatAlertDetails("受信したアラート", firing), false)); err != nil {
+		log.Printf("アラートの詳細の投稿エラー: %v", err)
+	}
+	return incidentID, nil
+}
+
+// alertStatusLabel はアラートの状態の表示名
+func alertStatusLabel(status string) string {
+	if status == alertStatusResolved {
+		return "✅ 解消済み"
+	}
+	return "🔥 発生中"
+}
+
+// formatIncidentAlerts はインシデントに紐付けたアラートの一覧を作成（発生中のものを先に表示）
+func formatIncidentAlerts(incidentID int64, alerts []IncidentAlert) string {
+	if len(alerts) == 0 {
+		return fmt.Sprintf("インシデント #%d に紐付けたアラートはありません", incidentID)
+	}
+
+	sorted := append([]IncidentAlert(nil), alerts...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].Status == alertStatusFiring && sorted[j].Status != alertStatusFiring
+	})
+	firing := 0
+	for _, alert := range sorted {
+		if alert.Status == alertStatusFiring {
+			firing++
+		}
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "🚨 *インシデント #%d のアラート（発生中 %d件 / 解消済み %d件）*\n", incidentID, firing, len(sorted)-firing)
+	for _, alert := range sorted {
+		fmt.Fprintf(&b, "\n%s *%s*", alertStatusLabel(alert.Status), alert.Summary)
+		if alert.Service != "" {
+			fmt.Fprintf(&b, " `%s`", alert.Service)
+		}
+		if alert.GeneratorURL != "" {
+			fmt.Fprintf(&b, " <%s|グラフ>", alert.GeneratorURL)
+		}
+		fmt.Fprintf(&b, "\n  %s・受信 %d回（初回: %s、最終: %s）",
+			alert.Source,
+			alert.ReceivedCount,
+			alert.FirstReceivedAt.Local().Format("01/02 15:04"),
+			alert.LastReceivedAt.Local().Format("01/02 15:04"),
+		)
+	}
+	return b.String()
+}
+
+// showIncidentAlerts はインシデントに紐付けたアラートと発生中・解消済みの状態を表示
+func showIncidentAlerts(ctx *CommandContext, ephemeral bool) {
+	if store == nil {
+		ctx.reply("⚠️ データベース機能が無効のため、インシデント情報を取得できません。", ephemeral)
+		return
+	}
+
+	incidentID, err := resolveTargetIncident(ctx)
+	if err != nil {
+		ctx.reply(fmt.Sprintf("❌ %v", err), true)
+		return
+	}
+
+	alerts, err := store.IncidentAlerts(incidentID)
+	if err != nil {
+		ctx.reply(fmt.Sprintf("❌ アラートの取得に失敗しました: %v", err), true)
+		return
+	}
+
+	ctx.reply(formatIncidentAlerts(incidentID, alerts), ephemeral)
+	log.Printf("インシデント %d のアラートを表示しました (%d件)", incidentID, len(alerts))
+}
@@ -0,0 +1,116 @@
+package main
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+// alertmanagerFlappingPayload は同じアラート（フィンガープリント a1b2c3）の状態だけが変わった Webhook の本文
+func alertmanagerFlappingPayload(status string) string {
+	return `{
+  "status": "` + status + `",
+  "receiver": "incident-bot",
+  "alerts": [
+    {
+      "status": "` + status + `",
+      "labels": {"alertname": "HighErrorRate", "service": "payments", "severity": "page", "instance": "api-1"},
+      "annotations": {"summary": "決済APIのエラー率が上昇"},
+      "startsAt": "2025-01-01T01:00:00Z",
+      "fingerprint": "a1b2c3"
+    }
+  ]
+}`
+}
+
+func TestAlertFingerprint(t *testing.T) {
+	if got := alertFingerprint(Alert{Fingerprint: "a1b2c3"}); got != "a1b2c3" {
+		t.Errorf("送信元のフィンガープリントを使用するべきです: %q", got)
+	}
+
+	// 送信元が付与しない場合はラベルから作成する（順序に依存しない）
+	first := alertFingerprint(Alert{Labels: map[string]string{"alertname": "A", "instance": "api-1"}})
+	second := alertFingerprint(Alert{Labels: map[string]string{"instance": "api-1", "alertname": "A"}})
+	other := alertFingerprint(Alert{Labels: map[string]string{"alertname": "A", "instance": "api-2"}})
+	if first == "" || first != second || first == other {
+		t.Errorf("alertFingerprint() = %q, %q, %q", first, second, other)
+	}
+}
+
+func TestFormatIncidentAlerts(t *testing.T) {
+	now := time.Now()
+	alerts := []IncidentAlert{
+		{Fingerprint: "fp1", Source: "Alertmanager", Status: alertStatusResolved, Summary: "解消したアラート", FirstReceivedAt: now, LastReceivedAt: now, ReceivedCount: 3},
+		{Fingerprint: "fp2", Source: "Alertmanager", Status: alertStatusFiring, Summary: "発生中のアラート", Service: "payments", FirstReceivedAt: now, LastReceivedAt: now, ReceivedCount: 1},
+	}
+
+	text := formatIncidentAlerts(5, alerts)
+	for _, want := range []string{"インシデント #5 のアラート（発生中 1件 / 解消済み 1件）", "`payments`", "受信 3回"} {
+		if !strings.Contains(text, want) {
+			t.Errorf("アラートの一覧に %q が含まれていません: %s", want, text)
+		}
+	}
+	// 発生中のアラートを先に表示する
+	if strings.Index(text, "発生中のアラート") > strings.Index(text, "解消したアラート") {
+		t.Errorf("発生中のアラートを先に表示するべきです: %s", text)
+	}
+
+	if text := formatIncidentAlerts(5, nil); !strings.Contains(text, "紐付けたアラートはありません") {
+		t.Errorf("アラートがない場合のメッセージ = %s", text)
+	}
+}
+
+func TestE2EAlertsGroupIntoExistingIncident(t *testing.T) {
+	fake, api := setupE2E(t)
+	setAlertsConfig(t, AlertsConfig{Alertmanager: AlertWebhookConfig{AllowUnauthenticated: true}})
+	handler := newAlertWebhookMux(api, "UBOT")
+
+	if code := postAlertmanagerWebhook(t, handler, http.MethodPost, "", alertmanagerFlappingPayload(alertStatusFiring)); code != http.StatusOK {
+		t.Fatalf("ステータスコード = %d", code)
+	}
+	incidentChannelID, created := fake.channelByName("incident-" + time.Now().Format("20060102"))
+	if !created {
+		t.Fatal("最初のアラートでインシデントチャンネルが作成されるべきです")
+	}
+	incident, _ := store.FindActiveIncidentByChannel(incidentChannelID)
+
+	// 同じアラートの再通知ではインシデントを作成せず、インシデントチャンネルにも投稿しない
+	fake.reset()
+	postAlertmanagerWebhook(t, handler, http.MethodPost, "", alertmanagerFlappingPayload(alertStatusFiring))
+	if calls := fake.callsTo("conversations.create"); len(calls) != 0 {
+		t.Errorf("再通知でインシデントチャンネルを作成しないべきです: %+v", calls)
+	}
+	if messages := fake.messagesTo(incidentChannelID); len(messages) != 0 {
+		t.Errorf("再通知はインシデントチャンネルに投稿しないべきです: %+v", messages)
+	}
+
+	// 解消したアラートはインシデントチャンネルに投稿する
+	postAlertmanagerWebhook(t, handler, http.MethodPost, "", alertmanagerFlappingPayload(alertStatusResolved))
+	if !fake.hasMessage(incidentChannelID, "インシデント #1 にまとめたアラート（1件）") || !fake.hasMessage(incidentChannelID, "(resolved)") {
+		t.Error("解消したアラートがインシデントチャンネルに投稿されるべきです")
+	}
+
+	// 同じサービスの別のアラートは期間内であれば同じインシデントにまとめる
+	fake.reset()
+	other := strings.ReplaceAll(alertmanagerFlappingPayload(alertStatusFiring), "a1b2c3", "d4e5f6")
+	other = strings.ReplaceAll(other, "HighErrorRate", "HighLatency")
+	postAlertmanagerWebhook(t, handler, http.MethodPost, "", other)
+	if calls := fake.callsTo("conversations.create"); len(calls) != 0 {
+		t.Errorf("同じサービスのアラートでインシデントチャンネルを作成しないべきです: %+v", calls)
+	}
+	if !fake.hasMessage(incidentChannelID, "HighLatency") {
+		t.Error("同じサービスのアラートがインシデントチャンネルに投稿されるべきです")
+	}
+
+	alerts, _ := store.IncidentAlerts(incident.ID)
+	if len(alerts) != 2 || alerts[0].Status != alertStatusResolved || alerts[0].ReceivedCount != 3 || alerts[1].Status != alertStatusFiring {
+		t.Errorf("IncidentAlerts() = %+v", alerts)
+	}
+
+	// インシデントチャンネルでアラートの一覧を表示できる
+	handleEventsAPIEvent(api, mentionEvent(incidentChannelID, "U001", "<@UBOT> alerts"))
+	if !fake.hasMessage(incidentChannelID, "発生中 1件 / 解消済み 1件") {
+		t.Error("インシデントに紐付けたアラートの一覧が表示されるべきです")
+	}
+}
@@ -0,0 +1,138 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"slices"
+	"time"
+
+	"github.com/slack-go/slack"
+)
+
+// defaultAlertQuietPeriod はアラートがすべて解消してから自動で復旧済みにするまでのデフォルトの時間
+const defaultAlertQuietPeriod = 30 * time.Minute
+
+// alertAutoResolveCheckInterval は自動で復旧済みにするインシデントを確認する間隔
+const alertAutoResolveCheckInterval = time.Minute
+
+// alertQuietPeriod はアラートが再発しなければ自動で復旧済みにするまでの時間を取得
+func alertQuietPeriod() time.Duration {
+	if minutes := config.Alerts.AutoResolve.QuietMinutes; minutes > 0 {
+		return time.Duration(minutes) * time.Minute
+	}
+	return defaultAlertQuietPeriod
+}
+
+// autoResolvesSeverity は重要度のインシデントをアラートの解消後に自動で復旧済みにするかチェック
+func autoResolvesSeverity(severity string) bool {
+	return slices.Contains(config.Alerts.AutoResolve.Severities, severity)
+}
+
+// allAlertsResolved はアラートがすべて解消済みかチェックし、最後に受信した日時を返す（アラートがない場合は false）
+func allAlertsResolved(alerts []IncidentAlert) (time.Time, bool) {
+	var lastReceivedAt time.Time
+	for _, alert := range alerts {
+		if alert.Status != alertStatusResolved {
+			return time.Time{}, false
+		}
+		if alert.LastReceivedAt.After(lastReceivedAt) {
+			lastReceivedAt = alert.LastReceivedAt
+		}
+	}
+	return lastReceivedAt, len(alerts) > 0
+}
+
+// postAlertsResolvedPrompt はアラートがすべて解消したことを復旧ボタン付きでインシデントチャンネルに投稿
+// 自動で復旧済みにする重要度の場合は、アラートが再発しなければ自動で復旧済みにすることも案内する
+func postAlertsResolvedPrompt(api SlackAPI, incident *Incident) {
+	text := fmt.Sprintf("✅ *インシデント #%d のアラートがすべて解消しました*\n", incident.ID)
+	if autoResolvesSeverity(incident.Severity) {
+		text += fmt.Sprintf("アラートが%d分間再発しなければ自動で復旧済みにします。すぐに復旧済みにする場合は「✅ 復旧完了」を押してください。", int(alertQuietPeriod().Minutes()))
+	} else {
+		text += "サービスの復旧を確認できたら「✅ 復旧完了」を押して復旧済みにしてください。"
+	}
+
+	_, _, err := api.PostMessage(
+		incident.ChannelID,
+		slack.MsgOptionText(fmt.Sprintf("インシデント #%d のアラートがすべて解消しました", incident.ID), false),
+		slack.MsgOptionBlocks(
+			slack.NewSectionBlock(slack.NewTextBlockObject("mrkdwn", text, false, false), nil, nil),
+			slack.NewActionBlock("alerts_resolved_actions", newResolveButton(incident.ID)),
+		),
+	)
+	if err != nil {
+		log.Printf("アラート解消の通知の投稿エラー: %v", err)
+		return
+	}
+	log.Printf("インシデント %d のアラートがすべて解消したことを通知しました", incident.ID)
+}
+
+// notifyIfAlertsResolved はインシデントのアラートがすべて解消した場合に復旧を促す
+func notifyIfAlertsResolved(api SlackAPI, incident *Incident) {
+	alerts, err := store.IncidentAlerts(incident.ID)
+	if err != nil {
+		log.Printf("インシデント %d のアラート取得エラー: %v", incident.ID, err)
+		return
+	}
+	if _, resolved := allAlertsResolved(alerts); resolved {
+		postAlertsResolvedPrompt(api, incident)
+	}
+}
+
+// autoResolveAlertIncidents はアラートがすべて解消してから一定時間再発しなかった対応中のインシデントを復旧済みにする
+func autoResolveAlertIncidents(api SlackAPI, now time.Time) {
+	if store == nil || len(config.Alerts.AutoResolve.Severities) == 0 {
+		return
+	}
+
+	filter := IncidentListFilter{Statuses: activeStatuses, Severities: config.Alerts.AutoResolve.Severities, SortBy: incidentListSortOldest}
+	incidents, _, err := store.ListIncidents(filter, 0, 0)
+	if err != nil {
+		log.Printf("自動で復旧済みにするインシデントの取得エラー: %v", err)
+		return
+	}
+
+	quietPeriod := alertQuietPeriod()
+	for _, incident := range incidents {
+		alerts, err := store.IncidentAlerts(incident.ID)
+		if err != nil {
+			log.Printf("インシデント %d のアラート取得エラー: %v", incident.ID, err)
+			continue
+		}
+		lastReceivedAt, resolved := allAlertsResolved(alerts)
+		if !resolved || now.Sub(lastReceivedAt) < quietPeriod {
+			continue
+		}
+
+		if err := resolveIncident(incident.ID, "system", "システム（アラート解消）", ""); err != nil {
+			log.Printf("インシデント %d の自動復旧エラー: %v", incident.ID, err)
+			continue
+		}
+		log.Printf("インシデント %d を自動的に復旧済みにしました（アラート解消）", incident.ID)
+		announceResolution(api, &incident, fmt.Sprintf("自動復旧（アラートが%d分間再発しなかったため）", int(quietPeriod.Minutes())), "")
+	}
+}
+
+// startAlertAutoResolver はアラートが解消したインシデントの自動復旧を開始（対象の重要度が未設定の場合は開始しない）
+func startAlertAutoResolver(ctx context.Context, api SlackAPI) {
+	if len(config.Alerts.AutoResolve.Severities) == 0 {
+		return
+	}
+	log.Printf("アラートが解消したインシデントの自動復旧を開始します (重要度: %v, 再発なしの時間: %v)", config.Alerts.AutoResolve.Severities, alertQuietPeriod())
+
+	go func() {
+		ticker := time.NewTicker(alertAutoResolveCheckInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				log.Println("アラートが解消したインシデントの自動復旧を停止しました")
+				return
+			case now := <-ticker.C:
+				autoResolveAlertIncidents(api, now)
+			}
+		}
+	}()
+}
@@ -0,0 +1,102 @@
+package main
+
+import (
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestAllAlertsResolved(t *testing.T) {
+	now := time.Now()
+	resolved := IncidentAlert{Fingerprint: "fp1", Status: alertStatusResolved, LastReceivedAt: now.Add(-time.Hour)}
+	latest := IncidentAlert{Fingerprint: "fp2", Status: alertStatusResolved, LastReceivedAt: now}
+	firing := IncidentAlert{Fingerprint: "fp3", Status: alertStatusFiring, LastReceivedAt: now}
+
+	if lastReceivedAt, ok := allAlertsResolved([]IncidentAlert{resolved, latest}); !ok || !lastReceivedAt.Equal(now) {
+		t.Errorf("allAlertsResolved() = %v, %v", lastReceivedAt, ok)
+	}
+	if _, ok := allAlertsResolved([]IncidentAlert{resolved, firing}); ok {
+		t.Error("発生中のアラートがある場合は false になるべきです")
+	}
+	if _, ok := allAlertsResolved(nil); ok {
+		t.Error("アラートがない場合は false になるべきです")
+	}
+}
+
+// openFlappingAlertIncident は発生中のアラートからインシデントを作成し、インシデントチャンネルとインシデントを返す
+func openFlappingAlertIncident(t *testing.T, fake *fakeSlack, handler http.Handler) (string, *Incident) {
+	t.Helper()
+	if code := postAlertmanagerWebhook(t, handler, http.MethodPost, "", alertmanagerFlappingPayload(alertStatusFiring)); code != http.StatusOK {
+		t.Fatalf("ステータスコード = %d", code)
+	}
+	incidentChannelID, created := fake.channelByName("incident-" + time.Now().Format("20060102"))
+	if !created {
+		t.Fatal("インシデントチャンネルが作成されるべきです")
+	}
+	incident, err := store.FindActiveIncidentByChannel(incidentChannelID)
+	if err != nil || incident == nil {
+		t.Fatalf("インシデントが保存されるべきです: %v", err)
+	}
+	return incidentChannelID, incident
+}
+
+func TestE2EAlertsResolvedPromptsConfirmation(t *testing.T) {
+	fake, api := setupE2E(t)
+	setAlertsConfig(t, AlertsConfig{Alertmanager: AlertWebhookConfig{AllowUnauthenticated: true}, DefaultSeverity: "high", AutoResolve: AlertAutoResolveConfig{Severities: []string{"low"}}})
+	handler := newAlertWebhookMux(api, "UBOT")
+	incidentChannelID, incident := openFlappingAlertIncident(t, fake, handler)
+
+	// アラートがすべて解消すると復旧ボタン付きで復旧を促す
+	postAlertmanagerWebhook(t, handler, http.MethodPost, "", alertmanagerFlappingPayload(alertStatusResolved))
+	if !fake.hasMessage(incidentChannelID, "アラートがすべて解消しました") || !fake.hasMessage(incidentChannelID, "alerts_resolved_actions") {
+		t.Fatal("インシデントチャンネルに復旧を促すメッセージが投稿されるべきです")
+	}
+
+	// 自動で復旧済みにする重要度ではないため、時間が経っても対応中のまま
+	autoResolveAlertIncidents(api, time.Now().Add(2*time.Hour))
+	if got, _ := store.GetIncident(incident.ID); got.Status != StatusInvestigating {
+		t.Errorf("ステータス = %s、自動で復旧済みにしないべきです", got.Status)
+	}
+
+	// ボタンを押すと復旧済みになる
+	handleInteraction(api, buttonClick("resolve_incident", "incident_1", incidentChannelID, "U002"))
+	if got, _ := store.GetIncident(incident.ID); got.Status != StatusResolved {
+		t.Errorf("ステータス = %s、復旧済みになるべきです", got.Status)
+	}
+}
+
+func TestE2EAlertsResolvedAutoResolvesLowSeverity(t *testing.T) {
+	fake, api := setupE2E(t)
+	setAlertsConfig(t, AlertsConfig{Alertmanager: AlertWebhookConfig{AllowUnauthenticated: true}, DefaultSeverity: "low", AutoResolve: AlertAutoResolveConfig{Severities: []string{"low"}, QuietMinutes: 10}})
+	handler := newAlertWebhookMux(api, "UBOT")
+	incidentChannelID, incident := openFlappingAlertIncident(t, fake, handler)
+
+	// 発生中のアラートがある間は復旧済みにしない
+	autoResolveAlertIncidents(api, time.Now().Add(time.Hour))
+	if got, _ := store.GetIncident(incident.ID); got.Status != StatusInvestigating {
+		t.Fatalf("ステータス = %s、発生中のアラートがある間は対応中のままであるべきです", got.Status)
+	}
+
+	postAlertmanagerWebhook(t, handler, http.MethodPost, "", alertmanagerFlappingPayload(alertStatusResolved))
+	if !fake.hasMessage(incidentChannelID, "10分間再発しなければ自動で復旧済みにします") {
+		t.Error("自動で復旧済みにすることを案内するべきです")
+	}
+
+	// 再発しない時間が経つまでは対応中のまま
+	autoResolveAlertIncidents(api, time.Now().Add(5*time.Minute))
+	if got, _ := store.GetIncident(incident.ID); got.Status != StatusInvestigating {
+		t.Fatalf("ステータス = %s、再発しない時間が経つまでは対応中のままであるべきです", got.Status)
+	}
+
+	autoResolveAlertIncidents(api, time.Now().Add(11*time.Minute))
+	got, _ := store.GetIncident(incident.ID)
+	if got.Status != StatusResolved {
+		t.Fatalf("ステータス = %s、自動で復旧済みになるべきです", got.Status)
+	}
+	if !fake.hasMessage(incidentChannelID, "自動復旧（アラートが10分間再発しなかったため）") || !fake.hasMessage(e2eAnnouncementChannelID, "インシデントが復旧しました") {
+		t.Error("インシデントチャンネルと全体周知チャンネルに復旧が通知されるべきです")
+	}
+	if state := timekeeperManager.currentState(incident.ID); state == timekeeperStateRunning {
+		t.Error("タイムキーパーが停止されるべきです")
+	}
+}
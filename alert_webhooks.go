@@ -0,0 +1,190 @@
+package main
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"io"
+	"log"
+	"net/http"
+	"strings"
+)
+
+// 送信元の検証に使うヘッダーのデフォルト
+const (
+	defaultWebhookSecretHeader = "X-Webhook-Secret"
+	defaultWebhookHMACHeader   = "X-Signature-256"
+)
+
+// maxWebhookBodySize は受け付ける Webhook の本文の最大サイズ
+const maxWebhookBodySize = 1 << 20
+
+// alertAdapter は監視システムの Webhook の本文をアラートに変換する
+// 送信元ごとに実装し、変換したアラートはすべて handleAlerts でインシデントにまとめる
+type alertAdapter interface {
+	// source は送信元の名前（アラートの Source とログに使用）
+	source() string
+	// parse は Webhook の本文をアラートの一覧に変換
+	parse(body []byte) ([]Alert, error)
+}
+
+// alertSource は Webhook を受け付けるパスと送信元の変換・検証方法
+type alertSource struct {
+	path    string
+	adapter alertAdapter
+	auth    AlertWebhookConfig
+}
+
+// alertSources は設定から Webhook を受け付ける送信元の一覧を作成
+// Alertmanager とカスタムの送信元は常に、Grafana・Datadog は enabled の場合に受け付ける
+// いずれも送信元の検証方法か allow_unauthenticated の設定が必要
+func alertSources() []alertSource {
+	withDefaultPath := func(path, fallback string) string {
+		if path == "" {
+			return fallback
+		}
+		return path
+	}
+
+	var sources []alertSource
+	if config.Alerts.Alertmanager.authConfigured("Alertmanager") {
+		sources = append(sources, alertSource{
+			path:    withDefaultPath(config.Alerts.Alertmanager.Path, defaultAlertmanagerPath),
+			adapter: alertmanagerAdapter{},
+			auth:    config.Alerts.Alertmanager,
+		})
+	}
+	if config.Alerts.Grafana.Enabled && config.Alerts.Grafana.authConfigured("Grafana") {
+		sources = append(sources, alertSource{
+			path:    withDefaultPath(config.Alerts.Grafana.Path, defaultGrafanaPath),
+			adapter: grafanaAdapter{},
+			auth:    config.Alerts.Grafana,
+		})
+	}
+	if config.Alerts.Datadog.Enabled && config.Alerts.Datadog.authConfigured("Datadog") {
+		sources = append(sources, alertSource{
+			path:    withDefaultPath(config.Alerts.Datadog.Path, defaultDatadogPath),
+			adapter: datadogAdapter{},
+			auth:    config.Alerts.Datadog,
+		})
+	}
+	for _, custom := range config.Alerts.Custom {
+		if custom.Name == "" {
+			log.Println("name が設定されていないカスタムの送信元は受け付けません")
+			continue
+		}
+		if err := custom.validate(); err != nil {
+			log.Printf("カスタムの送信元 %s の設定が不正なため受け付けません: %v", custom.Name, err)
+			continue
+		}
+		if !custom.authConfigured(custom.Name) {
+			continue
+		}
+		sources = append(sources, alertSource{
+			path:    withDefaultPath(custom.Path, "/webhooks/"+custom.Name),
+			adapter: customAlertAdapter{config: custom},
+			auth:    custom.AlertWebhookConfig,
+		})
+	}
+	return sources
+}
+
+// hasCredentials は送信元の検証方法が1つ以上設定されているかチェック
+func (c AlertWebhookConfig) hasCredentials() bool {
+	return c.BearerToken != "" || c.Secret != "" || c.HMACSecret != ""
+}
+
+// authConfigured は検証方法が設定されているか、検証しないことが明示されているかチェック
+// どちらでもない場合は受け付けない（インターネットから誰でもインシデントを作成できてしまうため）
+func (c AlertWebhookConfig) authConfigured(name string) bool {
+	if c.hasCredentials() || c.AllowUnauthenticated {
+		return true
+	}
+	log.Printf("%s の Webhook は送信元の検証方法（bearer_token・secret・hmac_secret）が設定されていないため受け付けません（検証せずに受け付ける場合は allow_unauthenticated = true を設定してください）", name)
+	return false
+}
+
+// authorizedBearer は Authorization ヘッダーのトークンが一致するかチェック（トークンが未設定の場合は検証しない）
+func authorizedBearer(r *http.Request, token string) bool {
+	if token == "" {
+		return true
+	}
+	got := []byte(r.Header.Get("Authorization"))
+	want := []byte("Bearer " + token)
+	return subtle.ConstantTimeCompare(got, want) == 1
+}
+
+// authorizedSecret はヘッダーの共有シークレットが一致するかチェック（シークレットが未設定の場合は検証しない）
+func authorizedSecret(r *http.Request, header, secret string) bool {
+	if secret == "" {
+		return true
+	}
+	if header == "" {
+		header = defaultWebhookSecretHeader
+	}
+	return subtle.ConstantTimeCompare([]byte(r.Header.Get(header)), []byte(secret)) == 1
+}
+
+// authorizedHMAC はヘッダーの署名が本文の HMAC-SHA256 と一致するかチェック（鍵が未設定の場合は検証しない）
+// 署名は16進数で、GitHub などと同じ "sha256=" の接頭辞があってもよい
+func authorizedHMAC(r *http.Request, body []byte, header, secret string) bool {
+	if secret == "" {
+		return true
+	}
+	if header == "" {
+		header = defaultWebhookHMACHeader
+	}
+	signature, err := hex.DecodeString(strings.TrimPrefix(strings.TrimSpace(r.Header.Get(header)), "sha256="))
+	if err != nil || len(signature) == 0 {
+		return false
+	}
+
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return hmac.Equal(signature, mac.Sum(nil))
+}
+
+// authorized はリクエストが設定したすべての検証方法を満たすかチェック
+func (c AlertWebhookConfig) authorized(r *http.Request, body []byte) bool {
+	return authorizedBearer(r, c.BearerToken) &&
+		authorizedSecret(r, c.SecretHeader, c.Secret) &&
+		authorizedHMAC(r, body, c.HMACHeader, c.HMACSecret)
+}
+
+// alertWebhookHandler は監視システムの Webhook を受け付け、アラートを対応中のインシデントにまとめるかインシデントを作成する
+// 処理に失敗した場合は 500 を返し、送信元に再送させる
+func alertWebhookHandler(api SlackAPI, reporterID string, adapter alertAdapter, auth AlertWebhookConfig) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		body, err := io.ReadAll(io.LimitReader(r.Body, maxWebhookBodySize))
+		if err != nil {
+			http.Error(w, "failed to read body", http.StatusBadRequest)
+			return
+		}
+		if !auth.authorized(r, body) {
+			log.Printf("%s の Webhook の認証に失敗しました (送信元: %s)", adapter.source(), r.RemoteAddr)
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+
+		alerts, err := adapter.parse(body)
+		if err != nil {
+			log.Printf("%s の Webhook の解析エラー: %v", adapter.source(), err)
+			http.Error(w, "invalid payload", http.StatusBadRequest)
+			return
+		}
+		log.Printf("%s の Webhook を受信しました (アラート: %d件)", adapter.source(), len(alerts))
+
+		if err := handleAlerts(api, alerts, reporterID); err != nil {
+			log.Printf("アラートの処理エラー: %v", err)
+			http.Error(w, "failed to handle alerts", http.StatusInternalServerError)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	})
+}
@@ -0,0 +1,198 @@
+package main
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// signWebhookBody はテスト用に本文の HMAC-SHA256 署名を作成
+func signWebhookBody(secret, body string) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(body))
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+func TestAlertWebhookConfigAuthorized(t *testing.T) {
+	body := []byte(`{"status":"firing"}`)
+	newRequest := func(headers map[string]string) *http.Request {
+		req := httptest.NewRequest(http.MethodPost, "/webhooks/test", nil)
+		for name, value := range headers {
+			req.Header.Set(name, value)
+		}
+		return req
+	}
+
+	tests := []struct {
+		name    string
+		config  AlertWebhookConfig
+		headers map[string]string
+		want    bool
+	}{
+		{"検証方法なし", AlertWebhookConfig{}, nil, true},
+		{"Bearer トークン一致", AlertWebhookConfig{BearerToken: "token"}, map[string]string{"Authorization": "Bearer token"}, true},
+		{"Bearer トークン不一致", AlertWebhookConfig{BearerToken: "token"}, map[string]string{"Authorization": "Bearer other"}, false},
+		{"共有シークレット一致", AlertWebhookConfig{Secret: "s3cret"}, map[string]string{defaultWebhookSecretHeader: "s3cret"}, true},
+		{"共有シークレットのヘッダー指定", AlertWebhookConfig{Secret: "s3cret", SecretHeader: "X-Custom"}, map[string]string{"X-Custom": "s3cret"}, true},
+		{"共有シークレットなし", AlertWebhookConfig{Secret: "s3cret"}, nil, false},
+		{"HMAC 署名一致", AlertWebhookConfig{HMACSecret: "key"}, map[string]string{defaultWebhookHMACHeader: signWebhookBody("key", string(body))}, true},
+		{"HMAC 署名（接頭辞なし）", AlertWebhookConfig{HMACSecret: "key"}, map[string]string{defaultWebhookHMACHeader: strings.TrimPrefix(signWebhookBody("key", string(body)), "sha256=")}, true},
+		{"HMAC 署名の鍵違い", AlertWebhookConfig{HMACSecret: "key"}, map[string]string{defaultWebhookHMACHeader: signWebhookBody("other", string(body))}, false},
+		{"HMAC 署名が16進数でない", AlertWebhookConfig{HMACSecret: "key"}, map[string]string{defaultWebhookHMACHeader: "not-hex"}, false},
+		{"複数の検証方法の一部のみ一致", AlertWebhookConfig{BearerToken: "token", Secret: "s3cret"}, map[string]string{"Authorization": "Bearer token"}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.config.authorized(newRequest(tt.headers), body); got != tt.want {
+				t.Errorf("authorized() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAlertSources(t *testing.T) {
+	setAlertsConfig(t, AlertsConfig{
+		Alertmanager: AlertWebhookConfig{BearerToken: "token"},
+		Grafana:      AlertWebhookConfig{Enabled: true, Secret: "s3cret"},
+		Datadog:      AlertWebhookConfig{Path: "/dd", Secret: "s3cret"},
+		Custom: []CustomAlertSource{
+			{Name: "uptime", AlertWebhookConfig: AlertWebhookConfig{AllowUnauthenticated: true}},
+			{Name: "broken", Status: "$.status[", AlertWebhookConfig: AlertWebhookConfig{HMACSecret: "key"}},
+			{Summary: "$.title", AlertWebhookConfig: AlertWebhookConfig{HMACSecret: "key"}},
+			{Name: "open"},
+		},
+	})
+
+	var paths []string
+	for _, source := range alertSources() {
+		paths = append(paths, source.path+"="+source.adapter.source())
+	}
+	// Datadog は enabled でないため受け付けず、設定が不正・名前のない・検証方法のないカスタムの送信元も受け付けない
+	want := []string{defaultAlertmanagerPath + "=Alertmanager", defaultGrafanaPath + "=Grafana", "/webhooks/uptime=uptime"}
+	if strings.Join(paths, ",") != strings.Join(want, ",") {
+		t.Errorf("alertSources() = %v, want %v", paths, want)
+	}
+}
+
+func TestAlertSourcesRequireCredentials(t *testing.T) {
+	setAlertsConfig(t, AlertsConfig{
+		Grafana: AlertWebhookConfig{Enabled: true},
+		Datadog: AlertWebhookConfig{Enabled: true},
+		Custom:  []CustomAlertSource{{Name: "uptime"}},
+	})
+
+	// 検証方法のない送信元は Alertmanager も含めて受け付けない
+	mux := newAlertWebhookMux(nil, "UBOT")
+	for _, path := range []string{defaultAlertmanagerPath, defaultGrafanaPath, defaultDatadogPath, "/webhooks/uptime"} {
+		req := httptest.NewRequest(http.MethodPost, path, strings.NewReader(`{}`))
+		rec := httptest.NewRecorder()
+		mux.ServeHTTP(rec, req)
+		if rec.Code != http.StatusNotFound {
+			t.Errorf("%s のステータスコード = %d", path, rec.Code)
+		}
+	}
+	if sources := alertSources(); len(sources) != 0 {
+		t.Errorf("alertSources() = %+v", sources)
+	}
+
+	// allow_unauthenticated を設定した場合は検証せずに受け付ける
+	config.Alerts.Alertmanager.AllowUnauthenticated = true
+	config.Alerts.Grafana.AllowUnauthenticated = true
+	if sources := alertSources(); len(sources) != 2 || sources[0].path != defaultAlertmanagerPath || sources[1].path != defaultGrafanaPath {
+		t.Errorf("allow_unauthenticated の場合の alertSources() = %+v", sources)
+	}
+}
+
+func TestAlertWebhookMuxSkipsDuplicatePaths(t *testing.T) {
+	setAlertsConfig(t, AlertsConfig{
+		Alertmanager: AlertWebhookConfig{AllowUnauthenticated: true},
+		Grafana:      AlertWebhookConfig{Enabled: true, Path: defaultAlertmanagerPath, AllowUnauthenticated: true},
+	})
+
+	// 同じパスは先に設定した送信元（Alertmanager）が受け付ける
+	mux := newAlertWebhookMux(nil, "UBOT")
+	req := httptest.NewRequest(http.MethodPost, defaultAlertmanagerPath, strings.NewReader(`{"status":"resolved","alerts":[]}`))
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, req)
+	if rec.Code != http.StatusOK {
+		t.Errorf("ステータスコード = %d", rec.Code)
+	}
+}
+
+func TestAlertWebhookHandlerRequiresHMACSignature(t *testing.T) {
+	setAlertsConfig(t, AlertsConfig{})
+	handler := alertWebhookHandler(nil, "UBOT", grafanaAdapter{}, AlertWebhookConfig{HMACSecret: "key"})
+	body := `{"status":"resolved","alerts":[]}`
+
+	post := func(signature string) int {
+		req := httptest.NewRequest(http.MethodPost, defaultGrafanaPath, strings.NewReader(body))
+		if signature != "" {
+			req.Header.Set(defaultWebhookHMACHeader, signature)
+		}
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		return rec.Code
+	}
+
+	if code := post(""); code != http.StatusUnauthorized {
+		t.Errorf("署名がない場合のステータスコード = %d", code)
+	}
+	if code := post(signWebhookBody("key", body+" ")); code != http.StatusUnauthorized {
+		t.Errorf("本文と署名が一致しない場合のステータスコード = %d", code)
+	}
+	if code := post(signWebhookBody("key", body)); code != http.StatusOK {
+		t.Errorf("署名が一致する場合のステータスコード = %d", code)
+	}
+}
+
+func TestLoadConfigAlertWebhooks(t *testing.T) {
+	original := config
+	t.Cleanup(func() { config = original })
+
+	path := filepath.Join(t.TempDir(), "config.toml")
+	content := `
+[alerts.alertmanager]
+bearer_token = "am-token"
+
+[alerts.datadog]
+enabled = true
+secret = "dd-secret"
+
+[[alerts.custom]]
+name = "uptime"
+path = "/hooks/uptime"
+hmac_secret = "key"
+alerts = "$.checks"
+status = "$.state"
+resolved_values = ["up"]
+
+[alerts.custom.labels]
+service = "$.service"
+`
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := loadConfig(path); err != nil {
+		t.Fatalf("loadConfig() error = %v", err)
+	}
+
+	if config.Alerts.Alertmanager.BearerToken != "am-token" || !config.Alerts.Datadog.Enabled || config.Alerts.Datadog.Secret != "dd-secret" {
+		t.Errorf("送信元の設定 = %+v, %+v", config.Alerts.Alertmanager, config.Alerts.Datadog)
+	}
+	if len(config.Alerts.Custom) != 1 {
+		t.Fatalf("カスタムの送信元 = %+v", config.Alerts.Custom)
+	}
+	// 検証方法とパスは送信元ごとの設定と同じキーで指定する
+	custom := config.Alerts.Custom[0]
+	if custom.Name != "uptime" || custom.Path != "/hooks/uptime" || custom.HMACSecret != "key" || custom.Alerts != "$.checks" ||
+		custom.ResolvedValues[0] != "up" || custom.Labels["service"] != "$.service" {
+		t.Errorf("カスタムの送信元 = %+v", custom)
+	}
+}
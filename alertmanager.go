@@ -0,0 +1,73 @@
+package main
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// defaultAlertmanagerPath は Alertmanager の Webhook を受け付けるパス
+const defaultAlertmanagerPath = "/webhooks/alertmanager"
+
+// alertmanagerPayload は Alertmanager の Webhook の本文（version 4）
+type alertmanagerPayload struct {
+	Version           string              `json:"version"`
+	GroupKey          string              `json:"groupKey"`
+	Status            string              `json:"status"`
+	Receiver          string              `json:"receiver"`
+	GroupLabels       map[string]string   `json:"groupLabels"`
+	CommonLabels      map[string]string   `json:"commonLabels"`
+	CommonAnnotations map[string]string   `json:"commonAnnotations"`
+	ExternalURL       string              `json:"externalURL"`
+	Alerts            []alertmanagerAlert `json:"alerts"`
+}
+
+// alertmanagerAlert は Alertmanager の Webhook に含まれるアラート1件
+type alertmanagerAlert struct {
+	Status       string            `json:"status"`
+	Labels       map[string]string `json:"labels"`
+	Annotations  map[string]string `json:"annotations"`
+	StartsAt     time.Time         `json:"startsAt"`
+	EndsAt       time.Time         `json:"endsAt"`
+	GeneratorURL string            `json:"generatorURL"`
+	Fingerprint  string            `json:"fingerprint"`
+}
+
+// alerts は Webhook の本文をアラートの一覧に変換
+// アラートごとのアノテーションがない項目はグループ共通のアノテーションで補う
+func (p alertmanagerPayload) alerts(source string) []Alert {
+	alerts := make([]Alert, 0, len(p.Alerts))
+	for _, a := range p.Alerts {
+		annotations := make(map[string]string, len(p.CommonAnnotations)+len(a.Annotations))
+		for name, value := range p.CommonAnnotations {
+			annotations[name] = value
+		}
+		for name, value := range a.Annotations {
+			annotations[name] = value
+		}
+
+		alerts = append(alerts, Alert{
+			Source:       source,
+			Status:       a.Status,
+			Fingerprint:  a.Fingerprint,
+			Labels:       a.Labels,
+			Annotations:  annotations,
+			StartsAt:     a.StartsAt,
+			EndsAt:       a.EndsAt,
+			GeneratorURL: a.GeneratorURL,
+		})
+	}
+	return alerts
+}
+
+// alertmanagerAdapter は Prometheus Alertmanager の Webhook の alertAdapter
+type alertmanagerAdapter struct{}
+
+func (alertmanagerAdapter) source() string { return "Alertmanager" }
+
+func (a alertmanagerAdapter) parse(body []byte) ([]Alert, error) {
+	var payload alertmanagerPayload
+	if err := json.Unmarshal(body, &payload); err != nil {
+		return nil, err
+	}
+	return payload.alerts(a.source()), nil
+}
@@ -0,0 +1,147 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// alertmanagerTestPayload は発生中のアラート2件を含む Alertmanager の Webhook の本文
+const alertmanagerTestPayload = `{
+  "version": "4",
+  "groupKey": "{}:{alertname=\"HighErrorRate\"}",
+  "status": "firing",
+  "receiver": "incident-bot",
+  "groupLabels": {"alertname": "HighErrorRate"},
+  "commonLabels": {"alertname": "HighErrorRate", "service": "payments", "severity": "page"},
+  "commonAnnotations": {"summary": "決済APIのエラー率が上昇"},
+  "externalURL": "http://alertmanager:9093",
+  "alerts": [
+    {
+      "status": "firing",
+      "labels": {"alertname": "HighErrorRate", "service": "payments", "severity": "page", "instance": "api-1"},
+      "annotations": {"description": "api-1 の 5xx が 10% を超えています"},
+      "startsAt": "2025-01-01T01:00:00Z",
+      "endsAt": "0001-01-01T00:00:00Z",
+      "generatorURL": "http://prometheus:9090/graph?g0.expr=rate",
+      "fingerprint": "a1b2c3"
+    },
+    {
+      "status": "resolved",
+      "labels": {"alertname": "HighErrorRate", "service": "payments", "severity": "page", "instance": "api-2"},
+      "annotations": {},
+      "startsAt": "2025-01-01T00:50:00Z",
+      "endsAt": "2025-01-01T00:55:00Z",
+      "fingerprint": "d4e5f6"
+    }
+  ]
+}`
+
+// postAlertmanagerWebhook は Alertmanager の Webhook を送信し、レスポンスのステータスコードを返す
+func postAlertmanagerWebhook(t *testing.T, handler http.Handler, method, token, body string) int {
+	t.Helper()
+	req := httptest.NewRequest(method, defaultAlertmanagerPath, strings.NewReader(body))
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	return rec.Code
+}
+
+func TestAlertmanagerPayloadAlerts(t *testing.T) {
+	payload := alertmanagerPayload{
+		CommonAnnotations: map[string]string{"summary": "共通の概要", "runbook": "http://runbook"},
+		Alerts: []alertmanagerAlert{
+			{Status: "firing", Labels: map[string]string{"alertname": "A"}, Annotations: map[string]string{"summary": "個別の概要"}, Fingerprint: "fp1"},
+			{Status: "resolved", Labels: map[string]string{"alertname": "B"}, Fingerprint: "fp2"},
+		},
+	}
+
+	alerts := payload.alerts("Alertmanager")
+	if len(alerts) != 2 || alerts[0].Source != "Alertmanager" || alerts[1].Status != alertStatusResolved || alerts[1].Fingerprint != "fp2" {
+		t.Fatalf("alerts() = %+v", alerts)
+	}
+	// アラートごとのアノテーションを優先し、ない項目は共通のアノテーションで補う
+	if alerts[0].Annotations["summary"] != "個別の概要" || alerts[0].Annotations["runbook"] != "http://runbook" {
+		t.Errorf("アノテーション = %+v", alerts[0].Annotations)
+	}
+	if alerts[1].summary() != "共通の概要" {
+		t.Errorf("summary() = %q", alerts[1].summary())
+	}
+}
+
+func TestAlertmanagerWebhookRejectsInvalidRequests(t *testing.T) {
+	setAlertsConfig(t, AlertsConfig{Alertmanager: AlertWebhookConfig{BearerToken: "secret"}})
+	handler := newAlertWebhookMux(nil, "UBOT")
+
+	if code := postAlertmanagerWebhook(t, handler, http.MethodGet, "secret", ""); code != http.StatusMethodNotAllowed {
+		t.Errorf("GET のステータスコード = %d", code)
+	}
+	if code := postAlertmanagerWebhook(t, handler, http.MethodPost, "wrong", alertmanagerTestPayload); code != http.StatusUnauthorized {
+		t.Errorf("トークンが一致しない場合のステータスコード = %d", code)
+	}
+	if code := postAlertmanagerWebhook(t, handler, http.MethodPost, "", alertmanagerTestPayload); code != http.StatusUnauthorized {
+		t.Errorf("トークンがない場合のステータスコード = %d", code)
+	}
+	if code := postAlertmanagerWebhook(t, handler, http.MethodPost, "secret", "{"); code != http.StatusBadRequest {
+		t.Errorf("不正な本文のステータスコード = %d", code)
+	}
+	// 発生中のアラートがない場合はインシデントを作成せずに受け付ける
+	if code := postAlertmanagerWebhook(t, handler, http.MethodPost, "secret", `{"status":"resolved","alerts":[]}`); code != http.StatusOK {
+		t.Errorf("解消済みのみの場合のステータスコード = %d", code)
+	}
+}
+
+func TestE2EAlertmanagerWebhookOpensIncident(t *testing.T) {
+	fake, api := setupE2E(t)
+	setAlertsConfig(t, AlertsConfig{
+		SeverityMap: map[string]string{"page": "critical"},
+		InviteUsers: []string{"U002"},
+		Alertmanager: AlertWebhookConfig{
+			BearerToken: "secret",
+		},
+	})
+
+	handler := newAlertWebhookMux(api, "UBOT")
+	if code := postAlertmanagerWebhook(t, handler, http.MethodPost, "secret", alertmanagerTestPayload); code != http.StatusOK {
+		t.Fatalf("ステータスコード = %d", code)
+	}
+
+	// モーダルからの報告と同じ流れでチャンネル・インシデント・全体周知・タイムキーパーを作成する
+	channelName := "incident-" + time.Now().Format("20060102")
+	incidentChannelID, created := fake.channelByName(channelName)
+	if !created {
+		t.Fatalf("チャンネル %s が作成されるべきです", channelName)
+	}
+	if invites := fake.callsTo("conversations.invite"); len(invites) != 1 || invites[0].Params.Get("users") != "U002" {
+		t.Errorf("設定したユーザーが招待されるべきです: %+v", invites)
+	}
+
+	incident, err := store.FindActiveIncidentByChannel(incidentChannelID)
+	if err != nil || incident == nil {
+		t.Fatalf("インシデントが保存されるべきです: %v", err)
+	}
+	if incident.Title != "[payments] 決済APIのエラー率が上昇" || incident.Severity != "critical" || incident.ReporterID != "UBOT" {
+		t.Errorf("保存されたインシデント = %+v", incident)
+	}
+	if state := timekeeperManager.currentState(incident.ID); state != timekeeperStateRunning {
+		t.Error("タイムキーパーが開始されるべきです")
+	}
+
+	if !fake.hasMessage(incidentChannelID, "アラートからインシデントを作成しました") || !fake.hasMessage(incidentChannelID, "assign_handler") {
+		t.Error("インシデントチャンネルに報告と担当者ボタンが投稿されるべきです")
+	}
+	// 発生中のアラートの詳細だけを投稿する
+	if !fake.hasMessage(incidentChannelID, "受信したアラート（1件）") || !fake.hasMessage(incidentChannelID, "instance=api-1") {
+		t.Error("インシデントチャンネルにアラートの詳細が投稿されるべきです")
+	}
+	if fake.hasMessage(incidentChannelID, "instance=api-2") {
+		t.Error("解消済みのアラートは投稿しないべきです")
+	}
+	if !fake.hasMessage(e2eAnnouncementChannelID, "決済APIのエラー率が上昇") || !fake.hasMessage(e2eAnnouncementChannelID, "<#"+incidentChannelID+">") {
+		t.Error("全体周知チャンネルに報告とインシデントチャンネルへのリンクが投稿されるべきです")
+	}
+}
@@ -0,0 +1,286 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"log"
+	"net/http"
+	"slices"
+	"sort"
+	"strings"
+	"text/template"
+	"time"
+)
+
+// アラートの状態
+const (
+	alertStatusFiring   = "firing"
+	alertStatusResolved = "resolved"
+)
+
+// アラートから作成するインシデントのデフォルト
+const (
+	defaultAlertSeverityLabel = "severity"
+	defaultAlertServiceLabel  = "service"
+	defaultAlertSeverity      = "high"
+)
+
+// Alert は監視システムから受信したアラート1件
+type Alert struct {
+	Source       string // 受信した Webhook（alertmanager など）
+	Status       string // firing または resolved
+	Fingerprint  string
+	Labels       map[string]string
+	Annotations  map[string]string
+	StartsAt     time.Time
+	EndsAt       time.Time
+	GeneratorURL string
+}
+
+// alertTitleData はルールのタイトルのテンプレートに渡す値
+type alertTitleData struct {
+	Labels      map[string]string
+	Annotations map[string]string
+	Service     string
+	Summary     string
+}
+
+// summary はアラートの概要（summary アノテーション、ない場合は alertname ラベル）
+func (a Alert) summary() string {
+	if summary := a.Annotations["summary"]; summary != "" {
+		return summary
+	}
+	if name := a.Labels["alertname"]; name != "" {
+		return name
+	}
+	return "名前のないアラート"
+}
+
+// matches はルールの条件にラベルが一致するかチェック（"*" は値があれば一致）
+func (r AlertRule) matches(labels map[string]string) bool {
+	for name, want := range r.Match {
+		got, ok := labels[name]
+		if !ok || (want != "*" && got != want) {
+			return false
+		}
+	}
+	return true
+}
+
+// matchAlertRule はラベルに一致する最初のルールを取得（ない場合は nil）
+func matchAlertRule(labels map[string]string) *AlertRule {
+	for i := range config.Alerts.Rules {
+		if config.Alerts.Rules[i].matches(labels) {
+			return &config.Alerts.Rules[i]
+		}
+	}
+	return nil
+}
+
+// alertSeverityLabel は重要度を表すラベル名を取得
+func alertSeverityLabel() string {
+	if config.Alerts.SeverityLabel != "" {
+		return config.Alerts.SeverityLabel
+	}
+	return defaultAlertSeverityLabel
+}
+
+// alertSeverity はアラートの重要度を判定（ルール → 重要度ラベルの対応 → ラベルの値 → デフォルトの順）
+func alertSeverity(labels map[string]string, rule *AlertRule) string {
+	if rule != nil && slices.Contains(severityOrder, rule.Severity) {
+		return rule.Severity
+	}
+
+	value := strings.ToLower(labels[alertSeverityLabel()])
+	if mapped, ok := config.Alerts.SeverityMap[value]; ok && slices.Contains(severityOrder, mapped) {
+		return mapped
+	}
+	if slices.Contains(severityOrder, value) {
+		return value
+	}
+
+	if slices.Contains(severityOrder, config.Alerts.DefaultSeverity) {
+		return config.Alerts.DefaultSeverity
+	}
+	return defaultAlertSeverity
+}
+
+// alertService はアラートのサービス名を取得（ルールで指定された場合はその値）
+func alertService(labels map[string]string, rule *AlertRule) string {
+	if rule != nil && rule.Service != "" {
+		return rule.Service
+	}
+	label := config.Alerts.ServiceLabel
+	if label == "" {
+		label = defaultAlertServiceLabel
+	}
+	return labels[label]
+}
+
+// alertTitle はインシデントのタイトルを作成（ルールのテンプレートを展開できない場合は「[サービス名] 概要」）
+func alertTitle(alert Alert, service string, rule *AlertRule) string {
+	fallback := alert.summary()
+	if service != "" {
+		fallback = fmt.Sprintf("[%s] %s", service, fallback)
+	}
+	if rule == nil || rule.Title == "" {
+		return fallback
+	}
+
+	tmpl, err := template.New("title").Option("missingkey=zero").Parse(rule.Title)
+	if err != nil {
+		log.Printf("アラートのタイトルのテンプレート解析エラー: %v", err)
+		return fallback
+	}
+	var title bytes.Buffer
+	data := alertTitleData{Labels: alert.Labels, Annotations: alert.Annotations, Service: service, Summary: alert.summary()}
+	if err := tmpl.Execute(&title, data); err != nil || strings.TrimSpace(title.String()) == "" {
+		log.Printf("アラートのタイトルのテンプレート展開エラー: %v", err)
+		return fallback
+	}
+	return strings.TrimSpace(title.String())
+}
+
+// firingAlerts は発生中のアラートだけを取得
+func firingAlerts(alerts []Alert) []Alert {
+	var firing []Alert
+	for _, alert := range alerts {
+		if alert.Status == alertStatusFiring {
+			firing = append(firing, alert)
+		}
+	}
+	return firing
+}
+
+// alertIncidentReport は発生中のアラートからインシデントの報告内容を作成（ルールで無視する場合は false）
+// 最初のアラートのラベルでルールを選び、報告者はBot自身とする
+func alertIncidentReport(alerts []Alert, reporterID string) (incidentReport, bool) {
+	first := alerts[0]
+	rule := matchAlertRule(first.Labels)
+	if rule != nil && rule.Ignore {
+		return incidentReport{}, false
+	}
+
+	severity := alertSeverity(first.Labels, rule)
+	service := alertService(first.Labels, rule)
+	title := alertTitle(first, service, rule)
+
+	description := first.Annotations["description"]
+	if description == "" {
+		description = first.summary()
+	}
+	impact := first.Annotations["impact"]
+	if impact == "" && service != "" {
+		impact = fmt.Sprintf("%s（アラートから自動判定）", service)
+	}
+	if impact == "" {
+		impact = "未確認"
+	}
+
+	invite := append([]string(nil), config.Alerts.InviteUsers...)
+	if rule != nil {
+		invite = append(invite, rule.InviteUsers...)
+	}
+	slices.Sort(invite)
+	invite = slices.Compact(invite)
+
+	message := fmt.Sprintf(
+		"%s *アラートからインシデントを作成しました*\n\n"+
+			"*タイトル:* %s\n"+
+			"*重要度:* %s %s\n"+
+			"*ステータス:* %s\n"+
+			"*影響範囲:* %s\n"+
+			"*詳細:*\n%s\n\n"+
+			"*報告元:* %s（%d件のアラート）\n"+
+			"*発生日時:* %s",
+		severityEmojis[severity],
+		title,
+		severityEmojis[severity],
+		severity,
+		statusLabel(StatusInvestigating),
+		impact,
+		description,
+		first.Source,
+		len(alerts),
+		first.StartsAt.Local().Format("2006-01-02 15:04:05"),
+	)
+
+	return incidentReport{
+		Title:         title,
+		Severity:      severity,
+		Description:   description,
+		Impact:        impact,
+		ReporterID:    reporterID,
+		ReporterName:  first.Source,
+		Message:       message,
+		InviteUserIDs: invite,
+	}, true
+}
+
+// formatAlertDetails はインシデントチャンネルに投稿するアラートの詳細（ラベル・アノテーション・発生日時）を作成
+func formatAlertDetails(heading string, alerts []Alert) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "🚨 *%s（%d件）*\n", heading, len(alerts))
+	for _, alert := range alerts {
+		fmt.Fprintf(&b, "\n• *%s* (%s)", alert.summary(), alert.Status)
+		if alert.GeneratorURL != "" {
+			fmt.Fprintf(&b, " <%s|グラフ>", alert.GeneratorURL)
+		}
+		fmt.Fprintf(&b, "\n  発生: %s", alert.StartsAt.Local().Format("2006-01-02 15:04:05"))
+
+		names := make([]string, 0, len(alert.Labels))
+		for name := range alert.Labels {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		labels := make([]string, 0, len(names))
+		for _, name := range names {
+			labels = append(labels, fmt.Sprintf("%s=%s", name, alert.Labels[name]))
+		}
+		if len(labels) > 0 {
+			fmt.Fprintf(&b, "\n  ラベル: `%s`", strings.Join(labels, " "))
+		}
+		if description := alert.Annotations["description"]; description != "" {
+			fmt.Fprintf(&b, "\n  %s", description)
+		}
+	}
+	return b.String()
+}
+
+// newAlertWebhookMux は送信元ごとのパスで Webhook を受け付ける http.ServeMux を作成（同じパスの送信元は最初のものだけを受け付ける）
+func newAlertWebhookMux(api SlackAPI, reporterID string) *http.ServeMux {
+	mux := http.NewServeMux()
+	mounted := make(map[string]string)
+	for _, source := range alertSources() {
+		name := source.adapter.source()
+		if other, exists := mounted[source.path]; exists {
+			log.Printf("%s の Webhook のパス %s は %s と重複するため受け付けません", name, source.path, other)
+			continue
+		}
+		if !source.auth.hasCredentials() {
+			log.Printf("⚠️ %s の Webhook の送信元を検証しません（bearer_token・secret・hmac_secret のいずれかの設定を推奨します）", name)
+		}
+		mux.Handle(source.path, alertWebhookHandler(api, reporterID, source.adapter, source.auth))
+		mounted[source.path] = name
+		log.Printf("%s の Webhook を %s%s で受け付けます", name, config.Alerts.ListenAddr, source.path)
+	}
+	return mux
+}
+
+// startAlertWebhookServer はアラートの Webhook を受け付ける HTTP サーバーを開始（listen_addr が空の場合は開始しない）
+func startAlertWebhookServer(api SlackAPI, reporterID string) {
+	if config.Alerts.ListenAddr == "" {
+		return
+	}
+
+	server := &http.Server{
+		Addr:              config.Alerts.ListenAddr,
+		Handler:           newAlertWebhookMux(api, reporterID),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	go func() {
+		if err := server.ListenAndServe(); err != nil {
+			log.Printf("Webhook サーバーの実行エラー: %v", err)
+		}
+	}()
+}
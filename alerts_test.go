@@ -0,0 +1,141 @@
+package main
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+// setAlertsConfig はアラートの設定を差し替える（テスト終了時に元に戻す）
+func setAlertsConfig(t *testing.T, alerts AlertsConfig) {
+	t.Helper()
+	original := config.Alerts
+	config.Alerts = alerts
+	t.Cleanup(func() { config.Alerts = original })
+}
+
+// firingAlert はテスト用の発生中のアラートを作成
+func firingAlert(labels, annotations map[string]string) Alert {
+	return Alert{
+		Source:      "Alertmanager",
+		Status:      alertStatusFiring,
+		Fingerprint: "fp-" + labels["alertname"],
+		Labels:      labels,
+		Annotations: annotations,
+		StartsAt:    time.Date(2025, 1, 1, 10, 0, 0, 0, time.Local),
+	}
+}
+
+func TestAlertSeverity(t *testing.T) {
+	setAlertsConfig(t, AlertsConfig{
+		SeverityMap:     map[string]string{"page": "critical", "warning": "medium", "broken": "unknown"},
+		DefaultSeverity: "low",
+	})
+
+	tests := []struct {
+		name   string
+		labels map[string]string
+		rule   *AlertRule
+		want   string
+	}{
+		{"ルールの重要度を優先", map[string]string{"severity": "page"}, &AlertRule{Severity: "high"}, "high"},
+		{"ラベルの値の対応", map[string]string{"severity": "Page"}, nil, "critical"},
+		{"ラベルの値がそのまま重要度", map[string]string{"severity": "high"}, nil, "high"},
+		{"対応先が重要度でない場合はデフォルト", map[string]string{"severity": "broken"}, nil, "low"},
+		{"ラベルがない場合はデフォルト", map[string]string{}, nil, "low"},
+		{"ルールの重要度が不正な場合はラベルから判定", map[string]string{"severity": "warning"}, &AlertRule{Severity: "urgent"}, "medium"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := alertSeverity(tt.labels, tt.rule); got != tt.want {
+				t.Errorf("alertSeverity() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+
+	// デフォルトの重要度が未設定・不正な場合は high
+	config.Alerts.DefaultSeverity = ""
+	if got := alertSeverity(nil, nil); got != defaultAlertSeverity {
+		t.Errorf("alertSeverity() = %s, want %s", got, defaultAlertSeverity)
+	}
+}
+
+func TestAlertIncidentReport(t *testing.T) {
+	setAlertsConfig(t, AlertsConfig{
+		InviteUsers: []string{"UONCALL"},
+		Rules: []AlertRule{
+			{Match: map[string]string{"alertname": "Watchdog"}, Ignore: true},
+			{
+				Match:       map[string]string{"service": "payments", "env": "*"},
+				Severity:    "critical",
+				Title:       "{{ .Service }} ({{ .Labels.env }}): {{ .Summary }}",
+				InviteUsers: []string{"UPAY", "UONCALL"},
+			},
+		},
+	})
+
+	alert := firingAlert(
+		map[string]string{"alertname": "HighErrorRate", "service": "payments", "env": "prod", "severity": "warning"},
+		map[string]string{"summary": "決済APIのエラー率が上昇", "description": "5xx が 10% を超えています", "impact": "決済できない"},
+	)
+	report, ok := alertIncidentReport([]Alert{alert}, "UBOT")
+	if !ok {
+		t.Fatal("無視するルールに一致しないアラートはインシデントを作成するべきです")
+	}
+	if report.Title != "payments (prod): 決済APIのエラー率が上昇" || report.Severity != "critical" {
+		t.Errorf("タイトル・重要度 = %q, %q", report.Title, report.Severity)
+	}
+	if report.Description != "5xx が 10% を超えています" || report.Impact != "決済できない" {
+		t.Errorf("詳細・影響範囲 = %q, %q", report.Description, report.Impact)
+	}
+	if report.ReporterID != "UBOT" || report.ReporterName != "Alertmanager" || report.OriginChannelID != "" {
+		t.Errorf("報告者・報告元 = %+v", report)
+	}
+	if strings.Join(report.InviteUserIDs, ",") != "UONCALL,UPAY" {
+		t.Errorf("招待するユーザー = %v", report.InviteUserIDs)
+	}
+	if !strings.Contains(report.Message, "アラートからインシデントを作成しました") || !strings.Contains(report.Message, "2025-01-01 10:00:00") {
+		t.Errorf("報告メッセージ = %q", report.Message)
+	}
+
+	// ルールに一致しない場合はラベルから作成する
+	other := firingAlert(map[string]string{"alertname": "DiskFull", "service": "search", "severity": "high"}, nil)
+	report, _ = alertIncidentReport([]Alert{other}, "UBOT")
+	if report.Title != "[search] DiskFull" || report.Severity != "high" || report.Description != "DiskFull" {
+		t.Errorf("ルールに一致しない場合 = %+v", report)
+	}
+	if !strings.Contains(report.Impact, "search") || strings.Join(report.InviteUserIDs, ",") != "UONCALL" {
+		t.Errorf("影響範囲・招待するユーザー = %q, %v", report.Impact, report.InviteUserIDs)
+	}
+
+	// 無視するルール
+	if _, ok := alertIncidentReport([]Alert{firingAlert(map[string]string{"alertname": "Watchdog"}, nil)}, "UBOT"); ok {
+		t.Error("無視するルールに一致したアラートはインシデントを作成しないべきです")
+	}
+}
+
+func TestAlertTitleTemplateFallback(t *testing.T) {
+	alert := firingAlert(map[string]string{"alertname": "HighLatency"}, map[string]string{"summary": "遅延"})
+
+	// テンプレートが不正・空になる場合は「[サービス名] 概要」
+	for _, title := range []string{"{{ .Labels.env", "{{ .Labels.missing }}"} {
+		if got := alertTitle(alert, "api", &AlertRule{Title: title}); got != "[api] 遅延" {
+			t.Errorf("alertTitle(%q) = %q", title, got)
+		}
+	}
+	if got := alertTitle(alert, "", nil); got != "遅延" {
+		t.Errorf("alertTitle() = %q", got)
+	}
+}
+
+func TestFormatAlertDetails(t *testing.T) {
+	alert := firingAlert(map[string]string{"alertname": "HighErrorRate", "service": "payments"}, map[string]string{"description": "5xx が増加"})
+	alert.GeneratorURL = "http://prometheus/graph"
+
+	details := formatAlertDetails("受信したアラート", []Alert{alert})
+	for _, want := range []string{"受信したアラート（1件）", "HighErrorRate", "<http://prometheus/graph|グラフ>", "`alertname=HighErrorRate service=payments`", "5xx が増加"} {
+		if !strings.Contains(details, want) {
+			t.Errorf("アラートの詳細に %q が含まれていません: %s", want, details)
+		}
+	}
+}
@@ -1,28 +1,21 @@
 package main
 
 import (
-	"database/sql"
 	"fmt"
 	"log"
-	"strings"
 	"time"
 
 	"github.com/slack-go/slack"
 )
 
 // showHelp はヘルプメッセージを表示
-func showHelp(api *slack.Client, channelID string) {
+func showHelp(api SlackAPI, channelID string) {
 	helpMessage := "📚 *インシデントレスポンスボット - ヘルプ*\n\n" +
 		"*基本的な使い方:*\n" +
 		"• ボットをメンションするとインシデント報告ボタンが表示されます\n" +
 		"• ボタンをクリックしてインシデント情報を入力してください\n\n" +
 		"*利用可能なコマンド:*\n" +
-		"• `@bot help` または `@bot ヘルプ`\n" +
-		"  このヘルプメッセージを表示\n\n" +
-		"• `@bot handler` または `@bot ハンドラー` または `@bot 担当`\n" +
-		"  このチャンネルのインシデントハンドラーを確認\n\n" +
-		"• `@bot list` または `@bot 一覧` または `@bot リスト`\n" +
-		"  オープン中のインシデント一覧を表示\n\n" +
+		mentionRouter.helpText() +
 		"*インシデント報告の流れ:*\n" +
 		"1️⃣ ボットをメンション\n" +
 		"2️⃣ 「🚨 インシデントを報告」ボタンをクリック\n" +
@@ -35,6 +28,9 @@ func showHelp(api *slack.Client, channelID string) {
 		"• インシデント対応ガイドラインの自動表示\n" +
 		"• 全体周知チャンネルへの通知\n" +
 		"• データベースでのインシデント管理"
+	if notice := degradedNotice(); notice != "" {
+		helpMessage += "\n\n" + notice
+	}
 
 	_, _, err := api.PostMessage(
 		channelID,
@@ -55,95 +51,10 @@ func showHelp(api *slack.Client, channelID string) {
 }
 
 // showHandler はチャンネルのハンドラー情報を表示
-func showHandler(api *slack.Client, channelID string) {
-	// データベースが無効な場合
-	if db == nil {
-		msg := "⚠️ データベース機能が無効のため、ハンドラー情報を取得できません。"
-		api.PostMessage(channelID, slack.MsgOptionText(msg, false))
-		return
-	}
-
-	// チャンネルのインシデント情報を取得
-	query := `
-		SELECT id, title, severity, handler_id, handler_name, reporter_name, created_at
-		FROM incidents
-		WHERE channel_id = $1 AND status = 'open'
-		ORDER BY created_at DESC
-		LIMIT 1
-	`
-
-	var incidentID int64
-	var title, severity, handlerID, handlerName, reporterName string
-	var createdAt time.Time
-	var handlerIDNull, handlerNameNull sql.NullString
-
-	err := db.QueryRow(query, channelID).Scan(&incidentID, &title, &severity, &handlerIDNull, &handlerNameNull, &reporterName, &createdAt)
-	if err != nil {
-		if err == sql.ErrNoRows {
-			msg := "ℹ️ このチャンネルにはオープンなインシデントがありません。"
-			api.PostMessage(channelID, slack.MsgOptionText(msg, false))
-		} else {
-			log.Printf("ハンドラー情報取得エラー: %v", err)
-			msg := fmt.Sprintf("❌ ハンドラー情報の取得に失敗しました: %v", err)
-			api.PostMessage(channelID, slack.MsgOptionText(msg, false))
-		}
-		return
-	}
-
-	if handlerIDNull.Valid {
-		handlerID = handlerIDNull.String
-	}
-	if handlerNameNull.Valid {
-		handlerName = handlerNameNull.String
-	}
-
-	// 重要度に応じた絵文字
-	severityEmoji := map[string]string{
-		"critical": "🔴",
-		"high":     "🟠",
-		"medium":   "🟡",
-		"low":      "🟢",
-	}
-	emoji := severityEmoji[severity]
-
-	// メッセージを構築
-	var message string
-	if handlerID != "" {
-		message = fmt.Sprintf(
-			"%s *インシデント情報*\n\n"+
-				"*タイトル:* %s\n"+
-				"*重要度:* %s %s\n"+
-				"*報告者:* %s\n"+
-				"*担当者:* <@%s> (%s)\n"+
-				"*作成日時:* %s",
-			emoji,
-			title,
-			emoji,
-			severity,
-			reporterName,
-			handlerID,
-			handlerName,
-			createdAt.Format("2006-01-02 15:04:05"),
-		)
-	} else {
-		message = fmt.Sprintf(
-			"%s *インシデント情報*\n\n"+
-				"*タイトル:* %s\n"+
-				"*重要度:* %s %s\n"+
-				"*報告者:* %s\n"+
-				"*担当者:* 未割り当て\n"+
-				"*作成日時:* %s\n\n"+
-				"💡 「🙋 担当者になる」ボタンで担当者を割り当ててください。",
-			emoji,
-			title,
-			emoji,
-			severity,
-			reporterName,
-			createdAt.Format("2006-01-02 15:04:05"),
-		)
-	}
+func showHandler(api SlackAPI, channelID string) {
+	message := buildHandlerMessage(channelID)
 
-	_, _, err = api.PostMessage(
+	_, _, err := api.PostMessage(
 		channelID,
 		slack.MsgOptionText(message, false),
 		slack.MsgOptionBlocks(
@@ -161,33 +72,28 @@ func showHandler(api *slack.Client, channelID string) {
 	}
 }
 
-// showIncidentList はオープンなインシデント一覧を表示
-func showIncidentList(api *slack.Client, channelID string) {
+// buildHandlerMessage はチャンネルのハンドラー情報メッセージを生成
+func buildHandlerMessage(channelID string) string {
 	// データベースが無効な場合
-	if db == nil {
-		msg := "⚠️ データベース機能が無効のため、インシデント一覧を取得できません。"
-		api.PostMessage(channelID, slack.MsgOptionText(msg, false))
-		return
+	if store == nil {
+		return "⚠️ データベース機能が無効のため、ハンドラー情報を取得できません。"
 	}
 
-	// オープンなインシデント一覧を取得
-	query := `
-		SELECT id, title, severity, channel_id, channel_name, handler_name, reporter_name, created_at
-		FROM incidents
-		WHERE status = 'open'
-		ORDER BY created_at DESC
-		LIMIT 10
-	`
-
-	rows, err := db.Query(query)
+	// チャンネルのインシデント情報を取得
+	incident, err := store.FindActiveIncidentByChannel(channelID)
 	if err != nil {
-		log.Printf("インシデント一覧取得エラー: %v", err)
-		msg := fmt.Sprintf("❌ インシデント一覧の取得に失敗しました: %v", err)
-		api.PostMessage(channelID, slack.MsgOptionText(msg, false))
-		return
+		log.Printf("ハンドラー情報取得エラー: %v", err)
+		return fmt.Sprintf("❌ ハンドラー情報の取得に失敗しました: %v", err)
+	}
+	if incident == nil {
+		return "ℹ️ このチャンネルにはオープンなインシデントがありません。"
 	}
-	defer rows.Close()
 
+	return formatHandlerInfo(incident.Title, incident.Severity, incident.Status, incident.ReporterName, incident.HandlerID, incident.HandlerName, incident.CreatedAt)
+}
+
+// formatHandlerInfo はインシデントのハンドラー情報を整形
+func formatHandlerInfo(title, severity, status, reporterName, handlerID, handlerName string, createdAt time.Time) string {
 	// 重要度に応じた絵文字
 	severityEmoji := map[string]string{
 		"critical": "🔴",
@@ -195,60 +101,44 @@ func showIncidentList(api *slack.Client, channelID string) {
 		"medium":   "🟡",
 		"low":      "🟢",
 	}
+	emoji := severityEmoji[severity]
 
-	var incidents []string
-	for rows.Next() {
-		var id int64
-		var title, severity, incidentChannelID, incidentChannelName, reporterName string
-		var handlerName sql.NullString
-		var createdAt time.Time
-
-		err := rows.Scan(&id, &title, &severity, &incidentChannelID, &incidentChannelName, &handlerName, &reporterName, &createdAt)
-		if err != nil {
-			log.Printf("インシデント情報スキャンエラー: %v", err)
-			continue
-		}
-
-		emoji := severityEmoji[severity]
-		handler := "未割り当て"
-		if handlerName.Valid {
-			handler = handlerName.String
-		}
-
-		incident := fmt.Sprintf(
-			"%s *#%d* - %s\n  チャンネル: <#%s> | 担当: %s | 報告: %s",
+	if handlerID != "" {
+		return fmt.Sprintf(
+			"%s *インシデント情報*\n\n"+
+				"*タイトル:* %s\n"+
+				"*重要度:* %s %s\n"+
+				"*ステータス:* %s\n"+
+				"*報告者:* %s\n"+
+				"*担当者:* <@%s> (%s)\n"+
+				"*作成日時:* %s",
 			emoji,
-			id,
 			title,
-			incidentChannelID,
-			handler,
+			emoji,
+			severity,
+			statusLabel(status),
 			reporterName,
+			handlerID,
+			handlerName,
+			createdAt.Format("2006-01-02 15:04:05"),
 		)
-		incidents = append(incidents, incident)
-	}
-
-	if len(incidents) == 0 {
-		msg := "✅ 現在オープンなインシデントはありません。"
-		api.PostMessage(channelID, slack.MsgOptionText(msg, false))
-		return
 	}
 
-	message := fmt.Sprintf("📋 *オープン中のインシデント一覧* (%d件)\n\n%s", len(incidents), strings.Join(incidents, "\n\n"))
-
-	_, _, err = api.PostMessage(
-		channelID,
-		slack.MsgOptionText(message, false),
-		slack.MsgOptionBlocks(
-			slack.NewSectionBlock(
-				slack.NewTextBlockObject("mrkdwn", message, false, false),
-				nil, nil,
-			),
-		),
+	return fmt.Sprintf(
+		"%s *インシデント情報*\n\n"+
+			"*タイトル:* %s\n"+
+			"*重要度:* %s %s\n"+
+			"*ステータス:* %s\n"+
+			"*報告者:* %s\n"+
+			"*担当者:* 未割り当て\n"+
+			"*作成日時:* %s\n\n"+
+			"💡 「🙋 担当者になる」ボタンで担当者を割り当ててください。",
+		emoji,
+		title,
+		emoji,
+		severity,
+		statusLabel(status),
+		reporterName,
+		createdAt.Format("2006-01-02 15:04:05"),
 	)
-
-	if err != nil {
-		log.Printf("インシデント一覧投稿エラー: %v", err)
-	} else {
-		log.Printf("インシデント一覧を表示しました (%d件)", len(incidents))
-	}
 }
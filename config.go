@@ -6,9 +6,16 @@ import (
 
 // Config は設定ファイルの構造
 type Config struct {
-	Slack    SlackConfig    `toml:"slack"`
-	Channels ChannelsConfig `toml:"channels"`
-	Database DatabaseConfig `toml:"database"`
+	Slack          SlackConfig          `toml:"slack"`
+	Channels       ChannelsConfig       `toml:"channels"`
+	Database       DatabaseConfig       `toml:"database"`
+	Storage        StorageConfig        `toml:"storage"`
+	Postmortem     PostmortemConfig     `toml:"postmortem"`
+	ActionItems    ActionItemsConfig    `toml:"action_items"`
+	Timekeeper     TimekeeperConfig     `toml:"timekeeper"`
+	LeaderElection LeaderElectionConfig `toml:"leader_election"`
+	Alerts         AlertsConfig         `toml:"alerts"`
+	Webhooks       WebhooksConfig       `toml:"webhooks"`
 }
 
 // SlackConfig はSlack関連の設定
@@ -31,6 +38,130 @@ type DatabaseConfig struct {
 	Password string `toml:"password"`
 	DBName   string `toml:"dbname"`
 	SSLMode  string `toml:"sslmode"`
+
+	DisableAutoMigrate bool   `toml:"disable_auto_migrate"` // 起動時にマイグレーションを適用しない（incident-bot migrate up で適用する）
+	JournalFile        string `toml:"journal_file"`         // データベースに接続できない間にインシデントを一時保存するJSONファイル（デフォルトは incident-journal.json）
+	OutboxFile         string `toml:"outbox_file"`          // データベースに接続できない間の既存インシデントへの変更を記録するJSONファイル（デフォルトは incident-outbox.json）
+}
+
+// StorageConfig はインシデントの保存先の設定
+type StorageConfig struct {
+	Backend string `toml:"backend"` // "postgres"（デフォルト）または "memory"
+	File    string `toml:"file"`    // backend = "memory" の場合にインシデントを保存するJSONファイル（空の場合は保存しない）
+}
+
+// PostmortemConfig はポストモーテム生成の設定
+type PostmortemConfig struct {
+	TemplateFile     string `toml:"template_file"`      // テンプレートファイルのパス（空の場合は組み込みテンプレート）
+	DisableOnResolve bool   `toml:"disable_on_resolve"` // 復旧時に「ポストモーテムを作成」ボタンを表示しない
+}
+
+// ActionItemsConfig はアクションアイテムの設定
+type ActionItemsConfig struct {
+	ReminderTime    string `toml:"reminder_time"`    // 期限切れのリマインドDMを送信する時刻（"09:00" 形式、デフォルトは09:00）
+	DisableReminder bool   `toml:"disable_reminder"` // 期限切れのリマインドDMを送信しない
+}
+
+// TimekeeperConfig はタイムキーパーの設定
+type TimekeeperConfig struct {
+	Mode            string         `toml:"mode"`             // "post"（経過時間を投稿、デフォルト）または "update"（ピン留めした1つのメッセージを更新）
+	IntervalMinutes map[string]int `toml:"interval_minutes"` // 重要度ごとの経過時間の投稿間隔（分）
+	Milestones      []int          `toml:"milestones"`       // エスカレーションを促すマイルストーン（発生からの経過分、空配列で無効）
+}
+
+// LeaderElectionConfig は複数レプリカで動かす場合のリーダー選出の設定
+type LeaderElectionConfig struct {
+	Disable              bool  `toml:"disable"`                // リーダー選出を行わず、常にこのレプリカでバックグラウンド処理を実行する
+	LockID               int64 `toml:"lock_id"`                // PostgreSQL のアドバイザリロックのキー（同じデータベースを使うBot同士で共通にする）
+	CheckIntervalSeconds int   `toml:"check_interval_seconds"` // ロックの取得・保持を確認する間隔（秒、デフォルトは5秒）
+}
+
+// AlertsConfig は監視システムのアラートからインシデントを作成する設定
+type AlertsConfig struct {
+	ListenAddr      string                 `toml:"listen_addr"`      // Webhook を受け付けるアドレス（例: ":8080"、空の場合は受け付けない）
+	SeverityLabel   string                 `toml:"severity_label"`   // 重要度を表すラベル（デフォルトは severity）
+	ServiceLabel    string                 `toml:"service_label"`    // サービス名を表すラベル（デフォルトは service）
+	DefaultSeverity string                 `toml:"default_severity"` // 重要度を判定できない場合の重要度（デフォルトは high）
+	SeverityMap     map[string]string      `toml:"severity_map"`     // 重要度ラベルの値から重要度への対応（例: page = "critical"）
+	InviteUsers     []string               `toml:"invite_users"`     // インシデントチャンネルに招待するユーザーID
+	Rules           []AlertRule            `toml:"rules"`            // ラベルに応じてインシデントの内容を変えるルール（上から順に最初に一致したものを適用）
+	Grouping        AlertGroupingConfig    `toml:"grouping"`
+	AutoResolve     AlertAutoResolveConfig `toml:"auto_resolve"`
+	Alertmanager    AlertWebhookConfig     `toml:"alertmanager"`
+	Grafana         AlertWebhookConfig     `toml:"grafana"`
+	Datadog         AlertWebhookConfig     `toml:"datadog"`
+	Custom          []CustomAlertSource    `toml:"custom"` // 任意の JSON の Webhook（項目の場所を JSONPath 形式で指定）
+}
+
+// AlertRule はラベルが一致するアラートから作成するインシデントの内容
+type AlertRule struct {
+	Match       map[string]string `toml:"match"`        // ラベルの値（すべて一致した場合に適用、"*" は値があれば一致）
+	Severity    string            `toml:"severity"`     // 重要度（空の場合はラベルから判定）
+	Service     string            `toml:"service"`      // サービス名（空の場合はラベルから取得）
+	Title       string            `toml:"title"`        // タイトルのテンプレート（text/template、.Labels .Annotations .Service .Summary を使用可）
+	InviteUsers []string          `toml:"invite_users"` // インシデントチャンネルに追加で招待するユーザーID
+	Ignore      bool              `toml:"ignore"`       // インシデントを作成しない
+}
+
+// AlertGroupingConfig はアラートを対応中のインシデントにまとめる条件
+// 同じフィンガープリントのアラートは常に同じインシデントにまとめる
+type AlertGroupingConfig struct {
+	ServiceWindowMinutes int  `toml:"service_window_minutes"` // 同じサービスのアラートをまとめる期間（分、デフォルトは30）
+	DisableService       bool `toml:"disable_service"`        // 同じサービスのアラートをまとめない
+}
+
+// AlertAutoResolveConfig はアラートがすべて解消したインシデントを自動で復旧済みにする条件
+// 対象外の重要度のインシデントには、復旧を確認するボタンを投稿する
+type AlertAutoResolveConfig struct {
+	Severities   []string `toml:"severities"`    // 自動で復旧済みにする重要度（空の場合は自動で復旧しない）
+	QuietMinutes int      `toml:"quiet_minutes"` // アラートが再発しなければ復旧済みにするまでの時間（分、デフォルトは30）
+}
+
+// AlertWebhookConfig は監視システムの Webhook を受け付けるパスと送信元の検証方法
+// 検証方法を複数設定した場合はすべてを満たすリクエストだけを受け付ける
+type AlertWebhookConfig struct {
+	Enabled      bool   `toml:"enabled"`       // Webhook を受け付ける（Grafana・Datadog のみ、Alertmanager とカスタムは常に受け付ける）
+	Path         string `toml:"path"`          // Webhook を受け付けるパス（デフォルトは /webhooks/<送信元>）
+	BearerToken  string `toml:"bearer_token"`  // Authorization: Bearer で送られるトークン
+	SecretHeader string `toml:"secret_header"` // 共有シークレットを送るヘッダー（デフォルトは X-Webhook-Secret）
+	Secret       string `toml:"secret"`        // 共有シークレット
+	HMACHeader   string `toml:"hmac_header"`   // 本文の HMAC-SHA256 署名（16進数）を送るヘッダー（デフォルトは X-Signature-256）
+	HMACSecret   string `toml:"hmac_secret"`   // 署名の鍵
+
+	// AllowUnauthenticated は検証方法を設定せずに受け付ける（未設定の場合は検証方法のない送信元を受け付けない）
+	AllowUnauthenticated bool `toml:"allow_unauthenticated"`
+}
+
+// CustomAlertSource は任意の JSON の Webhook からアラートを取り出す設定
+// 各項目は JSONPath 形式（例: "$.data.items[0].name"）で、alerts を指定した場合は配列の各要素からの場所を指定する
+type CustomAlertSource struct {
+	AlertWebhookConfig
+	Name           string            `toml:"name"`            // 送信元の名前（パスのデフォルトは /webhooks/<名前>）
+	Alerts         string            `toml:"alerts"`          // アラートの配列の場所（空の場合は本文全体を1件のアラートとする）
+	Status         string            `toml:"status"`          // 状態の場所（空の場合は常に発生中）
+	ResolvedValues []string          `toml:"resolved_values"` // 解消を表す状態の値（デフォルトは resolved, ok, recovered, normal）
+	Fingerprint    string            `toml:"fingerprint"`     // フィンガープリントの場所（空の場合はラベルから作成）
+	Summary        string            `toml:"summary"`         // 概要の場所
+	Description    string            `toml:"description"`     // 詳細の場所
+	StartsAt       string            `toml:"starts_at"`       // 発生日時の場所（RFC 3339 または UNIX 時間、空の場合は受信日時）
+	URL            string            `toml:"url"`             // グラフ・ダッシュボードへのリンクの場所
+	Labels         map[string]string `toml:"labels"`          // ラベル名と値の場所（severity・service などのラベルで重要度・サービスを判定）
+}
+
+// WebhooksConfig はインシデントの変化を外部のシステムに通知する Webhook の設定
+type WebhooksConfig struct {
+	Endpoints             []WebhookEndpoint `toml:"endpoints"`
+	MaxAttempts           int               `toml:"max_attempts"`            // 失敗した場合を含めた最大送信回数（デフォルトは5回）
+	InitialBackoffSeconds int               `toml:"initial_backoff_seconds"` // 最初の再送までの待ち時間（秒、以降は2倍ずつ延ばす、デフォルトは10秒）
+	TimeoutSeconds        int               `toml:"timeout_seconds"`         // 1回の送信のタイムアウト（秒、デフォルトは10秒）
+}
+
+// WebhookEndpoint は Webhook の送信先
+type WebhookEndpoint struct {
+	Name   string   `toml:"name"`   // 送信先の名前（配信ログと再送に使用、空の場合は URL）
+	URL    string   `toml:"url"`    // 送信先の URL
+	Secret string   `toml:"secret"` // 送信日時と本文の HMAC-SHA256 署名の鍵（空の場合は署名しない）
+	Events []string `toml:"events"` // 送信するイベント（空の場合はすべて）
 }
 
 var config Config
@@ -0,0 +1,111 @@
+package main
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+)
+
+// defaultResolvedValues は状態がこれらの値の場合に解消とみなす（大文字・小文字は区別しない）
+var defaultResolvedValues = []string{"resolved", "ok", "recovered", "normal"}
+
+// customAlertAdapter は設定した JSONPath で任意の JSON の Webhook からアラートを取り出す alertAdapter
+type customAlertAdapter struct {
+	config CustomAlertSource
+}
+
+func (a customAlertAdapter) source() string { return a.config.Name }
+
+// paths は設定したすべての JSONPath を返す（設定の検証に使用）
+func (c CustomAlertSource) paths() []string {
+	paths := []string{c.Alerts, c.Status, c.Fingerprint, c.Summary, c.Description, c.StartsAt, c.URL}
+	for _, path := range c.Labels {
+		paths = append(paths, path)
+	}
+	return paths
+}
+
+// validate は設定した JSONPath をすべて解析できるかチェック
+func (c CustomAlertSource) validate() error {
+	for _, path := range c.paths() {
+		if path == "" {
+			continue
+		}
+		if _, err := parseJSONPath(path); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// alertStatus は状態の値をアラートの状態に変換（解消を表す値以外は発生中）
+func (c CustomAlertSource) alertStatus(value string) string {
+	resolvedValues := c.ResolvedValues
+	if len(resolvedValues) == 0 {
+		resolvedValues = defaultResolvedValues
+	}
+	if slices.ContainsFunc(resolvedValues, func(resolved string) bool { return strings.EqualFold(resolved, value) }) {
+		return alertStatusResolved
+	}
+	return alertStatusFiring
+}
+
+func (a customAlertAdapter) parse(body []byte) ([]Alert, error) {
+	root, err := decodeJSON(body)
+	if err != nil {
+		return nil, err
+	}
+
+	items := []interface{}{root}
+	if a.config.Alerts != "" {
+		value, found := lookupJSONPath(root, a.config.Alerts)
+		list, isList := value.([]interface{})
+		if !found || !isList {
+			return nil, fmt.Errorf("アラートの配列 %s が見つかりません", a.config.Alerts)
+		}
+		items = list
+	}
+
+	now := time.Now()
+	alerts := make([]Alert, 0, len(items))
+	for _, item := range items {
+		labels := make(map[string]string, len(a.config.Labels))
+		for name, path := range a.config.Labels {
+			if value := lookupJSONString(item, path); value != "" {
+				labels[name] = value
+			}
+		}
+		annotations := make(map[string]string)
+		if summary := lookupJSONString(item, a.config.Summary); summary != "" {
+			annotations["summary"] = summary
+		}
+		if description := lookupJSONString(item, a.config.Description); description != "" {
+			annotations["description"] = description
+		}
+
+		status := alertStatusFiring
+		if a.config.Status != "" {
+			status = a.config.alertStatus(lookupJSONString(item, a.config.Status))
+		}
+		startsAt := now
+		if a.config.StartsAt != "" {
+			if value, found := lookupJSONPath(item, a.config.StartsAt); found {
+				if t, ok := jsonTime(value); ok {
+					startsAt = t
+				}
+			}
+		}
+
+		alerts = append(alerts, Alert{
+			Source:       a.source(),
+			Status:       status,
+			Fingerprint:  lookupJSONString(item, a.config.Fingerprint),
+			Labels:       labels,
+			Annotations:  annotations,
+			StartsAt:     startsAt,
+			GeneratorURL: lookupJSONString(item, a.config.URL),
+		})
+	}
+	return alerts, nil
+}
@@ -0,0 +1,129 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// uptimeAlertSource は死活監視サービスの Webhook を想定したカスタムの送信元
+var uptimeAlertSource = CustomAlertSource{
+	AlertWebhookConfig: AlertWebhookConfig{Secret: "s3cret"},
+	Name:               "uptime",
+	Alerts:             "$.checks",
+	Status:             "$.state",
+	ResolvedValues:     []string{"up"},
+	Fingerprint:        "$.id",
+	Summary:            "$.message",
+	StartsAt:           "$.since",
+	URL:                "$.links.report",
+	Labels:             map[string]string{"alertname": "$.name", "service": "$.tags.service", "severity": "$.tags.level"},
+}
+
+// uptimeTestPayload は停止中1件・復旧1件を含む死活監視サービスの Webhook の本文
+const uptimeTestPayload = `{
+  "checks": [
+    {"id": 101, "name": "checkout", "state": "down", "message": "決済ページに接続できません", "since": "2025-01-01T01:00:00Z",
+     "tags": {"service": "payments", "level": "page"}, "links": {"report": "https://uptime.example.com/101"}},
+    {"id": 102, "name": "top", "state": "UP", "message": "トップページが復旧", "tags": {"service": "web"}}
+  ]
+}`
+
+func TestCustomAlertAdapterParse(t *testing.T) {
+	alerts, err := customAlertAdapter{config: uptimeAlertSource}.parse([]byte(uptimeTestPayload))
+	if err != nil {
+		t.Fatalf("parse() error = %v", err)
+	}
+	if len(alerts) != 2 {
+		t.Fatalf("アラートの件数 = %d", len(alerts))
+	}
+
+	down := alerts[0]
+	if down.Source != "uptime" || down.Status != alertStatusFiring || down.Fingerprint != "101" || down.GeneratorURL != "https://uptime.example.com/101" {
+		t.Errorf("停止中のアラート = %+v", down)
+	}
+	if down.Labels["alertname"] != "checkout" || down.Labels["service"] != "payments" || down.Labels["severity"] != "page" || down.summary() != "決済ページに接続できません" {
+		t.Errorf("ラベル = %+v, アノテーション = %+v", down.Labels, down.Annotations)
+	}
+	if down.StartsAt.UTC().Format("2006-01-02T15:04:05Z") != "2025-01-01T01:00:00Z" {
+		t.Errorf("StartsAt = %v", down.StartsAt)
+	}
+
+	// 解消を表す値は大文字・小文字を区別せず、見つからない項目のラベルは付けない
+	up := alerts[1]
+	if up.Status != alertStatusResolved {
+		t.Errorf("復旧のアラートの状態 = %s", up.Status)
+	}
+	if _, exists := up.Labels["severity"]; exists || up.StartsAt.IsZero() {
+		t.Errorf("復旧のアラート = %+v", up)
+	}
+
+	// アラートの配列を指定しない場合は本文全体を1件のアラートとする
+	single := CustomAlertSource{Name: "single", Summary: "$.title"}
+	alerts, err = customAlertAdapter{config: single}.parse([]byte(`{"title": "単発のアラート"}`))
+	if err != nil || len(alerts) != 1 || alerts[0].Status != alertStatusFiring || alerts[0].summary() != "単発のアラート" {
+		t.Errorf("parse() = %+v, %v", alerts, err)
+	}
+
+	if _, err := (customAlertAdapter{config: uptimeAlertSource}).parse([]byte(`{"checks": {}}`)); err == nil {
+		t.Error("アラートの配列がない本文はエラーになるべきです")
+	}
+}
+
+func TestCustomAlertSourceValidate(t *testing.T) {
+	if err := uptimeAlertSource.validate(); err != nil {
+		t.Errorf("validate() error = %v", err)
+	}
+	invalid := CustomAlertSource{Name: "broken", Labels: map[string]string{"service": "$.tags["}}
+	if err := invalid.validate(); err == nil {
+		t.Error("解析できない JSONPath はエラーになるべきです")
+	}
+}
+
+func TestE2ECustomAlertSourceOpensIncident(t *testing.T) {
+	fake, api := setupE2E(t)
+	setAlertsConfig(t, AlertsConfig{
+		SeverityMap: map[string]string{"page": "critical"},
+		Custom:      []CustomAlertSource{uptimeAlertSource},
+	})
+	mux := newAlertWebhookMux(api, "UBOT")
+
+	post := func(secret string) int {
+		req := httptest.NewRequest(http.MethodPost, "/webhooks/uptime", strings.NewReader(uptimeTestPayload))
+		req.Header.Set(defaultWebhookSecretHeader, secret)
+		rec := httptest.NewRecorder()
+		mux.ServeHTTP(rec, req)
+		return rec.Code
+	}
+
+	if code := post("wrong"); code != http.StatusUnauthorized {
+		t.Fatalf("シークレットが一致しない場合のステータスコード = %d", code)
+	}
+	if len(fake.callsTo("conversations.create")) != 0 {
+		t.Fatal("認証に失敗した場合はインシデントを作成しないべきです")
+	}
+	if code := post("s3cret"); code != http.StatusOK {
+		t.Fatalf("ステータスコード = %d", code)
+	}
+
+	// Alertmanager と同じ流れで発生中のアラートからインシデントを作成する
+	incident, err := store.FindIncidentByAlert("101")
+	if err != nil || incident == nil {
+		t.Fatalf("アラートを紐付けたインシデントが作成されるべきです: %v", err)
+	}
+	if incident.Title != "[payments] 決済ページに接続できません" || incident.Severity != "critical" {
+		t.Errorf("保存されたインシデント = %+v", incident)
+	}
+	if !fake.hasMessage(incident.ChannelID, "受信したアラート（1件）") || fake.hasMessage(incident.ChannelID, "トップページが復旧") {
+		t.Error("発生中のアラートの詳細だけをインシデントチャンネルに投稿するべきです")
+	}
+
+	// 再送は同じインシデントにまとめる
+	if code := post("s3cret"); code != http.StatusOK {
+		t.Fatalf("再送のステータスコード = %d", code)
+	}
+	if creates := fake.callsTo("conversations.create"); len(creates) != 1 {
+		t.Errorf("再送でインシデントを作成しないべきです: %d件", len(creates))
+	}
+}
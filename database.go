@@ -6,8 +6,6 @@ import (
 	"log"
 	"os"
 	"time"
-
-	_ "github.com/lib/pq"
 )
 
 var db *sql.DB
@@ -70,6 +68,9 @@ func initDB() error {
 
 	// 接続テスト
 	if err = db.Ping(); err != nil {
+		// 接続できない場合はデータベース機能を無効として扱えるよう nil に戻す
+		db.Close()
+		db = nil
 		return fmt.Errorf("データベース接続テストエラー: %v", err)
 	}
 
@@ -83,394 +84,453 @@ func initDB() error {
 	return nil
 }
 
-// saveIncident はインシデントをデータベースに保存
-func saveIncident(title, severity, description, impact, channelID, channelName, reporterID, reporterName string) (int64, error) {
-	if db == nil {
+// saveIncident はインシデントを保存
+// sourcePermalink はメッセージショートカットから報告された場合の元メッセージのリンク（なければ空文字列）
+func saveIncident(title, severity, description, impact, channelID, channelName, reporterID, reporterName, sourcePermalink string) (int64, error) {
+	if store == nil {
 		return 0, fmt.Errorf("データベース接続が初期化されていません")
 	}
 
-	query := `
-		INSERT INTO incidents (title, severity, description, impact, channel_id, channel_name, reporter_id, reporter_name, status)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, 'open')
-		RETURNING id
-	`
-
-	var incidentID int64
-	err := db.QueryRow(query, title, severity, description, impact, channelID, channelName, reporterID, reporterName).Scan(&incidentID)
+	incidentID, err := store.CreateIncident(NewIncident{
+		Title:           title,
+		Severity:        severity,
+		Description:     description,
+		Impact:          impact,
+		ChannelID:       channelID,
+		ChannelName:     channelName,
+		ReporterID:      reporterID,
+		ReporterName:    reporterName,
+		SourcePermalink: sourcePermalink,
+	})
 	if err != nil {
-		return 0, fmt.Errorf("インシデント保存エラー: %v", err)
+		return 0, err
 	}
 
-	log.Printf("インシデントをデータベースに保存しました (ID: %d)", incidentID)
+	log.Printf("インシデントを保存しました (ID: %d)", incidentID)
+	emitIncidentWebhook(webhookEventCreated, incidentID, reporterID, nil)
 	return incidentID, nil
 }
 
 // assignHandler はインシデントハンドラーを割り当て
 func assignHandler(incidentID int64, handlerID, handlerName, assignedBy string) error {
-	if db == nil {
+	if store == nil {
 		return fmt.Errorf("データベース接続が初期化されていません")
 	}
 
-	tx, err := db.Begin()
-	if err != nil {
-		return fmt.Errorf("トランザクション開始エラー: %v", err)
-	}
-	defer tx.Rollback()
-
-	// インシデントのハンドラーを更新
-	updateQuery := `
-		UPDATE incidents
-		SET handler_id = $1, handler_name = $2, updated_at = CURRENT_TIMESTAMP
-		WHERE id = $3
-	`
-	_, err = tx.Exec(updateQuery, handlerID, handlerName, incidentID)
-	if err != nil {
-		return fmt.Errorf("ハンドラー更新エラー: %v", err)
-	}
-
-	// ハンドラー履歴を記録
-	historyQuery := `
-		INSERT INTO incident_handler_history (incident_id, old_handler_id, new_handler_id, assigned_by)
-		SELECT $1, handler_id, $2, $3
-		FROM incidents
-		WHERE id = $1
-	`
-	_, err = tx.Exec(historyQuery, incidentID, handlerID, assignedBy)
-	if err != nil {
-		return fmt.Errorf("ハンドラー履歴記録エラー: %v", err)
-	}
-
-	if err = tx.Commit(); err != nil {
-		return fmt.Errorf("トランザクションコミットエラー: %v", err)
+	oldHandlerID := currentHandlerID(incidentID)
+	if err := store.ChangeHandler(incidentID, handlerID, handlerName, assignedBy); err != nil {
+		return err
 	}
 
 	log.Printf("インシデント %d のハンドラーを %s に割り当てました", incidentID, handlerName)
+	emitIncidentWebhook(webhookEventHandlerChanged, incidentID, assignedBy, &webhookChange{Old: oldHandlerID, New: handlerID})
 	return nil
 }
 
 // getIncidentByChannelID はチャンネルIDからインシデントを取得
 func getIncidentByChannelID(channelID string) (int64, string, error) {
-	if db == nil {
+	if store == nil {
 		return 0, "", fmt.Errorf("データベース接続が初期化されていません")
 	}
 
-	query := `
-		SELECT id, title
-		FROM incidents
-		WHERE channel_id = $1 AND status = 'open'
-		ORDER BY created_at DESC
-		LIMIT 1
-	`
-
-	var incidentID int64
-	var title string
-	err := db.QueryRow(query, channelID).Scan(&incidentID, &title)
+	incident, err := store.FindActiveIncidentByChannel(channelID)
 	if err != nil {
-		if err == sql.ErrNoRows {
-			return 0, "", fmt.Errorf("チャンネル %s のオープンなインシデントが見つかりません", channelID)
-		}
-		return 0, "", fmt.Errorf("インシデント取得エラー: %v", err)
+		return 0, "", err
 	}
-
-	return incidentID, title, nil
+	if incident == nil {
+		return 0, "", fmt.Errorf("チャンネル %s のオープンなインシデントが見つかりません", channelID)
+	}
+	return incident.ID, incident.Title, nil
 }
 
-// getIncidentDetails はインシデントIDから詳細情報を取得
-func getIncidentDetails(incidentID int64) (map[string]interface{}, error) {
-	if db == nil {
-		return nil, fmt.Errorf("データベース接続が初期化されていません")
+// getLatestIncidentByChannelID はチャンネルの最新のインシデントIDを取得（復旧済み・クローズ済みも含む）
+func getLatestIncidentByChannelID(channelID string) (int64, error) {
+	if store == nil {
+		return 0, fmt.Errorf("データベース接続が初期化されていません")
 	}
 
-	query := `
-		SELECT title, severity, description, impact, status, channel_id, channel_name,
-		       reporter_id, reporter_name, handler_id, handler_name, created_at, updated_at
-		FROM incidents
-		WHERE id = $1
-	`
-
-	var title, severity, description, impact, status, channelID, channelName, reporterID, reporterName string
-	var handlerID, handlerName sql.NullString
-	var createdAt, updatedAt time.Time
-
-	err := db.QueryRow(query, incidentID).Scan(
-		&title, &severity, &description, &impact, &status, &channelID, &channelName,
-		&reporterID, &reporterName, &handlerID, &handlerName, &createdAt, &updatedAt,
-	)
+	incident, err := store.FindLatestIncidentByChannel(channelID)
 	if err != nil {
-		if err == sql.ErrNoRows {
-			return nil, fmt.Errorf("インシデントID %d が見つかりません", incidentID)
-		}
-		return nil, fmt.Errorf("インシデント詳細取得エラー: %v", err)
+		return 0, err
 	}
-
-	details := map[string]interface{}{
-		"id":            incidentID,
-		"title":         title,
-		"severity":      severity,
-		"description":   description,
-		"impact":        impact,
-		"status":        status,
-		"channel_id":    channelID,
-		"channel_name":  channelName,
-		"reporter_id":   reporterID,
-		"reporter_name": reporterName,
-		"created_at":    createdAt,
-		"updated_at":    updatedAt,
+	if incident == nil {
+		return 0, fmt.Errorf("チャンネル %s のインシデントが見つかりません", channelID)
 	}
+	return incident.ID, nil
+}
 
-	if handlerID.Valid {
-		details["handler_id"] = handlerID.String
-	}
-	if handlerName.Valid {
-		details["handler_name"] = handlerName.String
+// getIncidentDetails はインシデントIDから詳細情報を取得
+func getIncidentDetails(incidentID int64) (*Incident, error) {
+	if store == nil {
+		return nil, fmt.Errorf("データベース接続が初期化されていません")
 	}
 
-	return details, nil
+	return store.GetIncident(incidentID)
 }
 
 // updateIncident はインシデントの詳細情報を更新
 func updateIncident(incidentID int64, field, oldValue, newValue, updatedBy, updatedByName string) error {
-	if db == nil {
+	if store == nil {
 		return fmt.Errorf("データベース接続が初期化されていません")
 	}
 
-	tx, err := db.Begin()
-	if err != nil {
-		return fmt.Errorf("トランザクション開始エラー: %v", err)
-	}
-	defer tx.Rollback()
-
-	// インシデントを更新
-	var updateQuery string
-	switch field {
-	case "title":
-		updateQuery = "UPDATE incidents SET title = $1, updated_at = CURRENT_TIMESTAMP WHERE id = $2"
-	case "severity":
-		updateQuery = "UPDATE incidents SET severity = $1, updated_at = CURRENT_TIMESTAMP WHERE id = $2"
-	case "description":
-		updateQuery = "UPDATE incidents SET description = $1, updated_at = CURRENT_TIMESTAMP WHERE id = $2"
-	case "impact":
-		updateQuery = "UPDATE incidents SET impact = $1, updated_at = CURRENT_TIMESTAMP WHERE id = $2"
-	default:
-		return fmt.Errorf("更新できないフィールド: %s", field)
-	}
-
-	_, err = tx.Exec(updateQuery, newValue, incidentID)
-	if err != nil {
-		return fmt.Errorf("インシデント更新エラー: %v", err)
-	}
-
-	// 更新履歴を記録
-	historyQuery := `
-		INSERT INTO incident_update_history (incident_id, field_name, old_value, new_value, updated_by, updated_by_name)
-		VALUES ($1, $2, $3, $4, $5, $6)
-	`
-	_, err = tx.Exec(historyQuery, incidentID, field, oldValue, newValue, updatedBy, updatedByName)
-	if err != nil {
-		return fmt.Errorf("更新履歴記録エラー: %v", err)
-	}
-
-	if err = tx.Commit(); err != nil {
-		return fmt.Errorf("トランザクションコミットエラー: %v", err)
+	if err := store.UpdateIncidentField(incidentID, field, oldValue, newValue, updatedBy, updatedByName); err != nil {
+		return err
 	}
 
 	log.Printf("インシデント %d の %s を更新しました", incidentID, field)
+	if field == "severity" && oldValue != newValue {
+		emitIncidentWebhook(webhookEventSeverityChanged, incidentID, updatedBy, &webhookChange{Old: oldValue, New: newValue})
+	}
 	return nil
 }
 
 // changeHandler はインシデントハンドラーを変更（交代）
 func changeHandler(incidentID int64, newHandlerID, newHandlerName, changedBy string) error {
-	if db == nil {
+	if store == nil {
 		return fmt.Errorf("データベース接続が初期化されていません")
 	}
 
-	tx, err := db.Begin()
-	if err != nil {
-		return fmt.Errorf("トランザクション開始エラー: %v", err)
+	oldHandlerID := currentHandlerID(incidentID)
+	if err := store.ChangeHandler(incidentID, newHandlerID, newHandlerName, changedBy); err != nil {
+		return err
 	}
-	defer tx.Rollback()
 
-	// 現在のハンドラーを取得
-	var oldHandlerID sql.NullString
-	err = tx.QueryRow("SELECT handler_id FROM incidents WHERE id = $1", incidentID).Scan(&oldHandlerID)
+	log.Printf("インシデント %d のハンドラーを %s に変更しました", incidentID, newHandlerName)
+	emitIncidentWebhook(webhookEventHandlerChanged, incidentID, changedBy, &webhookChange{Old: oldHandlerID, New: newHandlerID})
+	return nil
+}
+
+// currentHandlerID は Webhook で通知する変更前の担当者を取得（Webhook を送信しない場合は取得しない）
+func currentHandlerID(incidentID int64) string {
+	if !webhooksEnabled() {
+		return ""
+	}
+	incident, err := store.GetIncident(incidentID)
 	if err != nil {
-		return fmt.Errorf("現在のハンドラー取得エラー: %v", err)
+		return ""
 	}
+	return incident.HandlerID
+}
 
-	// ハンドラーを更新
-	updateQuery := `
-		UPDATE incidents
-		SET handler_id = $1, handler_name = $2, updated_at = CURRENT_TIMESTAMP
-		WHERE id = $3
-	`
-	_, err = tx.Exec(updateQuery, newHandlerID, newHandlerName, incidentID)
-	if err != nil {
-		return fmt.Errorf("ハンドラー更新エラー: %v", err)
+// getUpdateHistory はインシデントの更新履歴を取得
+func getUpdateHistory(incidentID int64, limit int) ([]FieldUpdate, error) {
+	if store == nil {
+		return nil, fmt.Errorf("データベース接続が初期化されていません")
 	}
 
-	// ハンドラー履歴を記録
-	var oldHandlerIDStr string
-	if oldHandlerID.Valid {
-		oldHandlerIDStr = oldHandlerID.String
+	return store.UpdateHistory(incidentID, limit)
+}
+
+// getHandlerHistory はインシデントの担当者変更履歴を取得
+func getHandlerHistory(incidentID int64, limit int) ([]HandlerChange, error) {
+	if store == nil {
+		return nil, fmt.Errorf("データベース接続が初期化されていません")
+	}
+
+	return store.HandlerHistory(incidentID, limit)
+}
+
+// getStatusHistory はインシデントのステータス変更履歴を取得
+func getStatusHistory(incidentID int64, limit int) ([]StatusChange, error) {
+	if store == nil {
+		return nil, fmt.Errorf("データベース接続が初期化されていません")
+	}
+
+	return store.StatusHistory(incidentID, limit)
+}
+
+// getOpenIncidents はオープンなインシデント一覧を保存されたタイムキーパーの状態とともに取得（タイムキーパー復元・同期用）
+// タイムキーパーの状態はインシデントIDをキーに返す（保存されていないものは含めない）
+func getOpenIncidents() ([]Incident, map[int64]TimekeeperState, error) {
+	if store == nil {
+		return nil, nil, fmt.Errorf("データベース接続が初期化されていません")
 	}
 
-	historyQuery := `
-		INSERT INTO incident_handler_history (incident_id, old_handler_id, new_handler_id, assigned_by)
-		VALUES ($1, $2, $3, $4)
-	`
-	_, err = tx.Exec(historyQuery, incidentID, oldHandlerIDStr, newHandlerID, changedBy)
+	incidents, _, err := store.ListIncidents(IncidentListFilter{Statuses: activeStatuses, SortBy: incidentListSortOldest}, 0, 0)
 	if err != nil {
-		return fmt.Errorf("ハンドラー履歴記録エラー: %v", err)
+		return nil, nil, fmt.Errorf("オープンなインシデント取得エラー: %v", err)
 	}
 
-	if err = tx.Commit(); err != nil {
-		return fmt.Errorf("トランザクションコミットエラー: %v", err)
+	if len(incidents) == 0 {
+		return incidents, nil, nil
 	}
 
-	log.Printf("インシデント %d のハンドラーを %s に変更しました", incidentID, newHandlerName)
-	return nil
+	incidentIDs := make([]int64, 0, len(incidents))
+	for _, incident := range incidents {
+		incidentIDs = append(incidentIDs, incident.ID)
+	}
+	states, err := getTimekeeperStates(incidentIDs)
+	if err != nil {
+		return nil, nil, err
+	}
+	return incidents, states, nil
 }
 
-// getUpdateHistory はインシデントの更新履歴を取得
-func getUpdateHistory(incidentID int64, limit int) ([]map[string]interface{}, error) {
-	if db == nil {
+// listIncidents はステータスと担当者で絞り込んだインシデント一覧を取得（handlerIDが空の場合は担当者で絞り込まない）
+// オープンなインシデントは作成日時、復旧済みのインシデントは復旧日時の新しい順に並ぶ
+func listIncidents(statuses []string, handlerID string, limit int) ([]Incident, error) {
+	if store == nil {
 		return nil, fmt.Errorf("データベース接続が初期化されていません")
 	}
 
-	query := `
-		SELECT field_name, old_value, new_value, updated_by, updated_by_name, updated_at, note
-		FROM incident_update_history
-		WHERE incident_id = $1
-		ORDER BY updated_at DESC
-		LIMIT $2
-	`
+	filter := IncidentListFilter{Statuses: statuses, HandlerID: handlerID, SortBy: incidentListSortRecent}
+	incidents, _, err := store.ListIncidents(filter, limit, 0)
+	return incidents, err
+}
 
-	rows, err := db.Query(query, incidentID, limit)
-	if err != nil {
-		return nil, fmt.Errorf("更新履歴取得エラー: %v", err)
+// searchIncidents はフィルター条件に一致するインシデントの1ページ分と全件数を取得
+func searchIncidents(filter IncidentListFilter, limit, offset int) ([]Incident, int, error) {
+	if store == nil {
+		return nil, 0, fmt.Errorf("データベース接続が初期化されていません")
+	}
+
+	return store.ListIncidents(filter, limit, offset)
+}
+
+// searchIncidentsByText はキーワードに一致する過去のインシデントを関連度順に取得
+func searchIncidentsByText(terms []string, limit int) ([]Incident, error) {
+	if store == nil {
+		return nil, fmt.Errorf("データベース接続が初期化されていません")
 	}
-	defer rows.Close()
 
-	var history []map[string]interface{}
-	for rows.Next() {
-		var fieldName, oldValue, newValue, updatedBy, updatedByName string
-		var note sql.NullString
-		var updatedAt time.Time
+	return store.SearchIncidents(terms, limit)
+}
 
-		err := rows.Scan(&fieldName, &oldValue, &newValue, &updatedBy, &updatedByName, &updatedAt, &note)
+// アクションアイテムのステータス
+const (
+	actionItemStatusOpen = "open"
+	actionItemStatusDone = "done"
+)
+
+// actionItemListLimit はアクションアイテム一覧の最大件数
+const actionItemListLimit = 50
+
+// addActionItem はインシデントにアクションアイテムを追加
+// dueDate は "2006-01-02" 形式（空文字列の場合は期限なし）
+func addActionItem(incidentID int64, title, ownerID, ownerName, dueDate, link, createdBy string) (int64, error) {
+	if store == nil {
+		return 0, fmt.Errorf("データベース接続が初期化されていません")
+	}
+
+	item := ActionItem{
+		IncidentID: incidentID,
+		Title:      title,
+		OwnerID:    ownerID,
+		OwnerName:  ownerName,
+		Link:       link,
+		CreatedBy:  createdBy,
+	}
+	if dueDate != "" {
+		due, err := time.Parse("2006-01-02", dueDate)
 		if err != nil {
-			log.Printf("履歴スキャンエラー: %v", err)
-			continue
+			return 0, fmt.Errorf("期限の形式が不正です: %s", dueDate)
 		}
+		item.DueDate = &due
+	}
 
-		record := map[string]interface{}{
-			"field_name":      fieldName,
-			"old_value":       oldValue,
-			"new_value":       newValue,
-			"updated_by":      updatedBy,
-			"updated_by_name": updatedByName,
-			"updated_at":      updatedAt,
-		}
+	itemID, err := store.AddActionItem(item)
+	if err != nil {
+		return 0, err
+	}
 
-		if note.Valid {
-			record["note"] = note.String
-		}
+	log.Printf("インシデント %d にアクションアイテム %d を追加しました", incidentID, itemID)
+	return itemID, nil
+}
 
-		history = append(history, record)
+// listActionItems はアクションアイテム一覧を取得
+// incidentID が0の場合はすべてのインシデント、ownerID が空の場合はすべての担当者が対象
+func listActionItems(incidentID int64, ownerID string, includeDone bool) ([]ActionItem, error) {
+	if store == nil {
+		return nil, fmt.Errorf("データベース接続が初期化されていません")
 	}
 
-	return history, nil
+	return store.ListActionItems(incidentID, ownerID, includeDone, actionItemListLimit)
 }
 
-// getOpenIncidents はオープンなインシデント一覧を取得（タイムキーパー復元用）
-func getOpenIncidents() ([]map[string]interface{}, error) {
-	if db == nil {
+// getOverdueActionItems は期限切れで、本日まだリマインドしていない未完了のアクションアイテムを取得
+func getOverdueActionItems(today time.Time) ([]ActionItem, error) {
+	if store == nil {
 		return nil, fmt.Errorf("データベース接続が初期化されていません")
 	}
 
-	query := `
-		SELECT id, channel_id, created_at
-		FROM incidents
-		WHERE status = 'open'
-		ORDER BY created_at ASC
-	`
+	return store.OverdueActionItems(today)
+}
 
-	rows, err := db.Query(query)
+// completeActionItem はアクションアイテムを完了にし、対象のインシデントIDとタイトルを返す
+func completeActionItem(itemID int64, completedBy string) (int64, string, error) {
+	if store == nil {
+		return 0, "", fmt.Errorf("データベース接続が初期化されていません")
+	}
+
+	item, err := store.CompleteActionItem(itemID, completedBy)
 	if err != nil {
-		return nil, fmt.Errorf("オープンなインシデント取得エラー: %v", err)
+		return 0, "", err
 	}
-	defer rows.Close()
 
-	var incidents []map[string]interface{}
-	for rows.Next() {
-		var id int64
-		var channelID string
-		var createdAt time.Time
+	log.Printf("アクションアイテム %d を完了にしました", itemID)
+	return item.IncidentID, item.Title, nil
+}
 
-		err := rows.Scan(&id, &channelID, &createdAt)
-		if err != nil {
-			log.Printf("インシデント情報スキャンエラー: %v", err)
-			continue
-		}
+// markActionItemsReminded はアクションアイテムのリマインド日時を記録
+func markActionItemsReminded(itemIDs []int64) error {
+	if store == nil {
+		return fmt.Errorf("データベース接続が初期化されていません")
+	}
 
-		incident := map[string]interface{}{
-			"id":         id,
-			"channel_id": channelID,
-			"created_at": createdAt,
-		}
-		incidents = append(incidents, incident)
+	return store.MarkActionItemsReminded(itemIDs)
+}
+
+// saveTimekeeperState はタイムキーパーの状態（running/paused/stopped）と投稿間隔を保存
+func saveTimekeeperState(incidentID int64, state string, intervalMinutes int) error {
+	if store == nil {
+		return fmt.Errorf("データベース接続が初期化されていません")
 	}
 
-	return incidents, nil
+	return store.SaveTimekeeperState(incidentID, state, intervalMinutes)
 }
 
-// resolveIncident はインシデントを復旧済みにする
-func resolveIncident(incidentID int64, resolvedBy, resolvedByName string) error {
-	if db == nil {
+// saveTimekeeperProgress はタイムキーパーの投稿の進捗（最終投稿時刻・マイルストーン・ピン留めメッセージ）を保存
+func saveTimekeeperProgress(incidentID int64, progress timekeeperProgress) error {
+	if store == nil {
 		return fmt.Errorf("データベース接続が初期化されていません")
 	}
 
-	tx, err := db.Begin()
+	return store.SaveTimekeeperProgress(incidentID, progress)
+}
+
+// getTimekeeperState はタイムキーパーの保存された状態を取得（保存されていない場合は nil）
+func getTimekeeperState(incidentID int64) (*TimekeeperState, error) {
+	states, err := getTimekeeperStates([]int64{incidentID})
 	if err != nil {
-		return fmt.Errorf("トランザクション開始エラー: %v", err)
+		return nil, err
 	}
-	defer tx.Rollback()
 
-	// インシデントのステータスを更新
-	updateQuery := `
-		UPDATE incidents
-		SET status = 'resolved', resolved_at = CURRENT_TIMESTAMP, updated_at = CURRENT_TIMESTAMP
-		WHERE id = $1 AND status = 'open'
-	`
-	result, err := tx.Exec(updateQuery, incidentID)
-	if err != nil {
-		return fmt.Errorf("インシデント復旧更新エラー: %v", err)
+	state, exists := states[incidentID]
+	if !exists {
+		return nil, nil
+	}
+	return &state, nil
+}
+
+// getTimekeeperStates は複数のインシデントの保存されたタイムキーパーの状態を取得（状態が保存されていないものは含めない）
+func getTimekeeperStates(incidentIDs []int64) (map[int64]TimekeeperState, error) {
+	if store == nil {
+		return nil, fmt.Errorf("データベース接続が初期化されていません")
 	}
 
-	rowsAffected, err := result.RowsAffected()
-	if err != nil {
-		return fmt.Errorf("更新行数取得エラー: %v", err)
+	return store.TimekeeperStates(incidentIDs)
+}
+
+// transitionTimekeeperState はタイムキーパーの状態が from のいずれかの場合のみ to に変更し、変更したかを返す
+// 状態が保存されていない（状態の保存に対応する前から動作している）場合は動作中として扱う
+// 再開（running への変更）時は、一時停止中の分をさかのぼって投稿しないよう投稿の進捗をリセットする
+func transitionTimekeeperState(incidentID int64, from []string, to string) (bool, error) {
+	if store == nil {
+		return false, fmt.Errorf("データベース接続が初期化されていません")
 	}
 
-	if rowsAffected == 0 {
-		return fmt.Errorf("インシデント %d は既に復旧済みか存在しません", incidentID)
+	return store.TransitionTimekeeperState(incidentID, from, to)
+}
+
+// saveTimekeeperInterval はタイムキーパーの投稿間隔を保存（重要度の変更時）
+func saveTimekeeperInterval(incidentID int64, intervalMinutes int) error {
+	if store == nil {
+		return fmt.Errorf("データベース接続が初期化されていません")
 	}
 
-	// ステータス変更履歴を記録
-	historyQuery := `
-		INSERT INTO incident_status_history (incident_id, old_status, new_status, changed_by, note)
-		VALUES ($1, 'open', 'resolved', $2, $3)
-	`
+	return store.SaveTimekeeperInterval(incidentID, intervalMinutes)
+}
+
+// DurationStats は所要時間の集計結果
+type DurationStats struct {
+	Count  int // 集計対象の件数（担当者未割り当て・未復旧のインシデントは含まない）
+	Mean   time.Duration
+	Median time.Duration
+	P90    time.Duration
+}
+
+// IncidentStats は重要度ごとのインシデント統計
+type IncidentStats struct {
+	Count         int
+	TimeToAssign  DurationStats // 報告から最初の担当者割り当てまで（MTTA）
+	TimeToResolve DurationStats // 報告から復旧まで（MTTR）
+}
+
+// getIncidentStats は集計期間とその直前の同じ長さの期間のインシデント統計を取得
+// 戻り値のマップのキーは重要度で、空文字列は全体の集計
+func getIncidentStats(until time.Time, period time.Duration) (current, previous map[string]IncidentStats, err error) {
+	if store == nil {
+		return nil, nil, fmt.Errorf("データベース接続が初期化されていません")
+	}
+
+	return store.IncidentStats(until, period)
+}
+
+// resolveIncident はインシデントを復旧済みにする
+// resolutionNote が指定された場合は復旧メモとして保存する（全文検索の対象）
+func resolveIncident(incidentID int64, resolvedBy, resolvedByName, resolutionNote string) error {
 	note := fmt.Sprintf("%s により復旧完了", resolvedByName)
-	_, err = tx.Exec(historyQuery, incidentID, resolvedBy, note)
-	if err != nil {
-		return fmt.Errorf("ステータス履歴記録エラー: %v", err)
+	if resolutionNote != "" {
+		note = resolutionNote
+	}
+	if store == nil {
+		return fmt.Errorf("データベース接続が初期化されていません")
 	}
 
-	if err = tx.Commit(); err != nil {
-		return fmt.Errorf("トランザクションコミットエラー: %v", err)
+	// ステータスと復旧メモは同時に保存する（片方だけ保存された状態を残さない）
+	oldStatus, err := store.ResolveIncident(incidentID, resolvedBy, note, resolutionNote)
+	if err != nil {
+		return err
 	}
 
 	log.Printf("インシデント %d を復旧済みに更新しました (復旧者: %s)", incidentID, resolvedByName)
+	emitIncidentWebhook(webhookEventStatusChanged, incidentID, resolvedBy, &webhookChange{Old: oldStatus, New: StatusResolved})
+	emitIncidentWebhook(webhookEventResolved, incidentID, resolvedBy, nil)
 	return nil
 }
+
+// changeIncidentStatus はインシデントのステータスを変更し、変更前のステータスを返す
+// 許可されていない遷移の場合はエラーを返す
+func changeIncidentStatus(incidentID int64, newStatus, changedBy, note string) (string, error) {
+	if store == nil {
+		return "", fmt.Errorf("データベース接続が初期化されていません")
+	}
+
+	oldStatus, err := store.ChangeStatus(incidentID, newStatus, changedBy, note)
+	if err != nil {
+		return "", err
+	}
+
+	log.Printf("インシデント %d のステータスを %s から %s に変更しました", incidentID, oldStatus, newStatus)
+	emitIncidentWebhook(webhookEventStatusChanged, incidentID, changedBy, &webhookChange{Old: oldStatus, New: newStatus})
+	return oldStatus, nil
+}
+
+// claimProcessedEvent はイベントを処理済みとして記録し、初めて記録した場合は true を返す
+// 既に記録されていても有効期限が切れている場合は記録し直す
+func claimProcessedEvent(eventKey string, ttl time.Duration) (bool, error) {
+	if store == nil {
+		return false, fmt.Errorf("データベース接続が初期化されていません")
+	}
+
+	return store.ClaimProcessedEvent(eventKey, ttl)
+}
+
+// purgeExpiredProcessedEvents は有効期限が切れた処理済みイベントを削除し、削除した件数を返す
+func purgeExpiredProcessedEvents() (int64, error) {
+	if store == nil {
+		return 0, fmt.Errorf("データベース接続が初期化されていません")
+	}
+
+	return store.PurgeExpiredProcessedEvents()
+}
+
+// lookupJournalIncidentID はジャーナルの仮IDに対応する登録後のインシデントIDを取得（未登録の場合は0）
+func lookupJournalIncidentID(journalID int64) (int64, error) {
+	if store == nil {
+		return 0, fmt.Errorf("データベース接続が初期化されていません")
+	}
+
+	return store.LookupJournalIncidentID(journalID)
+}
@@ -1,18 +1,20 @@
 package main
 
 import (
+	"strings"
 	"testing"
+	"time"
 )
 
 // モックデータベース接続のテスト
 func TestDatabaseConnectionNil(t *testing.T) {
 	// データベース接続がnilの場合のテスト
-	originalDB := db
-	db = nil
-	defer func() { db = originalDB }()
+	originalDB, originalStore := db, store
+	db, store = nil, nil
+	defer func() { db, store = originalDB, originalStore }()
 
 	// saveIncident
-	_, err := saveIncident("test", "high", "desc", "impact", "ch1", "channel", "u1", "user")
+	_, err := saveIncident("test", "high", "desc", "impact", "ch1", "channel", "u1", "user", "")
 	if err == nil {
 		t.Error("データベースがnilの場合、saveIncidentはエラーを返すべきです")
 	}
@@ -53,27 +55,195 @@ func TestDatabaseConnectionNil(t *testing.T) {
 		t.Error("データベースがnilの場合、getUpdateHistoryはエラーを返すべきです")
 	}
 
+	// getHandlerHistory
+	_, err = getHandlerHistory(1, 10)
+	if err == nil {
+		t.Error("データベースがnilの場合、getHandlerHistoryはエラーを返すべきです")
+	}
+
+	// getStatusHistory
+	_, err = getStatusHistory(1, 10)
+	if err == nil {
+		t.Error("データベースがnilの場合、getStatusHistoryはエラーを返すべきです")
+	}
+
 	// getOpenIncidents
-	_, err = getOpenIncidents()
+	_, _, err = getOpenIncidents()
 	if err == nil {
 		t.Error("データベースがnilの場合、getOpenIncidentsはエラーを返すべきです")
 	}
 
+	// listIncidents
+	_, err = listIncidents(activeStatuses, "", 10)
+	if err == nil {
+		t.Error("データベースがnilの場合、listIncidentsはエラーを返すべきです")
+	}
+
+	// searchIncidents
+	_, _, err = searchIncidents(IncidentListFilter{}, 10, 0)
+	if err == nil {
+		t.Error("データベースがnilの場合、searchIncidentsはエラーを返すべきです")
+	}
+
+	// searchIncidentsByText
+	_, err = searchIncidentsByText([]string{"DB"}, 10)
+	if err == nil {
+		t.Error("データベースがnilの場合、searchIncidentsByTextはエラーを返すべきです")
+	}
+
+	// getIncidentStats
+	_, _, err = getIncidentStats(time.Now(), 7*24*time.Hour)
+	if err == nil {
+		t.Error("データベースがnilの場合、getIncidentStatsはエラーを返すべきです")
+	}
+
 	// resolveIncident
-	err = resolveIncident(1, "u1", "user")
+	err = resolveIncident(1, "u1", "user", "")
 	if err == nil {
 		t.Error("データベースがnilの場合、resolveIncidentはエラーを返すべきです")
 	}
+
+	// changeIncidentStatus
+	_, err = changeIncidentStatus(1, StatusIdentified, "u1", "note")
+	if err == nil {
+		t.Error("データベースがnilの場合、changeIncidentStatusはエラーを返すべきです")
+	}
+
+	// getLatestIncidentByChannelID
+	_, err = getLatestIncidentByChannelID("ch1")
+	if err == nil {
+		t.Error("データベースがnilの場合、getLatestIncidentByChannelIDはエラーを返すべきです")
+	}
+
+	// addActionItem
+	_, err = addActionItem(1, "title", "u1", "user", "2025-01-31", "", "u1")
+	if err == nil {
+		t.Error("データベースがnilの場合、addActionItemはエラーを返すべきです")
+	}
+
+	// listActionItems
+	_, err = listActionItems(1, "", true)
+	if err == nil {
+		t.Error("データベースがnilの場合、listActionItemsはエラーを返すべきです")
+	}
+
+	// getOverdueActionItems
+	_, err = getOverdueActionItems(time.Now())
+	if err == nil {
+		t.Error("データベースがnilの場合、getOverdueActionItemsはエラーを返すべきです")
+	}
+
+	// completeActionItem
+	_, _, err = completeActionItem(1, "u1")
+	if err == nil {
+		t.Error("データベースがnilの場合、completeActionItemはエラーを返すべきです")
+	}
+
+	// markActionItemsReminded
+	err = markActionItemsReminded([]int64{1})
+	if err == nil {
+		t.Error("データベースがnilの場合、markActionItemsRemindedはエラーを返すべきです")
+	}
+
+	// saveTimekeeperState
+	err = saveTimekeeperState(1, timekeeperStatePaused, 10)
+	if err == nil {
+		t.Error("データベースがnilの場合、saveTimekeeperStateはエラーを返すべきです")
+	}
+
+	// saveTimekeeperProgress
+	err = saveTimekeeperProgress(1, timekeeperProgress{LastPostedAt: time.Now()})
+	if err == nil {
+		t.Error("データベースがnilの場合、saveTimekeeperProgressはエラーを返すべきです")
+	}
+
+	// getTimekeeperState
+	_, err = getTimekeeperState(1)
+	if err == nil {
+		t.Error("データベースがnilの場合、getTimekeeperStateはエラーを返すべきです")
+	}
+
+	// transitionTimekeeperState
+	_, err = transitionTimekeeperState(1, []string{timekeeperStateRunning}, timekeeperStatePaused)
+	if err == nil {
+		t.Error("データベースがnilの場合、transitionTimekeeperStateはエラーを返すべきです")
+	}
+
+	// saveTimekeeperInterval
+	err = saveTimekeeperInterval(1, 10)
+	if err == nil {
+		t.Error("データベースがnilの場合、saveTimekeeperIntervalはエラーを返すべきです")
+	}
+
+	// claimProcessedEvent
+	_, err = claimProcessedEvent("envelope:1", time.Hour)
+	if err == nil {
+		t.Error("データベースがnilの場合、claimProcessedEventはエラーを返すべきです")
+	}
+
+	// purgeExpiredProcessedEvents
+	_, err = purgeExpiredProcessedEvents()
+	if err == nil {
+		t.Error("データベースがnilの場合、purgeExpiredProcessedEventsはエラーを返すべきです")
+	}
+
+	// lookupJournalIncidentID
+	_, err = lookupJournalIncidentID(-1)
+	if err == nil {
+		t.Error("データベースがnilの場合、lookupJournalIncidentIDはエラーを返すべきです")
+	}
 }
 
 func TestDatabaseErrorMessages(t *testing.T) {
 	// データベース接続がnilの場合のエラーメッセージをテスト
-	originalDB := db
-	db = nil
-	defer func() { db = originalDB }()
+	originalDB, originalStore := db, store
+	db, store = nil, nil
+	defer func() { db, store = originalDB, originalStore }()
 
-	_, err := saveIncident("test", "high", "desc", "impact", "ch1", "channel", "u1", "user")
+	_, err := saveIncident("test", "high", "desc", "impact", "ch1", "channel", "u1", "user", "")
 	if err != nil && err.Error() != "データベース接続が初期化されていません" {
 		t.Errorf("予期しないエラーメッセージ: %v", err)
 	}
 }
+
+func TestBuildIncidentListQuery(t *testing.T) {
+	filter := IncidentListFilter{
+		Severities: []string{"critical"},
+		HandlerID:  "U1",
+		ReporterID: "U2",
+		Since:      time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+		SortBy:     incidentListSortSeverity,
+	}
+
+	query, countQuery, args := buildIncidentListQuery(filter, 10, 20)
+
+	for _, expected := range []string{
+		"status = ANY($1)",
+		"severity = ANY($2)",
+		"handler_id = $3",
+		"reporter_id = $4",
+		"created_at >= $5",
+		"LIMIT $6 OFFSET $7",
+		"CASE severity WHEN 'critical' THEN 0",
+	} {
+		if !strings.Contains(query, expected) {
+			t.Errorf("クエリに %q が含まれていません:\n%s", expected, query)
+		}
+	}
+	if len(args) != 7 || args[5] != 10 || args[6] != 20 {
+		t.Errorf("引数が間違っています: %v", args)
+	}
+	// 全件数は LIMIT・OFFSET を除いた同じ条件で数える
+	if !strings.HasPrefix(countQuery, "SELECT COUNT(*) FROM incidents WHERE status = ANY($1)") || !strings.Contains(countQuery, "created_at >= $5") || strings.Contains(countQuery, "$6") {
+		t.Errorf("件数のクエリが間違っています:\n%s", countQuery)
+	}
+
+	// 条件なし・未割り当て
+	query, _, args = buildIncidentListQuery(IncidentListFilter{Unassigned: true, SortBy: incidentListSortOldest}, 10, 0)
+	if !strings.Contains(query, "handler_id IS NULL") || !strings.Contains(query, "ORDER BY created_at ASC") {
+		t.Errorf("未割り当て・古い順のクエリが間違っています:\n%s", query)
+	}
+	if len(args) != 3 {
+		t.Errorf("引数の数が間違っています: %d", len(args))
+	}
+}
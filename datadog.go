@@ -0,0 +1,101 @@
+package main
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// defaultDatadogPath は Datadog の Webhook を受け付けるパス
+const defaultDatadogPath = "/webhooks/datadog"
+
+// datadogTitlePrefix はモニターのタイトルの先頭に付く状態（例: "[Triggered on {host:web-1}] "）
+var datadogTitlePrefix = regexp.MustCompile(`^(\[[^\]]*\]\s*)+`)
+
+// datadogPrioritySeverities は Datadog のモニターの優先度から重要度への対応
+var datadogPrioritySeverities = map[string]string{
+	"p1": "critical",
+	"p2": "high",
+	"p3": "medium",
+	"p4": "low",
+	"p5": "low",
+}
+
+// datadogAdapter は Datadog の Webhook インテグレーションの alertAdapter
+// 本文は README に記載したテンプレート（$ALERT_ID・$ALERT_TRANSITION などの変数）で送信されることを前提とする
+type datadogAdapter struct{}
+
+func (datadogAdapter) source() string { return "Datadog" }
+
+// datadogTags は "env:prod,service:payments" 形式のタグをラベルに変換（値のないタグは無視する）
+func datadogTags(tags string) map[string]string {
+	labels := make(map[string]string)
+	for _, tag := range strings.Split(tags, ",") {
+		name, value, found := strings.Cut(strings.TrimSpace(tag), ":")
+		if found && name != "" && value != "" {
+			labels[name] = value
+		}
+	}
+	return labels
+}
+
+func (a datadogAdapter) parse(body []byte) ([]Alert, error) {
+	data, err := decodeJSON(body)
+	if err != nil {
+		return nil, err
+	}
+	payload, ok := data.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("本文がオブジェクトではありません")
+	}
+	field := func(name string) string {
+		return strings.TrimSpace(jsonString(payload[name]))
+	}
+
+	alertID := field("alert_id")
+	if alertID == "" {
+		return nil, fmt.Errorf("alert_id がありません")
+	}
+	title := datadogTitlePrefix.ReplaceAllString(field("title"), "")
+
+	labels := datadogTags(field("tags"))
+	labels["alertname"] = title
+	labels["monitor_id"] = alertID
+	if host := field("hostname"); host != "" {
+		labels["host"] = host
+	}
+	if priority := strings.ToLower(field("priority")); priority != "" {
+		labels["priority"] = priority
+		if _, exists := labels[alertSeverityLabel()]; !exists && datadogPrioritySeverities[priority] != "" {
+			labels[alertSeverityLabel()] = datadogPrioritySeverities[priority]
+		}
+	}
+
+	status := alertStatusFiring
+	if strings.EqualFold(field("alert_transition"), "Recovered") || strings.EqualFold(field("alert_type"), "success") {
+		status = alertStatusResolved
+	}
+	startsAt, ok := jsonTime(payload["date"])
+	if !ok {
+		startsAt = time.Now()
+	}
+
+	annotations := map[string]string{"summary": title}
+	if description := field("body"); description != "" {
+		annotations["description"] = description
+	}
+
+	// 同じモニターでも対象（$ALERT_SCOPE）ごとに別のアラートとして扱う
+	fingerprint := alertFingerprint(Alert{Labels: map[string]string{"monitor_id": alertID, "scope": field("alert_scope")}})
+
+	return []Alert{{
+		Source:       a.source(),
+		Status:       status,
+		Fingerprint:  fingerprint,
+		Labels:       labels,
+		Annotations:  annotations,
+		StartsAt:     startsAt,
+		GeneratorURL: field("link"),
+	}}, nil
+}
@@ -0,0 +1,84 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+// datadogTestPayload は README のテンプレートで送信される Datadog の Webhook の本文
+func datadogTestPayload(transition, alertType, tags string) string {
+	return `{
+  "id": "7461924375401",
+  "title": "[` + transition + ` on {host:web-1}] High CPU usage",
+  "body": "CPU 使用率が 90% を超えています",
+  "alert_id": "12345",
+  "alert_transition": "` + transition + `",
+  "alert_type": "` + alertType + `",
+  "alert_scope": "host:web-1",
+  "priority": "P2",
+  "hostname": "web-1",
+  "tags": "` + tags + `",
+  "link": "https://app.datadoghq.com/event/event?id=7461924375401",
+  "date": 1735693200000
+}`
+}
+
+func TestDatadogAdapterParse(t *testing.T) {
+	setAlertsConfig(t, AlertsConfig{})
+
+	alerts, err := datadogAdapter{}.parse([]byte(datadogTestPayload("Triggered", "error", "env:prod,service:payments,team")))
+	if err != nil {
+		t.Fatalf("parse() error = %v", err)
+	}
+	if len(alerts) != 1 {
+		t.Fatalf("アラートの件数 = %d", len(alerts))
+	}
+
+	alert := alerts[0]
+	if alert.Source != "Datadog" || alert.Status != alertStatusFiring {
+		t.Errorf("アラート = %+v", alert)
+	}
+	// タイトルの状態は取り除き、タグ・優先度をラベルにする（値のないタグは無視する）
+	wantLabels := map[string]string{
+		"alertname":  "High CPU usage",
+		"monitor_id": "12345",
+		"host":       "web-1",
+		"env":        "prod",
+		"service":    "payments",
+		"priority":   "p2",
+		"severity":   "high",
+	}
+	if len(alert.Labels) != len(wantLabels) {
+		t.Errorf("ラベル = %+v", alert.Labels)
+	}
+	for name, want := range wantLabels {
+		if alert.Labels[name] != want {
+			t.Errorf("ラベル %s = %q, want %q", name, alert.Labels[name], want)
+		}
+	}
+	if alert.summary() != "High CPU usage" || alert.Annotations["description"] != "CPU 使用率が 90% を超えています" {
+		t.Errorf("アノテーション = %+v", alert.Annotations)
+	}
+	if !alert.StartsAt.Equal(time.Date(2025, 1, 1, 1, 0, 0, 0, time.UTC)) || alert.GeneratorURL == "" {
+		t.Errorf("StartsAt = %v, GeneratorURL = %q", alert.StartsAt, alert.GeneratorURL)
+	}
+
+	// 復旧の通知は同じフィンガープリントの解消済みのアラートになる
+	recovered, err := datadogAdapter{}.parse([]byte(datadogTestPayload("Recovered", "success", "env:prod")))
+	if err != nil {
+		t.Fatalf("parse() error = %v", err)
+	}
+	if recovered[0].Status != alertStatusResolved || recovered[0].Fingerprint != alert.Fingerprint {
+		t.Errorf("復旧のアラート = %+v（発生時のフィンガープリント: %s）", recovered[0], alert.Fingerprint)
+	}
+
+	// タグで重要度を指定した場合は優先度から変換しない
+	tagged, _ := datadogAdapter{}.parse([]byte(datadogTestPayload("Triggered", "error", "severity:page")))
+	if tagged[0].Labels["severity"] != "page" {
+		t.Errorf("タグの重要度を優先するべきです: %+v", tagged[0].Labels)
+	}
+
+	if _, err := (datadogAdapter{}).parse([]byte(`{"title":"no id"}`)); err == nil {
+		t.Error("alert_id がない本文はエラーになるべきです")
+	}
+}
@@ -0,0 +1,228 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/slack-go/slack"
+)
+
+// インシデント詳細表示の制限
+const (
+	incidentHistoryFetchLimit = 50 // 各履歴テーブルから取得する最大件数
+	incidentTimelineLimit     = 30 // タイムラインに表示する最大件数（新しいものを優先）
+	detailTextLimit           = 1000
+	timelineSectionTextLimit  = 2900 // セクションブロックのテキスト上限（3000文字）に収まるように分割
+)
+
+// updateFieldLabels は更新履歴のフィールド名の表示名
+var updateFieldLabels = map[string]string{
+	"title":       "タイトル",
+	"severity":    "重要度",
+	"description": "詳細説明",
+	"impact":      "影響範囲",
+}
+
+// timelineEntry はタイムラインの1件分の履歴
+type timelineEntry struct {
+	At   time.Time
+	Text string
+}
+
+// showIncidentDetail はインシデントの詳細と変更履歴を表示
+func showIncidentDetail(ctx *CommandContext, ephemeral bool) {
+	if store == nil {
+		ctx.reply("⚠️ データベース機能が無効のため、インシデント情報を取得できません。", ephemeral)
+		return
+	}
+
+	incidentID, err := resolveTargetIncident(ctx)
+	if err != nil {
+		ctx.reply(fmt.Sprintf("❌ %v", err), true)
+		return
+	}
+
+	incident, err := getIncidentDetails(incidentID)
+	if err != nil {
+		ctx.reply(fmt.Sprintf("❌ インシデント情報の取得に失敗しました: %v", err), true)
+		return
+	}
+
+	updates, err := getUpdateHistory(incidentID, incidentHistoryFetchLimit)
+	if err != nil {
+		log.Printf("更新履歴取得エラー: %v", err)
+	}
+	handlers, err := getHandlerHistory(incidentID, incidentHistoryFetchLimit)
+	if err != nil {
+		log.Printf("担当者履歴取得エラー: %v", err)
+	}
+	statuses, err := getStatusHistory(incidentID, incidentHistoryFetchLimit)
+	if err != nil {
+		log.Printf("ステータス履歴取得エラー: %v", err)
+	}
+
+	timeline := buildIncidentTimeline(updates, handlers, statuses)
+	blocks := buildIncidentDetailBlocks(incident, timeline, time.Now())
+
+	ctx.replyBlocks(fmt.Sprintf("インシデント #%d の詳細", incidentID), blocks, ephemeral)
+	log.Printf("インシデント %d の詳細を表示しました (履歴: %d件)", incidentID, len(timeline))
+}
+
+// buildIncidentTimeline は更新・担当者・ステータスの各履歴を時系列順（古い順）に統合
+func buildIncidentTimeline(updates []FieldUpdate, handlers []HandlerChange, statuses []StatusChange) []timelineEntry {
+	var timeline []timelineEntry
+
+	for _, update := range updates {
+		label, ok := updateFieldLabels[update.FieldName]
+		if !ok {
+			label = update.FieldName
+		}
+		timeline = append(timeline, timelineEntry{
+			At: update.UpdatedAt,
+			Text: fmt.Sprintf("📝 <@%s> が%sを変更: %s → %s",
+				update.UpdatedBy,
+				label,
+				formatHistoryValue(update.OldValue),
+				formatHistoryValue(update.NewValue),
+			),
+		})
+	}
+
+	for _, change := range handlers {
+		var text string
+		switch {
+		case change.OldHandlerID == "":
+			text = fmt.Sprintf("🙋 <@%s> が担当者を <@%s> に設定", change.AssignedBy, change.NewHandlerID)
+		case change.OldHandlerID == change.NewHandlerID:
+			text = fmt.Sprintf("🙋 <@%s> が担当者 <@%s> を再設定", change.AssignedBy, change.NewHandlerID)
+		default:
+			text = fmt.Sprintf("🔁 <@%s> が担当者を <@%s> から <@%s> に変更", change.AssignedBy, change.OldHandlerID, change.NewHandlerID)
+		}
+		timeline = append(timeline, timelineEntry{At: change.AssignedAt, Text: text})
+	}
+
+	for _, change := range statuses {
+		var text string
+		if change.OldStatus != "" {
+			text = fmt.Sprintf("🔄 <@%s> がステータスを変更: %s → %s", change.ChangedBy, statusLabel(change.OldStatus), statusLabel(change.NewStatus))
+		} else {
+			text = fmt.Sprintf("🚨 <@%s> がインシデントを報告 (%s)", change.ChangedBy, statusLabel(change.NewStatus))
+		}
+		timeline = append(timeline, timelineEntry{At: change.ChangedAt, Text: text})
+	}
+
+	sort.SliceStable(timeline, func(i, j int) bool {
+		return timeline[i].At.Before(timeline[j].At)
+	})
+
+	return timeline
+}
+
+// formatHistoryValue は履歴の値を1行に収まるように整形
+func formatHistoryValue(value string) string {
+	value = strings.Join(strings.Fields(value), " ")
+	if value == "" {
+		return "（なし）"
+	}
+	return "`" + truncateRunes(value, 50) + "`"
+}
+
+// buildIncidentDetailBlocks はインシデント詳細のBlock Kitブロックを構築
+func buildIncidentDetailBlocks(incident *Incident, timeline []timelineEntry, now time.Time) []slack.Block {
+	handler := "未割り当て"
+	if incident.HandlerID != "" {
+		handler = fmt.Sprintf("<@%s>", incident.HandlerID)
+	}
+
+	elapsedLabel := "経過時間"
+	elapsed := now.Sub(incident.CreatedAt)
+	if incident.ResolvedAt != nil && !isActiveStatus(incident.Status) {
+		elapsedLabel = "対応時間"
+		elapsed = incident.ResolvedAt.Sub(incident.CreatedAt)
+	}
+
+	fields := []*slack.TextBlockObject{
+		slack.NewTextBlockObject("mrkdwn", fmt.Sprintf("*重要度:*\n%s %s", severityEmojis[incident.Severity], incident.Severity), false, false),
+		slack.NewTextBlockObject("mrkdwn", fmt.Sprintf("*ステータス:*\n%s", statusLabel(incident.Status)), false, false),
+		slack.NewTextBlockObject("mrkdwn", fmt.Sprintf("*担当者:*\n%s", handler), false, false),
+		slack.NewTextBlockObject("mrkdwn", fmt.Sprintf("*報告者:*\n<@%s>", incident.ReporterID), false, false),
+		slack.NewTextBlockObject("mrkdwn", fmt.Sprintf("*%s:*\n%s", elapsedLabel, formatElapsed(elapsed)), false, false),
+		slack.NewTextBlockObject("mrkdwn", fmt.Sprintf("*チャンネル:*\n<#%s>", incident.ChannelID), false, false),
+		slack.NewTextBlockObject("mrkdwn", fmt.Sprintf("*報告日時:*\n%s", incident.CreatedAt.Format("2006-01-02 15:04:05")), false, false),
+	}
+	if incident.SourcePermalink != "" {
+		fields = append(fields, slack.NewTextBlockObject("mrkdwn", fmt.Sprintf("*報告元:*\n<%s|元のメッセージ>", incident.SourcePermalink), false, false))
+	}
+
+	summary := fmt.Sprintf(
+		"*影響範囲:*\n%s\n\n*詳細:*\n%s",
+		truncateRunes(incident.Impact, detailTextLimit),
+		truncateRunes(incident.Description, detailTextLimit),
+	)
+	if incident.ResolutionNote != "" {
+		summary += fmt.Sprintf("\n\n*復旧メモ:*\n%s", truncateRunes(incident.ResolutionNote, detailTextLimit))
+	}
+
+	blocks := []slack.Block{
+		slack.NewHeaderBlock(slack.NewTextBlockObject("plain_text",
+			truncateRunes(fmt.Sprintf("%s #%d %s", severityEmojis[incident.Severity], incident.ID, incident.Title), 150),
+			true, false,
+		)),
+		slack.NewSectionBlock(nil, fields, nil),
+		slack.NewSectionBlock(slack.NewTextBlockObject("mrkdwn", summary, false, false), nil, nil),
+		slack.NewDividerBlock(),
+		slack.NewSectionBlock(slack.NewTextBlockObject("mrkdwn", "*🕒 タイムライン*", false, false), nil, nil),
+	}
+
+	if len(timeline) == 0 {
+		blocks = append(blocks, slack.NewContextBlock("", slack.NewTextBlockObject("mrkdwn", "履歴はありません", false, false)))
+		return blocks
+	}
+
+	// 新しい履歴を優先して表示し、古い履歴は件数のみ表示
+	if omitted := len(timeline) - incidentTimelineLimit; omitted > 0 {
+		timeline = timeline[omitted:]
+		blocks = append(blocks, slack.NewContextBlock("",
+			slack.NewTextBlockObject("mrkdwn", fmt.Sprintf("（古い履歴 %d 件は省略されています）", omitted), false, false),
+		))
+	}
+
+	var lines []string
+	for _, entry := range timeline {
+		lines = append(lines, fmt.Sprintf("`%s` %s", entry.At.Format("01/02 15:04"), entry.Text))
+	}
+	for _, chunk := range chunkLines(lines, timelineSectionTextLimit) {
+		blocks = append(blocks, slack.NewSectionBlock(slack.NewTextBlockObject("mrkdwn", chunk, false, false), nil, nil))
+	}
+
+	return blocks
+}
+
+// chunkLines は行を改行で連結し、各チャンクが最大文字数を超えないように分割
+func chunkLines(lines []string, max int) []string {
+	var chunks []string
+	var current []string
+	length := 0
+
+	for _, line := range lines {
+		lineLength := len([]rune(line))
+		if len(current) > 0 && length+1+lineLength > max {
+			chunks = append(chunks, strings.Join(current, "\n"))
+			current = nil
+			length = 0
+		}
+		if len(current) > 0 {
+			length++
+		}
+		current = append(current, line)
+		length += lineLength
+	}
+	if len(current) > 0 {
+		chunks = append(chunks, strings.Join(current, "\n"))
+	}
+
+	return chunks
+}
@@ -0,0 +1,173 @@
+package main
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/slack-go/slack"
+)
+
+func TestBuildIncidentTimeline(t *testing.T) {
+	base := time.Date(2025, 1, 1, 10, 0, 0, 0, time.UTC)
+
+	updates := []FieldUpdate{
+		{FieldName: "severity", OldValue: "high", NewValue: "critical", UpdatedBy: "U2", UpdatedByName: "suzuki", UpdatedAt: base.Add(20 * time.Minute)},
+	}
+	handlers := []HandlerChange{
+		{OldHandlerID: "", NewHandlerID: "U2", AssignedBy: "U2", AssignedAt: base.Add(5 * time.Minute)},
+		{OldHandlerID: "U2", NewHandlerID: "U3", AssignedBy: "U3", AssignedAt: base.Add(30 * time.Minute)},
+	}
+	statuses := []StatusChange{
+		{OldStatus: StatusInvestigating, NewStatus: StatusIdentified, ChangedBy: "U3", ChangedAt: base.Add(40 * time.Minute)},
+		{NewStatus: StatusInvestigating, ChangedBy: "U1", ChangedAt: base},
+	}
+
+	timeline := buildIncidentTimeline(updates, handlers, statuses)
+
+	expected := []string{
+		"<@U1> がインシデントを報告",
+		"<@U2> が担当者を <@U2> に設定",
+		"<@U2> が重要度を変更: `high` → `critical`",
+		"<@U3> が担当者を <@U2> から <@U3> に変更",
+		"<@U3> がステータスを変更: 🔍 調査中 → 🎯 原因特定",
+	}
+
+	if len(timeline) != len(expected) {
+		t.Fatalf("タイムラインの件数が間違っています: %d, 期待値: %d", len(timeline), len(expected))
+	}
+	for i, want := range expected {
+		if !strings.Contains(timeline[i].Text, want) {
+			t.Errorf("timeline[%d] = %q, %q を含むべきです", i, timeline[i].Text, want)
+		}
+		if i > 0 && timeline[i].At.Before(timeline[i-1].At) {
+			t.Errorf("タイムラインが時系列順になっていません: %d", i)
+		}
+	}
+}
+
+func TestFormatHistoryValue(t *testing.T) {
+	if got := formatHistoryValue(""); got != "（なし）" {
+		t.Errorf("空の値の表示が間違っています: %s", got)
+	}
+	if got := formatHistoryValue("line1\nline2"); got != "`line1 line2`" {
+		t.Errorf("改行を含む値の表示が間違っています: %s", got)
+	}
+	if got := formatHistoryValue(strings.Repeat("あ", 100)); len([]rune(got)) > 60 {
+		t.Errorf("長い値が切り詰められていません: %d文字", len([]rune(got)))
+	}
+}
+
+func testDetailIncident() *Incident {
+	return &Incident{
+		ID:           42,
+		Title:        "APIエラー率上昇",
+		Severity:     "critical",
+		Description:  "5xxが増加",
+		Impact:       "全ユーザー",
+		Status:       StatusIdentified,
+		ChannelID:    "C123",
+		ReporterID:   "U1",
+		ReporterName: "tanaka",
+		HandlerID:    "U2",
+		CreatedAt:    time.Date(2025, 1, 1, 10, 0, 0, 0, time.UTC),
+	}
+}
+
+func TestBuildIncidentDetailBlocks(t *testing.T) {
+	now := time.Date(2025, 1, 1, 11, 30, 0, 0, time.UTC)
+	timeline := []timelineEntry{
+		{At: time.Date(2025, 1, 1, 10, 0, 0, 0, time.UTC), Text: "🚨 <@U1> がインシデントを報告"},
+	}
+
+	blocks := buildIncidentDetailBlocks(testDetailIncident(), timeline, now)
+
+	header, ok := blocks[0].(*slack.HeaderBlock)
+	if !ok || !strings.Contains(header.Text.Text, "#42 APIエラー率上昇") {
+		t.Fatalf("先頭はインシデントIDとタイトルを含むヘッダーであるべきです: %#v", blocks[0])
+	}
+
+	fields := blocks[1].(*slack.SectionBlock).Fields
+	var fieldText []string
+	for _, field := range fields {
+		fieldText = append(fieldText, field.Text)
+	}
+	joined := strings.Join(fieldText, "\n")
+	for _, want := range []string{"🎯 原因特定", "<@U2>", "*経過時間:*\n1時間30分", "<#C123>"} {
+		if !strings.Contains(joined, want) {
+			t.Errorf("詳細フィールドに %q が含まれていません: %s", want, joined)
+		}
+	}
+
+	last := blocks[len(blocks)-1].(*slack.SectionBlock)
+	if !strings.Contains(last.Text.Text, "`01/01 10:00` 🚨 <@U1> がインシデントを報告") {
+		t.Errorf("タイムラインが表示されていません: %s", last.Text.Text)
+	}
+}
+
+func TestBuildIncidentDetailBlocksResolved(t *testing.T) {
+	incident := testDetailIncident()
+	resolvedAt := time.Date(2025, 1, 1, 10, 45, 0, 0, time.UTC)
+	incident.Status = StatusResolved
+	incident.ResolvedAt = &resolvedAt
+
+	blocks := buildIncidentDetailBlocks(incident, nil, time.Date(2025, 1, 2, 0, 0, 0, 0, time.UTC))
+
+	fields := blocks[1].(*slack.SectionBlock).Fields
+	found := false
+	for _, field := range fields {
+		if field.Text == "*対応時間:*\n45分" {
+			found = true
+		}
+	}
+	if !found {
+		t.Error("復旧済みのインシデントは対応時間を表示するべきです")
+	}
+
+	if _, ok := blocks[len(blocks)-1].(*slack.ContextBlock); !ok {
+		t.Error("履歴がない場合は空の表示になるべきです")
+	}
+}
+
+func TestBuildIncidentDetailBlocksOmitsOldHistory(t *testing.T) {
+	base := time.Date(2025, 1, 1, 10, 0, 0, 0, time.UTC)
+	var timeline []timelineEntry
+	for i := 0; i < incidentTimelineLimit+5; i++ {
+		timeline = append(timeline, timelineEntry{At: base.Add(time.Duration(i) * time.Minute), Text: "更新"})
+	}
+
+	blocks := buildIncidentDetailBlocks(testDetailIncident(), timeline, base)
+
+	var text []string
+	for _, block := range blocks {
+		switch b := block.(type) {
+		case *slack.ContextBlock:
+			text = append(text, b.ContextElements.Elements[0].(*slack.TextBlockObject).Text)
+		case *slack.SectionBlock:
+			if b.Text != nil {
+				text = append(text, b.Text.Text)
+			}
+		}
+	}
+	joined := strings.Join(text, "\n")
+
+	if !strings.Contains(joined, "古い履歴 5 件は省略") {
+		t.Error("省略された履歴の件数が表示されていません")
+	}
+	if strings.Contains(joined, "`01/01 10:04`") || !strings.Contains(joined, "`01/01 10:05`") {
+		t.Error("新しい履歴が優先して表示されるべきです")
+	}
+}
+
+func TestChunkLines(t *testing.T) {
+	lines := []string{"aaaa", "bbbb", "cccc"}
+
+	chunks := chunkLines(lines, 9)
+	if len(chunks) != 2 || chunks[0] != "aaaa\nbbbb" || chunks[1] != "cccc" {
+		t.Errorf("分割結果が間違っています: %q", chunks)
+	}
+
+	if chunks := chunkLines(nil, 10); len(chunks) != 0 {
+		t.Errorf("空の入力は空の結果になるべきです: %q", chunks)
+	}
+}
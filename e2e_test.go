@@ -0,0 +1,388 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/slack-go/slack"
+	"github.com/slack-go/slack/slackevents"
+)
+
+// e2eAnnouncementChannelID はエンドツーエンドテストの全体周知チャンネル
+const e2eAnnouncementChannelID = "CANNOUNCE"
+
+// setupE2E は偽の Slack サーバーとメモリ上の保存先でBotを動かす準備をする（テスト終了時に元に戻す）
+func setupE2E(t *testing.T) (*fakeSlack, SlackAPI) {
+	t.Helper()
+
+	// タイムキーパーのゴルーチンが設定を読むため、設定は全体周知チャンネルの項目だけを差し替える
+	originalStore, originalDB, originalChannels := store, db, config.Channels
+	originalTimekeeper, originalEvents, originalViewers := timekeeperManager, processedEvents, homeViewers
+	t.Cleanup(func() {
+		timekeeperManager.haltAll()
+		store, db, config.Channels = originalStore, originalDB, originalChannels
+		timekeeperManager, processedEvents, homeViewers = originalTimekeeper, originalEvents, originalViewers
+	})
+
+	memory, err := newMemoryStore("")
+	if err != nil {
+		t.Fatalf("newMemoryStore() error = %v", err)
+	}
+	store, db = memory, nil
+	config.Channels = ChannelsConfig{
+		EnableAnnouncement:   true,
+		AnnouncementChannels: []string{e2eAnnouncementChannelID},
+	}
+	timekeeperManager = &TimekeeperManager{
+		timekeepers: make(map[int64]chan bool),
+		entries:     make(map[int64]*timekeeperEntry),
+		stopReasons: make(map[chan bool]string),
+	}
+	processedEvents = &processedEventCache{expiresAt: make(map[string]time.Time)}
+	homeViewers = &HomeViewers{users: make(map[string]time.Time)}
+
+	fake := newFakeSlack(t)
+	fake.addChannel("CGENERAL", "general")
+	fake.addChannel(e2eAnnouncementChannelID, "announcements")
+	fake.addUser("U001", "reporter", "報告 太郎")
+	fake.addUser("U002", "handler", "担当 花子")
+	return fake, fake.client()
+}
+
+// mentionEvent はBotへのメンションのイベントを作成
+func mentionEvent(channelID, userID, text string) slackevents.EventsAPIEvent {
+	return slackevents.EventsAPIEvent{
+		Type: slackevents.CallbackEvent,
+		InnerEvent: slackevents.EventsAPIInnerEvent{
+			Type: string(slackevents.AppMention),
+			Data: &slackevents.AppMentionEvent{Channel: channelID, User: userID, Text: text},
+		},
+	}
+}
+
+// buttonClick はボタンクリックのインタラクションを作成
+func buttonClick(actionID, value, channelID, userID string) slack.InteractionCallback {
+	var callback slack.InteractionCallback
+	callback.Type = slack.InteractionTypeBlockActions
+	callback.TriggerID = "trigger-" + actionID
+	callback.Channel.ID = channelID
+	callback.User.ID = userID
+	callback.User.Name = userID
+	callback.ActionCallback.BlockActions = []*slack.BlockAction{{ActionID: actionID, Value: value}}
+	return callback
+}
+
+// incidentReportSubmission はインシデント報告モーダルの送信を作成
+func incidentReportSubmission(viewID, privateMetadata, userID, title, severity string) slack.InteractionCallback {
+	var callback slack.InteractionCallback
+	callback.Type = slack.InteractionTypeViewSubmission
+	callback.User.ID = userID
+	callback.User.Name = userID
+	callback.View.ID = viewID
+	callback.View.CallbackID = "incident_report_modal"
+	callback.View.PrivateMetadata = privateMetadata
+	callback.View.State = &slack.ViewState{Values: map[string]map[string]slack.BlockAction{
+		"title_block":       {"incident_title": {Value: title}},
+		"severity_block":    {"incident_severity": {SelectedOption: slack.OptionBlockObject{Value: severity}}},
+		"description_block": {"incident_description": {Value: "決済APIが500エラーを返している"}},
+		"impact_block":      {"incident_impact": {Value: "全ユーザー"}},
+	}}
+	return callback
+}
+
+// openedModal は views.open で開いたモーダルのコールバックIDと PrivateMetadata を取得
+func openedModal(t *testing.T, call fakeSlackCall) (string, string) {
+	t.Helper()
+
+	var view struct {
+		CallbackID      string `json:"callback_id"`
+		PrivateMetadata string `json:"private_metadata"`
+	}
+	if err := json.Unmarshal([]byte(call.Params.Get("view")), &view); err != nil {
+		t.Fatalf("モーダルの解析エラー: %v", err)
+	}
+	return view.CallbackID, view.PrivateMetadata
+}
+
+func TestE2EIncidentLifecycle(t *testing.T) {
+	fake, api := setupE2E(t)
+
+	// メンションすると報告ボタンが表示される
+	handleEventsAPIEvent(api, mentionEvent("CGENERAL", "U001", "<@UBOT>"))
+	if !fake.hasMessage("CGENERAL", "open_incident_modal") {
+		t.Fatal("メンションしたチャンネルにインシデント報告ボタンが投稿されるべきです")
+	}
+
+	// ボタンをクリックすると報告モーダルが開く
+	handleInteraction(api, buttonClick("open_incident_modal", "open_modal", "CGENERAL", "U001"))
+	opened := fake.callsTo("views.open")
+	if len(opened) != 1 || opened[0].Params.Get("trigger_id") != "trigger-open_incident_modal" {
+		t.Fatalf("views.open の呼び出し = %+v", opened)
+	}
+	callbackID, metadata := openedModal(t, opened[0])
+	if callbackID != "incident_report_modal" {
+		t.Errorf("モーダルのコールバックID = %s", callbackID)
+	}
+	if !fake.hasMessage("CGENERAL", "入力中です") {
+		t.Error("入力中メッセージが投稿されるべきです")
+	}
+
+	// モーダルを送信するとインシデントチャンネルが作成され、報告が投稿される
+	submission := incidentReportSubmission("V100", metadata, "U001", "決済APIの障害", "critical")
+	handleInteraction(api, submission)
+
+	channelName := "incident-" + time.Now().Format("20060102")
+	incidentChannelID, created := fake.channelByName(channelName)
+	if !created {
+		t.Fatalf("チャンネル %s が作成されるべきです: %+v", channelName, fake.callsTo("conversations.create"))
+	}
+	if invites := fake.callsTo("conversations.invite"); len(invites) != 1 || invites[0].Params.Get("users") != "U001" {
+		t.Errorf("報告者が招待されるべきです: %+v", invites)
+	}
+	if topics := fake.callsTo("conversations.setTopic"); len(topics) != 1 || !topics[0].contains("決済APIの障害") {
+		t.Errorf("トピックにタイトルが設定されるべきです: %+v", topics)
+	}
+	if !fake.hasMessage("CGENERAL", "インシデントが報告されました") {
+		t.Error("報告したチャンネルに報告が投稿されるべきです")
+	}
+	if !fake.hasMessage(incidentChannelID, "決済APIの障害") || !fake.hasMessage(incidentChannelID, "assign_handler") {
+		t.Error("インシデントチャンネルに報告と担当者ボタンが投稿されるべきです")
+	}
+	if !fake.hasMessage(e2eAnnouncementChannelID, "決済APIの障害") || !fake.hasMessage(e2eAnnouncementChannelID, "<#"+incidentChannelID+">") {
+		t.Error("全体周知チャンネルに報告とインシデントチャンネルへのリンクが投稿されるべきです")
+	}
+
+	incident, err := store.FindActiveIncidentByChannel(incidentChannelID)
+	if err != nil || incident == nil {
+		t.Fatalf("インシデントが保存されるべきです: %v", err)
+	}
+	if incident.Title != "決済APIの障害" || incident.Severity != "critical" || incident.ReporterName != "報告 太郎" {
+		t.Errorf("保存されたインシデント = %+v", incident)
+	}
+	if state := timekeeperManager.currentState(incident.ID); state != timekeeperStateRunning {
+		t.Error("タイムキーパーが開始されるべきです")
+	}
+
+	// 同じモーダルの送信が再配信されてもインシデントは作成されない
+	handleInteraction(api, submission)
+	if creates := fake.callsTo("conversations.create"); len(creates) != 1 {
+		t.Errorf("チャンネルの作成回数 = %d, want 1", len(creates))
+	}
+
+	// 担当者になる
+	incidentValue := fmt.Sprintf("incident_%d", incident.ID)
+	handleInteraction(api, buttonClick("assign_handler", incidentValue, incidentChannelID, "U002"))
+	if !fake.hasMessage(incidentChannelID, "<@U002> さんがこのインシデントの担当者になりました") {
+		t.Error("担当者の割り当てが投稿されるべきです")
+	}
+
+	// 復旧する
+	fake.postUserMessage(incidentChannelID, "U002", "DBを再起動しました")
+	handleInteraction(api, buttonClick("resolve_incident", incidentValue, incidentChannelID, "U002"))
+
+	resolved, err := store.GetIncident(incident.ID)
+	if err != nil {
+		t.Fatalf("GetIncident() error = %v", err)
+	}
+	if resolved.Status != StatusResolved || resolved.HandlerID != "U002" {
+		t.Errorf("復旧後のインシデント = %+v", resolved)
+	}
+	if !fake.hasMessage(incidentChannelID, "インシデントが復旧しました") || !fake.hasMessage(incidentChannelID, "<@U002>") {
+		t.Error("インシデントチャンネルに対応メンバー付きの復旧が投稿されるべきです")
+	}
+	if !fake.hasMessage(e2eAnnouncementChannelID, "インシデントが復旧しました") {
+		t.Error("全体周知チャンネルに復旧が投稿されるべきです")
+	}
+	if !fake.hasMessage(incidentChannelID, statusActionID(StatusPostmortem)) {
+		t.Error("復旧後はポストモーテムへの変更ボタンが表示されるべきです")
+	}
+	if state := timekeeperManager.currentState(incident.ID); state == timekeeperStateRunning {
+		t.Error("復旧したらタイムキーパーは停止するべきです")
+	}
+}
+
+func TestE2EIncidentChannelNameTaken(t *testing.T) {
+	fake, api := setupE2E(t)
+
+	// 同じ日のチャンネルが既にある場合はランダムなサフィックスを付けて作成する
+	baseName := "incident-" + time.Now().Format("20060102")
+	fake.takeChannelName(baseName)
+
+	handleInteraction(api, incidentReportSubmission("V200", "CGENERAL", "U001", "ログインできない", "high"))
+
+	creates := fake.callsTo("conversations.create")
+	if len(creates) != 2 {
+		t.Fatalf("conversations.create の呼び出し回数 = %d, want 2", len(creates))
+	}
+	name := creates[1].Params.Get("name")
+	if !strings.HasPrefix(name, baseName+"-") || len(name) != len(baseName)+7 {
+		t.Errorf("再試行したチャンネル名 = %s", name)
+	}
+
+	channelID, _ := fake.channelByName(name)
+	if incident, _ := store.FindActiveIncidentByChannel(channelID); incident == nil || incident.ChannelName != name {
+		t.Errorf("作成したチャンネルにインシデントが保存されるべきです: %+v", incident)
+	}
+}
+
+func TestE2EIncidentNotSaved(t *testing.T) {
+	fake, api := setupE2E(t)
+
+	// 保存に失敗した場合はIDを使う操作やタイムキーパーを始めずに、報告者に知らせる
+	store = &flakyStore{memoryStore: store.(*memoryStore), down: true}
+
+	handleInteraction(api, incidentReportSubmission("V250", "CGENERAL", "U001", "保存できない障害", "high"))
+
+	channelID, ok := fake.channelByName("incident-" + time.Now().Format("20060102"))
+	if !ok {
+		t.Fatal("保存に失敗してもインシデントチャンネルは作成されるべきです")
+	}
+	if !fake.hasMessage(channelID, "<@U001> ⚠️ インシデントを保存できませんでした") {
+		t.Error("報告者に保存できなかったことを知らせるべきです")
+	}
+	if fake.hasMessage(channelID, "assign_handler") {
+		t.Error("保存できなかったインシデントに担当者ボタンを投稿するべきではありません")
+	}
+	if timekeeperManager.isTimekeeperRunning(0) || len(timekeeperManager.entries) != 0 {
+		t.Error("保存できなかったインシデントのタイムキーパーを開始するべきではありません")
+	}
+}
+
+func TestE2ENotInChannelFallsBackToDM(t *testing.T) {
+	fake, api := setupE2E(t)
+
+	// Botが参加していないチャンネルから報告された場合は報告者のDMに投稿する
+	fake.addChannel("CPRIVATE", "secret")
+	fake.notInChannel["CPRIVATE"] = true
+
+	handleInteraction(api, incidentReportSubmission("V300", "CPRIVATE", "U001", "社内ツールの障害", "low"))
+
+	if !fake.hasMessage("U001", "社内ツールの障害") {
+		t.Error("報告者のDMに報告が投稿されるべきです")
+	}
+	if creates := fake.callsTo("conversations.create"); len(creates) != 1 {
+		t.Errorf("DMに投稿した場合もインシデントチャンネルは作成されるべきです: %+v", creates)
+	}
+}
+
+func TestE2ESlashCommandStatus(t *testing.T) {
+	fake, api := setupE2E(t)
+
+	handleInteraction(api, incidentReportSubmission("V400", "CGENERAL", "U001", "検索が遅い", "medium"))
+	creates := fake.callsTo("conversations.create")
+	if len(creates) != 1 {
+		t.Fatalf("conversations.create の呼び出し回数 = %d, want 1", len(creates))
+	}
+	channelID, _ := fake.channelByName(creates[0].Params.Get("name"))
+	fake.reset()
+
+	// スラッシュコマンドの応答は response_url に送信される
+	handleSlashCommand(api, slack.SlashCommand{
+		Command:     "/incident",
+		Text:        "status",
+		ChannelID:   channelID,
+		UserID:      "U001",
+		ResponseURL: fake.responseURL(),
+	})
+
+	responses := fake.callsTo("response_url")
+	if len(responses) != 1 || !responses[0].contains("検索が遅い") {
+		t.Errorf("response_url への応答 = %+v", responses)
+	}
+	if posts := fake.callsTo("chat.postMessage"); len(posts) != 0 {
+		t.Errorf("スラッシュコマンドの応答はチャンネルに投稿しないべきです: %+v", posts)
+	}
+}
+
+func TestE2EDegradedModeBackfill(t *testing.T) {
+	fake, api := setupE2E(t)
+	resetJournalIncidentIDs(t)
+	originalHealth := dbHealth
+	dbHealth = &databaseHealth{}
+	t.Cleanup(func() { dbHealth = originalHealth })
+
+	// データベースに接続できないため、インシデントはジャーナルに保存する
+	journal, err := openIncidentJournal(filepath.Join(t.TempDir(), "journal.json"))
+	if err != nil {
+		t.Fatalf("openIncidentJournal() error = %v", err)
+	}
+	store = journal
+	dbHealth.markDegraded(time.Now())
+
+	handleInteraction(api, buttonClick("open_incident_modal", "open_modal", "CGENERAL", "U001"))
+	_, metadata := openedModal(t, fake.callsTo("views.open")[0])
+	handleInteraction(api, incidentReportSubmission("V200", metadata, "U001", "決済APIの障害", "critical"))
+
+	incidentChannelID, created := fake.channelByName("incident-" + time.Now().Format("20060102"))
+	if !created {
+		t.Fatal("縮退運転中もインシデントチャンネルが作成されるべきです")
+	}
+	incident, err := store.FindActiveIncidentByChannel(incidentChannelID)
+	if err != nil || incident == nil || incident.ID >= 0 {
+		t.Fatalf("インシデントは負の仮IDでジャーナルに保存されるべきです: %+v, %v", incident, err)
+	}
+	journalValue := fmt.Sprintf("incident_%d", incident.ID)
+	if !fake.hasMessage(incidentChannelID, journalValue) {
+		t.Error("縮退運転中も担当者ボタンが投稿されるべきです")
+	}
+	if !fake.hasMessage(incidentChannelID, "縮退運転中") {
+		t.Error("インシデントチャンネルに縮退運転のお知らせが投稿されるべきです")
+	}
+
+	// データベースの復旧後にジャーナルのインシデントを登録する
+	target, _ := newMemoryStore("")
+	imported, err := backfillIncidentJournal(&testJournalImporter{target: target}, journal)
+	if err != nil || len(imported) != 1 {
+		t.Fatalf("backfillIncidentJournal() = %+v, %v", imported, err)
+	}
+	store = target
+	dbHealth.markHealthy()
+
+	// 縮退運転中に投稿したボタンは登録後のインシデントを操作する
+	handleInteraction(api, buttonClick("assign_handler", journalValue, incidentChannelID, "U002"))
+	registered, err := store.GetIncident(imported[0].IncidentID)
+	if err != nil {
+		t.Fatalf("GetIncident() error = %v", err)
+	}
+	if registered.HandlerID != "U002" {
+		t.Errorf("仮IDのボタンで登録後のインシデントの担当者が設定されるべきです: %+v", registered)
+	}
+}
+
+func TestE2EMentionWithoutCommand(t *testing.T) {
+	fake, api := setupE2E(t)
+
+	// コマンドでない単語で始まるメンションには報告ボタンを表示する
+	handleEventsAPIEvent(api, mentionEvent("CGENERAL", "U001", "<@UBOT> blacklist is broken"))
+	if !fake.hasMessage("CGENERAL", "open_incident_modal") {
+		t.Errorf("報告ボタンが投稿されるべきです: %+v", fake.messagesTo("CGENERAL"))
+	}
+	if ephemerals := fake.callsTo("chat.postEphemeral"); len(ephemerals) != 0 {
+		t.Errorf("不明なコマンドとして応答しないべきです: %+v", ephemerals)
+	}
+
+	// インシデントチャンネルでは操作ボタンを表示する
+	fake.addChannel("CINC", "incident-1")
+	if _, err := saveIncident("決済APIの障害", "high", "説明", "決済", "CINC", "incident-1", "U001", "報告 太郎", ""); err != nil {
+		t.Fatal(err)
+	}
+	handleEventsAPIEvent(api, mentionEvent("CINC", "U001", "<@UBOT> 誰か見てもらえますか"))
+	if !fake.hasMessage("CINC", "担当者になる") || !fake.hasMessage("CINC", "詳細を更新") {
+		t.Errorf("操作ボタンが投稿されるべきです: %+v", fake.messagesTo("CINC"))
+	}
+
+	// コマンドの打ち間違いには近いコマンドを案内する
+	fake.reset()
+	handleEventsAPIEvent(api, mentionEvent("CGENERAL", "U001", "<@UBOT> hepl"))
+	ephemerals := fake.callsTo("chat.postEphemeral")
+	if len(ephemerals) != 1 || !ephemerals[0].contains("不明なコマンドです") || !ephemerals[0].contains("@bot help") {
+		t.Errorf("打ち間違いへの応答 = %+v", ephemerals)
+	}
+	if fake.hasMessage("CGENERAL", "open_incident_modal") {
+		t.Error("打ち間違いの場合は報告ボタンを表示しないべきです")
+	}
+}
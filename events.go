@@ -0,0 +1,76 @@
+package main
+
+import (
+	"github.com/slack-go/slack"
+	"github.com/slack-go/slack/slackevents"
+)
+
+// handleEventsAPIEvent は Events API のイベントをイベントタイプに応じたハンドラーに振り分け
+func handleEventsAPIEvent(api SlackAPI, eventsAPIEvent slackevents.EventsAPIEvent) {
+	switch eventsAPIEvent.Type {
+	case slackevents.CallbackEvent:
+		innerEvent := eventsAPIEvent.InnerEvent
+		switch ev := innerEvent.Data.(type) {
+		case *slackevents.AppMentionEvent:
+			// メンション受信時の処理
+			handleAppMention(api, ev)
+		case *slackevents.ChannelArchiveEvent:
+			// チャンネルアーカイブ時の処理
+			handleChannelArchive(api, ev)
+		case *slackevents.AppHomeOpenedEvent:
+			// App Homeを開いた時の処理
+			handleAppHomeOpened(api, ev)
+		}
+	}
+}
+
+// handleInteraction はボタンクリック・メッセージショートカット・モーダル送信をハンドラーに振り分け
+func handleInteraction(api SlackAPI, callback slack.InteractionCallback) {
+	switch callback.Type {
+	case slack.InteractionTypeBlockActions:
+		// ボタンクリック時の処理
+		if len(callback.ActionCallback.BlockActions) > 0 {
+			action := callback.ActionCallback.BlockActions[0]
+			switch action.ActionID {
+			case "open_incident_modal":
+				handleOpenModal(api, callback)
+			case "assign_handler":
+				handleAssignHandler(api, callback)
+			case "update_incident":
+				handleUpdateIncident(api, callback)
+			case "resolve_incident":
+				handleResolveIncident(api, callback)
+			case "stop_timekeeper":
+				handleStopTimekeeper(api, callback)
+			case "pause_timekeeper":
+				handlePauseTimekeeper(api, callback)
+			case "resume_timekeeper":
+				handleResumeTimekeeper(api, callback)
+			case "generate_postmortem":
+				handleGeneratePostmortem(api, callback)
+			case "add_action_item":
+				handleOpenActionItemModal(api, callback)
+			case "incident_list_prev", "incident_list_next":
+				handleIncidentListPage(api, callback)
+			default:
+				if _, ok := parseStatusActionID(action.ActionID); ok {
+					handleChangeStatus(api, callback)
+				}
+			}
+		}
+	case slack.InteractionTypeMessageAction:
+		// メッセージショートカット
+		if callback.CallbackID == reportMessageShortcutID {
+			handleReportMessageShortcut(api, callback)
+		}
+	case slack.InteractionTypeViewSubmission:
+		// モーダル送信時の処理
+		if callback.View.CallbackID == "incident_report_modal" {
+			handleModalSubmission(api, callback)
+		} else if callback.View.CallbackID == "incident_update_modal" {
+			handleUpdateModalSubmission(api, callback)
+		} else if callback.View.CallbackID == actionItemModalCallbackID {
+			handleActionItemModalSubmission(api, callback)
+		}
+	}
+}
@@ -0,0 +1,330 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/slack-go/slack"
+)
+
+// 偽の Slack サーバーでのBotのユーザーIDとBot ID
+const (
+	fakeSlackBotUserID = "UBOT"
+	fakeSlackBotID     = "BBOT"
+)
+
+// fakeSlackCall は偽の Slack サーバーが受け取った Web API の呼び出し
+type fakeSlackCall struct {
+	Method string     // 例: "chat.postMessage"
+	Params url.Values // フォームの値（JSONで送信された場合はトップレベルの項目）
+}
+
+// contains は呼び出しのいずれかの値に substr が含まれるかを判定（ブロック・添付ファイルのJSONも対象）
+func (c fakeSlackCall) contains(substr string) bool {
+	for _, values := range c.Params {
+		for _, value := range values {
+			if strings.Contains(value, substr) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// fakeSlackMessage は会話履歴として返すメッセージ
+type fakeSlackMessage struct {
+	User  string `json:"user,omitempty"`
+	BotID string `json:"bot_id,omitempty"`
+	Text  string `json:"text"`
+	TS    string `json:"ts"`
+}
+
+// fakeSlack は Slack Web API の偽サーバー
+// 呼び出しを記録し、チャンネル作成・メッセージ投稿・モーダル表示などに Slack と同じ形式で応答する
+type fakeSlack struct {
+	t      *testing.T
+	server *httptest.Server
+
+	mu           sync.Mutex
+	seq          int
+	calls        []fakeSlackCall
+	channels     map[string]string // チャンネルID -> チャンネル名
+	users        map[string]slack.User
+	takenNames   map[string]bool // 作成済みとして name_taken を返すチャンネル名
+	notInChannel map[string]bool // Botが参加しておらず not_in_channel を返すチャンネル
+	history      map[string][]fakeSlackMessage
+}
+
+// newFakeSlack は偽の Slack サーバーを起動（テスト終了時に停止）
+func newFakeSlack(t *testing.T) *fakeSlack {
+	t.Helper()
+
+	f := &fakeSlack{
+		t:            t,
+		channels:     make(map[string]string),
+		users:        make(map[string]slack.User),
+		takenNames:   make(map[string]bool),
+		notInChannel: make(map[string]bool),
+		history:      make(map[string][]fakeSlackMessage),
+	}
+	f.server = httptest.NewServer(http.HandlerFunc(f.serveHTTP))
+	t.Cleanup(f.server.Close)
+	return f
+}
+
+// client は偽の Slack サーバーに接続する Slack クライアントを作成
+func (f *fakeSlack) client() *slack.Client {
+	return slack.New("xoxb-test", slack.OptionAPIURL(f.server.URL+"/api/"))
+}
+
+// responseURL はスラッシュコマンドの応答先（response_url）のURLを返す
+func (f *fakeSlack) responseURL() string {
+	return f.server.URL + "/response"
+}
+
+// addChannel はチャンネルを登録
+func (f *fakeSlack) addChannel(id, name string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.channels[id] = name
+}
+
+// addUser はユーザーを登録
+func (f *fakeSlack) addUser(id, name, realName string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.users[id] = slack.User{ID: id, Name: name, RealName: realName, Profile: slack.UserProfile{RealName: realName}}
+}
+
+// takeChannelName はチャンネル名を作成済みにする
+func (f *fakeSlack) takeChannelName(name string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.takenNames[name] = true
+}
+
+// postUserMessage はユーザーの発言を会話履歴に追加
+func (f *fakeSlack) postUserMessage(channelID, userID, text string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.history[channelID] = append(f.history[channelID], fakeSlackMessage{User: userID, Text: text, TS: f.nextTS()})
+}
+
+// channelByName はチャンネル名からチャンネルIDを取得
+func (f *fakeSlack) channelByName(name string) (string, bool) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for id, channelName := range f.channels {
+		if channelName == name {
+			return id, true
+		}
+	}
+	return "", false
+}
+
+// callsTo は指定したメソッドの呼び出しを古い順に返す
+func (f *fakeSlack) callsTo(method string) []fakeSlackCall {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	var result []fakeSlackCall
+	for _, call := range f.calls {
+		if call.Method == method {
+			result = append(result, call)
+		}
+	}
+	return result
+}
+
+// messagesTo はチャンネルに投稿されたメッセージ（chat.postMessage）を古い順に返す
+func (f *fakeSlack) messagesTo(channelID string) []fakeSlackCall {
+	var result []fakeSlackCall
+	for _, call := range f.callsTo("chat.postMessage") {
+		if call.Params.Get("channel") == channelID {
+			result = append(result, call)
+		}
+	}
+	return result
+}
+
+// hasMessage はチャンネルに substr を含むメッセージが投稿されたかを判定
+func (f *fakeSlack) hasMessage(channelID, substr string) bool {
+	for _, call := range f.messagesTo(channelID) {
+		if call.contains(substr) {
+			return true
+		}
+	}
+	return false
+}
+
+// reset は記録した呼び出しを消去
+func (f *fakeSlack) reset() {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.calls = nil
+}
+
+// nextTS はメッセージのタイムスタンプを採番（呼び出し元でロックを取得しておくこと）
+func (f *fakeSlack) nextTS() string {
+	f.seq++
+	return fmt.Sprintf("1700000000.%06d", f.seq)
+}
+
+// nextID はチャンネル・モーダルなどのIDを採番（呼び出し元でロックを取得しておくこと）
+func (f *fakeSlack) nextID(prefix string) string {
+	f.seq++
+	return fmt.Sprintf("%s%06d", prefix, f.seq)
+}
+
+// parseParams はフォームまたはJSONで送信されたパラメーターを取得
+func parseParams(r *http.Request) (url.Values, error) {
+	if strings.HasPrefix(r.Header.Get("Content-Type"), "application/json") {
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			return nil, err
+		}
+		var fields map[string]json.RawMessage
+		if err := json.Unmarshal(body, &fields); err != nil {
+			return nil, err
+		}
+
+		params := url.Values{}
+		for key, raw := range fields {
+			var s string
+			if err := json.Unmarshal(raw, &s); err == nil {
+				params.Set(key, s)
+			} else {
+				params.Set(key, string(raw))
+			}
+		}
+		return params, nil
+	}
+
+	if err := r.ParseForm(); err != nil {
+		return nil, err
+	}
+	return r.Form, nil
+}
+
+// serveHTTP は Web API の呼び出しを記録して応答
+func (f *fakeSlack) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	params, err := parseParams(r)
+	if err != nil {
+		f.t.Errorf("偽の Slack サーバー: リクエスト解析エラー: %v", err)
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	var method string
+	switch {
+	case strings.HasPrefix(r.URL.Path, "/api/"):
+		method = strings.TrimPrefix(r.URL.Path, "/api/")
+	case r.URL.Path == "/response":
+		method = "response_url"
+	case r.URL.Path == "/upload":
+		method = "file_upload"
+	default:
+		http.NotFound(w, r)
+		return
+	}
+
+	f.mu.Lock()
+	f.calls = append(f.calls, fakeSlackCall{Method: method, Params: params})
+	response := f.respond(method, params)
+	f.mu.Unlock()
+
+	if method == "response_url" || method == "file_upload" {
+		w.Write([]byte("ok"))
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+// respond はメソッドごとの応答を作成（呼び出し元でロックを取得しておくこと）
+func (f *fakeSlack) respond(method string, params url.Values) map[string]interface{} {
+	ok := func(fields map[string]interface{}) map[string]interface{} {
+		fields["ok"] = true
+		return fields
+	}
+	fail := func(code string) map[string]interface{} {
+		return map[string]interface{}{"ok": false, "error": code}
+	}
+	channel := func(id string) map[string]interface{} {
+		return map[string]interface{}{"id": id, "name": f.channels[id]}
+	}
+
+	switch method {
+	case "auth.test":
+		return ok(map[string]interface{}{"user_id": fakeSlackBotUserID, "bot_id": fakeSlackBotID})
+
+	case "chat.postMessage":
+		channelID := params.Get("channel")
+		if f.notInChannel[channelID] {
+			return fail("not_in_channel")
+		}
+		ts := f.nextTS()
+		f.history[channelID] = append(f.history[channelID], fakeSlackMessage{BotID: fakeSlackBotID, Text: params.Get("text"), TS: ts})
+		return ok(map[string]interface{}{"channel": channelID, "ts": ts})
+	case "chat.postEphemeral":
+		return ok(map[string]interface{}{"message_ts": f.nextTS()})
+	case "chat.update":
+		return ok(map[string]interface{}{"channel": params.Get("channel"), "ts": params.Get("ts"), "text": params.Get("text")})
+	case "chat.getPermalink":
+		ts := strings.ReplaceAll(params.Get("message_ts"), ".", "")
+		return ok(map[string]interface{}{"channel": params.Get("channel"), "permalink": "https://example.slack.com/archives/" + params.Get("channel") + "/p" + ts})
+	case "pins.add", "pins.remove":
+		return ok(map[string]interface{}{})
+
+	case "conversations.create":
+		name := params.Get("name")
+		if f.takenNames[name] {
+			return fail("name_taken")
+		}
+		id := f.nextID("C")
+		f.channels[id] = name
+		f.takenNames[name] = true
+		return ok(map[string]interface{}{"channel": channel(id)})
+	case "conversations.info":
+		id := params.Get("channel")
+		if _, exists := f.channels[id]; !exists {
+			return fail("channel_not_found")
+		}
+		return ok(map[string]interface{}{"channel": channel(id)})
+	case "conversations.history":
+		return ok(map[string]interface{}{"messages": f.history[params.Get("channel")]})
+	case "conversations.invite", "conversations.setTopic":
+		return ok(map[string]interface{}{"channel": channel(params.Get("channel"))})
+
+	case "views.open", "views.publish", "views.update":
+		return ok(map[string]interface{}{"view": map[string]interface{}{"id": f.nextID("V")}})
+
+	case "users.info":
+		id := params.Get("user")
+		user, exists := f.users[id]
+		if !exists {
+			user = slack.User{ID: id, Name: id}
+		}
+		return ok(map[string]interface{}{"user": user})
+
+	case "files.getUploadURLExternal":
+		return ok(map[string]interface{}{"upload_url": f.server.URL + "/upload", "file_id": f.nextID("F")})
+	case "files.completeUploadExternal":
+		var files []map[string]interface{}
+		json.Unmarshal([]byte(params.Get("files")), &files)
+		return ok(map[string]interface{}{"files": files})
+
+	case "response_url", "file_upload":
+		return nil
+	}
+
+	f.t.Errorf("偽の Slack サーバー: 未対応のメソッドです: %s", method)
+	return fail("unknown_method")
+}
@@ -0,0 +1,60 @@
+package main
+
+import (
+	"encoding/json"
+)
+
+// defaultGrafanaPath は Grafana の Webhook を受け付けるパス
+const defaultGrafanaPath = "/webhooks/grafana"
+
+// grafanaPayload は Grafana Alerting（unified alerting）の Webhook 連絡先の本文
+// Alertmanager の本文に Grafana 独自の項目を加えたもの
+type grafanaPayload struct {
+	Receiver          string            `json:"receiver"`
+	Status            string            `json:"status"`
+	OrgID             int64             `json:"orgId"`
+	Title             string            `json:"title"`
+	CommonLabels      map[string]string `json:"commonLabels"`
+	CommonAnnotations map[string]string `json:"commonAnnotations"`
+	ExternalURL       string            `json:"externalURL"`
+	Alerts            []grafanaAlert    `json:"alerts"`
+}
+
+// grafanaAlert は Grafana の Webhook に含まれるアラート1件
+type grafanaAlert struct {
+	alertmanagerAlert
+	DashboardURL string `json:"dashboardURL"`
+	PanelURL     string `json:"panelURL"`
+	SilenceURL   string `json:"silenceURL"`
+	ValueString  string `json:"valueString"`
+}
+
+// grafanaAdapter は Grafana Alerting の Webhook の alertAdapter
+type grafanaAdapter struct{}
+
+func (grafanaAdapter) source() string { return "Grafana" }
+
+// parse は Alertmanager と同じくグループ共通のアノテーションで補い、リンクはパネル → アラートルールの順に使用する
+// 説明がない場合は評価した値（valueString）を説明にする
+func (a grafanaAdapter) parse(body []byte) ([]Alert, error) {
+	var payload grafanaPayload
+	if err := json.Unmarshal(body, &payload); err != nil {
+		return nil, err
+	}
+
+	common := alertmanagerPayload{CommonAnnotations: payload.CommonAnnotations}
+	for _, alert := range payload.Alerts {
+		common.Alerts = append(common.Alerts, alert.alertmanagerAlert)
+	}
+	alerts := common.alerts(a.source())
+
+	for i, alert := range payload.Alerts {
+		if alert.PanelURL != "" {
+			alerts[i].GeneratorURL = alert.PanelURL
+		}
+		if alerts[i].Annotations["description"] == "" && alert.ValueString != "" {
+			alerts[i].Annotations["description"] = "評価した値: " + alert.ValueString
+		}
+	}
+	return alerts, nil
+}
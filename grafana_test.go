@@ -0,0 +1,70 @@
+package main
+
+import (
+	"testing"
+)
+
+// grafanaTestPayload は Grafana Alerting の Webhook 連絡先の本文（発生中1件・解消済み1件）
+const grafanaTestPayload = `{
+  "receiver": "incident-bot",
+  "status": "firing",
+  "orgId": 1,
+  "title": "[FIRING:1] HighLatency",
+  "commonLabels": {"alertname": "HighLatency"},
+  "commonAnnotations": {"summary": "API のレイテンシが悪化"},
+  "externalURL": "http://grafana:3000/",
+  "alerts": [
+    {
+      "status": "firing",
+      "labels": {"alertname": "HighLatency", "service": "api", "severity": "critical"},
+      "annotations": {},
+      "startsAt": "2025-01-01T01:00:00Z",
+      "endsAt": "0001-01-01T00:00:00Z",
+      "generatorURL": "http://grafana:3000/alerting/grafana/abc/view",
+      "fingerprint": "9f8e7d",
+      "dashboardURL": "http://grafana:3000/d/xyz",
+      "panelURL": "http://grafana:3000/d/xyz?viewPanel=2",
+      "valueString": "[ var='A' labels={} value=1.5 ]"
+    },
+    {
+      "status": "resolved",
+      "labels": {"alertname": "HighLatency", "service": "web"},
+      "annotations": {"description": "web のレイテンシが回復"},
+      "startsAt": "2025-01-01T00:30:00Z",
+      "endsAt": "2025-01-01T00:45:00Z",
+      "generatorURL": "http://grafana:3000/alerting/grafana/def/view",
+      "fingerprint": "6c5b4a"
+    }
+  ]
+}`
+
+func TestGrafanaAdapterParse(t *testing.T) {
+	alerts, err := grafanaAdapter{}.parse([]byte(grafanaTestPayload))
+	if err != nil {
+		t.Fatalf("parse() error = %v", err)
+	}
+	if len(alerts) != 2 {
+		t.Fatalf("アラートの件数 = %d", len(alerts))
+	}
+
+	firing := alerts[0]
+	if firing.Source != "Grafana" || firing.Status != alertStatusFiring || firing.Fingerprint != "9f8e7d" || firing.Labels["service"] != "api" {
+		t.Errorf("発生中のアラート = %+v", firing)
+	}
+	// パネルの URL を優先し、共通のアノテーションと評価した値で補う
+	if firing.GeneratorURL != "http://grafana:3000/d/xyz?viewPanel=2" {
+		t.Errorf("GeneratorURL = %q", firing.GeneratorURL)
+	}
+	if firing.summary() != "API のレイテンシが悪化" || firing.Annotations["description"] != "評価した値: [ var='A' labels={} value=1.5 ]" {
+		t.Errorf("アノテーション = %+v", firing.Annotations)
+	}
+
+	resolved := alerts[1]
+	if resolved.Status != alertStatusResolved || resolved.GeneratorURL != "http://grafana:3000/alerting/grafana/def/view" || resolved.Annotations["description"] != "web のレイテンシが回復" {
+		t.Errorf("解消済みのアラート = %+v", resolved)
+	}
+
+	if _, err := (grafanaAdapter{}).parse([]byte("[")); err == nil {
+		t.Error("不正な本文はエラーになるべきです")
+	}
+}
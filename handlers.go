@@ -9,28 +9,17 @@ import (
 	"github.com/slack-go/slack/slackevents"
 )
 
-// handleAppMention はメンション受信時の処理（ボタンを表示）
-func handleAppMention(api *slack.Client, event *slackevents.AppMentionEvent) {
+// handleAppMention はメンション受信時の処理（コマンドの振り分け）
+func handleAppMention(api SlackAPI, event *slackevents.AppMentionEvent) {
 	log.Printf("メンションを受信しました: %s", event.Text)
 
-	// コマンドを解析
-	text := strings.ToLower(strings.TrimSpace(event.Text))
-
-	// helpコマンドの処理
-	if strings.Contains(text, "help") || strings.Contains(text, "ヘルプ") {
-		showHelp(api, event.Channel)
-		return
-	}
-
-	// handlerコマンドの処理（チャンネルのハンドラー情報を表示）
-	if strings.Contains(text, "handler") || strings.Contains(text, "ハンドラー") || strings.Contains(text, "担当") {
-		showHandler(api, event.Channel)
-		return
+	// コマンドを解析して実行
+	ctx := &CommandContext{
+		API:       api,
+		ChannelID: event.Channel,
+		UserID:    event.User,
 	}
-
-	// listコマンドの処理（オープンなインシデント一覧）
-	if strings.Contains(text, "list") || strings.Contains(text, "一覧") || strings.Contains(text, "リスト") {
-		showIncidentList(api, event.Channel)
+	if mentionRouter.dispatch(ctx, tokenizeCommand(event.Text)) {
 		return
 	}
 
@@ -52,16 +41,27 @@ func handleAppMention(api *slack.Client, event *slackevents.AppMentionEvent) {
 			return
 		}
 
+		// 現在のステータスを取得
+		status := StatusInvestigating
+		if incident, err := getIncidentDetails(incidentID); err == nil {
+			status = incident.Status
+		}
+
 		// ハンドラーボタンを表示
 		postHandlerButton(api, event.Channel, incidentID)
 
 		// インシデント操作ボタンを表示
-		postIncidentActionsButton(api, event.Channel, incidentID)
+		postIncidentActionsButton(api, event.Channel, incidentID, status)
 
 		return
 	}
 
 	// デフォルト: インシデント報告ボタンを表示
+	postReportButton(api, event.Channel)
+}
+
+// postReportButton はインシデント報告ボタンを投稿
+func postReportButton(api SlackAPI, channelID string) {
 	button := slack.NewButtonBlockElement(
 		"open_incident_modal",
 		"open_modal",
@@ -78,8 +78,8 @@ func handleAppMention(api *slack.Client, event *slackevents.AppMentionEvent) {
 	headerBlock := slack.NewSectionBlock(headerText, nil, nil)
 
 	// メッセージを送信
-	_, _, err = api.PostMessage(
-		event.Channel,
+	_, _, err := api.PostMessage(
+		channelID,
 		slack.MsgOptionBlocks(headerBlock, actionBlock),
 	)
 
@@ -92,7 +92,7 @@ func handleAppMention(api *slack.Client, event *slackevents.AppMentionEvent) {
 }
 
 // handleOpenModal はボタンクリック時にモーダルを開く
-func handleOpenModal(api *slack.Client, callback slack.InteractionCallback) {
+func handleOpenModal(api SlackAPI, callback slack.InteractionCallback) {
 	log.Println("モーダル表示ボタンがクリックされました")
 
 	// ユーザー情報を取得してDisplay Nameを取得
@@ -111,14 +111,16 @@ func handleOpenModal(api *slack.Client, callback slack.InteractionCallback) {
 		displayName = user.Name
 	}
 
-	// 「入力中です」メッセージを投稿
-	typingMessage := fmt.Sprintf("✍️ %sさんがインシデント報告を入力中です...", displayName)
-	_, _, err = api.PostMessage(
-		callback.Channel.ID,
-		slack.MsgOptionText(typingMessage, false),
-	)
-	if err != nil {
-		log.Printf("入力中メッセージの投稿エラー: %v", err)
+	// 「入力中です」メッセージを投稿（App Homeなどチャンネル外からの操作の場合は投稿しない）
+	if callback.Channel.ID != "" {
+		typingMessage := fmt.Sprintf("✍️ %sさんがインシデント報告を入力中です...", displayName)
+		_, _, err = api.PostMessage(
+			callback.Channel.ID,
+			slack.MsgOptionText(typingMessage, false),
+		)
+		if err != nil {
+			log.Printf("入力中メッセージの投稿エラー: %v", err)
+		}
 	}
 
 	// インシデント報告用のモーダルを作成
@@ -134,14 +136,23 @@ func handleOpenModal(api *slack.Client, callback slack.InteractionCallback) {
 	log.Println("インシデント報告モーダルを表示しました")
 }
 
+// parseIncidentActionValue はボタンのValue（incident_<ID>）からインシデントIDを取得
+// 縮退運転中に投稿したボタンの仮IDは登録後のIDに変換する
+func parseIncidentActionValue(value string) (int64, error) {
+	var incidentID int64
+	if _, err := fmt.Sscanf(value, "incident_%d", &incidentID); err != nil {
+		return 0, err
+	}
+	return canonicalIncidentID(incidentID), nil
+}
+
 // handleAssignHandler はインシデントハンドラー割り当て/更新ボタンがクリックされた時の処理（冪等）
-func handleAssignHandler(api *slack.Client, callback slack.InteractionCallback) {
+func handleAssignHandler(api SlackAPI, callback slack.InteractionCallback) {
 	log.Println("インシデントハンドラー割り当て/更新ボタンがクリックされました")
 
 	// ボタンのValueからインシデントIDを取得
 	action := callback.ActionCallback.BlockActions[0]
-	var incidentID int64
-	_, err := fmt.Sscanf(action.Value, "incident_%d", &incidentID)
+	incidentID, err := parseIncidentActionValue(action.Value)
 	if err != nil {
 		log.Printf("インシデントID解析エラー: %v", err)
 		return
@@ -159,13 +170,15 @@ func handleAssignHandler(api *slack.Client, callback slack.InteractionCallback)
 		handlerName = user.Name
 	}
 
+	channelID := callbackChannelID(callback, incidentID)
+
 	// ハンドラーを割り当て/更新（冪等操作）
 	err = changeHandler(incidentID, callback.User.ID, handlerName, callback.User.ID)
 	if err != nil {
 		log.Printf("ハンドラー割り当てエラー: %v", err)
 		// エラーメッセージを投稿
 		api.PostEphemeral(
-			callback.Channel.ID,
+			channelID,
 			callback.User.ID,
 			slack.MsgOptionText(fmt.Sprintf("❌ ハンドラー割り当てに失敗しました: %v", err), false),
 		)
@@ -175,7 +188,7 @@ func handleAssignHandler(api *slack.Client, callback slack.InteractionCallback)
 	// 成功メッセージを投稿
 	successMessage := fmt.Sprintf("✅ <@%s> さんがこのインシデントの担当者になりました！", callback.User.ID)
 	_, _, err = api.PostMessage(
-		callback.Channel.ID,
+		channelID,
 		slack.MsgOptionText(successMessage, false),
 	)
 
@@ -184,10 +197,27 @@ func handleAssignHandler(api *slack.Client, callback slack.InteractionCallback)
 	} else {
 		log.Printf("インシデント %d のハンドラーを %s に設定しました", incidentID, handlerName)
 	}
+
+	refreshAppHomes(api)
+}
+
+// callbackChannelID はインタラクションが発生したチャンネルIDを返す
+// App Homeなどチャンネル外から操作された場合はインシデントチャンネルのIDを返す
+func callbackChannelID(callback slack.InteractionCallback, incidentID int64) string {
+	if callback.Channel.ID != "" {
+		return callback.Channel.ID
+	}
+
+	incident, err := getIncidentDetails(incidentID)
+	if err != nil {
+		log.Printf("インシデントチャンネル取得エラー: %v", err)
+		return callback.User.ID
+	}
+	return incident.ChannelID
 }
 
 // postHandlerButton はインシデントハンドラー割り当てボタンを投稿
-func postHandlerButton(api *slack.Client, channelID string, incidentID int64) {
+func postHandlerButton(api SlackAPI, channelID string, incidentID int64) {
 	// ボタンを作成（冪等：何回でも押せる）
 	assignButton := slack.NewButtonBlockElement(
 		"assign_handler",
@@ -216,17 +246,8 @@ func postHandlerButton(api *slack.Client, channelID string, incidentID int64) {
 	}
 }
 
-// postIncidentActionsButton はインシデント操作ボタンを投稿
-func postIncidentActionsButton(api *slack.Client, channelID string, incidentID int64) {
-	// 更新ボタン
-	updateButton := slack.NewButtonBlockElement(
-		"update_incident",
-		fmt.Sprintf("incident_%d", incidentID),
-		slack.NewTextBlockObject("plain_text", "📝 詳細を更新", true, false),
-	)
-	updateButton.Style = slack.StylePrimary
-
-	// 復旧ボタン
+// newResolveButton は確認ダイアログ付きの復旧ボタンを作成
+func newResolveButton(incidentID int64) *slack.ButtonBlockElement {
 	resolveButton := slack.NewButtonBlockElement(
 		"resolve_incident",
 		fmt.Sprintf("incident_%d", incidentID),
@@ -239,23 +260,77 @@ func postIncidentActionsButton(api *slack.Client, channelID string, incidentID i
 		Confirm: slack.NewTextBlockObject("plain_text", "復旧完了", false, false),
 		Deny:    slack.NewTextBlockObject("plain_text", "キャンセル", false, false),
 	}
+	return resolveButton
+}
+
+// postIncidentActionsButton はインシデント操作ボタンを投稿
+// 現在のステータスから遷移可能なステータスへの変更ボタンを表示する
+func postIncidentActionsButton(api SlackAPI, channelID string, incidentID int64, status string) {
+	status = normalizeStatus(status)
+	var elements []slack.BlockElement
 
-	// タイムキーパー停止ボタン
-	stopTimekeeperButton := slack.NewButtonBlockElement(
-		"stop_timekeeper",
+	// 更新ボタン
+	updateButton := slack.NewButtonBlockElement(
+		"update_incident",
 		fmt.Sprintf("incident_%d", incidentID),
-		slack.NewTextBlockObject("plain_text", "⏹️ タイムキーパーを止める", true, false),
+		slack.NewTextBlockObject("plain_text", "📝 詳細を更新", true, false),
 	)
-	stopTimekeeperButton.Style = "danger"
+	updateButton.Style = slack.StylePrimary
+	elements = append(elements, updateButton)
+
+	// ステータス変更ボタン（遷移可能なステータスのみ）
+	for _, next := range nextStatuses(status) {
+		if next == StatusResolved {
+			elements = append(elements, newResolveButton(incidentID))
+			continue
+		}
+
+		statusButton := slack.NewButtonBlockElement(
+			statusActionID(next),
+			fmt.Sprintf("incident_%d", incidentID),
+			slack.NewTextBlockObject("plain_text", statusLabel(next), true, false),
+		)
+		if next == StatusClosed {
+			statusButton.Confirm = &slack.ConfirmationBlockObject{
+				Title:   slack.NewTextBlockObject("plain_text", "クローズの確認", false, false),
+				Text:    slack.NewTextBlockObject("mrkdwn", "このインシデントをクローズしますか？\nクローズ後はステータスを変更できません。", false, false),
+				Confirm: slack.NewTextBlockObject("plain_text", "クローズ", false, false),
+				Deny:    slack.NewTextBlockObject("plain_text", "キャンセル", false, false),
+			}
+		}
+		elements = append(elements, statusButton)
+	}
+
+	// ポストモーテム作成ボタン（復旧後）
+	if (status == StatusResolved && !config.Postmortem.DisableOnResolve) || status == StatusPostmortem {
+		postmortemButton := slack.NewButtonBlockElement(
+			"generate_postmortem",
+			fmt.Sprintf("incident_%d", incidentID),
+			slack.NewTextBlockObject("plain_text", "📝 ポストモーテムを作成", true, false),
+		)
+		elements = append(elements, postmortemButton)
+	}
+
+	// アクションアイテム追加ボタン
+	elements = append(elements, newAddActionItemButton(incidentID))
+
+	// タイムキーパーの一時停止・再開・停止ボタン（対応中のみ）
+	if isActiveStatus(status) {
+		switch timekeeperManager.currentState(incidentID) {
+		case timekeeperStatePaused:
+			elements = append(elements, newResumeTimekeeperButton(incidentID))
+		case timekeeperStateRunning:
+			elements = append(elements, newPauseTimekeeperButton(incidentID))
+		}
+		elements = append(elements, newStopTimekeeperButton(incidentID))
+	}
 
 	actionBlock := slack.NewActionBlock(
 		fmt.Sprintf("incident_actions_%d", incidentID),
-		updateButton,
-		resolveButton,
-		stopTimekeeperButton,
+		elements...,
 	)
 
-	headerText := slack.NewTextBlockObject("mrkdwn", "インシデント情報を管理:", false, false)
+	headerText := slack.NewTextBlockObject("mrkdwn", fmt.Sprintf("インシデント情報を管理（現在のステータス: %s）:", statusLabel(status)), false, false)
 	headerBlock := slack.NewSectionBlock(headerText, nil, nil)
 
 	_, _, err := api.PostMessage(
@@ -270,21 +345,113 @@ func postIncidentActionsButton(api *slack.Client, channelID string, incidentID i
 	}
 }
 
+// handleChangeStatus はステータス変更ボタンがクリックされた時の処理
+func handleChangeStatus(api SlackAPI, callback slack.InteractionCallback) {
+	action := callback.ActionCallback.BlockActions[0]
+	newStatus, ok := parseStatusActionID(action.ActionID)
+	if !ok {
+		log.Printf("不明なステータス変更アクションです: %s", action.ActionID)
+		return
+	}
+	log.Printf("ステータス変更ボタン (%s) がクリックされました", newStatus)
+
+	// ボタンのValueからインシデントIDを取得
+	incidentID, err := parseIncidentActionValue(action.Value)
+	if err != nil {
+		log.Printf("インシデントID解析エラー: %v", err)
+		return
+	}
+
+	if err := changeStatusAndAnnounce(api, incidentID, newStatus, callback.User.ID, callback.User.Name); err != nil {
+		api.PostEphemeral(
+			callbackChannelID(callback, incidentID),
+			callback.User.ID,
+			slack.MsgOptionText(fmt.Sprintf("❌ %v", err), false),
+		)
+	}
+}
+
+// changeStatusAndAnnounce はインシデントのステータスを変更し、インシデントチャンネルと全体周知チャンネルに通知する
+// 復旧済みへの変更は復旧通知を行う resolveAndAnnounce に委譲する
+func changeStatusAndAnnounce(api SlackAPI, incidentID int64, newStatus, userID, userName string) error {
+	if newStatus == StatusResolved {
+		return resolveAndAnnounce(api, incidentID, userID, userName, "")
+	}
+
+	incident, err := getIncidentDetails(incidentID)
+	if err != nil {
+		log.Printf("インシデント詳細取得エラー: %v", err)
+		return fmt.Errorf("インシデント情報の取得に失敗しました: %v", err)
+	}
+	channelID := incident.ChannelID
+	severity := incident.Severity
+
+	note := fmt.Sprintf("%s によりステータス変更", userName)
+	oldStatus, err := changeIncidentStatus(incidentID, newStatus, userID, note)
+	if err != nil {
+		log.Printf("ステータス変更エラー: %v", err)
+		return fmt.Errorf("ステータスの変更に失敗しました: %v", err)
+	}
+
+	statusMessage := fmt.Sprintf(
+		"🔄 *インシデントのステータスが変更されました*\n\n"+
+			"*タイトル:* %s\n"+
+			"*ステータス:* %s → *%s*\n"+
+			"*変更者:* <@%s>\n"+
+			"*インシデントID:* #%d",
+		incident.Title,
+		statusLabel(oldStatus),
+		statusLabel(newStatus),
+		userID,
+		incidentID,
+	)
+
+	_, _, err = api.PostMessage(
+		channelID,
+		slack.MsgOptionText(statusMessage, false),
+		slack.MsgOptionBlocks(
+			slack.NewSectionBlock(
+				slack.NewTextBlockObject("mrkdwn", statusMessage, false, false),
+				nil, nil,
+			),
+		),
+	)
+	if err != nil {
+		log.Printf("ステータス変更通知投稿エラー: %v", err)
+	}
+
+	// 全体周知チャンネルにも現在のフェーズを通知
+	if config.Channels.EnableAnnouncement && len(config.Channels.AnnouncementChannels) > 0 {
+		postToAnnouncementChannels(api, statusMessage, channelID, severity)
+	}
+
+	// 次のステータスへの操作ボタンを表示
+	postIncidentActionsButton(api, channelID, incidentID, newStatus)
+
+	// 対応中でなくなった場合はタイムキーパーを停止
+	if !isActiveStatus(newStatus) && timekeeperManager.stopTimekeeper(incidentID) {
+		log.Printf("インシデント %d のタイムキーパーを自動停止しました", incidentID)
+	}
+
+	refreshAppHomes(api)
+
+	return nil
+}
+
 // handleUpdateIncident はインシデント更新ボタンがクリックされた時の処理
-func handleUpdateIncident(api *slack.Client, callback slack.InteractionCallback) {
+func handleUpdateIncident(api SlackAPI, callback slack.InteractionCallback) {
 	log.Println("インシデント更新ボタンがクリックされました")
 
 	// ボタンのValueからインシデントIDを取得
 	action := callback.ActionCallback.BlockActions[0]
-	var incidentID int64
-	_, err := fmt.Sscanf(action.Value, "incident_%d", &incidentID)
+	incidentID, err := parseIncidentActionValue(action.Value)
 	if err != nil {
 		log.Printf("インシデントID解析エラー: %v", err)
 		return
 	}
 
 	// 現在のインシデント詳細を取得
-	details, err := getIncidentDetails(incidentID)
+	incident, err := getIncidentDetails(incidentID)
 	if err != nil {
 		log.Printf("インシデント詳細取得エラー: %v", err)
 		api.PostEphemeral(
@@ -296,7 +463,7 @@ func handleUpdateIncident(api *slack.Client, callback slack.InteractionCallback)
 	}
 
 	// 更新用モーダルを作成
-	modalView := createUpdateIncidentModal(incidentID, details)
+	modalView := createUpdateIncidentModal(incident)
 
 	// モーダルを開く
 	_, err = api.OpenView(callback.TriggerID, modalView)
@@ -309,49 +476,58 @@ func handleUpdateIncident(api *slack.Client, callback slack.InteractionCallback)
 }
 
 // handleResolveIncident はインシデント復旧ボタンがクリックされた時の処理
-func handleResolveIncident(api *slack.Client, callback slack.InteractionCallback) {
+func handleResolveIncident(api SlackAPI, callback slack.InteractionCallback) {
 	log.Println("インシデント復旧ボタンがクリックされました")
 
 	// ボタンのValueからインシデントIDを取得
 	action := callback.ActionCallback.BlockActions[0]
-	var incidentID int64
-	_, err := fmt.Sscanf(action.Value, "incident_%d", &incidentID)
+	incidentID, err := parseIncidentActionValue(action.Value)
 	if err != nil {
 		log.Printf("インシデントID解析エラー: %v", err)
 		return
 	}
 
-	// インシデント詳細を取得
-	details, err := getIncidentDetails(incidentID)
-	if err != nil {
-		log.Printf("インシデント詳細取得エラー: %v", err)
+	if err := resolveAndAnnounce(api, incidentID, callback.User.ID, callback.User.Name, ""); err != nil {
 		api.PostEphemeral(
 			callback.Channel.ID,
 			callback.User.ID,
-			slack.MsgOptionText(fmt.Sprintf("❌ インシデント情報の取得に失敗しました: %v", err), false),
+			slack.MsgOptionText(fmt.Sprintf("❌ %v", err), false),
 		)
-		return
+	}
+}
+
+// resolveAndAnnounce はインシデントを復旧済みにし、インシデントチャンネルと全体周知チャンネルに通知する
+func resolveAndAnnounce(api SlackAPI, incidentID int64, userID, userName, resolutionNote string) error {
+	// インシデント詳細を取得
+	incident, err := getIncidentDetails(incidentID)
+	if err != nil {
+		log.Printf("インシデント詳細取得エラー: %v", err)
+		return fmt.Errorf("インシデント情報の取得に失敗しました: %v", err)
 	}
 
 	// ユーザー情報を取得
-	user, err := api.GetUserInfo(callback.User.ID)
-	resolvedByName := callback.User.Name
+	user, err := api.GetUserInfo(userID)
+	resolvedByName := userName
 	if err == nil && user.RealName != "" {
 		resolvedByName = user.RealName
 	}
 
 	// インシデントを復旧済みにする
-	err = resolveIncident(incidentID, callback.User.ID, resolvedByName)
+	err = resolveIncident(incidentID, userID, resolvedByName, resolutionNote)
 	if err != nil {
 		log.Printf("インシデント復旧エラー: %v", err)
-		api.PostEphemeral(
-			callback.Channel.ID,
-			callback.User.ID,
-			slack.MsgOptionText(fmt.Sprintf("❌ インシデントの復旧に失敗しました: %v", err), false),
-		)
-		return
+		return fmt.Errorf("インシデントの復旧に失敗しました: %v", err)
 	}
 
+	announceResolution(api, incident, fmt.Sprintf("<@%s>", userID), resolutionNote)
+	return nil
+}
+
+// announceResolution は復旧をインシデントチャンネルと全体周知チャンネルに通知し、タイムキーパーを停止して操作ボタンを表示する
+// resolvedBy は復旧者として表示するテキスト（ユーザーのメンションなど）
+func announceResolution(api SlackAPI, incident *Incident, resolvedBy, resolutionNote string) {
+	incidentID, channelID := incident.ID, incident.ChannelID
+
 	// 重要度に応じた絵文字
 	severityEmoji := map[string]string{
 		"critical": "🔴",
@@ -359,10 +535,10 @@ func handleResolveIncident(api *slack.Client, callback slack.InteractionCallback
 		"medium":   "🟡",
 		"low":      "🟢",
 	}
-	emoji := severityEmoji[details["severity"].(string)]
+	emoji := severityEmoji[incident.Severity]
 
 	// チャンネルメンバーを取得（対応メンバー一覧）
-	contributors, err := getChannelContributors(api, callback.Channel.ID)
+	contributors, err := getChannelContributors(api, channelID)
 	if err != nil {
 		log.Printf("対応メンバー取得エラー: %v", err)
 	}
@@ -372,18 +548,25 @@ func handleResolveIncident(api *slack.Client, callback slack.InteractionCallback
 		"✅ *インシデントが復旧しました*\n\n"+
 			"%s *タイトル:* %s\n"+
 			"*重要度:* %s %s\n"+
-			"*復旧者:* <@%s>\n"+
+			"*ステータス:* %s\n"+
+			"*復旧者:* %s\n"+
 			"*インシデントID:* #%d\n"+
 			"*チャンネル:* <#%s>",
 		emoji,
-		details["title"].(string),
+		incident.Title,
 		emoji,
-		details["severity"].(string),
-		callback.User.ID,
+		incident.Severity,
+		statusLabel(StatusResolved),
+		resolvedBy,
 		incidentID,
-		callback.Channel.ID,
+		channelID,
 	)
 
+	// 復旧メモを追加
+	if resolutionNote != "" {
+		resolveMessage += fmt.Sprintf("\n*復旧メモ:* %s", resolutionNote)
+	}
+
 	// 対応メンバー一覧を追加
 	if len(contributors) > 0 {
 		resolveMessage += fmt.Sprintf("\n\n👥 *対応メンバー:* %s", contributors)
@@ -396,7 +579,7 @@ func handleResolveIncident(api *slack.Client, callback slack.InteractionCallback
 	}
 
 	_, _, err = api.PostMessage(
-		callback.Channel.ID,
+		channelID,
 		slack.MsgOptionText("インシデントが復旧しました", false),
 		slack.MsgOptionAttachments(attachment),
 	)
@@ -410,23 +593,27 @@ func handleResolveIncident(api *slack.Client, callback slack.InteractionCallback
 	// 全体周知チャンネルに復旧通知を送信（緑の縦棒付き）
 	if config.Channels.EnableAnnouncement && len(config.Channels.AnnouncementChannels) > 0 {
 		log.Println("全体周知チャンネルに復旧通知を送信します")
-		postResolveToAnnouncementChannels(api, resolveMessage, callback.Channel.ID)
+		postResolveToAnnouncementChannels(api, resolveMessage, channelID)
 	}
 
 	// タイムキーパーを自動停止
 	if timekeeperManager.stopTimekeeper(incidentID) {
 		log.Printf("インシデント %d のタイムキーパーを自動停止しました", incidentID)
 	}
+
+	// ポストモーテム・クローズへの操作ボタンを表示
+	postIncidentActionsButton(api, channelID, incidentID, StatusResolved)
+
+	refreshAppHomes(api)
 }
 
 // handleStopTimekeeper はタイムキーパー停止ボタンがクリックされた時の処理
-func handleStopTimekeeper(api *slack.Client, callback slack.InteractionCallback) {
+func handleStopTimekeeper(api SlackAPI, callback slack.InteractionCallback) {
 	log.Println("タイムキーパー停止ボタンがクリックされました")
 
 	// ボタンのValueからインシデントIDを取得
 	action := callback.ActionCallback.BlockActions[0]
-	var incidentID int64
-	_, err := fmt.Sscanf(action.Value, "incident_%d", &incidentID)
+	incidentID, err := parseIncidentActionValue(action.Value)
 	if err != nil {
 		log.Printf("インシデントID解析エラー: %v", err)
 		return
@@ -455,8 +642,77 @@ func handleStopTimekeeper(api *slack.Client, callback slack.InteractionCallback)
 	}
 }
 
+// handlePauseTimekeeper はタイムキーパー一時停止ボタンがクリックされた時の処理
+func handlePauseTimekeeper(api SlackAPI, callback slack.InteractionCallback) {
+	log.Println("タイムキーパー一時停止ボタンがクリックされました")
+
+	// ボタンのValueからインシデントIDを取得
+	action := callback.ActionCallback.BlockActions[0]
+	incidentID, err := parseIncidentActionValue(action.Value)
+	if err != nil {
+		log.Printf("インシデントID解析エラー: %v", err)
+		return
+	}
+
+	if !timekeeperManager.pauseTimekeeper(incidentID) {
+		api.PostEphemeral(
+			callback.Channel.ID,
+			callback.User.ID,
+			slack.MsgOptionText("ℹ️ タイムキーパーは動作していません。", false),
+		)
+		return
+	}
+
+	message := fmt.Sprintf("⏸️ <@%s> がインシデント #%d のタイムキーパーを一時停止しました。再開するまで経過時間とマイルストーンは投稿されません。", callback.User.ID, incidentID)
+	_, _, err = api.PostMessage(
+		callback.Channel.ID,
+		slack.MsgOptionText(message, false),
+		slack.MsgOptionBlocks(
+			slack.NewSectionBlock(
+				slack.NewTextBlockObject("mrkdwn", message, false, false),
+				nil,
+				slack.NewAccessory(newResumeTimekeeperButton(incidentID)),
+			),
+		),
+	)
+	if err != nil {
+		log.Printf("一時停止メッセージ投稿エラー: %v", err)
+	}
+}
+
+// handleResumeTimekeeper はタイムキーパー再開ボタンがクリックされた時の処理
+func handleResumeTimekeeper(api SlackAPI, callback slack.InteractionCallback) {
+	log.Println("タイムキーパー再開ボタンがクリックされました")
+
+	// ボタンのValueからインシデントIDを取得
+	action := callback.ActionCallback.BlockActions[0]
+	incidentID, err := parseIncidentActionValue(action.Value)
+	if err != nil {
+		log.Printf("インシデントID解析エラー: %v", err)
+		return
+	}
+
+	if !timekeeperManager.resumeTimekeeper(api, incidentID) {
+		api.PostEphemeral(
+			callback.Channel.ID,
+			callback.User.ID,
+			slack.MsgOptionText("ℹ️ タイムキーパーは一時停止中ではありません。", false),
+		)
+		return
+	}
+
+	message := fmt.Sprintf("▶️ <@%s> がインシデント #%d のタイムキーパーを再開しました", callback.User.ID, incidentID)
+	_, _, err = api.PostMessage(
+		callback.Channel.ID,
+		slack.MsgOptionText(message, false),
+	)
+	if err != nil {
+		log.Printf("再開メッセージ投稿エラー: %v", err)
+	}
+}
+
 // postToAnnouncementChannels は全体周知チャンネルにメッセージを投稿（赤/黄色の縦棒）
-func postToAnnouncementChannels(api *slack.Client, message string, incidentChannelID string, severity string) {
+func postToAnnouncementChannels(api SlackAPI, message string, incidentChannelID string, severity string) {
 	// 重要度に応じた色を決定
 	var color string
 	switch severity {
@@ -502,7 +758,7 @@ func postToAnnouncementChannels(api *slack.Client, message string, incidentChann
 }
 
 // postResolveToAnnouncementChannels は全体周知チャンネルに復旧通知を投稿（緑の縦棒）
-func postResolveToAnnouncementChannels(api *slack.Client, message string, incidentChannelID string) {
+func postResolveToAnnouncementChannels(api SlackAPI, message string, incidentChannelID string) {
 	for _, channelID := range config.Channels.AnnouncementChannels {
 		if channelID == "" {
 			continue
@@ -537,7 +793,7 @@ func postResolveToAnnouncementChannels(api *slack.Client, message string, incide
 }
 
 // getChannelContributors はチャンネルでメッセージを投稿したユーザー一覧を取得
-func getChannelContributors(api *slack.Client, channelID string) (string, error) {
+func getChannelContributors(api SlackAPI, channelID string) (string, error) {
 	log.Printf("チャンネル %s の対応メンバーを取得中...", channelID)
 
 	// チャンネルの会話履歴を取得（最大1000件）
@@ -604,7 +860,7 @@ func getChannelContributors(api *slack.Client, channelID string) (string, error)
 }
 
 // handleChannelArchive はチャンネルアーカイブ時の処理
-func handleChannelArchive(api *slack.Client, event *slackevents.ChannelArchiveEvent) {
+func handleChannelArchive(api SlackAPI, event *slackevents.ChannelArchiveEvent) {
 	log.Printf("チャンネルアーカイブイベントを受信しました: %s", event.Channel)
 
 	// チャンネルがインシデントチャンネルかどうかを確認
@@ -622,12 +878,13 @@ func handleChannelArchive(api *slack.Client, event *slackevents.ChannelArchiveEv
 	}
 
 	// インシデントを自動的に復旧済みにする
-	if db != nil {
-		err := resolveIncident(incidentID, "system", "システム（チャンネルアーカイブ）")
+	if store != nil {
+		err := resolveIncident(incidentID, "system", "システム（チャンネルアーカイブ）", "")
 		if err != nil {
 			log.Printf("インシデント %d の自動復旧エラー: %v", incidentID, err)
 		} else {
 			log.Printf("インシデント %d を自動的に復旧済みにしました（チャンネルアーカイブ）", incidentID)
+			refreshAppHomes(api)
 		}
 	}
 }
@@ -223,39 +223,37 @@ func TestCommandParsing(t *testing.T) {
 	tests := []struct {
 		name     string
 		text     string
-		isHelp   bool
-		isHandler bool
-		isList   bool
+		expected string // 空文字列はコマンドに該当しないことを表す
 	}{
-		{"helpコマンド", "help", true, false, false},
-		{"ヘルプコマンド", "ヘルプ", true, false, false},
-		{"handlerコマンド", "handler", false, true, false},
-		{"ハンドラーコマンド", "ハンドラー", false, true, false},
-		{"担当コマンド", "担当", false, true, false},
-		{"listコマンド", "list", false, false, true},
-		{"一覧コマンド", "一覧", false, false, true},
-		{"リストコマンド", "リスト", false, false, true},
-		{"通常のメンション", "hello", false, false, false},
+		{"helpコマンド", "<@U123> help", "help"},
+		{"ヘルプコマンド", "<@U123> ヘルプ", "help"},
+		{"handlerコマンド", "<@U123> handler", "handler"},
+		{"ハンドラーコマンド", "<@U123> ハンドラー", "handler"},
+		{"担当コマンド", "<@U123> 担当", "handler"},
+		{"listコマンド", "<@U123> list", "list"},
+		{"大文字のLIST", "<@U123> LIST", "list"},
+		{"一覧コマンド", "<@U123> 一覧", "list"},
+		{"リストコマンド", "<@U123> リスト", "list"},
+		{"statusコマンド", "<@U123> status 42", "status"},
+		{"状況コマンド", "<@U123> 状況", "status"},
+		{"部分一致はコマンドにならない", "<@U123> blacklist is broken", ""},
+		{"通常のメンション", "<@U123> hello", ""},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			text := strings.ToLower(strings.TrimSpace(tt.text))
-
-			isHelp := strings.Contains(text, "help") || strings.Contains(text, "ヘルプ")
-			isHandler := strings.Contains(text, "handler") || strings.Contains(text, "ハンドラー") || strings.Contains(text, "担当")
-			isList := strings.Contains(text, "list") || strings.Contains(text, "一覧") || strings.Contains(text, "リスト")
-
-			if isHelp != tt.isHelp {
-				t.Errorf("helpコマンドの判定が間違っています: %v, 期待値: %v", isHelp, tt.isHelp)
+			tokens := tokenizeCommand(tt.text)
+			if len(tokens) == 0 {
+				t.Fatal("トークンが空です")
 			}
 
-			if isHandler != tt.isHandler {
-				t.Errorf("handlerコマンドの判定が間違っています: %v, 期待値: %v", isHandler, tt.isHandler)
+			var actual string
+			if cmd, ok := mentionRouter.lookup(tokens[0]); ok {
+				actual = cmd.Name
 			}
 
-			if isList != tt.isList {
-				t.Errorf("listコマンドの判定が間違っています: %v, 期待値: %v", isList, tt.isList)
+			if actual != tt.expected {
+				t.Errorf("コマンドの判定が間違っています: %q, 期待値: %q", actual, tt.expected)
 			}
 		})
 	}
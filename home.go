@@ -0,0 +1,400 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/slack-go/slack"
+	"github.com/slack-go/slack/slackevents"
+)
+
+// App Homeに表示する最大件数（1ビューあたり100ブロックの上限に収まるように制限）
+const (
+	homeHandlingLimit       = 10
+	homeOpenLimit           = 40
+	homeRecentResolvedLimit = 5
+)
+
+const (
+	// homeViewerTTL はApp Homeを開いていないユーザーを再描画対象から外すまでの期間
+	homeViewerTTL = 30 * 24 * time.Hour
+	// homeViewerPurgeInterval は再描画対象から外れたユーザーを保存先から削除する間隔
+	homeViewerPurgeInterval = 24 * time.Hour
+)
+
+// homeRefreshDelay は再描画を始めるまでの待ち時間（この間の状態変化は1回の再描画にまとめる）
+var homeRefreshDelay = 3 * time.Second
+
+// HomeViewers はApp Homeを開いたユーザーを管理（状態変化時の再描画対象）
+// 再起動後や別のレプリカでも再描画できるよう、インシデントの保存先にも記録する
+type HomeViewers struct {
+	users map[string]time.Time // ユーザーIDと最後にApp Homeを開いた日時（保存先に記録できない場合の控え）
+	mu    sync.RWMutex
+
+	refreshScheduled bool       // 再描画が予約済みかどうか
+	refreshMu        sync.Mutex // 再描画を1つずつ実行する
+}
+
+var homeViewers = &HomeViewers{
+	users: make(map[string]time.Time),
+}
+
+// add はユーザーを再描画対象に追加（開くたびに最後に開いた日時を更新する）
+// 保存先に記録できなかった場合（縮退運転中など）もメモリ上には保持する
+func (hv *HomeViewers) add(userID string) {
+	hv.mu.Lock()
+	hv.users[userID] = time.Now()
+	hv.mu.Unlock()
+
+	if store == nil {
+		return
+	}
+	if err := store.AddHomeViewer(userID); err != nil {
+		log.Printf("App Homeの閲覧者の記録に失敗しました (ユーザー: %s): %v", userID, err)
+	}
+}
+
+// list は再描画対象のユーザー一覧を返す（保存先に記録されたユーザーとメモリ上のユーザーを合わせる）
+// homeViewerTTL の間App Homeを開いていないユーザーは含めない
+func (hv *HomeViewers) list(now time.Time) []string {
+	openedSince := now.Add(-homeViewerTTL)
+
+	var userIDs []string
+	if store != nil {
+		stored, err := store.HomeViewers(openedSince)
+		if err != nil {
+			log.Printf("App Homeの閲覧者の取得に失敗しました: %v", err)
+		}
+		userIDs = stored
+	}
+
+	hv.mu.Lock()
+	defer hv.mu.Unlock()
+
+	for userID, openedAt := range hv.users {
+		if openedAt.Before(openedSince) {
+			delete(hv.users, userID)
+			continue
+		}
+		if !slices.Contains(userIDs, userID) {
+			userIDs = append(userIDs, userID)
+		}
+	}
+	return userIDs
+}
+
+// scheduleRefresh は homeRefreshDelay 後の再描画を予約（予約済みの場合は何もしない）
+func (hv *HomeViewers) scheduleRefresh(api SlackAPI) {
+	hv.mu.Lock()
+	defer hv.mu.Unlock()
+
+	if hv.refreshScheduled {
+		return
+	}
+	hv.refreshScheduled = true
+
+	time.AfterFunc(homeRefreshDelay, func() {
+		hv.refreshMu.Lock()
+		defer hv.refreshMu.Unlock()
+
+		// 再描画中の状態変化は次の再描画で反映する
+		hv.mu.Lock()
+		hv.refreshScheduled = false
+		hv.mu.Unlock()
+
+		hv.refresh(api)
+	})
+}
+
+// refresh は再描画対象の全ユーザーのダッシュボードを再描画
+// インシデントの一覧は1回だけ取得し、全ユーザーで共有する
+func (hv *HomeViewers) refresh(api SlackAPI) {
+	now := time.Now()
+	userIDs := hv.list(now)
+	if len(userIDs) == 0 {
+		return
+	}
+
+	log.Printf("App Homeを再描画します (%d人)", len(userIDs))
+	dashboard := loadHomeDashboard()
+	for _, userID := range userIDs {
+		publishHomeView(api, userID, dashboard, now)
+	}
+}
+
+// handleAppHomeOpened はApp Homeが開かれた時の処理
+func handleAppHomeOpened(api SlackAPI, event *slackevents.AppHomeOpenedEvent) {
+	if event.Tab != "home" {
+		return
+	}
+
+	log.Printf("ユーザー %s がApp Homeを開きました", event.User)
+	homeViewers.add(event.User)
+	publishAppHome(api, event.User)
+}
+
+// refreshAppHomes はApp Homeを開いたユーザーのダッシュボードを再描画
+// インシデントの状態が変化した時に呼び出す（短時間の連続した変化は1回の再描画にまとめる）
+func refreshAppHomes(api SlackAPI) {
+	homeViewers.scheduleRefresh(api)
+}
+
+// purgeHomeViewersLoop はしばらくApp Homeを開いていないユーザーを定期的に保存先から削除（リーダーのみ）
+func purgeHomeViewersLoop(ctx context.Context) {
+	ticker := time.NewTicker(homeViewerPurgeInterval)
+	defer ticker.Stop()
+
+	for {
+		if store != nil {
+			deleted, err := store.PurgeHomeViewers(time.Now().Add(-homeViewerTTL))
+			if err != nil {
+				log.Printf("App Homeの閲覧者の削除エラー: %v", err)
+			} else if deleted > 0 {
+				log.Printf("しばらくApp Homeを開いていない閲覧者 %d 人を削除しました", deleted)
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// homeDashboard はApp Homeに表示するインシデントの一覧（全ユーザーで共有する）
+type homeDashboard struct {
+	active   []Incident // 対応中のインシデント（すべて）
+	resolved []Incident // 最近復旧したインシデント
+}
+
+// loadHomeDashboard はApp Homeに表示するインシデントの一覧を取得（データベースが無効な場合はnil）
+func loadHomeDashboard() *homeDashboard {
+	if store == nil {
+		return nil
+	}
+
+	active, err := listIncidents(activeStatuses, "", 0)
+	if err != nil {
+		log.Printf("オープンなインシデント取得エラー: %v", err)
+	}
+	resolved, err := listIncidents(finishedStatuses, "", homeRecentResolvedLimit)
+	if err != nil {
+		log.Printf("復旧済みインシデント取得エラー: %v", err)
+	}
+	return &homeDashboard{active: active, resolved: resolved}
+}
+
+// homeIncidents はユーザー1人分のApp Homeに表示するインシデント
+type homeIncidents struct {
+	Handling      []Incident
+	HandlingTotal int // 担当中のインシデントの全件数（Handling は homeHandlingLimit 件まで）
+	Open          []Incident
+	OpenTotal     int // オープン中のインシデントの全件数（Open は homeOpenLimit 件まで）
+	Resolved      []Incident
+}
+
+// forUser はユーザーのApp Homeに表示するインシデントを取り出す
+func (d *homeDashboard) forUser(userID string) homeIncidents {
+	var handling []Incident
+	for _, incident := range d.active {
+		if incident.HandlerID == userID {
+			handling = append(handling, incident)
+		}
+	}
+
+	return homeIncidents{
+		Handling:      handling[:min(len(handling), homeHandlingLimit)],
+		HandlingTotal: len(handling),
+		Open:          d.active[:min(len(d.active), homeOpenLimit)],
+		OpenTotal:     len(d.active),
+		Resolved:      d.resolved,
+	}
+}
+
+// publishAppHome はユーザーのApp Homeにダッシュボードを公開
+func publishAppHome(api SlackAPI, userID string) {
+	publishHomeView(api, userID, loadHomeDashboard(), time.Now())
+}
+
+// publishHomeView は取得済みのインシデントの一覧からユーザーのダッシュボードを構築して公開
+func publishHomeView(api SlackAPI, userID string, dashboard *homeDashboard, now time.Time) {
+	var blocks []slack.Block
+
+	if dashboard == nil {
+		blocks = buildHomeUnavailableBlocks()
+	} else {
+		blocks = buildHomeBlocks(dashboard.forUser(userID), now)
+	}
+	if notice := degradedNotice(); notice != "" {
+		blocks = append([]slack.Block{
+			slack.NewSectionBlock(slack.NewTextBlockObject("mrkdwn", notice, false, false), nil, nil),
+			slack.NewDividerBlock(),
+		}, blocks...)
+	}
+
+	view := slack.HomeTabViewRequest{
+		Type:   slack.VTHomeTab,
+		Blocks: slack.Blocks{BlockSet: blocks},
+	}
+
+	if _, err := api.PublishView(userID, view, ""); err != nil {
+		log.Printf("App Home公開エラー (ユーザー: %s): %v", userID, err)
+	}
+}
+
+// buildHomeUnavailableBlocks はデータベースが無効な場合のApp Homeを構築
+func buildHomeUnavailableBlocks() []slack.Block {
+	return []slack.Block{
+		slack.NewHeaderBlock(slack.NewTextBlockObject("plain_text", "🚨 インシデントダッシュボード", true, false)),
+		buildHomeActionsBlock(),
+		slack.NewSectionBlock(
+			slack.NewTextBlockObject("mrkdwn", "⚠️ データベース機能が無効のため、インシデント情報を表示できません。", false, false),
+			nil, nil,
+		),
+	}
+}
+
+// buildHomeActionsBlock はApp Homeのクイックアクションを構築
+func buildHomeActionsBlock() *slack.ActionBlock {
+	reportButton := slack.NewButtonBlockElement(
+		"open_incident_modal",
+		"open_modal",
+		slack.NewTextBlockObject("plain_text", "🚨 インシデントを報告", true, false),
+	)
+	reportButton.Style = slack.StyleDanger
+
+	return slack.NewActionBlock("home_actions", reportButton)
+}
+
+// buildHomeBlocks はApp Homeのダッシュボードを構築
+func buildHomeBlocks(incidents homeIncidents, now time.Time) []slack.Block {
+	blocks := []slack.Block{
+		slack.NewHeaderBlock(slack.NewTextBlockObject("plain_text", "🚨 インシデントダッシュボード", true, false)),
+		buildHomeActionsBlock(),
+		slack.NewContextBlock("home_updated_at",
+			slack.NewTextBlockObject("mrkdwn", fmt.Sprintf("最終更新: %s", now.Format("2006-01-02 15:04:05")), false, false),
+		),
+		slack.NewDividerBlock(),
+	}
+
+	// 自分が担当中のインシデント
+	blocks = append(blocks, buildHomeSectionHeader(fmt.Sprintf("🙋 あなたが担当中のインシデント (%d件)", incidents.HandlingTotal)))
+	if len(incidents.Handling) == 0 {
+		blocks = append(blocks, buildHomeEmptyBlock("担当中のインシデントはありません"))
+	}
+	for _, incident := range incidents.Handling {
+		blocks = append(blocks, buildHomeIncidentBlock(incident, now, false))
+	}
+	if rest := incidents.HandlingTotal - len(incidents.Handling); rest > 0 {
+		blocks = append(blocks, buildHomeEmptyBlock(fmt.Sprintf("他 %d件（`@bot list handler:@me` で確認できます）", rest)))
+	}
+	blocks = append(blocks, slack.NewDividerBlock())
+
+	// オープン中のインシデント（重要度別）
+	blocks = append(blocks, buildHomeSectionHeader(fmt.Sprintf("🔥 オープン中のインシデント (%d件)", incidents.OpenTotal)))
+	if len(incidents.Open) == 0 {
+		blocks = append(blocks, buildHomeEmptyBlock("✅ 現在オープンなインシデントはありません"))
+	}
+	grouped := groupIncidentsBySeverity(incidents.Open)
+	for _, severity := range severityOrder {
+		severityIncidents := grouped[severity]
+		if len(severityIncidents) == 0 {
+			continue
+		}
+		blocks = append(blocks, slack.NewContextBlock("",
+			slack.NewTextBlockObject("mrkdwn", fmt.Sprintf("%s *%s* (%d件)", severityEmojis[severity], severity, len(severityIncidents)), false, false),
+		))
+		for _, incident := range severityIncidents {
+			blocks = append(blocks, buildHomeIncidentBlock(incident, now, true))
+		}
+	}
+	if rest := incidents.OpenTotal - len(incidents.Open); rest > 0 {
+		blocks = append(blocks, buildHomeEmptyBlock(fmt.Sprintf("他 %d件（`@bot list` で確認できます）", rest)))
+	}
+	blocks = append(blocks, slack.NewDividerBlock())
+
+	// 最近復旧したインシデント
+	blocks = append(blocks, buildHomeSectionHeader("✅ 最近復旧したインシデント"))
+	if len(incidents.Resolved) == 0 {
+		blocks = append(blocks, buildHomeEmptyBlock("最近復旧したインシデントはありません"))
+	}
+	for _, incident := range incidents.Resolved {
+		blocks = append(blocks, buildHomeIncidentBlock(incident, now, false))
+	}
+
+	return blocks
+}
+
+// buildHomeSectionHeader はApp Homeのセクション見出しを構築
+func buildHomeSectionHeader(text string) slack.Block {
+	return slack.NewSectionBlock(slack.NewTextBlockObject("mrkdwn", "*"+text+"*", false, false), nil, nil)
+}
+
+// buildHomeEmptyBlock はApp Homeのセクションが空の場合の表示を構築
+func buildHomeEmptyBlock(text string) slack.Block {
+	return slack.NewContextBlock("", slack.NewTextBlockObject("mrkdwn", text, false, false))
+}
+
+// buildHomeIncidentBlock はApp Homeに表示するインシデント1件分のブロックを構築
+// assignable がtrueの場合は「担当者になる」ボタン、それ以外は「チャンネルを開く」ボタンを付ける
+func buildHomeIncidentBlock(incident Incident, now time.Time, assignable bool) slack.Block {
+	incidentID := incident.ID
+	channelID := incident.ChannelID
+
+	handler := "未割り当て"
+	if incident.HandlerID != "" {
+		handler = fmt.Sprintf("<@%s>", incident.HandlerID)
+	}
+
+	var timeInfo string
+	if incident.ResolvedAt != nil {
+		timeInfo = fmt.Sprintf("復旧: %s", incident.ResolvedAt.Format("01/02 15:04"))
+	} else {
+		timeInfo = fmt.Sprintf("経過: %s", formatElapsed(now.Sub(incident.CreatedAt)))
+	}
+
+	text := fmt.Sprintf(
+		"%s *#%d* %s\n%s | <#%s> | 担当: %s | %s",
+		severityEmojis[incident.Severity],
+		incidentID,
+		incident.Title,
+		statusLabel(incident.Status),
+		channelID,
+		handler,
+		timeInfo,
+	)
+
+	var accessory *slack.Accessory
+	if assignable {
+		assignButton := slack.NewButtonBlockElement(
+			"assign_handler",
+			fmt.Sprintf("incident_%d", incidentID),
+			slack.NewTextBlockObject("plain_text", "🙋 担当者になる", true, false),
+		)
+		accessory = slack.NewAccessory(assignButton)
+	} else {
+		openButton := slack.NewButtonBlockElement(
+			"open_incident_channel",
+			fmt.Sprintf("incident_%d", incidentID),
+			slack.NewTextBlockObject("plain_text", "チャンネルを開く", true, false),
+		)
+		openButton.URL = fmt.Sprintf("https://slack.com/app_redirect?channel=%s", channelID)
+		accessory = slack.NewAccessory(openButton)
+	}
+
+	return slack.NewSectionBlock(slack.NewTextBlockObject("mrkdwn", text, false, false), nil, accessory)
+}
+
+// groupIncidentsBySeverity はインシデントを重要度ごとに分類
+func groupIncidentsBySeverity(incidents []Incident) map[string][]Incident {
+	grouped := make(map[string][]Incident)
+	for _, incident := range incidents {
+		grouped[incident.Severity] = append(grouped[incident.Severity], incident)
+	}
+	return grouped
+}
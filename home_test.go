@@ -0,0 +1,247 @@
+package main
+
+import (
+	"fmt"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/slack-go/slack"
+)
+
+func testHomeIncident(id int64, severity string) Incident {
+	return Incident{
+		ID:           id,
+		Title:        "テストインシデント",
+		Severity:     severity,
+		Status:       StatusInvestigating,
+		ChannelID:    "C123",
+		ChannelName:  "incident-20250101",
+		ReporterName: "tanaka",
+		CreatedAt:    time.Date(2025, 1, 1, 10, 0, 0, 0, time.UTC),
+	}
+}
+
+func TestHomeViewers(t *testing.T) {
+	originalStore := store
+	store = nil
+	defer func() { store = originalStore }()
+
+	hv := &HomeViewers{users: make(map[string]time.Time)}
+
+	hv.add("U1")
+	hv.add("U2")
+	hv.add("U1")
+
+	if len(hv.list(time.Now())) != 2 {
+		t.Errorf("再描画対象のユーザー数が間違っています: %d, 期待値: 2", len(hv.list(time.Now())))
+	}
+
+	// しばらくApp Homeを開いていないユーザーは再描画対象から外す
+	userIDs := hv.list(time.Now().Add(homeViewerTTL + time.Hour))
+	if len(userIDs) != 0 {
+		t.Errorf("期限切れのユーザーが再描画対象に残っています: %v", userIDs)
+	}
+}
+
+func TestHomeViewersPersistedInStore(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "incidents.json")
+	memory, _ := newMemoryStore(path)
+	originalStore := store
+	store = memory
+	defer func() { store = originalStore }()
+
+	hv := &HomeViewers{users: make(map[string]time.Time)}
+	hv.add("U1")
+	hv.add("U2")
+
+	// 再起動後（別のレプリカ）も保存先に記録されたユーザーを再描画する
+	store, _ = newMemoryStore(path)
+	restarted := &HomeViewers{users: make(map[string]time.Time)}
+	restarted.add("U3")
+	userIDs := restarted.list(time.Now())
+	sort.Strings(userIDs)
+	if !reflect.DeepEqual(userIDs, []string{"U1", "U2", "U3"}) {
+		t.Errorf("再描画対象のユーザー = %v", userIDs)
+	}
+
+	// しばらく開いていないユーザーは保存先から削除する
+	deleted, err := store.PurgeHomeViewers(time.Now().Add(time.Hour))
+	if err != nil || deleted != 3 {
+		t.Errorf("PurgeHomeViewers() = %d, %v, 期待値: 3", deleted, err)
+	}
+	if userIDs, _ := store.HomeViewers(time.Time{}); len(userIDs) != 0 {
+		t.Errorf("削除後の閲覧者 = %v", userIDs)
+	}
+}
+
+func TestRefreshAppHomesCoalesced(t *testing.T) {
+	fake, api := setupE2E(t)
+	originalDelay := homeRefreshDelay
+	homeRefreshDelay = 50 * time.Millisecond
+	defer func() { homeRefreshDelay = originalDelay }()
+
+	homeViewers.add("U001")
+	homeViewers.add("U002")
+
+	// 短時間の連続した状態変化は1回の再描画にまとめる
+	for i := 0; i < 5; i++ {
+		refreshAppHomes(api)
+	}
+
+	deadline := time.Now().Add(5 * time.Second)
+	for len(fake.callsTo("views.publish")) < 2 && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	time.Sleep(100 * time.Millisecond)
+	if calls := fake.callsTo("views.publish"); len(calls) != 2 {
+		t.Errorf("views.publish の呼び出し回数 = %d, 期待値: 2（ユーザーごとに1回）", len(calls))
+	}
+}
+
+func TestGroupIncidentsBySeverity(t *testing.T) {
+	incidents := []Incident{
+		testHomeIncident(1, "low"),
+		testHomeIncident(2, "critical"),
+		testHomeIncident(3, "critical"),
+	}
+
+	grouped := groupIncidentsBySeverity(incidents)
+
+	if len(grouped["critical"]) != 2 {
+		t.Errorf("criticalの件数が間違っています: %d, 期待値: 2", len(grouped["critical"]))
+	}
+	if len(grouped["low"]) != 1 {
+		t.Errorf("lowの件数が間違っています: %d, 期待値: 1", len(grouped["low"]))
+	}
+	if len(grouped["high"]) != 0 {
+		t.Errorf("highの件数が間違っています: %d, 期待値: 0", len(grouped["high"]))
+	}
+}
+
+func TestBuildHomeBlocks(t *testing.T) {
+	now := time.Date(2025, 1, 1, 11, 30, 0, 0, time.UTC)
+
+	handling := []Incident{testHomeIncident(1, "high")}
+	open := []Incident{
+		testHomeIncident(1, "high"),
+		testHomeIncident(2, "critical"),
+	}
+	resolvedIncident := testHomeIncident(3, "low")
+	resolvedAt := time.Date(2025, 1, 1, 10, 45, 0, 0, time.UTC)
+	resolvedIncident.Status = StatusResolved
+	resolvedIncident.ResolvedAt = &resolvedAt
+	resolved := []Incident{resolvedIncident}
+
+	blocks := buildHomeBlocks(homeIncidents{
+		Handling:      handling,
+		HandlingTotal: len(handling),
+		Open:          open,
+		OpenTotal:     len(open),
+		Resolved:      resolved,
+	}, now)
+
+	// 100ブロックの上限を超えないことを確認
+	if len(blocks) > 100 {
+		t.Errorf("ブロック数が上限を超えています: %d", len(blocks))
+	}
+
+	var texts []string
+	assignButtons := 0
+	for _, block := range blocks {
+		section, ok := block.(*slack.SectionBlock)
+		if !ok || section.Text == nil {
+			continue
+		}
+		texts = append(texts, section.Text.Text)
+		if section.Accessory != nil && section.Accessory.ButtonElement != nil && section.Accessory.ButtonElement.ActionID == "assign_handler" {
+			assignButtons++
+		}
+	}
+	joined := strings.Join(texts, "\n")
+
+	for _, expected := range []string{"あなたが担当中のインシデント (1件)", "オープン中のインシデント (2件)", "最近復旧したインシデント", "経過: 1時間30分", "復旧: 01/01 10:45"} {
+		if !strings.Contains(joined, expected) {
+			t.Errorf("ダッシュボードに %q が含まれていません", expected)
+		}
+	}
+
+	// オープン中のインシデントには「担当者になる」ボタンが付く
+	if assignButtons != len(open) {
+		t.Errorf("「担当者になる」ボタンの数が間違っています: %d, 期待値: %d", assignButtons, len(open))
+	}
+
+	// 重要度の高い順に表示されることを確認
+	criticalIndex := strings.Index(joined, "#2")
+	highIndex := strings.LastIndex(joined, "#1")
+	if criticalIndex < 0 || highIndex < 0 || criticalIndex > highIndex {
+		t.Error("オープン中のインシデントが重要度順に並んでいません")
+	}
+}
+
+func TestBuildHomeBlocksEmpty(t *testing.T) {
+	blocks := buildHomeBlocks(homeIncidents{}, time.Now())
+
+	found := false
+	for _, block := range blocks {
+		if context, ok := block.(*slack.ContextBlock); ok {
+			for _, element := range context.ContextElements.Elements {
+				if text, ok := element.(*slack.TextBlockObject); ok && strings.Contains(text.Text, "現在オープンなインシデントはありません") {
+					found = true
+				}
+			}
+		}
+	}
+	if !found {
+		t.Error("インシデントがない場合のメッセージが表示されていません")
+	}
+}
+
+func TestHomeDashboardForUser(t *testing.T) {
+	var active []Incident
+	for i := int64(1); i <= homeOpenLimit+5; i++ {
+		incident := testHomeIncident(i, "high")
+		if i%2 == 0 {
+			incident.HandlerID = "U1"
+		}
+		active = append(active, incident)
+	}
+	dashboard := &homeDashboard{active: active}
+
+	incidents := dashboard.forUser("U1")
+	if len(incidents.Open) != homeOpenLimit || incidents.OpenTotal != homeOpenLimit+5 {
+		t.Errorf("オープン中のインシデント = %d件 (全 %d件)", len(incidents.Open), incidents.OpenTotal)
+	}
+	if len(incidents.Handling) != homeHandlingLimit || incidents.HandlingTotal != (homeOpenLimit+5)/2 {
+		t.Errorf("担当中のインシデント = %d件 (全 %d件)", len(incidents.Handling), incidents.HandlingTotal)
+	}
+
+	// 表示しきれない件数は全件数とあわせて表示する
+	var texts []string
+	for _, block := range buildHomeBlocks(incidents, time.Now()) {
+		switch b := block.(type) {
+		case *slack.SectionBlock:
+			texts = append(texts, b.Text.Text)
+		case *slack.ContextBlock:
+			for _, element := range b.ContextElements.Elements {
+				if text, ok := element.(*slack.TextBlockObject); ok {
+					texts = append(texts, text.Text)
+				}
+			}
+		}
+	}
+	joined := strings.Join(texts, "\n")
+	for _, expected := range []string{
+		fmt.Sprintf("オープン中のインシデント (%d件)", homeOpenLimit+5),
+		"他 5件",
+		fmt.Sprintf("あなたが担当中のインシデント (%d件)", (homeOpenLimit+5)/2),
+		fmt.Sprintf("他 %d件", (homeOpenLimit+5)/2-homeHandlingLimit),
+	} {
+		if !strings.Contains(joined, expected) {
+			t.Errorf("ダッシュボードに %q が含まれていません", expected)
+		}
+	}
+}
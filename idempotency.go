@@ -0,0 +1,101 @@
+package main
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/slack-go/slack/socketmode"
+)
+
+// processedEventTTL は処理済みイベントを記録しておく期間（Slack の再送はこの期間内に届く）
+const processedEventTTL = 24 * time.Hour
+
+// processedEventPurgeInterval は期限切れの処理済みイベントを削除する間隔
+const processedEventPurgeInterval = 1 * time.Hour
+
+// processedEventCache はデータベースを使用しない場合（または一時的に使用できない場合）の処理済みイベントの記録
+type processedEventCache struct {
+	expiresAt map[string]time.Time // イベントのキー -> 記録の有効期限
+	mu        sync.Mutex
+}
+
+var processedEvents = &processedEventCache{expiresAt: make(map[string]time.Time)}
+
+// claim はイベントを処理済みとして記録（既に記録されている場合は false）
+func (c *processedEventCache) claim(key string, now time.Time, ttl time.Duration) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if expiresAt, exists := c.expiresAt[key]; exists && now.Before(expiresAt) {
+		return false
+	}
+
+	// 期限切れの記録を削除
+	for k, expiresAt := range c.expiresAt {
+		if !now.Before(expiresAt) {
+			delete(c.expiresAt, k)
+		}
+	}
+
+	c.expiresAt[key] = now.Add(ttl)
+	return true
+}
+
+// markEventProcessed はイベントを処理済みとして記録し、初めて処理する場合は true を返す
+// Slack の再送、ボタンの連打、再接続による再配信で同じイベントを二重に処理しないために使用する
+// 複数レプリカで動かす場合や再起動後でも重複を検出できるよう、インシデントの保存先に記録する
+func markEventProcessed(key string) bool {
+	if key == "" {
+		return true
+	}
+
+	if store != nil {
+		claimed, err := claimProcessedEvent(key, processedEventTTL)
+		if err == nil {
+			return claimed
+		}
+		log.Printf("処理済みイベントの記録エラー（メモリ上で重複を確認します）: %v", err)
+	}
+
+	return processedEvents.claim(key, time.Now(), processedEventTTL)
+}
+
+// envelopeEventKey は Socket Mode のエンベロープIDから処理済みイベントのキーを作成
+func envelopeEventKey(evt socketmode.Event) string {
+	if evt.Request == nil || evt.Request.EnvelopeID == "" {
+		return ""
+	}
+	return "envelope:" + evt.Request.EnvelopeID
+}
+
+// viewSubmissionKey はモーダルのビューIDから処理済みイベントのキーを作成
+// 同じモーダルの送信が再配信された場合も同じキーになる
+func viewSubmissionKey(viewID string) string {
+	if viewID == "" {
+		return ""
+	}
+	return "view_submission:" + viewID
+}
+
+// purgeProcessedEventsLoop は期限切れの処理済みイベントを定期的に削除（リーダーのみ）
+func purgeProcessedEventsLoop(ctx context.Context) {
+	ticker := time.NewTicker(processedEventPurgeInterval)
+	defer ticker.Stop()
+
+	for {
+		deleted, err := purgeExpiredProcessedEvents()
+		if err != nil {
+			log.Printf("期限切れの処理済みイベント削除エラー: %v", err)
+		} else if deleted > 0 {
+			log.Printf("期限切れの処理済みイベント %d 件を削除しました", deleted)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
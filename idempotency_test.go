@@ -0,0 +1,73 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	"github.com/slack-go/slack/socketmode"
+)
+
+func TestProcessedEventCacheClaim(t *testing.T) {
+	cache := &processedEventCache{expiresAt: make(map[string]time.Time)}
+	now := time.Date(2025, 1, 1, 10, 0, 0, 0, time.UTC)
+
+	if !cache.claim("envelope:1", now, time.Hour) {
+		t.Fatal("初めてのイベントは処理するべきです")
+	}
+	if cache.claim("envelope:1", now.Add(time.Minute), time.Hour) {
+		t.Error("再送されたイベントは処理しないべきです")
+	}
+	if !cache.claim("envelope:2", now.Add(time.Minute), time.Hour) {
+		t.Error("別のイベントは処理するべきです")
+	}
+
+	// 有効期限が切れた記録は削除され、同じキーでも再び処理する
+	later := now.Add(2 * time.Hour)
+	if !cache.claim("envelope:1", later, time.Hour) {
+		t.Error("有効期限が切れた後は処理するべきです")
+	}
+	if _, exists := cache.expiresAt["envelope:2"]; exists {
+		t.Error("期限切れの記録は削除されるべきです")
+	}
+}
+
+func TestMarkEventProcessedWithoutDatabase(t *testing.T) {
+	originalDB := db
+	originalCache := processedEvents
+	db = nil
+	processedEvents = &processedEventCache{expiresAt: make(map[string]time.Time)}
+	defer func() {
+		db = originalDB
+		processedEvents = originalCache
+	}()
+
+	key := viewSubmissionKey("V123")
+	if !markEventProcessed(key) {
+		t.Fatal("初めてのモーダル送信は処理するべきです")
+	}
+	if markEventProcessed(key) {
+		t.Error("同じモーダルの送信は二重に処理しないべきです")
+	}
+
+	// キーが取得できない場合は重複を判定できないため処理する
+	if !markEventProcessed("") || !markEventProcessed("") {
+		t.Error("キーが空の場合は常に処理するべきです")
+	}
+}
+
+func TestEventKeys(t *testing.T) {
+	evt := socketmode.Event{Request: &socketmode.Request{EnvelopeID: "abc-123"}}
+	if key := envelopeEventKey(evt); key != "envelope:abc-123" {
+		t.Errorf("エンベロープのキーが間違っています: %s", key)
+	}
+	if key := envelopeEventKey(socketmode.Event{}); key != "" {
+		t.Errorf("リクエストがない場合は空であるべきです: %s", key)
+	}
+
+	if key := viewSubmissionKey("V123"); key != "view_submission:V123" {
+		t.Errorf("モーダル送信のキーが間違っています: %s", key)
+	}
+	if key := viewSubmissionKey(""); key != "" {
+		t.Errorf("ビューIDがない場合は空であるべきです: %s", key)
+	}
+}
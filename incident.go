@@ -91,9 +91,15 @@ func createIncidentModal(channelID string) slack.ModalViewRequest {
 }
 
 // handleModalSubmission はモーダル送信時の処理
-func handleModalSubmission(api *slack.Client, callback slack.InteractionCallback) {
+func handleModalSubmission(api SlackAPI, callback slack.InteractionCallback) {
 	log.Println("モーダル送信を受信しました")
 
+	// 同じモーダルの送信（連打や再配信）で二重にインシデントを作成しない
+	if !markEventProcessed(viewSubmissionKey(callback.View.ID)) {
+		log.Printf("モーダル %s は処理済みのため、インシデントを作成しません", callback.View.ID)
+		return
+	}
+
 	// モーダルから入力値を取得
 	values := callback.View.State.Values
 
@@ -127,11 +133,15 @@ func handleModalSubmission(api *slack.Client, callback slack.InteractionCallback
 	}
 	emoji := severityEmoji[severity]
 
+	// 報告元情報を取得（モーダルを開いたチャンネル、メッセージショートカットの場合は元メッセージ）
+	metadata := parseIncidentReportMetadata(callback.View.PrivateMetadata)
+
 	// チャンネルに報告メッセージを投稿
 	reportMessage := fmt.Sprintf(
 		"%s *インシデントが報告されました*\n\n"+
 			"*タイトル:* %s\n"+
 			"*重要度:* %s %s\n"+
+			"*ステータス:* %s\n"+
 			"*影響範囲:* %s\n"+
 			"*詳細:*\n%s\n\n"+
 			"*報告者:* <@%s>\n"+
@@ -140,21 +150,27 @@ func handleModalSubmission(api *slack.Client, callback slack.InteractionCallback
 		title,
 		emoji,
 		severity,
+		statusLabel(StatusInvestigating),
 		impact,
 		description,
 		callback.User.ID,
 		time.Now().Format("2006-01-02 15:04:05"),
 	)
 
+	// メッセージショートカットから報告された場合は元メッセージへのリンクを追加
+	if metadata.Permalink != "" {
+		reportMessage += fmt.Sprintf("\n*報告元:* <%s|元のメッセージ>", metadata.Permalink)
+	}
+
 	// チャンネルIDを取得（モーダルを開いたチャンネル）
-	channelID := callback.View.PrivateMetadata
+	channelID := metadata.ChannelID
+	threadTS := metadata.ThreadTS
 	if channelID == "" {
 		// PrivateMetadataが空の場合はユーザーのDMに送信
 		channelID = callback.User.ID
 	}
 
-	_, msgTimestamp, err := api.PostMessage(
-		channelID,
+	reportOptions := []slack.MsgOption{
 		slack.MsgOptionText(reportMessage, false),
 		slack.MsgOptionBlocks(
 			slack.NewSectionBlock(
@@ -162,7 +178,23 @@ func handleModalSubmission(api *slack.Client, callback slack.InteractionCallback
 				nil, nil,
 			),
 		),
-	)
+	}
+
+	// 元メッセージのスレッドがある場合はスレッドに投稿
+	threadOptions := reportOptions
+	if threadTS != "" {
+		threadOptions = append(threadOptions, slack.MsgOptionTS(threadTS))
+	}
+
+	_, msgTimestamp, err := api.PostMessage(channelID, threadOptions...)
+
+	// Botが参加していないチャンネル（スラッシュコマンド経由など）の場合はユーザーのDMに送信
+	if err != nil && isNotInChannelError(err) && channelID != callback.User.ID {
+		log.Printf("チャンネル %s に投稿できないため、報告者のDMに送信します: %v", channelID, err)
+		channelID = callback.User.ID
+		threadTS = ""
+		_, msgTimestamp, err = api.PostMessage(channelID, reportOptions...)
+	}
 
 	if err != nil {
 		log.Printf("メッセージ投稿エラー: %v", err)
@@ -181,61 +213,105 @@ func handleModalSubmission(api *slack.Client, callback slack.InteractionCallback
 		log.Printf("メッセージリンク: %s", messageLink)
 	}
 
+	// ユーザー情報を取得
+	reporterName := callback.User.Name
+	if user, err := api.GetUserInfo(callback.User.ID); err == nil && user.RealName != "" {
+		reporterName = user.RealName
+	}
+
+	openIncident(api, incidentReport{
+		Title:             title,
+		Severity:          severity,
+		Description:       description,
+		Impact:            impact,
+		ReporterID:        callback.User.ID,
+		ReporterName:      reporterName,
+		SourcePermalink:   metadata.Permalink,
+		Message:           reportMessage,
+		InviteUserIDs:     []string{callback.User.ID},
+		OriginChannelID:   channelID,
+		OriginThreadTS:    threadTS,
+		OriginMessageLink: messageLink,
+	})
+}
+
+// incidentReport は新しいインシデントの報告内容
+// モーダルから報告された場合もアラートから作成する場合も、openIncident で同じ流れでインシデントを作成する
+type incidentReport struct {
+	Title           string
+	Severity        string
+	Description     string
+	Impact          string
+	ReporterID      string
+	ReporterName    string
+	SourcePermalink string
+
+	Message       string   // 報告メッセージ（インシデントチャンネルと全体周知チャンネルに投稿する）
+	InviteUserIDs []string // インシデントチャンネルに招待するユーザー
+
+	OriginChannelID   string // 報告元のチャンネル（インシデントチャンネルへのリンクを投稿する、空の場合は投稿しない）
+	OriginThreadTS    string // 報告元のスレッド（リンクをスレッドに投稿する）
+	OriginMessageLink string // 報告元に投稿した報告メッセージへのリンク（全体周知に添える）
+}
+
+// openIncident は全体周知・インシデントチャンネルの作成・保存・報告の投稿・タイムキーパーの開始を行い、
+// 作成したインシデントのIDとチャンネルIDを返す（保存に失敗した場合のIDは0）
+func openIncident(api SlackAPI, report incidentReport) (int64, string, error) {
+	announce := config.Channels.EnableAnnouncement && len(config.Channels.AnnouncementChannels) > 0
+
 	// 全体周知チャンネルにも即座に報告を投稿（メッセージリンク付き）
-	if config.Channels.EnableAnnouncement && len(config.Channels.AnnouncementChannels) > 0 {
+	if announce {
 		log.Println("全体周知チャンネルにインシデント報告を投稿します")
 		// 報告元リンクを追加
-		reportMessageWithLink := reportMessage
-		if messageLink != "" {
-			reportMessageWithLink += fmt.Sprintf("\n\n📍 *インシデントは<#%s>の<%s|こちら>で報告されました*", channelID, messageLink)
+		reportMessageWithLink := report.Message
+		if report.OriginMessageLink != "" {
+			reportMessageWithLink += fmt.Sprintf("\n\n📍 *インシデントは<#%s>の<%s|こちら>で報告されました*", report.OriginChannelID, report.OriginMessageLink)
 		}
-		postToAnnouncementChannels(api, reportMessageWithLink, "", severity)
+		postToAnnouncementChannels(api, reportMessageWithLink, "", report.Severity)
 	}
 
 	// インシデント対応用チャンネルを作成
-	incidentChannel, err := createIncidentChannel(api, title, callback.User.ID)
-	var incidentChannelID string
-	var incidentID int64
+	incidentChannel, err := createIncidentChannel(api, report.Title, report.InviteUserIDs...)
 	if err != nil {
 		log.Printf("インシデントチャンネル作成エラー: %v", err)
-	} else {
-		incidentChannelID = incidentChannel.ID
-
-		// ユーザー情報を取得
-		user, err := api.GetUserInfo(callback.User.ID)
-		reporterName := callback.User.Name
-		if err == nil && user.RealName != "" {
-			reporterName = user.RealName
-		}
+		return 0, "", err
+	}
 
-		// インシデントをデータベースに保存
-		incidentID, err = saveIncident(
-			title,
-			severity,
-			description,
-			impact,
-			incidentChannel.ID,
-			incidentChannel.Name,
-			callback.User.ID,
-			reporterName,
-		)
-		if err != nil {
-			log.Printf("データベース保存エラー: %v", err)
-		}
+	// インシデントをデータベースに保存
+	incidentID, err := saveIncident(
+		report.Title,
+		report.Severity,
+		report.Description,
+		report.Impact,
+		incidentChannel.ID,
+		incidentChannel.Name,
+		report.ReporterID,
+		report.ReporterName,
+		report.SourcePermalink,
+	)
+	if err != nil {
+		log.Printf("データベース保存エラー: %v", err)
+	}
 
-		// 作成したチャンネルに報告を投稿
-		log.Printf("インシデントチャンネル %s に報告を投稿します", incidentChannel.ID)
-		postIncidentToChannel(api, incidentChannel.ID, reportMessage, channelID, incidentID)
+	// 作成したチャンネルに報告を投稿
+	log.Printf("インシデントチャンネル %s に報告を投稿します", incidentChannel.ID)
+	postIncidentToChannel(api, incidentChannel.ID, report.Message, report.OriginChannelID, report.OriginThreadTS, incidentID)
 
+	if incidentID != 0 {
 		// タイムキーパーを開始
-		timekeeperManager.startTimekeeper(api, incidentID, incidentChannel.ID, time.Now())
+		timekeeperManager.startTimekeeper(api, incidentID, incidentChannel.ID, report.Severity, time.Now())
 		log.Printf("インシデント %d のタイムキーパーを開始しました", incidentID)
+
+		refreshAppHomes(api)
+	} else {
+		// 保存できなかったインシデントはIDで操作できないため、タイムキーパーも開始せずに報告者に知らせる
+		postIncidentNotSaved(api, incidentChannel.ID, report.ReporterID)
 	}
 
 	// インシデントチャンネル作成後に、チャンネルリンク付きで全体周知を更新
-	if config.Channels.EnableAnnouncement && len(config.Channels.AnnouncementChannels) > 0 && incidentChannelID != "" {
+	if announce {
 		log.Println("全体周知チャンネルにインシデントチャンネル情報を追加投稿します")
-		channelLinkMessage := fmt.Sprintf("📋 *インシデント対応チャンネル:* <#%s>", incidentChannelID)
+		channelLinkMessage := fmt.Sprintf("📋 *インシデント対応チャンネル:* <#%s>", incidentChannel.ID)
 		for _, announcementChannelID := range config.Channels.AnnouncementChannels {
 			if announcementChannelID == "" {
 				continue
@@ -251,10 +327,25 @@ func handleModalSubmission(api *slack.Client, callback slack.InteractionCallback
 			}
 		}
 	}
+
+	return incidentID, incidentChannel.ID, nil
 }
 
-// createIncidentChannel はインシデント対応用のチャンネルを作成
-func createIncidentChannel(api *slack.Client, title string, reporterID string) (*slack.Channel, error) {
+// postIncidentNotSaved はインシデントを保存できなかったことをインシデントチャンネルで報告者に知らせる
+func postIncidentNotSaved(api SlackAPI, incidentChannelID, reporterID string) {
+	text := "⚠️ インシデントを保存できませんでした。このチャンネルでは担当者の割り当て・ステータスの変更・タイムキーパーを利用できません。\n" +
+		"データベースの状態を確認してから、もう一度報告してください。"
+	if reporterID != "" {
+		text = fmt.Sprintf("<@%s> %s", reporterID, text)
+	}
+
+	if _, _, err := api.PostMessage(incidentChannelID, slack.MsgOptionText(text, false)); err != nil {
+		log.Printf("保存失敗の通知エラー: %v", err)
+	}
+}
+
+// createIncidentChannel はインシデント対応用のチャンネルを作成し、inviteUserIDs のユーザーを招待
+func createIncidentChannel(api SlackAPI, title string, inviteUserIDs ...string) (*slack.Channel, error) {
 	// チャンネル名を生成: incident-yyyymmdd
 	now := time.Now()
 	baseChannelName := fmt.Sprintf("incident-%s", now.Format("20060102"))
@@ -286,11 +377,13 @@ func createIncidentChannel(api *slack.Client, title string, reporterID string) (
 		log.Printf("インシデントチャンネル %s (ID: %s) を作成しました", channelName, channel.ID)
 
 		// 報告者をチャンネルに招待
-		_, err = api.InviteUsersToConversation(channel.ID, reporterID)
-		if err != nil {
-			log.Printf("ユーザー招待エラー: %v", err)
-		} else {
-			log.Printf("報告者 %s をチャンネルに招待しました", reporterID)
+		if len(inviteUserIDs) > 0 {
+			_, err = api.InviteUsersToConversation(channel.ID, inviteUserIDs...)
+			if err != nil {
+				log.Printf("ユーザー招待エラー: %v", err)
+			} else {
+				log.Printf("%v をチャンネルに招待しました", inviteUserIDs)
+			}
 		}
 
 		// チャンネルのトピックを設定
@@ -307,7 +400,8 @@ func createIncidentChannel(api *slack.Client, title string, reporterID string) (
 }
 
 // postIncidentToChannel はインシデント対応チャンネルに報告とリンクを投稿
-func postIncidentToChannel(api *slack.Client, incidentChannelID string, reportMessage string, originalChannelID string, incidentID int64) {
+// originalThreadTS が指定された場合、元のチャンネルへのリンクはそのスレッドに投稿する
+func postIncidentToChannel(api SlackAPI, incidentChannelID string, reportMessage string, originalChannelID string, originalThreadTS string, incidentID int64) {
 	// ウェルカムメッセージを投稿
 	welcomeMessage := `🙏 *インシデント報告ありがとうございます！*
 
@@ -347,22 +441,35 @@ func postIncidentToChannel(api *slack.Client, incidentChannelID string, reportMe
 
 	log.Printf("インシデントチャンネル %s に報告を投稿しました", incidentChannelID)
 
-	// インシデントハンドラーボタンを投稿
-	if incidentID > 0 {
+	// インシデントハンドラーボタンを投稿（縮退運転中は負の仮ID）
+	if incidentID != 0 {
 		postHandlerButton(api, incidentChannelID, incidentID)
 		// インシデント操作ボタンを投稿
-		postIncidentActionsButton(api, incidentChannelID, incidentID)
+		postIncidentActionsButton(api, incidentChannelID, incidentID, StatusInvestigating)
+	}
+
+	// 縮退運転中は、復旧後に正式なIDで登録されることを知らせる
+	if notice := degradedNotice(); notice != "" {
+		if _, _, err := api.PostMessage(incidentChannelID, slack.MsgOptionText(notice, false)); err != nil {
+			log.Printf("縮退運転の通知エラー: %v", err)
+		}
 	}
 
 	// 障害対応に役立つ情報を投稿
 	postIncidentGuidelines(api, incidentChannelID)
 
-	// 元のチャンネルにインシデントチャンネルへのリンクを投稿
+	// 元のチャンネルにインシデントチャンネルへのリンクを投稿（アラートから作成した場合など報告元がない場合は投稿しない）
+	if originalChannelID == "" {
+		return
+	}
 	linkMessage := fmt.Sprintf("📋 インシデント対応チャンネルが作成されました: <#%s>", incidentChannelID)
-	_, _, err = api.PostMessage(
-		originalChannelID,
+	linkOptions := []slack.MsgOption{
 		slack.MsgOptionText(linkMessage, false),
-	)
+	}
+	if originalThreadTS != "" {
+		linkOptions = append(linkOptions, slack.MsgOptionTS(originalThreadTS))
+	}
+	_, _, err = api.PostMessage(originalChannelID, linkOptions...)
 
 	if err != nil {
 		log.Printf("元のチャンネルへのリンク投稿エラー: %v", err)
@@ -372,13 +479,13 @@ func postIncidentToChannel(api *slack.Client, incidentChannelID string, reportMe
 }
 
 // createUpdateIncidentModal はインシデント更新用のモーダルを作成
-func createUpdateIncidentModal(incidentID int64, currentDetails map[string]interface{}) slack.ModalViewRequest {
+func createUpdateIncidentModal(current *Incident) slack.ModalViewRequest {
 	// タイトル入力（現在の値をプレースホルダーに）
 	titleInput := slack.NewPlainTextInputBlockElement(
-		slack.NewTextBlockObject("plain_text", fmt.Sprintf("現在: %s", currentDetails["title"]), false, false),
+		slack.NewTextBlockObject("plain_text", fmt.Sprintf("現在: %s", current.Title), false, false),
 		"update_title",
 	)
-	titleInput.InitialValue = currentDetails["title"].(string)
+	titleInput.InitialValue = current.Title
 	titleBlock := slack.NewInputBlock(
 		"title_block",
 		slack.NewTextBlockObject("plain_text", "インシデントタイトル", false, false),
@@ -387,7 +494,7 @@ func createUpdateIncidentModal(incidentID int64, currentDetails map[string]inter
 	)
 
 	// 重要度選択（現在の値を初期選択に）
-	currentSeverity := currentDetails["severity"].(string)
+	currentSeverity := current.Severity
 	severityOptions := []*slack.OptionBlockObject{
 		slack.NewOptionBlockObject("critical", slack.NewTextBlockObject("plain_text", "🔴 Critical - サービス停止", false, false), nil),
 		slack.NewOptionBlockObject("high", slack.NewTextBlockObject("plain_text", "🟠 High - 重大な機能障害", false, false), nil),
@@ -425,7 +532,7 @@ func createUpdateIncidentModal(incidentID int64, currentDetails map[string]inter
 		"update_description",
 	)
 	descriptionInput.Multiline = true
-	descriptionInput.InitialValue = currentDetails["description"].(string)
+	descriptionInput.InitialValue = current.Description
 	descriptionBlock := slack.NewInputBlock(
 		"description_block",
 		slack.NewTextBlockObject("plain_text", "詳細説明", false, false),
@@ -438,7 +545,7 @@ func createUpdateIncidentModal(incidentID int64, currentDetails map[string]inter
 		slack.NewTextBlockObject("plain_text", "例: 全ユーザー、特定の機能のみ", false, false),
 		"update_impact",
 	)
-	impactInput.InitialValue = currentDetails["impact"].(string)
+	impactInput.InitialValue = current.Impact
 	impactBlock := slack.NewInputBlock(
 		"impact_block",
 		slack.NewTextBlockObject("plain_text", "影響範囲", false, false),
@@ -463,20 +570,21 @@ func createUpdateIncidentModal(incidentID int64, currentDetails map[string]inter
 		Submit:          slack.NewTextBlockObject("plain_text", "更新する", false, false),
 		Blocks:          blocks,
 		CallbackID:      "incident_update_modal",
-		PrivateMetadata: fmt.Sprintf("%d", incidentID),
+		PrivateMetadata: fmt.Sprintf("%d", current.ID),
 	}
 }
 
 // handleUpdateModalSubmission はインシデント更新モーダル送信時の処理
-func handleUpdateModalSubmission(api *slack.Client, callback slack.InteractionCallback) {
+func handleUpdateModalSubmission(api SlackAPI, callback slack.InteractionCallback) {
 	log.Println("インシデント更新モーダル送信を受信しました")
 
 	// インシデントIDを取得
 	var incidentID int64
 	fmt.Sscanf(callback.View.PrivateMetadata, "%d", &incidentID)
+	incidentID = canonicalIncidentID(incidentID)
 
 	// 現在の詳細を取得
-	currentDetails, err := getIncidentDetails(incidentID)
+	current, err := getIncidentDetails(incidentID)
 	if err != nil {
 		log.Printf("インシデント詳細取得エラー: %v", err)
 		return
@@ -497,12 +605,12 @@ func handleUpdateModalSubmission(api *slack.Client, callback slack.InteractionCa
 		updatedByName = user.RealName
 	}
 
-	channelID := currentDetails["channel_id"].(string)
+	channelID := current.ChannelID
 	var updatedFields []string
 
 	// 各フィールドをチェックして変更があれば更新
-	if newTitle != currentDetails["title"].(string) {
-		err := updateIncident(incidentID, "title", currentDetails["title"].(string), newTitle, callback.User.ID, updatedByName)
+	if newTitle != current.Title {
+		err := updateIncident(incidentID, "title", current.Title, newTitle, callback.User.ID, updatedByName)
 		if err != nil {
 			log.Printf("タイトル更新エラー: %v", err)
 		} else {
@@ -510,17 +618,19 @@ func handleUpdateModalSubmission(api *slack.Client, callback slack.InteractionCa
 		}
 	}
 
-	if newSeverity != currentDetails["severity"].(string) {
-		err := updateIncident(incidentID, "severity", currentDetails["severity"].(string), newSeverity, callback.User.ID, updatedByName)
+	if newSeverity != current.Severity {
+		err := updateIncident(incidentID, "severity", current.Severity, newSeverity, callback.User.ID, updatedByName)
 		if err != nil {
 			log.Printf("重要度更新エラー: %v", err)
 		} else {
 			updatedFields = append(updatedFields, "重要度")
+			// タイムキーパーの投稿間隔を新しい重要度に合わせる
+			timekeeperManager.setSeverity(incidentID, newSeverity)
 		}
 	}
 
-	if newDescription != currentDetails["description"].(string) {
-		err := updateIncident(incidentID, "description", currentDetails["description"].(string), newDescription, callback.User.ID, updatedByName)
+	if newDescription != current.Description {
+		err := updateIncident(incidentID, "description", current.Description, newDescription, callback.User.ID, updatedByName)
 		if err != nil {
 			log.Printf("詳細説明更新エラー: %v", err)
 		} else {
@@ -528,8 +638,8 @@ func handleUpdateModalSubmission(api *slack.Client, callback slack.InteractionCa
 		}
 	}
 
-	if newImpact != currentDetails["impact"].(string) {
-		err := updateIncident(incidentID, "impact", currentDetails["impact"].(string), newImpact, callback.User.ID, updatedByName)
+	if newImpact != current.Impact {
+		err := updateIncident(incidentID, "impact", current.Impact, newImpact, callback.User.ID, updatedByName)
 		if err != nil {
 			log.Printf("影響範囲更新エラー: %v", err)
 		} else {
@@ -542,9 +652,11 @@ func handleUpdateModalSubmission(api *slack.Client, callback slack.InteractionCa
 		updateMessage := fmt.Sprintf("📝 *インシデント情報が更新されました*\n\n"+
 			"*更新者:* <@%s>\n"+
 			"*更新項目:* %s\n"+
+			"*現在のステータス:* %s\n"+
 			"*インシデントID:* #%d",
 			callback.User.ID,
 			strings.Join(updatedFields, "、"),
+			statusLabel(current.Status),
 			incidentID,
 		)
 
@@ -564,13 +676,15 @@ func handleUpdateModalSubmission(api *slack.Client, callback slack.InteractionCa
 		} else {
 			log.Printf("インシデント %d の更新を通知しました", incidentID)
 		}
+
+		refreshAppHomes(api)
 	} else {
 		log.Printf("インシデント %d に変更はありませんでした", incidentID)
 	}
 }
 
 // postIncidentGuidelines はインシデント対応のガイドラインを投稿
-func postIncidentGuidelines(api *slack.Client, channelID string) {
+func postIncidentGuidelines(api SlackAPI, channelID string) {
 	// messages.goから取得
 	guidelinesMessage := GetIncidentGuidelines()
 
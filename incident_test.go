@@ -44,15 +44,15 @@ func TestCreateIncidentModal(t *testing.T) {
 
 func TestCreateUpdateIncidentModal(t *testing.T) {
 	// テスト用のインシデント詳細
-	incidentID := int64(123)
-	currentDetails := map[string]interface{}{
-		"title":       "テストインシデント",
-		"severity":    "high",
-		"description": "テスト詳細",
-		"impact":      "全ユーザー",
+	currentDetails := &Incident{
+		ID:          123,
+		Title:       "テストインシデント",
+		Severity:    "high",
+		Description: "テスト詳細",
+		Impact:      "全ユーザー",
 	}
 
-	modal := createUpdateIncidentModal(incidentID, currentDetails)
+	modal := createUpdateIncidentModal(currentDetails)
 
 	// モーダルの基本構造を確認
 	if modal.Type != slack.VTModal {
@@ -83,14 +83,15 @@ func TestCreateUpdateIncidentModalSeverityOptions(t *testing.T) {
 
 	for _, severity := range severities {
 		t.Run(severity, func(t *testing.T) {
-			currentDetails := map[string]interface{}{
-				"title":       "テスト",
-				"severity":    severity,
-				"description": "テスト",
-				"impact":      "テスト",
+			currentDetails := &Incident{
+				ID:          1,
+				Title:       "テスト",
+				Severity:    severity,
+				Description: "テスト",
+				Impact:      "テスト",
 			}
 
-			modal := createUpdateIncidentModal(1, currentDetails)
+			modal := createUpdateIncidentModal(currentDetails)
 
 			// 重要度ブロックを取得
 			if len(modal.Blocks.BlockSet) < 2 {
@@ -165,13 +166,14 @@ func TestIncidentModalBlockIDs(t *testing.T) {
 
 func TestUpdateModalBlockIDs(t *testing.T) {
 	// 更新モーダルのブロックIDが正しく設定されていることを確認
-	currentDetails := map[string]interface{}{
-		"title":       "テスト",
-		"severity":    "high",
-		"description": "テスト詳細",
-		"impact":      "影響範囲",
-	}
-	modal := createUpdateIncidentModal(1, currentDetails)
+	currentDetails := &Incident{
+		ID:          1,
+		Title:       "テスト",
+		Severity:    "high",
+		Description: "テスト詳細",
+		Impact:      "影響範囲",
+	}
+	modal := createUpdateIncidentModal(currentDetails)
 
 	expectedBlockIDs := []string{
 		"title_block",
@@ -0,0 +1,421 @@
+package main
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Webhook で通知するインシデントのイベント
+const (
+	webhookEventCreated         = "incident.created"
+	webhookEventSeverityChanged = "incident.severity_changed"
+	webhookEventHandlerChanged  = "incident.handler_changed"
+	webhookEventStatusChanged   = "incident.status_changed"
+	webhookEventResolved        = "incident.resolved"
+)
+
+// 配信記録の状態
+const (
+	webhookDeliveryPending   = "pending"   // 送信前・再送待ち
+	webhookDeliverySucceeded = "succeeded" // 2xx の応答を受け取った
+	webhookDeliveryFailed    = "failed"    // 最大送信回数に達した、または再送しても成功しない応答を受け取った
+)
+
+// Webhook の送信の設定のデフォルト
+const (
+	defaultWebhookMaxAttempts    = 5
+	defaultWebhookInitialBackoff = 10 * time.Second
+	defaultWebhookTimeout        = 10 * time.Second
+	maxWebhookBackoff            = 30 * time.Minute
+)
+
+// Webhook に付与するヘッダー（署名は受信する Webhook と同じ X-Signature-256）
+const (
+	webhookEventHeader     = "X-Incident-Event"
+	webhookDeliveryHeader  = "X-Incident-Delivery"
+	webhookTimestampHeader = "X-Incident-Timestamp" // 送信日時（Unix 秒、署名の対象）
+)
+
+// webhookDeliveriesLimit はコマンドで表示する配信記録の件数
+const webhookDeliveriesLimit = 20
+
+// webhookChange はイベントで変わった値（作成・復旧のイベントでは省略）
+type webhookChange struct {
+	Old string `json:"old"`
+	New string `json:"new"`
+}
+
+// webhookEvent は Webhook で送信する本文
+type webhookEvent struct {
+	ID         string         `json:"id"` // イベントID（すべての送信先で共通、再送・手動の再送でも同じ値で、受信側の重複排除に使用）
+	Event      string         `json:"event"`
+	OccurredAt time.Time      `json:"occurred_at"`
+	Actor      string         `json:"actor,omitempty"` // 操作したユーザーID（自動復旧などでは system）
+	Incident   Incident       `json:"incident"`        // イベント後のインシデント
+	Change     *webhookChange `json:"change,omitempty"`
+}
+
+// webhookRetryDelay は送信に失敗した後、次の送信までの待ち時間を取得（テストで差し替える）
+var webhookRetryDelay = webhookBackoff
+
+// webhooksEnabled は Webhook の送信先が設定されているかチェック
+func webhooksEnabled() bool {
+	return len(config.Webhooks.Endpoints) > 0
+}
+
+// name は送信先の名前を取得（未設定の場合は URL）
+func (e WebhookEndpoint) name() string {
+	if e.Name != "" {
+		return e.Name
+	}
+	return e.URL
+}
+
+// subscribes は送信先がイベントを受け取るかチェック（events が空の場合はすべて受け取る）
+func (e WebhookEndpoint) subscribes(event string) bool {
+	return len(e.Events) == 0 || slices.Contains(e.Events, event)
+}
+
+// findWebhookEndpoint は名前から送信先を取得
+func findWebhookEndpoint(name string) (WebhookEndpoint, bool) {
+	for _, endpoint := range config.Webhooks.Endpoints {
+		if endpoint.name() == name {
+			return endpoint, true
+		}
+	}
+	return WebhookEndpoint{}, false
+}
+
+// webhookMaxAttempts は失敗した場合を含めた最大送信回数を取得
+func webhookMaxAttempts() int {
+	if config.Webhooks.MaxAttempts > 0 {
+		return config.Webhooks.MaxAttempts
+	}
+	return defaultWebhookMaxAttempts
+}
+
+// webhookTimeout は1回の送信のタイムアウトを取得
+func webhookTimeout() time.Duration {
+	if config.Webhooks.TimeoutSeconds > 0 {
+		return time.Duration(config.Webhooks.TimeoutSeconds) * time.Second
+	}
+	return defaultWebhookTimeout
+}
+
+// webhookBackoff は attempts 回目の送信に失敗した後の待ち時間を取得（1回ごとに2倍、最大30分）
+func webhookBackoff(attempts int) time.Duration {
+	backoff := defaultWebhookInitialBackoff
+	if config.Webhooks.InitialBackoffSeconds > 0 {
+		backoff = time.Duration(config.Webhooks.InitialBackoffSeconds) * time.Second
+	}
+	for i := 1; i < attempts && backoff < maxWebhookBackoff; i++ {
+		backoff *= 2
+	}
+	return min(backoff, maxWebhookBackoff)
+}
+
+// retryableWebhookStatus は応答のステータスコードで再送するかチェック
+// 接続できない場合（0）・タイムアウト・レート制限・サーバーエラーは再送し、それ以外のクライアントエラーは再送しない
+func retryableWebhookStatus(code int) bool {
+	return code == 0 || code == http.StatusRequestTimeout || code == http.StatusTooManyRequests || code >= 500
+}
+
+// signWebhookPayload は送信日時と本文を "." で連結した文字列の HMAC-SHA256 署名を作成（"sha256=" の接頭辞付きの16進数）
+// 送信日時も署名することで、受信側は古い日時の Webhook を拒否して送信された内容の再利用を防げる
+func signWebhookPayload(secret, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp + "."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// emitIncidentWebhook はインシデントのイベントを、イベントを受け取る送信先ごとに配信記録を保存してから非同期で送信する
+// 縮退運転中の仮IDのインシデントは送信せず、データベースへの登録後に incident.created を送信する
+func emitIncidentWebhook(event string, incidentID int64, actor string, change *webhookChange) {
+	if !webhooksEnabled() || store == nil {
+		return
+	}
+	if incidentID < 0 {
+		log.Printf("インシデント %d は仮IDのため、%s の Webhook を送信しません", incidentID, event)
+		return
+	}
+
+	incident, err := store.GetIncident(incidentID)
+	if err != nil {
+		log.Printf("Webhook を送信するインシデント %d の取得エラー: %v", incidentID, err)
+		return
+	}
+	payload, err := json.Marshal(webhookEvent{
+		ID:         "evt_" + generateRandomString(20),
+		Event:      event,
+		OccurredAt: time.Now(),
+		Actor:      actor,
+		Incident:   *incident,
+		Change:     change,
+	})
+	if err != nil {
+		log.Printf("Webhook の本文の作成エラー: %v", err)
+		return
+	}
+
+	for _, endpoint := range config.Webhooks.Endpoints {
+		if !endpoint.subscribes(event) {
+			continue
+		}
+		delivery := WebhookDelivery{
+			IncidentID: incidentID,
+			Event:      event,
+			Endpoint:   endpoint.name(),
+			Payload:    string(payload),
+			Status:     webhookDeliveryPending,
+		}
+		// 配信記録を保存できない場合（縮退運転中など）も送信は行う
+		if deliveryID, err := store.CreateWebhookDelivery(delivery); err != nil {
+			log.Printf("配信記録の保存エラーのため、配信記録なしで %s に送信します: %v", delivery.Endpoint, err)
+		} else {
+			delivery.ID = deliveryID
+			webhookDeliveriesInFlight.claim(deliveryID)
+		}
+		go deliverWebhook(endpoint, delivery)
+	}
+}
+
+// sendWebhook は本文を送信先に1回送信し、応答のステータスコードを返す（2xx 以外はエラー）
+func sendWebhook(endpoint WebhookEndpoint, delivery WebhookDelivery) (int, error) {
+	body := []byte(delivery.Payload)
+	req, err := http.NewRequest(http.MethodPost, endpoint.URL, strings.NewReader(delivery.Payload))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "incident-response-bot")
+	req.Header.Set(webhookEventHeader, delivery.Event)
+	if delivery.ID > 0 {
+		req.Header.Set(webhookDeliveryHeader, strconv.FormatInt(delivery.ID, 10))
+	}
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+	req.Header.Set(webhookTimestampHeader, timestamp)
+	if endpoint.Secret != "" {
+		req.Header.Set(defaultWebhookHMACHeader, signWebhookPayload(endpoint.Secret, timestamp, body))
+	}
+
+	client := &http.Client{Timeout: webhookTimeout()}
+	resp, err := client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(resp.Body, maxWebhookBodySize))
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return resp.StatusCode, fmt.Errorf("HTTP %d", resp.StatusCode)
+	}
+	return resp.StatusCode, nil
+}
+
+// attemptWebhookDelivery は1回送信し、結果を配信記録に反映して保存する
+// retry が true の場合、失敗しても再送できる状態（pending）として記録する
+func attemptWebhookDelivery(endpoint WebhookEndpoint, delivery *WebhookDelivery, retry bool) error {
+	code, err := sendWebhook(endpoint, *delivery)
+	delivery.Attempts++
+	delivery.ResponseCode = code
+
+	switch {
+	case err == nil:
+		now := time.Now()
+		delivery.Status, delivery.LastError, delivery.DeliveredAt = webhookDeliverySucceeded, "", &now
+	case retry && retryableWebhookStatus(code):
+		delivery.Status, delivery.LastError = webhookDeliveryPending, err.Error()
+	default:
+		delivery.Status, delivery.LastError = webhookDeliveryFailed, err.Error()
+	}
+
+	if delivery.ID > 0 {
+		if saveErr := store.UpdateWebhookDelivery(*delivery); saveErr != nil {
+			log.Printf("配信記録 %d の更新エラー: %v", delivery.ID, saveErr)
+		}
+	}
+	return err
+}
+
+// deliverWebhook は成功するか最大送信回数に達するまで、待ち時間を倍に延ばしながら送信する
+// 再送の待機はこのプロセス内で行うため、再起動で中断した配信は pending のまま残る（redeliver で再送できる）
+// 配信記録がある場合は呼び出し元で webhookDeliveriesInFlight に登録しておくこと（送信が終わると解除する）
+func deliverWebhook(endpoint WebhookEndpoint, delivery WebhookDelivery) {
+	if delivery.ID > 0 {
+		defer webhookDeliveriesInFlight.release(delivery.ID)
+	}
+
+	maxAttempts := webhookMaxAttempts()
+	for {
+		err := attemptWebhookDelivery(endpoint, &delivery, delivery.Attempts+1 < maxAttempts)
+		if err == nil {
+			log.Printf("%s の Webhook を %s に送信しました (配信ID: %d, 送信回数: %d)", delivery.Event, delivery.Endpoint, delivery.ID, delivery.Attempts)
+			return
+		}
+		if delivery.Status == webhookDeliveryFailed {
+			log.Printf("%s の Webhook を %s に送信できませんでした (配信ID: %d, 送信回数: %d): %v", delivery.Event, delivery.Endpoint, delivery.ID, delivery.Attempts, err)
+			return
+		}
+
+		wait := webhookRetryDelay(delivery.Attempts)
+		log.Printf("%s の Webhook の %s への送信に失敗したため、%v 後に再送します (配信ID: %d): %v", delivery.Event, delivery.Endpoint, wait, delivery.ID, err)
+		time.Sleep(wait)
+	}
+}
+
+// redeliverWebhook は配信記録の本文を同じ送信先に1回再送し、更新した配信記録を返す
+// 自動の再送中の配信は、二重に送信したり送信回数・状態の更新が競合したりしないよう再送しない
+func redeliverWebhook(deliveryID int64) (*WebhookDelivery, error) {
+	if !webhookDeliveriesInFlight.claim(deliveryID) {
+		return nil, fmt.Errorf("配信 #%d は送信中のため再送できません（自動の再送が終わってから実行してください）", deliveryID)
+	}
+	defer webhookDeliveriesInFlight.release(deliveryID)
+
+	delivery, err := store.GetWebhookDelivery(deliveryID)
+	if err != nil {
+		return nil, err
+	}
+	// 別のレプリカで再送を待っている可能性がある間は再送しない（再起動で中断した配信は待ち時間が過ぎると再送できる）
+	if delivery.Status == webhookDeliveryPending {
+		if wait := time.Until(delivery.UpdatedAt.Add(webhookRetryDelay(delivery.Attempts) + webhookTimeout())); wait > 0 {
+			return nil, fmt.Errorf("配信 #%d は送信中のため再送できません（%s 後に再度実行してください）", deliveryID, formatElapsed(wait))
+		}
+	}
+	endpoint, found := findWebhookEndpoint(delivery.Endpoint)
+	if !found {
+		return nil, fmt.Errorf("送信先 %s は設定されていません", delivery.Endpoint)
+	}
+
+	if err := attemptWebhookDelivery(endpoint, delivery, false); err != nil {
+		log.Printf("配信 %d の再送エラー: %v", deliveryID, err)
+		return delivery, err
+	}
+	log.Printf("配信 %d を %s に再送しました", deliveryID, delivery.Endpoint)
+	return delivery, nil
+}
+
+// webhookDeliveriesInFlight はこのプロセスで送信中の配信ID（自動の再送と手動の再送が同時に行われないようにする）
+var webhookDeliveriesInFlight = &inFlightDeliveries{ids: make(map[int64]bool)}
+
+// inFlightDeliveries は送信中の配信IDの集合
+type inFlightDeliveries struct {
+	ids map[int64]bool
+	mu  sync.Mutex
+}
+
+// claim は配信を送信中として登録し、既に送信中の場合は false を返す
+func (d *inFlightDeliveries) claim(deliveryID int64) bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if d.ids[deliveryID] {
+		return false
+	}
+	d.ids[deliveryID] = true
+	return true
+}
+
+// release は配信の送信中の登録を解除
+func (d *inFlightDeliveries) release(deliveryID int64) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	delete(d.ids, deliveryID)
+}
+
+// webhookDeliveryStatusLabel は配信記録の状態の表示名
+func webhookDeliveryStatusLabel(status string) string {
+	switch status {
+	case webhookDeliverySucceeded:
+		return "✅ 成功"
+	case webhookDeliveryFailed:
+		return "❌ 失敗"
+	default:
+		return "⏳ 送信中"
+	}
+}
+
+// formatWebhookDeliveries はインシデントの配信記録の一覧を作成
+func formatWebhookDeliveries(incidentID int64, deliveries []WebhookDelivery) string {
+	if len(deliveries) == 0 {
+		return fmt.Sprintf("インシデント #%d の Webhook の配信記録はありません", incidentID)
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "📤 *インシデント #%d の Webhook の配信記録（新しい順）*\n", incidentID)
+	for _, delivery := range deliveries {
+		fmt.Fprintf(&b, "\n%s *#%d* `%s` → %s", webhookDeliveryStatusLabel(delivery.Status), delivery.ID, delivery.Event, delivery.Endpoint)
+		fmt.Fprintf(&b, "\n  %s・送信 %d回", delivery.CreatedAt.Local().Format("01/02 15:04"), delivery.Attempts)
+		if delivery.ResponseCode != 0 {
+			fmt.Fprintf(&b, "・HTTP %d", delivery.ResponseCode)
+		}
+		if delivery.LastError != "" && delivery.Status != webhookDeliverySucceeded {
+			fmt.Fprintf(&b, "・%s", delivery.LastError)
+		}
+	}
+	b.WriteString("\n\n`webhooks redeliver <配信ID>` で同じ本文を再送できます")
+	return b.String()
+}
+
+// handleWebhooksCommand は Webhook の配信記録を表示、または `redeliver <配信ID>` で再送する
+func handleWebhooksCommand(ctx *CommandContext, ephemeral bool) {
+	if store == nil {
+		ctx.reply("⚠️ データベース機能が無効のため、インシデント情報を取得できません。", ephemeral)
+		return
+	}
+
+	if len(ctx.Args) > 0 && (ctx.Args[0] == "redeliver" || ctx.Args[0] == "再送") {
+		redeliverWebhookCommand(ctx, ctx.Args[1:], ephemeral)
+		return
+	}
+
+	incidentID, err := resolveTargetIncident(ctx)
+	if err != nil {
+		ctx.reply(fmt.Sprintf("❌ %v", err), true)
+		return
+	}
+
+	deliveries, err := store.WebhookDeliveries(incidentID, webhookDeliveriesLimit)
+	if err != nil {
+		ctx.reply(fmt.Sprintf("❌ 配信記録の取得に失敗しました: %v", err), true)
+		return
+	}
+
+	ctx.reply(formatWebhookDeliveries(incidentID, deliveries), ephemeral)
+	log.Printf("インシデント %d の Webhook の配信記録を表示しました (%d件)", incidentID, len(deliveries))
+}
+
+// redeliverWebhookCommand は指定した配信IDの Webhook を再送し、結果を応答
+func redeliverWebhookCommand(ctx *CommandContext, args []string, ephemeral bool) {
+	if len(args) == 0 {
+		ctx.reply("❌ 再送する配信IDを指定してください（例: `webhooks redeliver 12`）", true)
+		return
+	}
+	deliveryID, err := strconv.ParseInt(strings.TrimPrefix(args[0], "#"), 10, 64)
+	if err != nil || deliveryID <= 0 {
+		ctx.reply(fmt.Sprintf("❌ 配信IDが不正です: %s", args[0]), true)
+		return
+	}
+
+	delivery, err := redeliverWebhook(deliveryID)
+	if delivery == nil {
+		ctx.reply(fmt.Sprintf("❌ %v", err), true)
+		return
+	}
+	if err != nil {
+		ctx.reply(fmt.Sprintf("❌ 配信 #%d（`%s` → %s）の再送に失敗しました: %v", deliveryID, delivery.Event, delivery.Endpoint, err), true)
+		return
+	}
+	ctx.reply(fmt.Sprintf("✅ 配信 #%d（`%s` → %s）を再送しました（HTTP %d）", deliveryID, delivery.Event, delivery.Endpoint, delivery.ResponseCode), ephemeral)
+}
@@ -0,0 +1,326 @@
+package main
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// webhookReceiver はテスト用の Webhook の送信先（受信した Webhook を記録し、statuses の順に応答する）
+type webhookReceiver struct {
+	mu       sync.Mutex
+	requests []*http.Request
+	bodies   [][]byte
+	statuses []int // 空になった後は 200 を返す
+}
+
+func newWebhookReceiver(t *testing.T, statuses ...int) (*webhookReceiver, *httptest.Server) {
+	t.Helper()
+	receiver := &webhookReceiver{statuses: statuses}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		receiver.mu.Lock()
+		defer receiver.mu.Unlock()
+		receiver.requests = append(receiver.requests, r)
+		receiver.bodies = append(receiver.bodies, body)
+		status := http.StatusOK
+		if len(receiver.statuses) > 0 {
+			status, receiver.statuses = receiver.statuses[0], receiver.statuses[1:]
+		}
+		w.WriteHeader(status)
+	}))
+	t.Cleanup(server.Close)
+	return receiver, server
+}
+
+// events は受信したイベントを受信順に返す
+func (r *webhookReceiver) events(t *testing.T) []webhookEvent {
+	t.Helper()
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var events []webhookEvent
+	for _, body := range r.bodies {
+		var event webhookEvent
+		if err := json.Unmarshal(body, &event); err != nil {
+			t.Fatalf("Webhook の本文の解析エラー: %v", err)
+		}
+		events = append(events, event)
+	}
+	return events
+}
+
+// setWebhooksConfig は Webhook の設定を差し替え、再送の待ち時間をなくす（テスト終了時に元に戻す）
+func setWebhooksConfig(t *testing.T, webhooks WebhooksConfig) {
+	t.Helper()
+	original, originalDelay := config.Webhooks, webhookRetryDelay
+	config.Webhooks = webhooks
+	webhookRetryDelay = func(int) time.Duration { return 0 }
+	t.Cleanup(func() { config.Webhooks, webhookRetryDelay = original, originalDelay })
+}
+
+// waitForWebhookDeliveries はインシデントの配信記録が count 件になり、すべての送信が終わるまで待つ
+func waitForWebhookDeliveries(t *testing.T, incidentID int64, count int) []WebhookDelivery {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		deliveries, err := store.WebhookDeliveries(incidentID, 0)
+		if err != nil {
+			t.Fatalf("WebhookDeliveries() error = %v", err)
+		}
+		done := len(deliveries) == count
+		for _, delivery := range deliveries {
+			if delivery.Status == webhookDeliveryPending {
+				done = false
+			}
+		}
+		if done {
+			return deliveries
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("配信記録 = %+v（%d件の送信が終わるべきです）", deliveries, count)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestWebhookBackoff(t *testing.T) {
+	setWebhooksConfig(t, WebhooksConfig{})
+
+	// 1回ごとに2倍に延ばし、30分を上限にする
+	for attempts, want := range map[int]time.Duration{1: 10 * time.Second, 2: 20 * time.Second, 3: 40 * time.Second, 20: maxWebhookBackoff} {
+		if got := webhookBackoff(attempts); got != want {
+			t.Errorf("webhookBackoff(%d) = %v, want %v", attempts, got, want)
+		}
+	}
+
+	config.Webhooks.InitialBackoffSeconds = 3
+	if got := webhookBackoff(2); got != 6*time.Second {
+		t.Errorf("initial_backoff_seconds = 3 の場合の webhookBackoff(2) = %v", got)
+	}
+}
+
+func TestRetryableWebhookStatus(t *testing.T) {
+	for code, want := range map[int]bool{0: true, 408: true, 429: true, 500: true, 503: true, 400: false, 401: false, 404: false} {
+		if got := retryableWebhookStatus(code); got != want {
+			t.Errorf("retryableWebhookStatus(%d) = %v, want %v", code, got, want)
+		}
+	}
+}
+
+func TestE2EIncidentWebhooksDeliverSignedEvents(t *testing.T) {
+	setupE2E(t)
+	receiver, server := newWebhookReceiver(t)
+	_, statusPage := newWebhookReceiver(t)
+	setWebhooksConfig(t, WebhooksConfig{Endpoints: []WebhookEndpoint{
+		{Name: "ticketing", URL: server.URL, Secret: "s3cret"},
+		{Name: "status-page", URL: statusPage.URL, Events: []string{webhookEventResolved}},
+	}})
+
+	incidentID, err := saveIncident("決済APIの障害", "high", "5xx が増加", "決済", "CINC", "incident-1", "U001", "報告 太郎", "")
+	if err != nil {
+		t.Fatalf("saveIncident() error = %v", err)
+	}
+	waitForWebhookDeliveries(t, incidentID, 1)
+	if err := updateIncident(incidentID, "severity", "high", "critical", "U001", "報告 太郎"); err != nil {
+		t.Fatal(err)
+	}
+	waitForWebhookDeliveries(t, incidentID, 2)
+	if err := changeHandler(incidentID, "U002", "担当 花子", "U002"); err != nil {
+		t.Fatal(err)
+	}
+	waitForWebhookDeliveries(t, incidentID, 3)
+	if _, err := changeIncidentStatus(incidentID, StatusIdentified, "U002", ""); err != nil {
+		t.Fatal(err)
+	}
+	waitForWebhookDeliveries(t, incidentID, 4)
+	// 詳細説明の更新は通知しない
+	if err := updateIncident(incidentID, "description", "5xx が増加", "DB の接続数が上限", "U002", "担当 花子"); err != nil {
+		t.Fatal(err)
+	}
+	if err := resolveIncident(incidentID, "U002", "担当 花子", "DB を再起動"); err != nil {
+		t.Fatal(err)
+	}
+	// 復旧はステータスの変更と復旧の両方を通知し、status-page は復旧だけを受け取る
+	deliveries := waitForWebhookDeliveries(t, incidentID, 7)
+
+	events := receiver.events(t)
+	if len(events) != 6 {
+		t.Fatalf("受信したイベント = %+v", events)
+	}
+	// 復旧時の2件は並行して送信するため、受信順は問わない
+	if events[4].Event == webhookEventResolved {
+		events[4], events[5] = events[5], events[4]
+	}
+	wantEvents := []string{webhookEventCreated, webhookEventSeverityChanged, webhookEventHandlerChanged, webhookEventStatusChanged, webhookEventStatusChanged, webhookEventResolved}
+	for i, want := range wantEvents {
+		if events[i].Event != want || events[i].Incident.ID != incidentID || events[i].ID == "" {
+			t.Errorf("%d件目のイベント = %+v, want %s", i+1, events[i], want)
+		}
+	}
+	if change := events[1].Change; change == nil || change.Old != "high" || change.New != "critical" || events[1].Incident.Severity != "critical" {
+		t.Errorf("重要度の変更 = %+v", events[1])
+	}
+	if change := events[2].Change; change == nil || change.Old != "" || change.New != "U002" || events[2].Actor != "U002" {
+		t.Errorf("担当者の変更 = %+v", events[2])
+	}
+	if change := events[3].Change; change == nil || change.Old != StatusInvestigating || change.New != StatusIdentified {
+		t.Errorf("ステータスの変更 = %+v", events[3])
+	}
+	if resolved := events[5]; resolved.Change != nil || resolved.Incident.Status != StatusResolved || resolved.Incident.ResolutionNote != "DB を再起動" {
+		t.Errorf("復旧 = %+v", resolved)
+	}
+
+	// 送信日時と本文の HMAC-SHA256 署名とイベント・配信IDのヘッダーを付与する
+	receiver.mu.Lock()
+	first, firstBody := receiver.requests[0], receiver.bodies[0]
+	receiver.mu.Unlock()
+	timestamp := first.Header.Get(webhookTimestampHeader)
+	if sentAt, err := strconv.ParseInt(timestamp, 10, 64); err != nil || time.Since(time.Unix(sentAt, 0)) > time.Minute {
+		t.Errorf("送信日時 = %q", timestamp)
+	}
+	if got := first.Header.Get(defaultWebhookHMACHeader); got != signWebhookPayload("s3cret", timestamp, firstBody) || got == signWebhookBody("s3cret", string(firstBody)) {
+		t.Errorf("署名 = %q", got)
+	}
+	if first.Header.Get(webhookEventHeader) != webhookEventCreated || first.Header.Get(webhookDeliveryHeader) == "" || first.Header.Get("Content-Type") != "application/json" {
+		t.Errorf("ヘッダー = %+v", first.Header)
+	}
+
+	statusPageDeliveries := 0
+	for _, delivery := range deliveries {
+		if delivery.Status != webhookDeliverySucceeded || delivery.Attempts != 1 || delivery.ResponseCode != http.StatusOK || delivery.DeliveredAt == nil {
+			t.Errorf("配信記録 = %+v", delivery)
+		}
+		if delivery.Endpoint == "status-page" {
+			statusPageDeliveries++
+			if delivery.Event != webhookEventResolved {
+				t.Errorf("status-page には復旧だけを送信するべきです: %+v", delivery)
+			}
+		}
+	}
+	if statusPageDeliveries != 1 {
+		t.Errorf("status-page への配信 = %d件", statusPageDeliveries)
+	}
+}
+
+func TestWebhookDeliveryRetries(t *testing.T) {
+	setupE2E(t)
+	incidentID, _ := saveIncident("障害", "high", "", "", "CINC", "incident-1", "U001", "報告 太郎", "")
+
+	// 再送できる失敗は成功するまで再送する
+	receiver, server := newWebhookReceiver(t, http.StatusServiceUnavailable, http.StatusTooManyRequests)
+	setWebhooksConfig(t, WebhooksConfig{Endpoints: []WebhookEndpoint{{URL: server.URL}}})
+	var waits []int
+	webhookRetryDelay = func(attempts int) time.Duration {
+		waits = append(waits, attempts)
+		return 0
+	}
+	emitIncidentWebhook(webhookEventStatusChanged, incidentID, "U001", nil)
+	deliveries := waitForWebhookDeliveries(t, incidentID, 1)
+	if delivery := deliveries[0]; delivery.Status != webhookDeliverySucceeded || delivery.Attempts != 3 || delivery.Endpoint != server.URL {
+		t.Errorf("配信記録 = %+v", delivery)
+	}
+	if len(waits) != 2 || waits[0] != 1 || waits[1] != 2 {
+		t.Errorf("再送の待ち時間の取得 = %v", waits)
+	}
+	// 再送でも同じ本文（イベントID）を送る
+	if events := receiver.events(t); len(events) != 3 || events[0].ID != events[2].ID {
+		t.Errorf("受信したイベント = %+v", events)
+	}
+
+	// 最大送信回数に達した場合は失敗にする
+	_, failing := newWebhookReceiver(t, 500, 500, 500)
+	config.Webhooks = WebhooksConfig{Endpoints: []WebhookEndpoint{{Name: "failing", URL: failing.URL}}, MaxAttempts: 2}
+	emitIncidentWebhook(webhookEventStatusChanged, incidentID, "U001", nil)
+	deliveries = waitForWebhookDeliveries(t, incidentID, 2)
+	if delivery := deliveries[0]; delivery.Status != webhookDeliveryFailed || delivery.Attempts != 2 || delivery.ResponseCode != 500 || delivery.LastError != "HTTP 500" {
+		t.Errorf("最大送信回数に達した配信記録 = %+v", delivery)
+	}
+
+	// 再送しても成功しないクライアントエラーは再送しない
+	_, rejecting := newWebhookReceiver(t, http.StatusBadRequest)
+	config.Webhooks = WebhooksConfig{Endpoints: []WebhookEndpoint{{Name: "rejecting", URL: rejecting.URL}}}
+	emitIncidentWebhook(webhookEventStatusChanged, incidentID, "U001", nil)
+	deliveries = waitForWebhookDeliveries(t, incidentID, 3)
+	if delivery := deliveries[0]; delivery.Status != webhookDeliveryFailed || delivery.Attempts != 1 {
+		t.Errorf("クライアントエラーの配信記録 = %+v", delivery)
+	}
+}
+
+func TestE2EWebhooksRedeliverCommand(t *testing.T) {
+	fake, api := setupE2E(t)
+	receiver, server := newWebhookReceiver(t, http.StatusBadGateway)
+	setWebhooksConfig(t, WebhooksConfig{Endpoints: []WebhookEndpoint{{Name: "ticketing", URL: server.URL}}, MaxAttempts: 1})
+
+	fake.addChannel("CINC", "incident-1")
+	incidentID, _ := saveIncident("障害", "high", "", "", "CINC", "incident-1", "U001", "報告 太郎", "")
+	deliveries := waitForWebhookDeliveries(t, incidentID, 1)
+	if deliveries[0].Status != webhookDeliveryFailed {
+		t.Fatalf("配信記録 = %+v", deliveries[0])
+	}
+
+	// インシデントチャンネルで配信記録を確認できる
+	handleEventsAPIEvent(api, mentionEvent("CINC", "U001", "<@UBOT> webhooks"))
+	if !fake.hasMessage("CINC", "インシデント #1 の Webhook の配信記録") || !fake.hasMessage("CINC", "HTTP 502") {
+		t.Error("配信記録の一覧が表示されるべきです")
+	}
+
+	// 失敗した配信を同じ本文で再送する
+	handleEventsAPIEvent(api, mentionEvent("CINC", "U001", "<@UBOT> webhooks redeliver 1"))
+	if !fake.hasMessage("CINC", "配信 #1（`incident.created` → ticketing）を再送しました（HTTP 200）") {
+		t.Error("再送の結果が表示されるべきです")
+	}
+	delivery, _ := store.GetWebhookDelivery(1)
+	if delivery.Status != webhookDeliverySucceeded || delivery.Attempts != 2 || delivery.LastError != "" {
+		t.Errorf("再送後の配信記録 = %+v", delivery)
+	}
+	if events := receiver.events(t); len(events) != 2 || events[0].ID != events[1].ID {
+		t.Errorf("受信したイベント = %+v", events)
+	}
+
+	// 存在しない配信・設定にない送信先は再送できない
+	handleEventsAPIEvent(api, mentionEvent("CINC", "U001", "<@UBOT> webhooks redeliver 99"))
+	config.Webhooks.Endpoints[0].Name = "renamed"
+	handleEventsAPIEvent(api, mentionEvent("CINC", "U001", "<@UBOT> webhooks redeliver 1"))
+	ephemerals := fake.callsTo("chat.postEphemeral")
+	if len(ephemerals) != 2 || !ephemerals[0].contains("配信ID 99 が見つかりません") || !ephemerals[1].contains("送信先 ticketing は設定されていません") {
+		t.Errorf("再送できない場合のメッセージ = %+v", ephemerals)
+	}
+}
+
+func TestRedeliverWebhookWhileSending(t *testing.T) {
+	setupE2E(t)
+	_, server := newWebhookReceiver(t)
+	setWebhooksConfig(t, WebhooksConfig{Endpoints: []WebhookEndpoint{{Name: "ticketing", URL: server.URL}}})
+
+	incidentID, _ := store.CreateIncident(newTestIncident("障害", "high", "CINC"))
+	deliveryID, _ := store.CreateWebhookDelivery(WebhookDelivery{IncidentID: incidentID, Event: webhookEventCreated, Endpoint: "ticketing", Payload: "{}", Status: webhookDeliveryPending})
+
+	// このプロセスで自動の再送中の配信は再送しない
+	webhookDeliveriesInFlight.claim(deliveryID)
+	if _, err := redeliverWebhook(deliveryID); err == nil || !strings.Contains(err.Error(), "送信中のため再送できません") {
+		t.Errorf("自動の再送中の redeliverWebhook() error = %v", err)
+	}
+	webhookDeliveriesInFlight.release(deliveryID)
+
+	// 別のレプリカで再送を待っている可能性がある間は再送しない
+	if _, err := redeliverWebhook(deliveryID); err == nil {
+		t.Error("最近更新した送信中の配信は再送しないべきです")
+	}
+	if delivery, _ := store.GetWebhookDelivery(deliveryID); delivery.Attempts != 0 {
+		t.Errorf("再送しない場合は送信回数を変えないべきです: %+v", delivery)
+	}
+
+	// 待ち時間が過ぎた配信（再起動で中断したものなど）は再送できる
+	webhookRetryDelay = func(int) time.Duration { return -time.Minute }
+	delivery, err := redeliverWebhook(deliveryID)
+	if err != nil || delivery.Status != webhookDeliverySucceeded || delivery.Attempts != 1 {
+		t.Errorf("redeliverWebhook() = %+v, %v", delivery, err)
+	}
+}
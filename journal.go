@@ -0,0 +1,152 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"sort"
+	"sync"
+)
+
+// defaultJournalFile はデータベースに接続できない間にインシデントを一時保存するファイル
+const defaultJournalFile = "incident-journal.json"
+
+// journalRecord はジャーナルに保存したインシデント1件と変更履歴（履歴は古い順）
+type journalRecord struct {
+	Incident       Incident
+	StatusHistory  []StatusChange
+	HandlerHistory []HandlerChange
+	UpdateHistory  []FieldUpdate
+	Alerts         []IncidentAlert
+}
+
+// journalImport はジャーナルからデータベースに登録したインシデント
+type journalImport struct {
+	JournalID  int64 // 仮ID
+	IncidentID int64 // 登録後のID
+	ChannelID  string
+}
+
+// journalImporter はジャーナルのインシデントを登録する保存先（postgresStore）
+type journalImporter interface {
+	importJournalRecord(record journalRecord) (int64, error)
+}
+
+// journalIDCache は仮IDから登録後のインシデントIDへの対応
+type journalIDCache struct {
+	mu  sync.RWMutex
+	ids map[int64]int64
+}
+
+// journalIncidentIDs は登録済みの仮IDの対応（データベースから取得したものも含む）
+var journalIncidentIDs = &journalIDCache{ids: make(map[int64]int64)}
+
+// get は仮IDに対応するインシデントIDを取得
+func (c *journalIDCache) get(journalID int64) (int64, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	incidentID, ok := c.ids[journalID]
+	return incidentID, ok
+}
+
+// set は仮IDに対応するインシデントIDを記録
+func (c *journalIDCache) set(journalID, incidentID int64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.ids[journalID] = incidentID
+}
+
+// openIncidentJournal はジャーナルを開く（path が空の場合はデフォルトのファイル）
+// ジャーナルのインシデントには PostgreSQL のIDと重ならない負の仮IDを採番する
+func openIncidentJournal(path string) (*memoryStore, error) {
+	if path == "" {
+		path = defaultJournalFile
+	}
+	journal, err := newMemoryStore(path)
+	if err != nil {
+		return nil, err
+	}
+	journal.temporaryIDs = true
+	return journal, nil
+}
+
+// journalRecords はジャーナルのインシデントと変更履歴を作成順に取得
+func (s *memoryStore) journalRecords() []journalRecord {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	records := make([]journalRecord, 0, len(s.data.Incidents))
+	for incidentID, incident := range s.data.Incidents {
+		records = append(records, journalRecord{
+			Incident:       *incident,
+			StatusHistory:  append([]StatusChange(nil), s.data.StatusHistory[incidentID]...),
+			HandlerHistory: append([]HandlerChange(nil), s.data.HandlerHistory[incidentID]...),
+			UpdateHistory:  append([]FieldUpdate(nil), s.data.UpdateHistory[incidentID]...),
+			Alerts:         append([]IncidentAlert(nil), s.data.Alerts[incidentID]...),
+		})
+	}
+	sort.Slice(records, func(i, j int) bool {
+		return records[i].Incident.CreatedAt.Before(records[j].Incident.CreatedAt)
+	})
+	return records
+}
+
+// removeIncident はインシデントと変更履歴を削除
+func (s *memoryStore) removeIncident(incidentID int64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.data.Incidents, incidentID)
+	delete(s.data.StatusHistory, incidentID)
+	delete(s.data.HandlerHistory, incidentID)
+	delete(s.data.UpdateHistory, incidentID)
+	delete(s.data.Alerts, incidentID)
+	return s.save()
+}
+
+// backfillIncidentJournal はジャーナルのインシデントをデータベースに登録し、登録したものをジャーナルから削除
+// 途中で失敗した場合は登録済みのものを返し、残りはジャーナルに残す（次回の登録時に続きから登録する）
+func backfillIncidentJournal(importer journalImporter, journal *memoryStore) ([]journalImport, error) {
+	var imported []journalImport
+	for _, record := range journal.journalRecords() {
+		journalID := record.Incident.ID
+		incidentID, err := importer.importJournalRecord(record)
+		if err != nil {
+			return imported, fmt.Errorf("仮ID %d のインシデント登録エラー: %v", journalID, err)
+		}
+
+		journalIncidentIDs.set(journalID, incidentID)
+		imported = append(imported, journalImport{JournalID: journalID, IncidentID: incidentID, ChannelID: record.Incident.ChannelID})
+		log.Printf("ジャーナルの仮ID %d のインシデントを ID %d として登録しました", journalID, incidentID)
+
+		if err := journal.removeIncident(journalID); err != nil {
+			return imported, err
+		}
+	}
+	return imported, nil
+}
+
+// canonicalIncidentID は仮IDを登録後のインシデントIDに変換（仮IDでない場合・未登録の場合はそのまま返す）
+// 縮退運転中に投稿したボタンには仮IDが埋め込まれているため、ボタン・モーダルから取得したIDはこの関数を通す
+func canonicalIncidentID(incidentID int64) int64 {
+	if incidentID >= 0 {
+		return incidentID
+	}
+	if registered, ok := journalIncidentIDs.get(incidentID); ok {
+		return registered
+	}
+	if store == nil {
+		return incidentID
+	}
+
+	// ほかのレプリカや再起動前に登録した仮IDはデータベースから取得
+	registered, err := lookupJournalIncidentID(incidentID)
+	if err != nil {
+		log.Printf("仮ID %d の登録後のID取得エラー: %v", incidentID, err)
+		return incidentID
+	}
+	if registered == 0 {
+		return incidentID
+	}
+	journalIncidentIDs.set(incidentID, registered)
+	return registered
+}
@@ -0,0 +1,158 @@
+package main
+
+import (
+	"fmt"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// testJournalImporter はジャーナルのインシデントをメモリ上の保存先に登録する journalImporter
+type testJournalImporter struct {
+	target   *memoryStore
+	failOn   int64 // この仮IDの登録を失敗させる
+	imported []journalRecord
+}
+
+func (i *testJournalImporter) importJournalRecord(record journalRecord) (int64, error) {
+	if record.Incident.ID == i.failOn {
+		return 0, fmt.Errorf("接続が切れました")
+	}
+	i.imported = append(i.imported, record)
+
+	incident := record.Incident
+	return i.target.CreateIncident(NewIncident{
+		Title:        incident.Title,
+		Severity:     incident.Severity,
+		Description:  incident.Description,
+		Impact:       incident.Impact,
+		ChannelID:    incident.ChannelID,
+		ChannelName:  incident.ChannelName,
+		ReporterID:   incident.ReporterID,
+		ReporterName: incident.ReporterName,
+	})
+}
+
+// resetJournalIncidentIDs は仮IDの対応を空にする（テスト終了時に元に戻す）
+func resetJournalIncidentIDs(t *testing.T) {
+	t.Helper()
+	original := journalIncidentIDs
+	journalIncidentIDs = &journalIDCache{ids: make(map[int64]int64)}
+	t.Cleanup(func() { journalIncidentIDs = original })
+}
+
+func TestIncidentJournalTemporaryIDs(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "journal.json")
+	journal, err := openIncidentJournal(path)
+	if err != nil {
+		t.Fatalf("openIncidentJournal() error = %v", err)
+	}
+
+	first, err := journal.CreateIncident(newTestIncident("DB障害", "high", "C001"))
+	if err != nil {
+		t.Fatalf("CreateIncident() error = %v", err)
+	}
+	second, _ := journal.CreateIncident(newTestIncident("API遅延", "medium", "C002"))
+	if first >= 0 || second >= 0 {
+		t.Errorf("ジャーナルのインシデントには負の仮IDを採番するべきです: %d, %d", first, second)
+	}
+	if second >= first {
+		t.Errorf("仮IDは作成順に小さくなるべきです: %d, %d", first, second)
+	}
+
+	// 再起動後も採番済みの仮IDと重ならない
+	reopened, err := openIncidentJournal(path)
+	if err != nil {
+		t.Fatalf("openIncidentJournal() error = %v", err)
+	}
+	third, _ := reopened.CreateIncident(newTestIncident("ログイン不可", "critical", "C003"))
+	if third >= second {
+		t.Errorf("再起動後の仮IDが採番済みの仮IDと重なっています: %d, %d", second, third)
+	}
+
+	// 通常のメモリ上の保存先は連番のまま
+	memory, _ := newMemoryStore("")
+	if id, _ := memory.CreateIncident(newTestIncident("DB障害", "high", "C001")); id != 1 {
+		t.Errorf("メモリ上の保存先のIDは1から採番するべきです: %d", id)
+	}
+}
+
+func TestBackfillIncidentJournal(t *testing.T) {
+	resetJournalIncidentIDs(t)
+
+	journal, err := openIncidentJournal(filepath.Join(t.TempDir(), "journal.json"))
+	if err != nil {
+		t.Fatalf("openIncidentJournal() error = %v", err)
+	}
+	first, _ := journal.CreateIncident(newTestIncident("DB障害", "high", "C001"))
+	time.Sleep(2 * time.Millisecond)
+	second, _ := journal.CreateIncident(newTestIncident("API遅延", "medium", "C002"))
+	if _, err := journal.ChangeStatus(first, StatusResolved, "U002", "再起動で復旧"); err != nil {
+		t.Fatalf("ChangeStatus() error = %v", err)
+	}
+
+	target, _ := newMemoryStore("")
+	importer := &testJournalImporter{target: target, failOn: second}
+
+	// 途中で失敗した場合は登録済みのものだけをジャーナルから削除する
+	imported, err := backfillIncidentJournal(importer, journal)
+	if err == nil {
+		t.Error("登録に失敗した場合はエラーを返すべきです")
+	}
+	if len(imported) != 1 || imported[0].JournalID != first || imported[0].IncidentID != 1 || imported[0].ChannelID != "C001" {
+		t.Fatalf("backfillIncidentJournal() = %+v", imported)
+	}
+	if len(importer.imported[0].StatusHistory) != 2 || importer.imported[0].Incident.Status != StatusResolved {
+		t.Errorf("変更履歴とともに登録するべきです: %+v", importer.imported[0])
+	}
+	if _, err := journal.GetIncident(first); err == nil {
+		t.Error("登録したインシデントはジャーナルから削除するべきです")
+	}
+	if _, err := journal.GetIncident(second); err != nil {
+		t.Error("登録できなかったインシデントはジャーナルに残すべきです")
+	}
+
+	// 次回は残りを登録する
+	importer.failOn = 0
+	imported, err = backfillIncidentJournal(importer, journal)
+	if err != nil {
+		t.Fatalf("backfillIncidentJournal() error = %v", err)
+	}
+	if len(imported) != 1 || imported[0].JournalID != second || imported[0].IncidentID != 2 {
+		t.Errorf("backfillIncidentJournal() = %+v", imported)
+	}
+	if records := journal.journalRecords(); len(records) != 0 {
+		t.Errorf("すべて登録した後はジャーナルが空になるべきです: %+v", records)
+	}
+
+	// 仮IDは登録後のIDに変換される
+	if got := canonicalIncidentID(first); got != 1 {
+		t.Errorf("canonicalIncidentID(%d) = %d, want 1", first, got)
+	}
+	if got, err := parseIncidentActionValue(fmt.Sprintf("incident_%d", second)); err != nil || got != 2 {
+		t.Errorf("parseIncidentActionValue() = %d, %v, want 2", got, err)
+	}
+}
+
+func TestCanonicalIncidentID(t *testing.T) {
+	resetJournalIncidentIDs(t)
+	originalDB := db
+	db = nil
+	defer func() { db = originalDB }()
+
+	// 通常のIDと未登録の仮IDはそのまま
+	for _, id := range []int64{1, 42, -1700000000000} {
+		if got := canonicalIncidentID(id); got != id {
+			t.Errorf("canonicalIncidentID(%d) = %d", id, got)
+		}
+	}
+
+	journalIncidentIDs.set(-1700000000000, 7)
+	if got := canonicalIncidentID(-1700000000000); got != 7 {
+		t.Errorf("canonicalIncidentID() = %d, want 7", got)
+	}
+
+	if _, err := parseIncidentActionValue("open_modal"); err == nil {
+		t.Error("インシデントIDを含まないValueはエラーになるべきです")
+	}
+}
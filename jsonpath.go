@@ -0,0 +1,150 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// jsonPathStep は JSONPath の1段分（オブジェクトのキーまたは配列の添字）
+type jsonPathStep struct {
+	key     string
+	index   int
+	isIndex bool
+}
+
+// parseJSONPath は JSONPath 形式の場所を解析
+// 対応するのはキーと添字の組み合わせのみ（例: "$.data.items[0].name"、"$['key.with.dot']"、"data.name"）
+func parseJSONPath(path string) ([]jsonPathStep, error) {
+	rest := strings.TrimPrefix(strings.TrimSpace(path), "$")
+	var steps []jsonPathStep
+
+	for i := 0; i < len(rest); {
+		switch {
+		case rest[i] == '[':
+			end := strings.IndexByte(rest[i:], ']')
+			if end < 0 {
+				return nil, fmt.Errorf("JSONPath %q の [ が閉じられていません", path)
+			}
+			inner := rest[i+1 : i+end]
+			i += end + 1
+
+			if len(inner) >= 2 && (inner[0] == '\'' || inner[0] == '"') && inner[len(inner)-1] == inner[0] {
+				steps = append(steps, jsonPathStep{key: inner[1 : len(inner)-1]})
+				continue
+			}
+			index, err := strconv.Atoi(inner)
+			if err != nil || index < 0 {
+				return nil, fmt.Errorf("JSONPath %q の添字 %q が不正です", path, inner)
+			}
+			steps = append(steps, jsonPathStep{index: index, isIndex: true})
+
+		default:
+			if rest[i] == '.' {
+				i++
+			}
+			end := strings.IndexAny(rest[i:], ".[")
+			if end < 0 {
+				end = len(rest) - i
+			}
+			if end == 0 {
+				return nil, fmt.Errorf("JSONPath %q のキーが空です", path)
+			}
+			steps = append(steps, jsonPathStep{key: rest[i : i+end]})
+			i += end
+		}
+	}
+	return steps, nil
+}
+
+// lookupJSONPath は JSON の値から JSONPath の場所の値を取り出す（見つからない場合は false）
+func lookupJSONPath(data interface{}, path string) (interface{}, bool) {
+	steps, err := parseJSONPath(path)
+	if err != nil {
+		return nil, false
+	}
+
+	current := data
+	for _, step := range steps {
+		if step.isIndex {
+			list, ok := current.([]interface{})
+			if !ok || step.index >= len(list) {
+				return nil, false
+			}
+			current = list[step.index]
+			continue
+		}
+		object, ok := current.(map[string]interface{})
+		if !ok {
+			return nil, false
+		}
+		if current, ok = object[step.key]; !ok {
+			return nil, false
+		}
+	}
+	return current, true
+}
+
+// decodeJSON は JSON を数値の桁を落とさずに解析（数値は json.Number になる）
+func decodeJSON(body []byte) (interface{}, error) {
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	decoder.UseNumber()
+	var data interface{}
+	if err := decoder.Decode(&data); err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+// jsonString は JSON の値を文字列に変換（オブジェクトと配列は JSON のまま、null は空文字列）
+func jsonString(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case json.Number:
+		return v.String()
+	case bool:
+		return strconv.FormatBool(v)
+	default:
+		encoded, err := json.Marshal(v)
+		if err != nil {
+			return fmt.Sprint(v)
+		}
+		return string(encoded)
+	}
+}
+
+// jsonTime は JSON の値を日時に変換（RFC 3339 の文字列、または UNIX 時間の秒・ミリ秒）
+func jsonTime(value interface{}) (time.Time, bool) {
+	text := strings.TrimSpace(jsonString(value))
+	if text == "" {
+		return time.Time{}, false
+	}
+	if t, err := time.Parse(time.RFC3339, text); err == nil {
+		return t, true
+	}
+
+	unix, err := strconv.ParseFloat(text, 64)
+	if err != nil || unix <= 0 {
+		return time.Time{}, false
+	}
+	// 13桁以上はミリ秒とみなす
+	if unix >= 1e12 {
+		return time.UnixMilli(int64(unix)), true
+	}
+	return time.Unix(int64(unix), 0), true
+}
+
+// lookupJSONString は JSONPath の場所の値を文字列で取り出す（path が空・見つからない場合は空文字列）
+func lookupJSONString(data interface{}, path string) string {
+	if path == "" {
+		return ""
+	}
+	value, _ := lookupJSONPath(data, path)
+	return jsonString(value)
+}
@@ -0,0 +1,55 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func TestLookupJSONPath(t *testing.T) {
+	data, err := decodeJSON([]byte(`{"data": {"items": [{"name": "first", "id": 12345678901234567890}], "key.with.dot": true}}`))
+	if err != nil {
+		t.Fatalf("decodeJSON() error = %v", err)
+	}
+
+	tests := []struct {
+		path  string
+		want  string
+		found bool
+	}{
+		{"$.data.items[0].name", "first", true},
+		{"data.items[0].name", "first", true},
+		{"$.data['key.with.dot']", "true", true},
+		{"$.data.items[0].id", "12345678901234567890", true},
+		{"$.data.items[1].name", "", false},
+		{"$.data.missing", "", false},
+		{"$.data.items.name", "", false},
+	}
+	for _, tt := range tests {
+		value, found := lookupJSONPath(data, tt.path)
+		if found != tt.found || jsonString(value) != tt.want {
+			t.Errorf("lookupJSONPath(%q) = %v, %v, want %q, %v", tt.path, value, found, tt.want, tt.found)
+		}
+	}
+
+	for _, path := range []string{"$.data[", "$.items[-1]", "$..name"} {
+		if _, err := parseJSONPath(path); err == nil {
+			t.Errorf("parseJSONPath(%q) はエラーになるべきです", path)
+		}
+	}
+}
+
+func TestJSONTime(t *testing.T) {
+	want := time.Date(2025, 1, 1, 1, 0, 0, 0, time.UTC)
+	for _, value := range []string{`"2025-01-01T01:00:00Z"`, `1735693200`, `1735693200000`, `"1735693200"`} {
+		data, _ := decodeJSON([]byte(value))
+		if got, ok := jsonTime(data); !ok || !got.Equal(want) {
+			t.Errorf("jsonTime(%s) = %v, %v", value, got, ok)
+		}
+	}
+	for _, value := range []string{`null`, `"yesterday"`, `0`} {
+		data, _ := decodeJSON([]byte(value))
+		if _, ok := jsonTime(data); ok {
+			t.Errorf("jsonTime(%s) は変換できないべきです", value)
+		}
+	}
+}
@@ -0,0 +1,198 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"log"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// defaultLeaderLockID はリーダー選出に使う PostgreSQL のアドバイザリロックのキー
+const defaultLeaderLockID int64 = 72_617_311
+
+// defaultLeaderCheckInterval はロックの取得・保持を確認する間隔
+const defaultLeaderCheckInterval = 5 * time.Second
+
+// LeaderElector は PostgreSQL のアドバイザリロックでリーダーを選出する
+// ロックは専用のコネクションで保持し、コネクションが切れるとロックも解放されてほかのレプリカが引き継ぐ
+type LeaderElector struct {
+	db        *sql.DB
+	lockID    int64
+	interval  time.Duration
+	onElected func(ctx context.Context) // リーダーに選出された時に呼ばれる（ctx はリーダーでなくなるとキャンセルされる）
+
+	leader atomic.Bool
+	mu     sync.Mutex
+	conn   *sql.Conn
+	cancel context.CancelFunc
+}
+
+// leaderElector はこのプロセスのリーダー選出（nil の場合は単一レプリカとして常にリーダー）
+var leaderElector *LeaderElector
+
+// isLeader はこのレプリカがバックグラウンド処理を担当するリーダーかチェック
+func isLeader() bool {
+	if leaderElector == nil {
+		return true
+	}
+	return leaderElector.isLeader()
+}
+
+// newLeaderElector は設定からリーダー選出を作成
+func newLeaderElector(database *sql.DB, onElected func(ctx context.Context)) *LeaderElector {
+	lockID := config.LeaderElection.LockID
+	if lockID == 0 {
+		lockID = defaultLeaderLockID
+	}
+	interval := defaultLeaderCheckInterval
+	if config.LeaderElection.CheckIntervalSeconds > 0 {
+		interval = time.Duration(config.LeaderElection.CheckIntervalSeconds) * time.Second
+	}
+
+	return &LeaderElector{
+		db:        database,
+		lockID:    lockID,
+		interval:  interval,
+		onElected: onElected,
+	}
+}
+
+// isLeader はリーダーとしてロックを保持しているかチェック
+func (le *LeaderElector) isLeader() bool {
+	return le.leader.Load()
+}
+
+// run はロックの取得と保持の確認を繰り返す（ctx がキャンセルされるとロックを解放して終了）
+func (le *LeaderElector) run(ctx context.Context) {
+	log.Printf("リーダー選出を開始します (ロックID: %d, 確認間隔: %v)", le.lockID, le.interval)
+
+	ticker := time.NewTicker(le.interval)
+	defer ticker.Stop()
+
+	for {
+		if le.isLeader() {
+			le.checkLeadership(ctx)
+		} else {
+			le.tryAcquire(ctx)
+		}
+
+		select {
+		case <-ctx.Done():
+			le.stepDown("シャットダウン")
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// tryAcquire はアドバイザリロックの取得を試み、取得できればリーダーとしての処理を開始
+func (le *LeaderElector) tryAcquire(ctx context.Context) {
+	conn, err := le.db.Conn(ctx)
+	if err != nil {
+		log.Printf("リーダー選出用のコネクション取得エラー: %v", err)
+		return
+	}
+
+	var acquired bool
+	if err := conn.QueryRowContext(ctx, "SELECT pg_try_advisory_lock($1)", le.lockID).Scan(&acquired); err != nil {
+		log.Printf("アドバイザリロック取得エラー: %v", err)
+		conn.Close()
+		return
+	}
+	if !acquired {
+		conn.Close()
+		return
+	}
+
+	workCtx, cancel := context.WithCancel(ctx)
+
+	le.mu.Lock()
+	le.conn = conn
+	le.cancel = cancel
+	le.mu.Unlock()
+	le.leader.Store(true)
+
+	log.Println("リーダーに選出されました。バックグラウンド処理を開始します")
+	if le.onElected != nil {
+		le.onElected(workCtx)
+	}
+}
+
+// checkLeadership はロックを保持しているコネクションが生きているか確認し、切れていればリーダーを降りる
+func (le *LeaderElector) checkLeadership(ctx context.Context) {
+	le.mu.Lock()
+	conn := le.conn
+	le.mu.Unlock()
+
+	if conn == nil {
+		le.stepDown("コネクションなし")
+		return
+	}
+	if err := conn.PingContext(ctx); err != nil {
+		le.stepDown(err.Error())
+	}
+}
+
+// stepDown はリーダーを降りてバックグラウンド処理を止め、ロックを保持していたコネクションを破棄する
+func (le *LeaderElector) stepDown(reason string) {
+	le.mu.Lock()
+	conn := le.conn
+	cancel := le.cancel
+	le.conn = nil
+	le.cancel = nil
+	le.mu.Unlock()
+
+	if !le.leader.Swap(false) {
+		return
+	}
+
+	if cancel != nil {
+		cancel()
+	}
+	if conn != nil {
+		// ロックを保持したままコネクションプールに戻らないよう、コネクションごと破棄する
+		conn.Raw(func(driverConn interface{}) error {
+			return driver.ErrBadConn
+		})
+		conn.Close()
+	}
+	log.Printf("リーダーを降りました (理由: %s)", reason)
+}
+
+// startBackgroundWork はタイムキーパー・アクションアイテムのリマインド・処理済みイベントの削除を開始
+// 複数レプリカで動かす場合でも、これらの処理はリーダーに選出されたレプリカだけで実行する
+func startBackgroundWork(api SlackAPI) {
+	// 稼働中にデータベースに接続できなくなって復旧した場合は既に開始しているため、二重に開始しない
+	backgroundWorkOnce.Do(func() { runBackgroundWork(api) })
+}
+
+// backgroundWorkOnce はバックグラウンド処理を一度だけ開始するためのもの
+var backgroundWorkOnce sync.Once
+
+// runBackgroundWork はバックグラウンド処理を開始（startBackgroundWork から一度だけ呼び出す）
+func runBackgroundWork(api SlackAPI) {
+	work := func(ctx context.Context) {
+		go timekeeperManager.syncLoop(ctx, api)
+		startAlertAutoResolver(ctx, api)
+		startActionItemReminder(ctx, api)
+		go purgeProcessedEventsLoop(ctx)
+		go purgeHomeViewersLoop(ctx)
+	}
+
+	// PostgreSQL を使用しない場合は単一レプリカとして動かす
+	if db == nil {
+		work(context.Background())
+		return
+	}
+	if config.LeaderElection.Disable {
+		log.Println("リーダー選出は無効です。このレプリカでバックグラウンド処理を実行します")
+		work(context.Background())
+		return
+	}
+
+	leaderElector = newLeaderElector(db, work)
+	go leaderElector.run(context.Background())
+}
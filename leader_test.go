@@ -0,0 +1,47 @@
+package main
+
+import "testing"
+
+func TestIsLeader(t *testing.T) {
+	original := leaderElector
+	defer func() { leaderElector = original }()
+
+	// リーダー選出を行わない場合（データベースなし・単一レプリカ）は常にリーダー
+	leaderElector = nil
+	if !isLeader() {
+		t.Error("リーダー選出がない場合はリーダーとして扱うべきです")
+	}
+
+	leaderElector = &LeaderElector{}
+	if isLeader() {
+		t.Error("ロックを取得するまではリーダーではないべきです")
+	}
+
+	leaderElector.leader.Store(true)
+	if !isLeader() {
+		t.Error("ロックを取得した後はリーダーであるべきです")
+	}
+
+	// リーダーを降りると以降はリーダーではない
+	leaderElector.stepDown("テスト")
+	if isLeader() {
+		t.Error("リーダーを降りた後はリーダーではないべきです")
+	}
+}
+
+func TestNewLeaderElector(t *testing.T) {
+	originalConfig := config
+	defer func() { config = originalConfig }()
+
+	config.LeaderElection = LeaderElectionConfig{}
+	le := newLeaderElector(nil, nil)
+	if le.lockID != defaultLeaderLockID || le.interval != defaultLeaderCheckInterval {
+		t.Errorf("デフォルト値が間違っています: lockID=%d, interval=%v", le.lockID, le.interval)
+	}
+
+	config.LeaderElection = LeaderElectionConfig{LockID: 42, CheckIntervalSeconds: 3}
+	le = newLeaderElector(nil, nil)
+	if le.lockID != 42 || le.interval.Seconds() != 3 {
+		t.Errorf("設定値が反映されていません: lockID=%d, interval=%v", le.lockID, le.interval)
+	}
+}
@@ -0,0 +1,127 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+// インシデントのステータス
+const (
+	StatusInvestigating = "investigating" // 調査中
+	StatusIdentified    = "identified"    // 原因特定
+	StatusMitigated     = "mitigated"     // 暫定対応済み
+	StatusMonitoring    = "monitoring"    // 経過観察中
+	StatusResolved      = "resolved"      // 復旧済み
+	StatusPostmortem    = "postmortem"    // ポストモーテム実施中
+	StatusClosed        = "closed"        // クローズ
+
+	// statusLegacyOpen は状態遷移導入前のステータス（調査中として扱う）
+	statusLegacyOpen = "open"
+)
+
+// activeStatuses は対応中（未復旧）とみなすステータス
+var activeStatuses = []string{
+	statusLegacyOpen,
+	StatusInvestigating,
+	StatusIdentified,
+	StatusMitigated,
+	StatusMonitoring,
+}
+
+// finishedStatuses は復旧済み以降のステータス
+var finishedStatuses = []string{
+	StatusResolved,
+	StatusPostmortem,
+	StatusClosed,
+}
+
+// statusTransitions は各ステータスから遷移可能なステータス（ボタンの表示順）
+var statusTransitions = map[string][]string{
+	StatusInvestigating: {StatusIdentified, StatusMitigated, StatusMonitoring, StatusResolved},
+	StatusIdentified:    {StatusMitigated, StatusMonitoring, StatusResolved},
+	StatusMitigated:     {StatusMonitoring, StatusResolved},
+	StatusMonitoring:    {StatusInvestigating, StatusResolved},
+	StatusResolved:      {StatusPostmortem, StatusClosed},
+	StatusPostmortem:    {StatusClosed},
+	StatusClosed:        {},
+}
+
+// statusLabels はステータスの表示名
+var statusLabels = map[string]string{
+	StatusInvestigating: "🔍 調査中",
+	StatusIdentified:    "🎯 原因特定",
+	StatusMitigated:     "🩹 暫定対応済み",
+	StatusMonitoring:    "👀 経過観察中",
+	StatusResolved:      "✅ 復旧済み",
+	StatusPostmortem:    "📝 ポストモーテム",
+	StatusClosed:        "🔒 クローズ",
+}
+
+// normalizeStatus は旧形式のステータスを現在のステータスに変換
+func normalizeStatus(status string) string {
+	if status == statusLegacyOpen || status == "" {
+		return StatusInvestigating
+	}
+	return status
+}
+
+// isActiveStatus はステータスが対応中（未復旧）かどうかを判定
+func isActiveStatus(status string) bool {
+	for _, s := range activeStatuses {
+		if s == status {
+			return true
+		}
+	}
+	return false
+}
+
+// nextStatuses は現在のステータスから遷移可能なステータスを返す
+func nextStatuses(current string) []string {
+	return statusTransitions[normalizeStatus(current)]
+}
+
+// canTransition はステータス遷移が許可されているかを判定
+func canTransition(from, to string) bool {
+	for _, s := range nextStatuses(from) {
+		if s == to {
+			return true
+		}
+	}
+	return false
+}
+
+// validateTransition はステータス遷移を検証し、許可されていない場合はエラーを返す
+func validateTransition(from, to string) error {
+	if _, ok := statusTransitions[to]; !ok {
+		return fmt.Errorf("不明なステータスです: %s", to)
+	}
+	if !canTransition(from, to) {
+		return fmt.Errorf("%s から %s には変更できません", statusLabel(from), statusLabel(to))
+	}
+	return nil
+}
+
+// statusLabel はステータスの表示名を返す
+func statusLabel(status string) string {
+	if label, ok := statusLabels[normalizeStatus(status)]; ok {
+		return label
+	}
+	return status
+}
+
+// statusActionID はステータス変更ボタンのアクションIDを返す
+func statusActionID(status string) string {
+	return "change_status_" + status
+}
+
+// parseStatusActionID はステータス変更ボタンのアクションIDから変更先のステータスを取得
+func parseStatusActionID(actionID string) (string, bool) {
+	if !strings.HasPrefix(actionID, "change_status_") {
+		return "", false
+	}
+	status := strings.TrimPrefix(actionID, "change_status_")
+	if _, ok := statusTransitions[status]; !ok {
+		return "", false
+	}
+	return status, true
+}
@@ -0,0 +1,114 @@
+package main
+
+import (
+	"testing"
+)
+
+func TestCanTransition(t *testing.T) {
+	tests := []struct {
+		from     string
+		to       string
+		expected bool
+	}{
+		{StatusInvestigating, StatusIdentified, true},
+		{StatusInvestigating, StatusResolved, true},
+		{StatusIdentified, StatusMitigated, true},
+		{StatusMitigated, StatusMonitoring, true},
+		{StatusMonitoring, StatusInvestigating, true},
+		{StatusMonitoring, StatusResolved, true},
+		{StatusResolved, StatusPostmortem, true},
+		{StatusResolved, StatusClosed, true},
+		{StatusPostmortem, StatusClosed, true},
+		// 旧ステータスは調査中として扱う
+		{statusLegacyOpen, StatusIdentified, true},
+		{statusLegacyOpen, StatusResolved, true},
+		// 許可されていない遷移
+		{StatusInvestigating, StatusInvestigating, false},
+		{StatusInvestigating, StatusPostmortem, false},
+		{StatusIdentified, StatusInvestigating, false},
+		{StatusResolved, StatusInvestigating, false},
+		{StatusPostmortem, StatusResolved, false},
+		{StatusClosed, StatusInvestigating, false},
+		{StatusClosed, StatusPostmortem, false},
+		{StatusInvestigating, "unknown", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.from+"->"+tt.to, func(t *testing.T) {
+			if result := canTransition(tt.from, tt.to); result != tt.expected {
+				t.Errorf("canTransition(%q, %q) = %v, want %v", tt.from, tt.to, result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestValidateTransition(t *testing.T) {
+	if err := validateTransition(StatusInvestigating, StatusIdentified); err != nil {
+		t.Errorf("許可された遷移でエラーが返されました: %v", err)
+	}
+	if err := validateTransition(StatusClosed, StatusInvestigating); err == nil {
+		t.Error("クローズ後の遷移はエラーになるべきです")
+	}
+	if err := validateTransition(StatusInvestigating, "unknown"); err == nil {
+		t.Error("不明なステータスへの遷移はエラーになるべきです")
+	}
+}
+
+func TestStatusTransitionsAreKnown(t *testing.T) {
+	// 遷移先がすべて定義済みのステータスであり、表示名を持つことを確認
+	for from, nexts := range statusTransitions {
+		if _, ok := statusLabels[from]; !ok {
+			t.Errorf("ステータス %q の表示名がありません", from)
+		}
+		for _, to := range nexts {
+			if _, ok := statusTransitions[to]; !ok {
+				t.Errorf("%q から未定義のステータス %q に遷移できます", from, to)
+			}
+		}
+	}
+}
+
+func TestIsActiveStatus(t *testing.T) {
+	for _, status := range []string{statusLegacyOpen, StatusInvestigating, StatusIdentified, StatusMitigated, StatusMonitoring} {
+		if !isActiveStatus(status) {
+			t.Errorf("%q は対応中として扱われるべきです", status)
+		}
+	}
+	for _, status := range finishedStatuses {
+		if isActiveStatus(status) {
+			t.Errorf("%q は対応中として扱われるべきではありません", status)
+		}
+	}
+}
+
+func TestStatusLabel(t *testing.T) {
+	if label := statusLabel(statusLegacyOpen); label != statusLabels[StatusInvestigating] {
+		t.Errorf("旧ステータスの表示名が不正です: %s", label)
+	}
+	if label := statusLabel("custom"); label != "custom" {
+		t.Errorf("未定義ステータスはそのまま表示されるべきです: %s", label)
+	}
+}
+
+func TestParseStatusActionID(t *testing.T) {
+	tests := []struct {
+		actionID string
+		expected string
+		ok       bool
+	}{
+		{statusActionID(StatusIdentified), StatusIdentified, true},
+		{"change_status_closed", StatusClosed, true},
+		{"change_status_unknown", "", false},
+		{"resolve_incident", "", false},
+		{"", "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.actionID, func(t *testing.T) {
+			status, ok := parseStatusActionID(tt.actionID)
+			if status != tt.expected || ok != tt.ok {
+				t.Errorf("parseStatusActionID(%q) = (%q, %v), want (%q, %v)", tt.actionID, status, ok, tt.expected, tt.ok)
+			}
+		})
+	}
+}
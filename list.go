@@ -0,0 +1,325 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/slack-go/slack"
+)
+
+// incidentListPageSize はインシデント一覧の1ページあたりの件数
+const incidentListPageSize = 10
+
+// インシデント一覧の並び順
+const (
+	incidentListSortSeverity = "severity" // 重要度の高い順（デフォルト）
+	incidentListSortNewest   = "new"      // 新しい順
+	incidentListSortOldest   = "old"      // 古い順
+	incidentListSortRecent   = "recent"   // 復旧日時（未復旧の場合は作成日時）の新しい順（App Home 用）
+)
+
+// incidentListFilterHelp はインシデント一覧の絞り込み条件の説明
+const incidentListFilterHelp = "*絞り込み条件:*\n" +
+	"• `severity:critical` - 重要度（`,` 区切りで複数指定可）\n" +
+	"• `status:resolved` - ステータス（`open` で対応中すべて、`all` ですべて）\n" +
+	"• `handler:@me` - 担当者（`none` で未割り当て）\n" +
+	"• `reporter:@ユーザー` - 報告者\n" +
+	"• `since:7d` - 期間（`m`/`h`/`d`/`w` 単位）\n" +
+	"• `sort:new` - 並び順（`severity`/`new`/`old`、デフォルトは重要度順）\n"
+
+// IncidentListFilter はインシデント一覧の絞り込み条件とページ位置
+// ページ送りボタンのValueにJSONとして保存する
+type IncidentListFilter struct {
+	Query      string    `json:"q,omitempty"`  // 入力された条件（表示用）
+	Statuses   []string  `json:"st,omitempty"` // 空の場合は対応中のステータス
+	Severities []string  `json:"sv,omitempty"`
+	HandlerID  string    `json:"h,omitempty"`
+	Unassigned bool      `json:"ua,omitempty"`
+	ReporterID string    `json:"r,omitempty"`
+	Since      time.Time `json:"since"`
+	SortBy     string    `json:"sort,omitempty"`
+	Page       int       `json:"p,omitempty"` // 0始まり
+}
+
+// parseIncidentListFilter はコマンド引数からインシデント一覧の絞り込み条件を解析
+// userID は `@me` の解決に、now は `since:` の基準時刻に使用する
+func parseIncidentListFilter(args []string, userID string, now time.Time) (IncidentListFilter, error) {
+	filter := IncidentListFilter{
+		Query:  strings.Join(args, " "),
+		SortBy: incidentListSortSeverity,
+	}
+
+	for _, arg := range args {
+		key, value, ok := strings.Cut(arg, ":")
+		if !ok || value == "" {
+			return filter, fmt.Errorf("不明な条件です: `%s`", arg)
+		}
+
+		switch strings.ToLower(key) {
+		case "severity", "重要度":
+			for _, severity := range strings.Split(strings.ToLower(value), ",") {
+				if _, ok := severityEmojis[severity]; !ok {
+					return filter, fmt.Errorf("不明な重要度です: `%s`", severity)
+				}
+				filter.Severities = append(filter.Severities, severity)
+			}
+
+		case "status", "ステータス":
+			for _, status := range strings.Split(strings.ToLower(value), ",") {
+				switch status {
+				case "open", "active":
+					filter.Statuses = append(filter.Statuses, activeStatuses...)
+				case "all":
+					filter.Statuses = append(filter.Statuses, activeStatuses...)
+					filter.Statuses = append(filter.Statuses, finishedStatuses...)
+				default:
+					if _, ok := statusTransitions[status]; !ok {
+						return filter, fmt.Errorf("不明なステータスです: `%s`", status)
+					}
+					filter.Statuses = append(filter.Statuses, status)
+				}
+			}
+
+		case "handler", "担当":
+			if value == "none" || value == "なし" {
+				filter.Unassigned = true
+				continue
+			}
+			handlerID, err := parseUserFilter(value, userID)
+			if err != nil {
+				return filter, err
+			}
+			filter.HandlerID = handlerID
+
+		case "reporter", "報告者":
+			reporterID, err := parseUserFilter(value, userID)
+			if err != nil {
+				return filter, err
+			}
+			filter.ReporterID = reporterID
+
+		case "since", "期間":
+			d, err := parseSinceDuration(value)
+			if err != nil {
+				return filter, err
+			}
+			filter.Since = now.Add(-d)
+
+		case "sort", "並び順":
+			switch strings.ToLower(value) {
+			case incidentListSortSeverity, incidentListSortNewest, incidentListSortOldest:
+				filter.SortBy = strings.ToLower(value)
+			default:
+				return filter, fmt.Errorf("不明な並び順です: `%s`", value)
+			}
+
+		default:
+			return filter, fmt.Errorf("不明な条件です: `%s`", arg)
+		}
+	}
+
+	return filter, nil
+}
+
+// parseUserFilter はユーザー指定（@me または <@U123>）からユーザーIDを取得
+func parseUserFilter(value, userID string) (string, error) {
+	switch value {
+	case "@me", "me", "自分":
+		if userID == "" {
+			return "", fmt.Errorf("`@me` はこのコマンドでは利用できません")
+		}
+		return userID, nil
+	}
+
+	if strings.HasPrefix(value, "<@") && strings.HasSuffix(value, ">") {
+		id := strings.TrimSuffix(strings.TrimPrefix(value, "<@"), ">")
+		// <@U123|name> 形式の場合は名前部分を除く
+		id, _, _ = strings.Cut(id, "|")
+		if id != "" {
+			return id, nil
+		}
+	}
+
+	return "", fmt.Errorf("ユーザーは `@me` またはメンションで指定してください: `%s`", value)
+}
+
+// parseSinceDuration は `7d` や `24h` 形式の期間を解析
+func parseSinceDuration(value string) (time.Duration, error) {
+	units := map[byte]time.Duration{
+		'm': time.Minute,
+		'h': time.Hour,
+		'd': 24 * time.Hour,
+		'w': 7 * 24 * time.Hour,
+	}
+
+	if len(value) >= 2 {
+		if unit, ok := units[value[len(value)-1]]; ok {
+			if n, err := strconv.Atoi(value[:len(value)-1]); err == nil && n > 0 {
+				return time.Duration(n) * unit, nil
+			}
+		}
+	}
+
+	return 0, fmt.Errorf("期間は `30m`、`24h`、`7d`、`2w` のように指定してください: `%s`", value)
+}
+
+// showIncidentList は絞り込み条件に一致するインシデント一覧を表示
+func showIncidentList(ctx *CommandContext, ephemeral bool) {
+	filter, err := parseIncidentListFilter(ctx.Args, ctx.UserID, time.Now())
+	if err != nil {
+		ctx.reply(fmt.Sprintf("❌ %v\n\n%s", err, incidentListFilterHelp), true)
+		return
+	}
+
+	text, blocks, count := buildIncidentListMessage(filter)
+	ctx.replyBlocks(text, blocks, ephemeral)
+	log.Printf("インシデント一覧を表示しました (%d件)", count)
+}
+
+// handleIncidentListPage はインシデント一覧のページ送りボタンがクリックされた時の処理
+// 元のメッセージを次（前）のページの内容で置き換える
+func handleIncidentListPage(api SlackAPI, callback slack.InteractionCallback) {
+	action := callback.ActionCallback.BlockActions[0]
+
+	var filter IncidentListFilter
+	if err := json.Unmarshal([]byte(action.Value), &filter); err != nil {
+		log.Printf("ページ情報の解析エラー: %v", err)
+		return
+	}
+
+	text, blocks, _ := buildIncidentListMessage(filter)
+
+	var err error
+	if callback.ResponseURL != "" {
+		err = slack.PostWebhook(callback.ResponseURL, &slack.WebhookMessage{
+			Text:            text,
+			Blocks:          &slack.Blocks{BlockSet: blocks},
+			ReplaceOriginal: true,
+		})
+	} else {
+		_, _, _, err = api.UpdateMessage(
+			callback.Channel.ID,
+			callback.Message.Timestamp,
+			slack.MsgOptionText(text, false),
+			slack.MsgOptionBlocks(blocks...),
+		)
+	}
+
+	if err != nil {
+		log.Printf("インシデント一覧の更新エラー: %v", err)
+	} else {
+		log.Printf("インシデント一覧をページ %d に更新しました", filter.Page+1)
+	}
+}
+
+// buildIncidentListMessage はインシデント一覧のテキスト・ブロックと表示件数を生成
+func buildIncidentListMessage(filter IncidentListFilter) (string, []slack.Block, int) {
+	// データベースが無効な場合
+	if store == nil {
+		return buildIncidentListTextMessage("⚠️ データベース機能が無効のため、インシデント一覧を取得できません。")
+	}
+
+	incidents, total, err := searchIncidents(filter, incidentListPageSize, filter.Page*incidentListPageSize)
+	// 表示中にインシデントがクローズされるなどしてページが範囲外になった場合は最後のページを表示
+	if err == nil && len(incidents) == 0 && total > 0 && filter.Page > 0 {
+		filter.Page = (total - 1) / incidentListPageSize
+		incidents, total, err = searchIncidents(filter, incidentListPageSize, filter.Page*incidentListPageSize)
+	}
+	if err != nil {
+		log.Printf("インシデント一覧取得エラー: %v", err)
+		return buildIncidentListTextMessage(fmt.Sprintf("❌ インシデント一覧の取得に失敗しました: %v", err))
+	}
+
+	return buildIncidentListBlocks(filter, incidents, total)
+}
+
+// buildIncidentListTextMessage はテキストのみのインシデント一覧メッセージを生成
+func buildIncidentListTextMessage(text string) (string, []slack.Block, int) {
+	return text, []slack.Block{
+		slack.NewSectionBlock(slack.NewTextBlockObject("mrkdwn", text, false, false), nil, nil),
+	}, 0
+}
+
+// buildIncidentListBlocks は取得したインシデント（1ページ分）からインシデント一覧のブロックを構築
+func buildIncidentListBlocks(filter IncidentListFilter, incidents []Incident, total int) (string, []slack.Block, int) {
+	if len(incidents) == 0 {
+		if filter.Query == "" && filter.Page == 0 {
+			return buildIncidentListTextMessage("✅ 現在オープンなインシデントはありません。")
+		}
+		return buildIncidentListTextMessage(fmt.Sprintf("🔍 条件に一致するインシデントはありません。\n条件: `%s`", filter.Query))
+	}
+
+	title := "オープン中のインシデント一覧"
+	if len(filter.Statuses) > 0 {
+		title = "インシデント一覧"
+	}
+	header := fmt.Sprintf("📋 *%s* (全%d件)", title, total)
+	if filter.Query != "" {
+		header += fmt.Sprintf("\n🔎 条件: `%s`", filter.Query)
+	}
+
+	blocks := []slack.Block{
+		slack.NewSectionBlock(slack.NewTextBlockObject("mrkdwn", header, false, false), nil, nil),
+	}
+
+	for _, incident := range incidents {
+		handler := "未割り当て"
+		if incident.HandlerName != "" {
+			handler = incident.HandlerName
+		}
+
+		line := fmt.Sprintf(
+			"%s *#%d* - %s\n  %s | チャンネル: <#%s> | 担当: %s | 報告: %s",
+			severityEmojis[incident.Severity],
+			incident.ID,
+			incident.Title,
+			statusLabel(incident.Status),
+			incident.ChannelID,
+			handler,
+			incident.ReporterName,
+		)
+		blocks = append(blocks, slack.NewSectionBlock(slack.NewTextBlockObject("mrkdwn", line, false, false), nil, nil))
+	}
+
+	// 件数が1ページに収まらない場合はページ情報とページ送りボタンを表示
+	first := filter.Page*incidentListPageSize + 1
+	last := first + len(incidents) - 1
+	totalPages := (total + incidentListPageSize - 1) / incidentListPageSize
+	if totalPages > 1 {
+		blocks = append(blocks, slack.NewContextBlock("",
+			slack.NewTextBlockObject("mrkdwn", fmt.Sprintf("%d〜%d件目を表示（ページ %d/%d）", first, last, filter.Page+1, totalPages), false, false),
+		))
+
+		var buttons []slack.BlockElement
+		if filter.Page > 0 {
+			buttons = append(buttons, buildIncidentListPageButton("incident_list_prev", "◀ 前のページ", filter, filter.Page-1))
+		}
+		if last < total {
+			buttons = append(buttons, buildIncidentListPageButton("incident_list_next", "次のページ ▶", filter, filter.Page+1))
+		}
+		if len(buttons) > 0 {
+			blocks = append(blocks, slack.NewActionBlock("incident_list_pagination", buttons...))
+		}
+	}
+
+	return header, blocks, len(incidents)
+}
+
+// buildIncidentListPageButton は指定ページに移動するボタンを構築
+func buildIncidentListPageButton(actionID, text string, filter IncidentListFilter, page int) *slack.ButtonBlockElement {
+	filter.Page = page
+	value, err := json.Marshal(filter)
+	if err != nil {
+		log.Printf("ページ情報のエンコードエラー: %v", err)
+	}
+
+	return slack.NewButtonBlockElement(
+		actionID,
+		string(value),
+		slack.NewTextBlockObject("plain_text", text, true, false),
+	)
+}
@@ -0,0 +1,213 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/slack-go/slack"
+)
+
+func TestParseIncidentListFilter(t *testing.T) {
+	now := time.Date(2025, 1, 8, 12, 0, 0, 0, time.UTC)
+
+	filter, err := parseIncidentListFilter(
+		[]string{"severity:critical,HIGH", "handler:@me", "reporter:<@U456|suzuki>", "since:7d", "status:resolved", "sort:new"},
+		"U123", now,
+	)
+	if err != nil {
+		t.Fatalf("予期しないエラー: %v", err)
+	}
+
+	if !reflect.DeepEqual(filter.Severities, []string{"critical", "high"}) {
+		t.Errorf("重要度が間違っています: %v", filter.Severities)
+	}
+	if filter.HandlerID != "U123" {
+		t.Errorf("@me が実行ユーザーに解決されていません: %s", filter.HandlerID)
+	}
+	if filter.ReporterID != "U456" {
+		t.Errorf("報告者が間違っています: %s", filter.ReporterID)
+	}
+	if !filter.Since.Equal(time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)) {
+		t.Errorf("期間が間違っています: %v", filter.Since)
+	}
+	if !reflect.DeepEqual(filter.Statuses, []string{StatusResolved}) {
+		t.Errorf("ステータスが間違っています: %v", filter.Statuses)
+	}
+	if filter.SortBy != incidentListSortNewest {
+		t.Errorf("並び順が間違っています: %s", filter.SortBy)
+	}
+}
+
+func TestParseIncidentListFilterDefaults(t *testing.T) {
+	filter, err := parseIncidentListFilter(nil, "U123", time.Now())
+	if err != nil {
+		t.Fatalf("予期しないエラー: %v", err)
+	}
+
+	if filter.SortBy != incidentListSortSeverity {
+		t.Errorf("デフォルトの並び順は重要度順であるべきです: %s", filter.SortBy)
+	}
+	if len(filter.Statuses) != 0 || filter.Query != "" {
+		t.Errorf("条件なしの場合は対応中のインシデントが対象になるべきです: %+v", filter)
+	}
+
+	filter, err = parseIncidentListFilter([]string{"handler:none", "status:all"}, "U123", time.Now())
+	if err != nil {
+		t.Fatalf("予期しないエラー: %v", err)
+	}
+	if !filter.Unassigned {
+		t.Error("handler:none は未割り当てを表すべきです")
+	}
+	if len(filter.Statuses) != len(activeStatuses)+len(finishedStatuses) {
+		t.Errorf("status:all はすべてのステータスを含むべきです: %v", filter.Statuses)
+	}
+}
+
+func TestParseIncidentListFilterErrors(t *testing.T) {
+	tests := [][]string{
+		{"critical"},
+		{"severity:urgent"},
+		{"status:done"},
+		{"handler:tanaka"},
+		{"since:7"},
+		{"since:-1d"},
+		{"sort:random"},
+		{"color:red"},
+		{"severity:"},
+	}
+
+	for _, args := range tests {
+		t.Run(strings.Join(args, " "), func(t *testing.T) {
+			if _, err := parseIncidentListFilter(args, "U123", time.Now()); err == nil {
+				t.Errorf("%v はエラーになるべきです", args)
+			}
+		})
+	}
+}
+
+func TestParseSinceDuration(t *testing.T) {
+	tests := map[string]time.Duration{
+		"30m": 30 * time.Minute,
+		"24h": 24 * time.Hour,
+		"7d":  7 * 24 * time.Hour,
+		"2w":  14 * 24 * time.Hour,
+	}
+
+	for input, expected := range tests {
+		d, err := parseSinceDuration(input)
+		if err != nil || d != expected {
+			t.Errorf("parseSinceDuration(%q) = (%v, %v), want %v", input, d, err, expected)
+		}
+	}
+}
+
+func testListIncidents(from, n int) []Incident {
+	var incidents []Incident
+	for i := 0; i < n; i++ {
+		incidents = append(incidents, Incident{
+			ID:           int64(from + i),
+			Title:        fmt.Sprintf("インシデント%d", from+i),
+			Severity:     "high",
+			Status:       StatusInvestigating,
+			ChannelID:    "C123",
+			ReporterName: "tanaka",
+			CreatedAt:    time.Date(2025, 1, 1, 10, 0, 0, 0, time.UTC),
+		})
+	}
+	return incidents
+}
+
+// findPaginationButtons はページ送りボタンのアクションIDとページ情報を返す
+func findPaginationButtons(t *testing.T, blocks []slack.Block) map[string]IncidentListFilter {
+	buttons := make(map[string]IncidentListFilter)
+	for _, block := range blocks {
+		actionBlock, ok := block.(*slack.ActionBlock)
+		if !ok {
+			continue
+		}
+		for _, element := range actionBlock.Elements.ElementSet {
+			button := element.(*slack.ButtonBlockElement)
+			var filter IncidentListFilter
+			if err := json.Unmarshal([]byte(button.Value), &filter); err != nil {
+				t.Fatalf("ボタンのValueが解析できません: %v", err)
+			}
+			buttons[button.ActionID] = filter
+		}
+	}
+	return buttons
+}
+
+func TestBuildIncidentListBlocksPagination(t *testing.T) {
+	filter := IncidentListFilter{Query: "severity:high", Severities: []string{"high"}, SortBy: incidentListSortSeverity}
+
+	// 1ページ目（全23件）
+	text, blocks, count := buildIncidentListBlocks(filter, testListIncidents(1, 10), 23)
+	if count != 10 {
+		t.Errorf("表示件数が間違っています: %d", count)
+	}
+	if !strings.Contains(text, "全23件") || !strings.Contains(text, "severity:high") {
+		t.Errorf("見出しに全件数と条件が含まれていません: %s", text)
+	}
+	buttons := findPaginationButtons(t, blocks)
+	if _, ok := buttons["incident_list_prev"]; ok {
+		t.Error("1ページ目には前のページボタンは不要です")
+	}
+	next, ok := buttons["incident_list_next"]
+	if !ok || next.Page != 1 || !reflect.DeepEqual(next.Severities, []string{"high"}) {
+		t.Errorf("次のページボタンが条件を引き継いでいません: %+v", next)
+	}
+
+	// 最終ページ（21〜23件目）
+	filter.Page = 2
+	_, blocks, _ = buildIncidentListBlocks(filter, testListIncidents(21, 3), 23)
+	buttons = findPaginationButtons(t, blocks)
+	if _, ok := buttons["incident_list_next"]; ok {
+		t.Error("最終ページには次のページボタンは不要です")
+	}
+	if prev, ok := buttons["incident_list_prev"]; !ok || prev.Page != 1 {
+		t.Errorf("前のページボタンが間違っています: %+v", prev)
+	}
+}
+
+func TestBuildIncidentListBlocksSinglePage(t *testing.T) {
+	_, blocks, _ := buildIncidentListBlocks(IncidentListFilter{}, testListIncidents(1, 3), 3)
+	if len(findPaginationButtons(t, blocks)) != 0 {
+		t.Error("1ページに収まる場合はページ送りボタンは不要です")
+	}
+	// 見出し + 3件
+	if len(blocks) != 4 {
+		t.Errorf("ブロック数が間違っています: %d", len(blocks))
+	}
+}
+
+func TestBuildIncidentListBlocksEmpty(t *testing.T) {
+	text, _, _ := buildIncidentListBlocks(IncidentListFilter{}, nil, 0)
+	if !strings.Contains(text, "オープンなインシデントはありません") {
+		t.Errorf("条件なしで0件の場合のメッセージが間違っています: %s", text)
+	}
+
+	text, _, _ = buildIncidentListBlocks(IncidentListFilter{Query: "severity:low"}, nil, 0)
+	if !strings.Contains(text, "条件に一致するインシデントはありません") {
+		t.Errorf("条件ありで0件の場合のメッセージが間違っています: %s", text)
+	}
+}
+
+func TestBuildIncidentListMessagePageOutOfRange(t *testing.T) {
+	setupE2E(t)
+	for i := 1; i <= incidentListPageSize+3; i++ {
+		store.CreateIncident(newTestIncident(fmt.Sprintf("インシデント%d", i), "high", fmt.Sprintf("C%03d", i)))
+	}
+
+	// 表示中にインシデントが減ってページが範囲外になった場合は最後のページを表示する
+	text, blocks, count := buildIncidentListMessage(IncidentListFilter{Page: 5})
+	if count != 3 || !strings.Contains(text, fmt.Sprintf("全%d件", incidentListPageSize+3)) {
+		t.Errorf("範囲外のページの表示 = %s (%d件)", text, count)
+	}
+	if prev, ok := findPaginationButtons(t, blocks)["incident_list_prev"]; !ok || prev.Page != 0 {
+		t.Errorf("前のページボタンが間違っています: %+v", prev)
+	}
+}
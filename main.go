@@ -1,6 +1,7 @@
 package main
 
 import (
+	"context"
 	"log"
 	"os"
 	"time"
@@ -17,6 +18,11 @@ func main() {
 		log.Println("環境変数からの読み込みを試みます")
 	}
 
+	// サブコマンド（incident-bot migrate up/down/status）
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		os.Exit(runMigrateCommand(os.Args[2:]))
+	}
+
 	// トークンの取得（設定ファイル優先、環境変数をフォールバック）
 	botToken := config.Slack.BotToken
 	appToken := config.Slack.AppToken
@@ -32,13 +38,80 @@ func main() {
 		log.Fatal("SLACK_BOT_TOKEN と SLACK_APP_TOKEN の設定が必要です（config.tomlまたは環境変数）")
 	}
 
-	// データベース接続を初期化
-	if err := initDB(); err != nil {
-		log.Printf("データベース接続エラー: %v", err)
-		log.Println("データベース機能は無効化されます")
-	} else {
+	// インシデントの保存先を初期化
+	var incidents *outboxStore
+	switch config.Storage.Backend {
+	case storageBackendMemory:
+		// PostgreSQL を使用しない（検索は部分一致のみ、複数レプリカでの運用は不可）
+		memory, err := newMemoryStore(config.Storage.File)
+		if err != nil {
+			log.Fatalf("インシデントの保存先の初期化エラー: %v", err)
+		}
+		store = memory
+		if config.Storage.File != "" {
+			log.Printf("インシデントをファイル %s に保存します", config.Storage.File)
+		} else {
+			log.Println("インシデントをメモリ上に保存します（再起動すると失われます）")
+		}
+	case "", storageBackendPostgres:
+		// データベースに接続できない間のインシデントを一時保存するジャーナルと、既存のインシデントへの変更を記録するアウトボックス
+		journal, err := openIncidentJournal(config.Database.JournalFile)
+		if err != nil {
+			log.Fatalf("ジャーナルの初期化エラー: %v", err)
+		}
+		outbox, err := openIncidentOutbox(config.Database.OutboxFile)
+		if err != nil {
+			log.Fatalf("アウトボックスの初期化エラー: %v", err)
+		}
+
+		// データベース接続を初期化
+		if err := initDB(); err != nil {
+			// 縮退運転としてインシデントをジャーナルに保存し、バックグラウンドで再接続を試みる
+			log.Printf("データベース接続エラー: %v", err)
+			log.Println("縮退運転を開始します（インシデントはジャーナルに一時保存し、再接続後に登録します）")
+			incidents = newOutboxStore(nil, journal, outbox)
+			store = incidents
+			dbHealth.markDegraded(time.Now())
+			break
+		}
 		defer db.Close()
 		log.Println("データベースに接続しました")
+
+		// 未適用のマイグレーションを適用（複数レプリカが同時に起動しても1つずつ適用される）
+		if config.Database.DisableAutoMigrate {
+			log.Println("起動時のマイグレーションは無効です")
+		} else {
+			migrations, err := migrateUp()
+			if err != nil {
+				log.Fatalf("マイグレーションエラー: %v", err)
+			}
+			log.Printf("マイグレーションを %d 件適用しました", len(migrations))
+		}
+		postgres := newPostgresStore(db)
+
+		// 前回の縮退運転中に登録できなかった操作とインシデントを登録
+		// 操作を登録できない場合は順序を保つため、縮退運転として続きの登録を再試行する
+		if replayed, err := replayIncidentOutbox(postgres, outbox); err != nil {
+			log.Printf("アウトボックスの操作の登録エラー: %v", err)
+			log.Println("縮退運転を開始します（アウトボックスの操作を登録できるまで再試行します）")
+			incidents = newOutboxStore(nil, journal, outbox)
+			store = incidents
+			dbHealth.markDegraded(time.Now())
+			break
+		} else if len(replayed) > 0 {
+			log.Printf("アウトボックスから %d 件の操作を登録しました", len(replayed))
+		}
+		if imported, err := backfillIncidentJournal(postgres, journal); err != nil {
+			log.Printf("ジャーナルのインシデント登録エラー: %v", err)
+		} else if len(imported) > 0 {
+			log.Printf("ジャーナルから %d 件のインシデントを登録しました", len(imported))
+		}
+
+		// 稼働中にデータベースに書き込めなくなった場合はジャーナルとアウトボックスに記録する
+		incidents = newOutboxStore(postgres, journal, outbox)
+		store = incidents
+	default:
+		log.Fatalf("不明な保存先です: %s（postgres または memory を指定してください）", config.Storage.Backend)
 	}
 
 	// Slack APIクライアントの作成
@@ -49,25 +122,18 @@ func main() {
 		slack.OptionLog(log.New(os.Stdout, "slack-bot: ", log.Lshortfile|log.LstdFlags)),
 	)
 
-	// オープンなインシデントのタイムキーパーを復元
-	if db != nil {
-		openIncidents, err := getOpenIncidents()
-		if err != nil {
-			log.Printf("オープンなインシデント取得エラー: %v", err)
-		} else if len(openIncidents) > 0 {
-			log.Printf("オープンなインシデント %d 件のタイムキーパーを復元します", len(openIncidents))
-			for _, incident := range openIncidents {
-				incidentID := incident["id"].(int64)
-				channelID := incident["channel_id"].(string)
-				createdAt := incident["created_at"].(time.Time)
-
-				timekeeperManager.startTimekeeper(api, incidentID, channelID, createdAt)
-				log.Printf("インシデント %d のタイムキーパーを復元しました (開始時刻: %v)", incidentID, createdAt)
-			}
-		} else {
-			log.Println("復元するオープンなインシデントはありません")
+	// タイムキーパーの復元・同期とアクションアイテムのリマインドを開始（リーダーのレプリカのみ）
+	// 縮退運転中は再接続して復旧した後に開始する
+	if incidents != nil {
+		incidents.onUnavailable = func() {
+			go reconnectDatabase(context.Background(), api, incidents)
 		}
 	}
+	if isDegraded() {
+		go reconnectDatabase(context.Background(), api, incidents)
+	} else if store != nil {
+		startBackgroundWork(api)
+	}
 
 	// Socket Modeクライアントの作成
 	client := socketmode.New(
@@ -85,6 +151,9 @@ func main() {
 
 	log.Printf("Botが起動しました。Bot ID: %s", botUserID)
 
+	// アラートの Webhook からインシデントを作成（報告者はBot自身）
+	startAlertWebhookServer(api, botUserID)
+
 	// 設定情報をログ出力
 	log.Printf("全体周知機能: %v", config.Channels.EnableAnnouncement)
 	log.Printf("全体周知チャンネル数: %d", len(config.Channels.AnnouncementChannels))
@@ -107,20 +176,14 @@ func main() {
 				// イベントを確認応答
 				client.Ack(*evt.Request)
 
-				// イベントタイプに応じた処理
-				switch eventsAPIEvent.Type {
-				case slackevents.CallbackEvent:
-					innerEvent := eventsAPIEvent.InnerEvent
-					switch ev := innerEvent.Data.(type) {
-					case *slackevents.AppMentionEvent:
-						// メンション受信時の処理
-						handleAppMention(api, ev)
-					case *slackevents.ChannelArchiveEvent:
-						// チャンネルアーカイブ時の処理
-						handleChannelArchive(api, ev)
-					}
+				// 再送・再配信されたイベントは処理しない
+				if !markEventProcessed(envelopeEventKey(evt)) {
+					log.Printf("処理済みのイベントのためスキップします (envelope_id: %s)", evt.Request.EnvelopeID)
+					continue
 				}
 
+				handleEventsAPIEvent(api, eventsAPIEvent)
+
 			case socketmode.EventTypeInteractive:
 				// モーダル送信などのインタラクティブイベント
 				callback, ok := evt.Data.(slack.InteractionCallback)
@@ -132,34 +195,33 @@ func main() {
 				// イベントを確認応答
 				client.Ack(*evt.Request)
 
-				// インタラクションタイプに応じた処理
-				switch callback.Type {
-				case slack.InteractionTypeBlockActions:
-					// ボタンクリック時の処理
-					if len(callback.ActionCallback.BlockActions) > 0 {
-						action := callback.ActionCallback.BlockActions[0]
-						switch action.ActionID {
-						case "open_incident_modal":
-							handleOpenModal(api, callback)
-						case "assign_handler":
-							handleAssignHandler(api, callback)
-						case "update_incident":
-							handleUpdateIncident(api, callback)
-						case "resolve_incident":
-							handleResolveIncident(api, callback)
-						case "stop_timekeeper":
-							handleStopTimekeeper(api, callback)
-						}
-					}
-				case slack.InteractionTypeViewSubmission:
-					// モーダル送信時の処理
-					if callback.View.CallbackID == "incident_report_modal" {
-						handleModalSubmission(api, callback)
-					} else if callback.View.CallbackID == "incident_update_modal" {
-						handleUpdateModalSubmission(api, callback)
-					}
+				// 再送・再配信されたイベントは処理しない
+				if !markEventProcessed(envelopeEventKey(evt)) {
+					log.Printf("処理済みのインタラクティブイベントのためスキップします (envelope_id: %s)", evt.Request.EnvelopeID)
+					continue
 				}
 
+				handleInteraction(api, callback)
+
+			case socketmode.EventTypeSlashCommand:
+				// スラッシュコマンド（/incident）
+				cmd, ok := evt.Data.(slack.SlashCommand)
+				if !ok {
+					log.Printf("スラッシュコマンドの型変換に失敗しました")
+					continue
+				}
+
+				// イベントを確認応答（応答はresponse_url経由で返す）
+				client.Ack(*evt.Request)
+
+				// 再送・再配信されたコマンドは処理しない
+				if !markEventProcessed(envelopeEventKey(evt)) {
+					log.Printf("処理済みのスラッシュコマンドのためスキップします (envelope_id: %s)", evt.Request.EnvelopeID)
+					continue
+				}
+
+				handleSlashCommand(api, cmd)
+
 			case socketmode.EventTypeConnecting:
 				log.Println("Slackに接続中...")
 
@@ -0,0 +1,1054 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// memoryStore はメモリ上にインシデントを保存する IncidentStore
+// ファイルを指定した場合は変更のたびにJSONで書き出し、起動時に読み込む（PostgreSQL を用意できない小規模な環境向け）
+type memoryStore struct {
+	path string // 空の場合はファイルに保存しない
+	mu   sync.RWMutex
+	data memoryStoreData
+
+	// temporaryIDs が true の場合は PostgreSQL のIDと重ならない負の仮IDを採番する（ジャーナル用）
+	temporaryIDs bool
+}
+
+// memoryStoreData はファイルに保存する内容
+type memoryStoreData struct {
+	NextID          int64                      `json:"next_id"`
+	LastTemporaryID int64                      `json:"last_temporary_id,omitempty"` // 最後に採番した仮ID
+	Incidents       map[int64]*Incident        `json:"incidents"`
+	StatusHistory   map[int64][]StatusChange   `json:"status_history"`
+	HandlerHistory  map[int64][]HandlerChange  `json:"handler_history"`
+	UpdateHistory   map[int64][]FieldUpdate    `json:"update_history"`
+	Alerts          map[int64][]IncidentAlert  `json:"alerts,omitempty"`
+	Deliveries      []WebhookDelivery          `json:"webhook_deliveries,omitempty"` // 配信IDの順（配信IDは位置+1）
+	ActionItems     []ActionItem               `json:"action_items,omitempty"`       // アクションアイテムIDの順（IDは位置+1）
+	Timekeepers     map[int64]*TimekeeperState `json:"timekeepers,omitempty"`
+	ProcessedEvents map[string]time.Time       `json:"processed_events,omitempty"` // イベントのキーと有効期限
+	HomeViewers     map[string]time.Time       `json:"home_viewers,omitempty"`     // ユーザーIDと最後にApp Homeを開いた日時
+}
+
+// newMemoryStore はメモリ上の IncidentStore を作成（path が空でない場合はファイルから読み込む）
+func newMemoryStore(path string) (*memoryStore, error) {
+	s := &memoryStore{
+		path: path,
+		data: memoryStoreData{
+			NextID:          1,
+			Incidents:       make(map[int64]*Incident),
+			StatusHistory:   make(map[int64][]StatusChange),
+			HandlerHistory:  make(map[int64][]HandlerChange),
+			UpdateHistory:   make(map[int64][]FieldUpdate),
+			Alerts:          make(map[int64][]IncidentAlert),
+			Timekeepers:     make(map[int64]*TimekeeperState),
+			ProcessedEvents: make(map[string]time.Time),
+			HomeViewers:     make(map[string]time.Time),
+		},
+	}
+	if path == "" {
+		return s, nil
+	}
+
+	content, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return s, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("インシデントファイル読み込みエラー: %v", err)
+	}
+	if err := json.Unmarshal(content, &s.data); err != nil {
+		return nil, fmt.Errorf("インシデントファイル解析エラー: %v", err)
+	}
+	if s.data.Incidents == nil {
+		s.data.Incidents = make(map[int64]*Incident)
+	}
+	if s.data.StatusHistory == nil {
+		s.data.StatusHistory = make(map[int64][]StatusChange)
+	}
+	if s.data.HandlerHistory == nil {
+		s.data.HandlerHistory = make(map[int64][]HandlerChange)
+	}
+	if s.data.UpdateHistory == nil {
+		s.data.UpdateHistory = make(map[int64][]FieldUpdate)
+	}
+	if s.data.Alerts == nil {
+		s.data.Alerts = make(map[int64][]IncidentAlert)
+	}
+	if s.data.Timekeepers == nil {
+		s.data.Timekeepers = make(map[int64]*TimekeeperState)
+	}
+	if s.data.ProcessedEvents == nil {
+		s.data.ProcessedEvents = make(map[string]time.Time)
+	}
+	if s.data.HomeViewers == nil {
+		s.data.HomeViewers = make(map[string]time.Time)
+	}
+	return s, nil
+}
+
+// save はファイルに書き出す（呼び出し元でロックを取得しておくこと）
+// 書き込み途中で停止しても壊れないよう、一時ファイルに書き出してから置き換える
+func (s *memoryStore) save() error {
+	if s.path == "" {
+		return nil
+	}
+
+	content, err := json.MarshalIndent(s.data, "", "  ")
+	if err != nil {
+		return fmt.Errorf("インシデントファイル書き込みエラー: %v", err)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(s.path), filepath.Base(s.path)+".tmp*")
+	if err != nil {
+		return fmt.Errorf("インシデントファイル書き込みエラー: %v", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(content); err != nil {
+		tmp.Close()
+		return fmt.Errorf("インシデントファイル書き込みエラー: %v", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("インシデントファイル書き込みエラー: %v", err)
+	}
+	if err := os.Rename(tmp.Name(), s.path); err != nil {
+		return fmt.Errorf("インシデントファイル書き込みエラー: %v", err)
+	}
+	return nil
+}
+
+// CreateIncident はインシデントを保存し、初期ステータスを履歴に記録
+func (s *memoryStore) CreateIncident(input NewIncident) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	incidentID := s.allocateID(now)
+
+	s.data.Incidents[incidentID] = &Incident{
+		ID:              incidentID,
+		Title:           input.Title,
+		Severity:        input.Severity,
+		Description:     input.Description,
+		Impact:          input.Impact,
+		Status:          StatusInvestigating,
+		ChannelID:       input.ChannelID,
+		ChannelName:     input.ChannelName,
+		ReporterID:      input.ReporterID,
+		ReporterName:    input.ReporterName,
+		SourcePermalink: input.SourcePermalink,
+		CreatedAt:       now,
+		UpdatedAt:       now,
+	}
+	s.data.StatusHistory[incidentID] = append(s.data.StatusHistory[incidentID], StatusChange{
+		NewStatus: StatusInvestigating,
+		ChangedBy: input.ReporterID,
+		ChangedAt: now,
+		Note:      "インシデント報告",
+	})
+
+	return incidentID, s.save()
+}
+
+// allocateID はインシデントIDを採番（呼び出し元でロックを取得しておくこと）
+func (s *memoryStore) allocateID(now time.Time) int64 {
+	if !s.temporaryIDs {
+		incidentID := s.data.NextID
+		s.data.NextID++
+		return incidentID
+	}
+
+	// 複数のレプリカのジャーナルでも重ならないよう、作成日時（ミリ秒）から負の仮IDを採番する
+	incidentID := -now.UnixMilli()
+	if s.data.LastTemporaryID != 0 && incidentID >= s.data.LastTemporaryID {
+		incidentID = s.data.LastTemporaryID - 1
+	}
+	s.data.LastTemporaryID = incidentID
+	return incidentID
+}
+
+// putIncident はインシデントをそのまま保存（変更履歴は記録しない）
+func (s *memoryStore) putIncident(incident Incident) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.data.Incidents[incident.ID] = &incident
+}
+
+// GetIncident はインシデントを取得
+func (s *memoryStore) GetIncident(incidentID int64) (*Incident, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	incident, exists := s.data.Incidents[incidentID]
+	if !exists {
+		return nil, errIncidentNotFound(incidentID)
+	}
+	copied := *incident
+	return &copied, nil
+}
+
+// findLatestByChannel はチャンネルのインシデントのうち条件に一致する最新のものを取得
+func (s *memoryStore) findLatestByChannel(channelID string, match func(*Incident) bool) *Incident {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var latest *Incident
+	for _, incident := range s.data.Incidents {
+		if incident.ChannelID != channelID || !match(incident) {
+			continue
+		}
+		if latest == nil || incident.CreatedAt.After(latest.CreatedAt) || (incident.CreatedAt.Equal(latest.CreatedAt) && incident.ID > latest.ID) {
+			latest = incident
+		}
+	}
+	if latest == nil {
+		return nil
+	}
+	copied := *latest
+	return &copied
+}
+
+// FindActiveIncidentByChannel はチャンネルの対応中のインシデントのうち最新のものを取得
+func (s *memoryStore) FindActiveIncidentByChannel(channelID string) (*Incident, error) {
+	return s.findLatestByChannel(channelID, func(i *Incident) bool { return isActiveStatus(i.Status) }), nil
+}
+
+// FindLatestIncidentByChannel はチャンネルの最新のインシデントを取得（復旧済み・クローズ済みも含む）
+func (s *memoryStore) FindLatestIncidentByChannel(channelID string) (*Incident, error) {
+	return s.findLatestByChannel(channelID, func(*Incident) bool { return true }), nil
+}
+
+// matchesIncidentListFilter はインシデントがフィルター条件に一致するかチェック（buildIncidentListQuery と同じ条件）
+func matchesIncidentListFilter(incident *Incident, filter IncidentListFilter) bool {
+	statuses := filter.Statuses
+	if len(statuses) == 0 {
+		statuses = activeStatuses
+	}
+	if !slices.Contains(statuses, incident.Status) {
+		return false
+	}
+	if len(filter.Severities) > 0 && !slices.Contains(filter.Severities, incident.Severity) {
+		return false
+	}
+	if filter.Unassigned {
+		if incident.HandlerID != "" {
+			return false
+		}
+	} else if filter.HandlerID != "" && incident.HandlerID != filter.HandlerID {
+		return false
+	}
+	if filter.ReporterID != "" && incident.ReporterID != filter.ReporterID {
+		return false
+	}
+	if !filter.Since.IsZero() && incident.CreatedAt.Before(filter.Since) {
+		return false
+	}
+	return true
+}
+
+// sortIncidents はインシデントを一覧の並び順に並べ替え（buildIncidentListQuery と同じ順序）
+func sortIncidents(incidents []Incident, sortBy string) {
+	severityRank := make(map[string]int, len(severityOrder))
+	for i, severity := range severityOrder {
+		severityRank[severity] = i
+	}
+	rank := func(severity string) int {
+		if r, ok := severityRank[severity]; ok {
+			return r
+		}
+		return len(severityOrder)
+	}
+	recent := func(incident Incident) time.Time {
+		if incident.ResolvedAt != nil {
+			return *incident.ResolvedAt
+		}
+		return incident.CreatedAt
+	}
+
+	sort.SliceStable(incidents, func(i, j int) bool {
+		a, b := incidents[i], incidents[j]
+		switch sortBy {
+		case incidentListSortSeverity:
+			if rank(a.Severity) != rank(b.Severity) {
+				return rank(a.Severity) < rank(b.Severity)
+			}
+		case incidentListSortOldest:
+			return a.CreatedAt.Before(b.CreatedAt)
+		case incidentListSortRecent:
+			return recent(a).After(recent(b))
+		}
+		return a.CreatedAt.After(b.CreatedAt)
+	})
+}
+
+// ListIncidents はフィルター条件に一致するインシデントの1ページ分と全件数を取得
+func (s *memoryStore) ListIncidents(filter IncidentListFilter, limit, offset int) ([]Incident, int, error) {
+	s.mu.RLock()
+	var matched []Incident
+	for _, incident := range s.data.Incidents {
+		if matchesIncidentListFilter(incident, filter) {
+			matched = append(matched, *incident)
+		}
+	}
+	s.mu.RUnlock()
+
+	return paginateIncidents(matched, filter.SortBy, limit, offset), len(matched), nil
+}
+
+// SearchIncidents はタイトル・詳細説明・影響範囲・復旧メモにすべてのキーワードを含むインシデントを取得
+// 大文字・小文字を区別しない部分一致で検索し、タイトルに含むキーワードが多いものを優先して新しい順に並べる
+func (s *memoryStore) SearchIncidents(terms []string, limit int) ([]Incident, error) {
+	keywords := make([]string, 0, len(terms))
+	for _, term := range terms {
+		keywords = append(keywords, strings.ToLower(term))
+	}
+
+	type searchResult struct {
+		incident Incident
+		score    int
+	}
+
+	s.mu.RLock()
+	var results []searchResult
+	for _, incident := range s.data.Incidents {
+		title := strings.ToLower(incident.Title)
+		text := strings.ToLower(strings.Join([]string{incident.Title, incident.Description, incident.Impact, incident.ResolutionNote}, " "))
+
+		matched, score := true, 0
+		for _, keyword := range keywords {
+			if !strings.Contains(text, keyword) {
+				matched = false
+				break
+			}
+			if strings.Contains(title, keyword) {
+				score++
+			}
+		}
+		if matched {
+			results = append(results, searchResult{incident: *incident, score: score})
+		}
+	}
+	s.mu.RUnlock()
+
+	sort.Slice(results, func(i, j int) bool {
+		a, b := results[i], results[j]
+		if a.score != b.score {
+			return a.score > b.score
+		}
+		if !a.incident.CreatedAt.Equal(b.incident.CreatedAt) {
+			return a.incident.CreatedAt.After(b.incident.CreatedAt)
+		}
+		return a.incident.ID > b.incident.ID
+	})
+
+	if limit > 0 && len(results) > limit {
+		results = results[:limit]
+	}
+	incidents := make([]Incident, 0, len(results))
+	for _, result := range results {
+		incidents = append(incidents, result.incident)
+	}
+	return incidents, nil
+}
+
+// paginateIncidents はインシデントを一覧の並び順に並べ替え、1ページ分を返す
+func paginateIncidents(incidents []Incident, sortBy string, limit, offset int) []Incident {
+	// 作成日時が同じ場合も順序が変わらないよう、ID順に並べてから並べ替える
+	sort.Slice(incidents, func(i, j int) bool { return incidents[i].ID > incidents[j].ID })
+	sortIncidents(incidents, sortBy)
+
+	if offset >= len(incidents) {
+		return nil
+	}
+	incidents = incidents[offset:]
+	if limit > 0 && len(incidents) > limit {
+		incidents = incidents[:limit]
+	}
+	return incidents
+}
+
+// UpdateIncidentField はタイトル・重要度・詳細説明・影響範囲のいずれかを更新し、更新履歴を記録
+func (s *memoryStore) UpdateIncidentField(incidentID int64, field, oldValue, newValue, updatedBy, updatedByName string) error {
+	if !updatableIncidentFields[field] {
+		return fmt.Errorf("更新できないフィールド: %s", field)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	incident, exists := s.data.Incidents[incidentID]
+	if !exists {
+		return errIncidentNotFound(incidentID)
+	}
+
+	switch field {
+	case "title":
+		incident.Title = newValue
+	case "severity":
+		incident.Severity = newValue
+	case "description":
+		incident.Description = newValue
+	case "impact":
+		incident.Impact = newValue
+	}
+
+	now := time.Now()
+	incident.UpdatedAt = now
+	s.data.UpdateHistory[incidentID] = append(s.data.UpdateHistory[incidentID], FieldUpdate{
+		FieldName:     field,
+		OldValue:      oldValue,
+		NewValue:      newValue,
+		UpdatedBy:     updatedBy,
+		UpdatedByName: updatedByName,
+		UpdatedAt:     now,
+	})
+
+	return s.save()
+}
+
+// ChangeHandler は担当者を変更し、担当者の変更履歴を記録
+func (s *memoryStore) ChangeHandler(incidentID int64, handlerID, handlerName, changedBy string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	incident, exists := s.data.Incidents[incidentID]
+	if !exists {
+		return errIncidentNotFound(incidentID)
+	}
+
+	now := time.Now()
+	s.data.HandlerHistory[incidentID] = append(s.data.HandlerHistory[incidentID], HandlerChange{
+		OldHandlerID: incident.HandlerID,
+		NewHandlerID: handlerID,
+		AssignedBy:   changedBy,
+		AssignedAt:   now,
+	})
+	incident.HandlerID = handlerID
+	incident.HandlerName = handlerName
+	incident.UpdatedAt = now
+
+	return s.save()
+}
+
+// ChangeStatus はステータスを変更し、変更前のステータスを返す
+func (s *memoryStore) ChangeStatus(incidentID int64, newStatus, changedBy, note string) (string, error) {
+	return s.changeStatus(incidentID, newStatus, changedBy, note, "")
+}
+
+// ResolveIncident はステータスを復旧済みに変更し、復旧メモ（空でない場合）と同時に保存して変更前のステータスを返す
+func (s *memoryStore) ResolveIncident(incidentID int64, changedBy, note, resolutionNote string) (string, error) {
+	return s.changeStatus(incidentID, StatusResolved, changedBy, note, resolutionNote)
+}
+
+// changeStatus はステータスの変更と復旧メモ（空でない場合）の保存をまとめて行う
+func (s *memoryStore) changeStatus(incidentID int64, newStatus, changedBy, note, resolutionNote string) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	incident, exists := s.data.Incidents[incidentID]
+	if !exists {
+		return "", errIncidentNotFound(incidentID)
+	}
+
+	oldStatus := normalizeStatus(incident.Status)
+	if err := validateTransition(oldStatus, newStatus); err != nil {
+		return "", err
+	}
+
+	now := time.Now()
+	incident.Status = newStatus
+	incident.UpdatedAt = now
+	if newStatus == StatusResolved {
+		incident.ResolvedAt = &now
+	}
+	if resolutionNote != "" {
+		incident.ResolutionNote = resolutionNote
+	}
+	s.data.StatusHistory[incidentID] = append(s.data.StatusHistory[incidentID], StatusChange{
+		OldStatus: oldStatus,
+		NewStatus: newStatus,
+		ChangedBy: changedBy,
+		ChangedAt: now,
+		Note:      note,
+	})
+
+	return oldStatus, s.save()
+}
+
+// latestFirst は古い順に記録された履歴を新しい順に最大 limit 件返す
+func latestFirst[T any](history []T, limit int) []T {
+	var result []T
+	for i := len(history) - 1; i >= 0 && (limit <= 0 || len(result) < limit); i-- {
+		result = append(result, history[i])
+	}
+	return result
+}
+
+// StatusHistory はステータスの変更履歴を新しい順に取得
+func (s *memoryStore) StatusHistory(incidentID int64, limit int) ([]StatusChange, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return latestFirst(s.data.StatusHistory[incidentID], limit), nil
+}
+
+// HandlerHistory は担当者の変更履歴を新しい順に取得
+func (s *memoryStore) HandlerHistory(incidentID int64, limit int) ([]HandlerChange, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return latestFirst(s.data.HandlerHistory[incidentID], limit), nil
+}
+
+// UpdateHistory は詳細情報の更新履歴を新しい順に取得
+func (s *memoryStore) UpdateHistory(incidentID int64, limit int) ([]FieldUpdate, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return latestFirst(s.data.UpdateHistory[incidentID], limit), nil
+}
+
+// AttachAlert はアラートをインシデントに紐付け、前回受信したときの状態を返す
+func (s *memoryStore) AttachAlert(alert IncidentAlert) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, exists := s.data.Incidents[alert.IncidentID]; !exists {
+		return "", errIncidentNotFound(alert.IncidentID)
+	}
+
+	now := time.Now()
+	alerts := s.data.Alerts[alert.IncidentID]
+	for i := range alerts {
+		if alerts[i].Fingerprint != alert.Fingerprint {
+			continue
+		}
+		previous := alerts[i].Status
+		alert.FirstReceivedAt = alerts[i].FirstReceivedAt
+		alert.LastReceivedAt = now
+		alert.ReceivedCount = alerts[i].ReceivedCount + 1
+		alerts[i] = alert
+		return previous, s.save()
+	}
+
+	alert.FirstReceivedAt = now
+	alert.LastReceivedAt = now
+	alert.ReceivedCount = 1
+	s.data.Alerts[alert.IncidentID] = append(alerts, alert)
+	return "", s.save()
+}
+
+// findIncidentByAlert はアラートが条件に一致する対応中のインシデントのうち最新のものを取得
+func (s *memoryStore) findIncidentByAlert(match func(IncidentAlert) bool) *Incident {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var latest *Incident
+	for incidentID, alerts := range s.data.Alerts {
+		incident, exists := s.data.Incidents[incidentID]
+		if !exists || !isActiveStatus(incident.Status) || !slices.ContainsFunc(alerts, match) {
+			continue
+		}
+		if latest == nil || incident.CreatedAt.After(latest.CreatedAt) || (incident.CreatedAt.Equal(latest.CreatedAt) && incident.ID > latest.ID) {
+			latest = incident
+		}
+	}
+	if latest == nil {
+		return nil
+	}
+	copied := *latest
+	return &copied
+}
+
+// FindIncidentByAlert はフィンガープリントのアラートを紐付けた対応中のインシデントを取得
+func (s *memoryStore) FindIncidentByAlert(fingerprint string) (*Incident, error) {
+	return s.findIncidentByAlert(func(a IncidentAlert) bool { return a.Fingerprint == fingerprint }), nil
+}
+
+// FindIncidentByAlertService は since 以降にサービスのアラートを受信した対応中のインシデントのうち最新のものを取得
+func (s *memoryStore) FindIncidentByAlertService(service string, since time.Time) (*Incident, error) {
+	return s.findIncidentByAlert(func(a IncidentAlert) bool {
+		return a.Service == service && !a.LastReceivedAt.Before(since)
+	}), nil
+}
+
+// IncidentAlerts はインシデントに紐付けたアラートを最初に受信した順に取得
+func (s *memoryStore) IncidentAlerts(incidentID int64) ([]IncidentAlert, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return append([]IncidentAlert(nil), s.data.Alerts[incidentID]...), nil
+}
+
+// CreateWebhookDelivery は Webhook の配信記録を保存し、配信IDを返す
+func (s *memoryStore) CreateWebhookDelivery(delivery WebhookDelivery) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, exists := s.data.Incidents[delivery.IncidentID]; !exists {
+		return 0, errIncidentNotFound(delivery.IncidentID)
+	}
+
+	now := time.Now()
+	delivery.ID = int64(len(s.data.Deliveries)) + 1
+	delivery.CreatedAt = now
+	delivery.UpdatedAt = now
+	s.data.Deliveries = append(s.data.Deliveries, delivery)
+	return delivery.ID, s.save()
+}
+
+// UpdateWebhookDelivery は配信記録の状態・送信回数・最後の結果を更新
+func (s *memoryStore) UpdateWebhookDelivery(delivery WebhookDelivery) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if delivery.ID <= 0 || delivery.ID > int64(len(s.data.Deliveries)) {
+		return errWebhookDeliveryNotFound(delivery.ID)
+	}
+	saved := &s.data.Deliveries[delivery.ID-1]
+	saved.Status = delivery.Status
+	saved.Attempts = delivery.Attempts
+	saved.ResponseCode = delivery.ResponseCode
+	saved.LastError = delivery.LastError
+	saved.DeliveredAt = delivery.DeliveredAt
+	saved.UpdatedAt = time.Now()
+	return s.save()
+}
+
+// GetWebhookDelivery は配信記録を取得
+func (s *memoryStore) GetWebhookDelivery(deliveryID int64) (*WebhookDelivery, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if deliveryID <= 0 || deliveryID > int64(len(s.data.Deliveries)) {
+		return nil, errWebhookDeliveryNotFound(deliveryID)
+	}
+	delivery := s.data.Deliveries[deliveryID-1]
+	return &delivery, nil
+}
+
+// WebhookDeliveries はインシデントの配信記録を新しい順に取得
+func (s *memoryStore) WebhookDeliveries(incidentID int64, limit int) ([]WebhookDelivery, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var deliveries []WebhookDelivery
+	for _, delivery := range s.data.Deliveries {
+		if delivery.IncidentID == incidentID {
+			deliveries = append(deliveries, delivery)
+		}
+	}
+	return latestFirst(deliveries, limit), nil
+}
+
+// AddActionItem はアクションアイテムを未完了として保存し、アクションアイテムIDを返す
+func (s *memoryStore) AddActionItem(item ActionItem) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, exists := s.data.Incidents[item.IncidentID]; !exists {
+		return 0, errIncidentNotFound(item.IncidentID)
+	}
+
+	item.ID = int64(len(s.data.ActionItems)) + 1
+	item.IncidentTitle = ""
+	item.Status = actionItemStatusOpen
+	item.CreatedAt = time.Now()
+	s.data.ActionItems = append(s.data.ActionItems, item)
+	return item.ID, s.save()
+}
+
+// withIncidentTitle はアクションアイテムにインシデントのタイトルを設定したものを返す（呼び出し元でロックを取得しておくこと）
+func (s *memoryStore) withIncidentTitle(item ActionItem) ActionItem {
+	if incident, exists := s.data.Incidents[item.IncidentID]; exists {
+		item.IncidentTitle = incident.Title
+	}
+	return item
+}
+
+// ListActionItems はアクションアイテムを未完了・期限の近い順に最大 limit 件取得（ListActionItems のクエリと同じ条件・順序）
+func (s *memoryStore) ListActionItems(incidentID int64, ownerID string, includeDone bool, limit int) ([]ActionItem, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var items []ActionItem
+	for _, item := range s.data.ActionItems {
+		if incidentID != 0 && item.IncidentID != incidentID {
+			continue
+		}
+		if ownerID != "" && item.OwnerID != ownerID {
+			continue
+		}
+		if !includeDone && item.Status != actionItemStatusOpen {
+			continue
+		}
+		items = append(items, s.withIncidentTitle(item))
+	}
+
+	sort.SliceStable(items, func(i, j int) bool {
+		a, b := items[i], items[j]
+		if (a.Status == actionItemStatusOpen) != (b.Status == actionItemStatusOpen) {
+			return a.Status == actionItemStatusOpen
+		}
+		switch {
+		case a.DueDate == nil && b.DueDate == nil:
+		case a.DueDate == nil || b.DueDate == nil:
+			return b.DueDate == nil
+		case !a.DueDate.Equal(*b.DueDate):
+			return a.DueDate.Before(*b.DueDate)
+		}
+		return a.ID < b.ID
+	})
+
+	if limit > 0 && len(items) > limit {
+		items = items[:limit]
+	}
+	return items, nil
+}
+
+// OverdueActionItems は期限切れで、today 以降にまだリマインドしていない未完了のアクションアイテムを取得
+// 期限は日付のみを表すため、日付の文字列で比較する
+func (s *memoryStore) OverdueActionItems(today time.Time) ([]ActionItem, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	todayDate := today.Format("2006-01-02")
+	var items []ActionItem
+	for _, item := range s.data.ActionItems {
+		if item.Status != actionItemStatusOpen || item.OwnerID == "" || item.DueDate == nil {
+			continue
+		}
+		if item.DueDate.Format("2006-01-02") >= todayDate {
+			continue
+		}
+		if item.LastRemindedAt != nil && !item.LastRemindedAt.Before(today) {
+			continue
+		}
+		items = append(items, s.withIncidentTitle(item))
+	}
+
+	sort.SliceStable(items, func(i, j int) bool {
+		if items[i].OwnerID != items[j].OwnerID {
+			return items[i].OwnerID < items[j].OwnerID
+		}
+		return items[i].DueDate.Before(*items[j].DueDate)
+	})
+	return items, nil
+}
+
+// CompleteActionItem は未完了のアクションアイテムを完了にし、完了にしたアクションアイテムを返す
+func (s *memoryStore) CompleteActionItem(itemID int64, completedBy string) (*ActionItem, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if itemID <= 0 || itemID > int64(len(s.data.ActionItems)) || s.data.ActionItems[itemID-1].Status != actionItemStatusOpen {
+		return nil, errActionItemNotCompletable(itemID)
+	}
+
+	now := time.Now()
+	item := &s.data.ActionItems[itemID-1]
+	item.Status = actionItemStatusDone
+	item.CompletedBy = completedBy
+	item.CompletedAt = &now
+
+	completed := s.withIncidentTitle(*item)
+	return &completed, s.save()
+}
+
+// MarkActionItemsReminded はアクションアイテムのリマインド日時を記録
+func (s *memoryStore) MarkActionItemsReminded(itemIDs []int64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	for _, itemID := range itemIDs {
+		if itemID > 0 && itemID <= int64(len(s.data.ActionItems)) {
+			s.data.ActionItems[itemID-1].LastRemindedAt = &now
+		}
+	}
+	return s.save()
+}
+
+// SaveTimekeeperState はタイムキーパーの状態と投稿間隔を保存
+func (s *memoryStore) SaveTimekeeperState(incidentID int64, state string, intervalMinutes int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, exists := s.data.Incidents[incidentID]; !exists {
+		return errIncidentNotFound(incidentID)
+	}
+
+	saved, exists := s.data.Timekeepers[incidentID]
+	if !exists {
+		lastMilestone := 0
+		saved = &TimekeeperState{LastMilestone: &lastMilestone}
+		s.data.Timekeepers[incidentID] = saved
+	}
+	saved.State = state
+	if intervalMinutes != 0 {
+		saved.IntervalMinutes = intervalMinutes
+	}
+	saved.UpdatedAt = time.Now()
+	return s.save()
+}
+
+// SaveTimekeeperProgress はタイムキーパーの投稿の進捗を保存
+func (s *memoryStore) SaveTimekeeperProgress(incidentID int64, progress timekeeperProgress) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	saved, exists := s.data.Timekeepers[incidentID]
+	if !exists {
+		return nil
+	}
+
+	saved.LastPostedAt = nil
+	if !progress.LastPostedAt.IsZero() {
+		lastPostedAt := progress.LastPostedAt
+		saved.LastPostedAt = &lastPostedAt
+	}
+	lastMilestone := progress.LastMilestone
+	saved.LastMilestone = &lastMilestone
+	saved.PinnedTS = progress.PinnedTS
+	saved.UpdatedAt = time.Now()
+	return s.save()
+}
+
+// SaveTimekeeperInterval はタイムキーパーの投稿間隔を保存
+func (s *memoryStore) SaveTimekeeperInterval(incidentID int64, intervalMinutes int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	saved, exists := s.data.Timekeepers[incidentID]
+	if !exists {
+		return nil
+	}
+	saved.IntervalMinutes = intervalMinutes
+	saved.UpdatedAt = time.Now()
+	return s.save()
+}
+
+// TimekeeperStates は複数のインシデントのタイムキーパーの状態を取得
+func (s *memoryStore) TimekeeperStates(incidentIDs []int64) (map[int64]TimekeeperState, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	states := make(map[int64]TimekeeperState)
+	for _, incidentID := range incidentIDs {
+		if saved, exists := s.data.Timekeepers[incidentID]; exists {
+			states[incidentID] = *saved
+		}
+	}
+	return states, nil
+}
+
+// TransitionTimekeeperState はタイムキーパーの状態が from のいずれかの場合のみ to に変更し、変更したかを返す
+func (s *memoryStore) TransitionTimekeeperState(incidentID int64, from []string, to string) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, exists := s.data.Incidents[incidentID]; !exists {
+		return false, errIncidentNotFound(incidentID)
+	}
+
+	now := time.Now()
+	saved, exists := s.data.Timekeepers[incidentID]
+	if !exists {
+		// 状態が保存されていない場合は動作中として扱う
+		if !slices.Contains(from, timekeeperStateRunning) {
+			return false, nil
+		}
+		lastMilestone := 0
+		s.data.Timekeepers[incidentID] = &TimekeeperState{State: to, LastMilestone: &lastMilestone, UpdatedAt: now}
+		return true, s.save()
+	}
+
+	if !slices.Contains(from, saved.State) {
+		return false, nil
+	}
+	saved.State = to
+	if to == timekeeperStateRunning {
+		saved.LastPostedAt = &now
+		saved.LastMilestone = nil
+	}
+	saved.UpdatedAt = now
+	return true, s.save()
+}
+
+// IncidentStats は集計期間とその直前の同じ長さの期間の重要度ごとの統計を取得（IncidentStats のクエリと同じ集計）
+func (s *memoryStore) IncidentStats(until time.Time, period time.Duration) (current, previous map[string]IncidentStats, err error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	type durations struct {
+		count    int
+		tta, ttr []time.Duration
+	}
+	groups := map[bool]map[string]*durations{true: {}, false: {}}
+	add := func(isCurrent bool, severity string, tta, ttr *time.Duration) {
+		group, exists := groups[isCurrent][severity]
+		if !exists {
+			group = &durations{}
+			groups[isCurrent][severity] = group
+		}
+		group.count++
+		if tta != nil {
+			group.tta = append(group.tta, *tta)
+		}
+		if ttr != nil {
+			group.ttr = append(group.ttr, *ttr)
+		}
+	}
+
+	currentFrom := until.Add(-period)
+	previousFrom := currentFrom.Add(-period)
+	for incidentID, incident := range s.data.Incidents {
+		if incident.CreatedAt.Before(previousFrom) || !incident.CreatedAt.Before(until) {
+			continue
+		}
+
+		var tta, ttr *time.Duration
+		for _, change := range s.data.HandlerHistory[incidentID] {
+			if elapsed := change.AssignedAt.Sub(incident.CreatedAt); tta == nil || elapsed < *tta {
+				tta = &elapsed
+			}
+		}
+		if incident.ResolvedAt != nil {
+			elapsed := incident.ResolvedAt.Sub(incident.CreatedAt)
+			ttr = &elapsed
+		}
+
+		isCurrent := !incident.CreatedAt.Before(currentFrom)
+		add(isCurrent, incident.Severity, tta, ttr)
+		add(isCurrent, "", tta, ttr)
+	}
+
+	result := func(isCurrent bool) map[string]IncidentStats {
+		stats := make(map[string]IncidentStats)
+		for severity, group := range groups[isCurrent] {
+			stats[severity] = IncidentStats{
+				Count:         group.count,
+				TimeToAssign:  summarizeDurations(group.tta),
+				TimeToResolve: summarizeDurations(group.ttr),
+			}
+		}
+		return stats
+	}
+	return result(true), result(false), nil
+}
+
+// summarizeDurations は所要時間の平均・中央値・90パーセンタイルを集計（PostgreSQL の percentile_cont と同じ線形補間）
+func summarizeDurations(values []time.Duration) DurationStats {
+	stats := DurationStats{Count: len(values)}
+	if len(values) == 0 {
+		return stats
+	}
+
+	sorted := append([]time.Duration(nil), values...)
+	slices.Sort(sorted)
+
+	var total time.Duration
+	for _, value := range sorted {
+		total += value
+	}
+	percentile := func(p float64) time.Duration {
+		position := p * float64(len(sorted)-1)
+		lower := int(position)
+		if lower+1 >= len(sorted) {
+			return sorted[lower]
+		}
+		fraction := position - float64(lower)
+		return sorted[lower] + time.Duration(fraction*float64(sorted[lower+1]-sorted[lower]))
+	}
+
+	stats.Mean = total / time.Duration(len(sorted))
+	stats.Median = percentile(0.5)
+	stats.P90 = percentile(0.9)
+	return stats
+}
+
+// ClaimProcessedEvent はイベントを処理済みとして記録し、初めて記録した場合は true を返す
+func (s *memoryStore) ClaimProcessedEvent(eventKey string, ttl time.Duration) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	if expiresAt, exists := s.data.ProcessedEvents[eventKey]; exists && now.Before(expiresAt) {
+		return false, nil
+	}
+	s.data.ProcessedEvents[eventKey] = now.Add(ttl)
+	return true, s.save()
+}
+
+// PurgeExpiredProcessedEvents は有効期限が切れた処理済みイベントを削除し、削除した件数を返す
+func (s *memoryStore) PurgeExpiredProcessedEvents() (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	var deleted int64
+	for eventKey, expiresAt := range s.data.ProcessedEvents {
+		if !now.Before(expiresAt) {
+			delete(s.data.ProcessedEvents, eventKey)
+			deleted++
+		}
+	}
+	if deleted == 0 {
+		return 0, nil
+	}
+	return deleted, s.save()
+}
+
+// LookupJournalIncidentID はジャーナルの仮IDに対応する登録後のインシデントIDを取得
+// メモリ上の保存先にはジャーナルのインシデントを登録しないため、常に未登録（0）を返す
+func (s *memoryStore) LookupJournalIncidentID(journalID int64) (int64, error) {
+	return 0, nil
+}
+
+// AddHomeViewer はApp Homeを開いたユーザーを再描画対象として記録（記録済みの場合は最後に開いた日時を更新）
+func (s *memoryStore) AddHomeViewer(userID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.data.HomeViewers[userID] = time.Now()
+	return s.save()
+}
+
+// HomeViewers は openedSince 以降にApp Homeを開いたユーザーの一覧を取得
+func (s *memoryStore) HomeViewers(openedSince time.Time) ([]string, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var userIDs []string
+	for userID, openedAt := range s.data.HomeViewers {
+		if !openedAt.Before(openedSince) {
+			userIDs = append(userIDs, userID)
+		}
+	}
+	slices.Sort(userIDs)
+	return userIDs, nil
+}
+
+// PurgeHomeViewers は openedBefore より前に最後にApp Homeを開いたユーザーを削除し、削除した件数を返す
+func (s *memoryStore) PurgeHomeViewers(openedBefore time.Time) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var deleted int64
+	for userID, openedAt := range s.data.HomeViewers {
+		if openedAt.Before(openedBefore) {
+			delete(s.data.HomeViewers, userID)
+			deleted++
+		}
+	}
+	if deleted == 0 {
+		return 0, nil
+	}
+	return deleted, s.save()
+}
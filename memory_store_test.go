@@ -0,0 +1,552 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// newTestIncident はテスト用のインシデントの入力内容を作成
+func newTestIncident(title, severity, channelID string) NewIncident {
+	return NewIncident{
+		Title:        title,
+		Severity:     severity,
+		Description:  "説明",
+		Impact:       "影響",
+		ChannelID:    channelID,
+		ChannelName:  "incident-" + channelID,
+		ReporterID:   "U001",
+		ReporterName: "reporter",
+	}
+}
+
+func TestMemoryStoreCreateAndFind(t *testing.T) {
+	s, err := newMemoryStore("")
+	if err != nil {
+		t.Fatalf("newMemoryStore() error = %v", err)
+	}
+
+	first, err := s.CreateIncident(newTestIncident("DB障害", "high", "C001"))
+	if err != nil {
+		t.Fatalf("CreateIncident() error = %v", err)
+	}
+	second, _ := s.CreateIncident(newTestIncident("API遅延", "medium", "C001"))
+	if first != 1 || second != 2 {
+		t.Errorf("IDが連番になっていません: %d, %d", first, second)
+	}
+
+	incident, err := s.GetIncident(first)
+	if err != nil {
+		t.Fatalf("GetIncident() error = %v", err)
+	}
+	if incident.Title != "DB障害" || incident.Status != StatusInvestigating {
+		t.Errorf("GetIncident() = %+v", incident)
+	}
+
+	// 取得したインシデントを変更しても保存内容には影響しない
+	incident.Title = "変更"
+	if got, _ := s.GetIncident(first); got.Title != "DB障害" {
+		t.Error("GetIncident() はコピーを返すべきです")
+	}
+
+	if _, err := s.GetIncident(99); err == nil {
+		t.Error("存在しないインシデントはエラーになるべきです")
+	}
+
+	// 対応中のインシデントのうち最新のもの
+	if _, err := s.ChangeStatus(second, StatusResolved, "U002", ""); err != nil {
+		t.Fatalf("ChangeStatus() error = %v", err)
+	}
+	active, err := s.FindActiveIncidentByChannel("C001")
+	if err != nil || active == nil || active.ID != first {
+		t.Errorf("FindActiveIncidentByChannel() = %+v, %v, want ID %d", active, err, first)
+	}
+	latest, err := s.FindLatestIncidentByChannel("C001")
+	if err != nil || latest == nil || latest.ID != second {
+		t.Errorf("FindLatestIncidentByChannel() = %+v, %v, want ID %d", latest, err, second)
+	}
+
+	// インシデントがないチャンネルはエラーではなく nil
+	if found, err := s.FindActiveIncidentByChannel("C999"); found != nil || err != nil {
+		t.Errorf("FindActiveIncidentByChannel() = %+v, %v, want nil, nil", found, err)
+	}
+}
+
+func TestMemoryStoreChangeStatus(t *testing.T) {
+	s, _ := newMemoryStore("")
+	id, _ := s.CreateIncident(newTestIncident("DB障害", "high", "C001"))
+
+	old, err := s.ChangeStatus(id, StatusIdentified, "U002", "原因はインデックス")
+	if err != nil {
+		t.Fatalf("ChangeStatus() error = %v", err)
+	}
+	if old != StatusInvestigating {
+		t.Errorf("変更前のステータス = %s, want %s", old, StatusInvestigating)
+	}
+
+	// 許可されていない遷移はエラーになり、ステータスも履歴も変わらない
+	if _, err := s.ChangeStatus(id, StatusClosed, "U002", ""); err == nil {
+		t.Error("原因特定からクローズへの変更はエラーになるべきです")
+	}
+
+	if _, err := s.ChangeStatus(id, StatusResolved, "U002", ""); err != nil {
+		t.Fatalf("ChangeStatus() error = %v", err)
+	}
+	incident, _ := s.GetIncident(id)
+	if incident.Status != StatusResolved || incident.ResolvedAt == nil {
+		t.Errorf("復旧時は復旧日時が記録されるべきです: %+v", incident)
+	}
+
+	history, _ := s.StatusHistory(id, 10)
+	if len(history) != 3 {
+		t.Fatalf("ステータス履歴の件数 = %d, want 3", len(history))
+	}
+	if history[0].NewStatus != StatusResolved || history[2].Note != "インシデント報告" {
+		t.Errorf("ステータス履歴は新しい順に並ぶべきです: %+v", history)
+	}
+	if history[1].OldStatus != StatusInvestigating || history[1].Note != "原因はインデックス" {
+		t.Errorf("ステータス履歴の内容が一致しません: %+v", history[1])
+	}
+
+	if limited, _ := s.StatusHistory(id, 1); len(limited) != 1 || limited[0].NewStatus != StatusResolved {
+		t.Errorf("StatusHistory(limit=1) = %+v", limited)
+	}
+}
+
+func TestMemoryStoreChangeHandlerAndUpdate(t *testing.T) {
+	s, _ := newMemoryStore("")
+	id, _ := s.CreateIncident(newTestIncident("DB障害", "high", "C001"))
+
+	if err := s.ChangeHandler(id, "U010", "alice", "U001"); err != nil {
+		t.Fatalf("ChangeHandler() error = %v", err)
+	}
+	if err := s.ChangeHandler(id, "U011", "bob", "U010"); err != nil {
+		t.Fatalf("ChangeHandler() error = %v", err)
+	}
+
+	incident, _ := s.GetIncident(id)
+	if incident.HandlerID != "U011" || incident.HandlerName != "bob" {
+		t.Errorf("担当者 = %s (%s), want U011 (bob)", incident.HandlerID, incident.HandlerName)
+	}
+	handlers, _ := s.HandlerHistory(id, 10)
+	if len(handlers) != 2 || handlers[0].OldHandlerID != "U010" || handlers[1].OldHandlerID != "" {
+		t.Errorf("担当者の変更履歴 = %+v", handlers)
+	}
+
+	if err := s.UpdateIncidentField(id, "severity", "high", "critical", "U010", "alice"); err != nil {
+		t.Fatalf("UpdateIncidentField() error = %v", err)
+	}
+	if incident, _ := s.GetIncident(id); incident.Severity != "critical" {
+		t.Errorf("重要度 = %s, want critical", incident.Severity)
+	}
+	updates, _ := s.UpdateHistory(id, 10)
+	if len(updates) != 1 || updates[0].FieldName != "severity" || updates[0].OldValue != "high" {
+		t.Errorf("更新履歴 = %+v", updates)
+	}
+
+	if err := s.UpdateIncidentField(id, "status", "", "closed", "U010", "alice"); err == nil {
+		t.Error("更新できないフィールドはエラーになるべきです")
+	}
+	if err := s.ChangeHandler(99, "U010", "alice", "U001"); err == nil {
+		t.Error("存在しないインシデントはエラーになるべきです")
+	}
+}
+
+func TestMemoryStoreListIncidents(t *testing.T) {
+	s, _ := newMemoryStore("")
+	base := time.Date(2025, 1, 1, 10, 0, 0, 0, time.UTC)
+
+	inputs := []struct {
+		severity string
+		handler  string
+	}{
+		{"low", "U010"},
+		{"critical", ""},
+		{"medium", "U010"},
+		{"high", ""},
+	}
+	for i, input := range inputs {
+		id, _ := s.CreateIncident(newTestIncident("インシデント", input.severity, "C001"))
+		s.data.Incidents[id].CreatedAt = base.Add(time.Duration(i) * time.Hour)
+		if input.handler != "" {
+			s.ChangeHandler(id, input.handler, "alice", "U001")
+		}
+	}
+	// 復旧済みのインシデントはデフォルトでは含まれない
+	resolved, _ := s.CreateIncident(newTestIncident("復旧済み", "critical", "C002"))
+	s.ChangeStatus(resolved, StatusResolved, "U001", "")
+
+	ids := func(incidents []Incident) []int64 {
+		var result []int64
+		for _, incident := range incidents {
+			result = append(result, incident.ID)
+		}
+		return result
+	}
+
+	tests := []struct {
+		name   string
+		filter IncidentListFilter
+		limit  int
+		offset int
+		want   []int64
+		total  int
+	}{
+		{"デフォルトは新しい順", IncidentListFilter{}, 0, 0, []int64{4, 3, 2, 1}, 4},
+		{"古い順", IncidentListFilter{SortBy: incidentListSortOldest}, 0, 0, []int64{1, 2, 3, 4}, 4},
+		{"重要度順", IncidentListFilter{SortBy: incidentListSortSeverity}, 0, 0, []int64{2, 4, 3, 1}, 4},
+		{"担当者", IncidentListFilter{HandlerID: "U010"}, 0, 0, []int64{3, 1}, 2},
+		{"未割り当て", IncidentListFilter{Unassigned: true}, 0, 0, []int64{4, 2}, 2},
+		{"重要度", IncidentListFilter{Severities: []string{"critical"}}, 0, 0, []int64{2}, 1},
+		{"期間", IncidentListFilter{Since: base.Add(2 * time.Hour)}, 0, 0, []int64{4, 3}, 2},
+		{"ステータス", IncidentListFilter{Statuses: finishedStatuses}, 0, 0, []int64{5}, 1},
+		{"ページ分割", IncidentListFilter{}, 2, 1, []int64{3, 2}, 4},
+		{"範囲外のページ", IncidentListFilter{}, 2, 10, nil, 4},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			incidents, total, err := s.ListIncidents(tt.filter, tt.limit, tt.offset)
+			if err != nil {
+				t.Fatalf("ListIncidents() error = %v", err)
+			}
+			if total != tt.total {
+				t.Errorf("total = %d, want %d", total, tt.total)
+			}
+			got := ids(incidents)
+			if len(got) != len(tt.want) {
+				t.Fatalf("ListIncidents() = %v, want %v", got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Errorf("ListIncidents() = %v, want %v", got, tt.want)
+					break
+				}
+			}
+		})
+	}
+}
+
+func TestMemoryStoreFilePersistence(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "incidents.json")
+
+	s, err := newMemoryStore(path)
+	if err != nil {
+		t.Fatalf("newMemoryStore() error = %v", err)
+	}
+	id, _ := s.CreateIncident(newTestIncident("DB障害", "high", "C001"))
+	s.ChangeHandler(id, "U010", "alice", "U001")
+	s.ResolveIncident(id, "U010", "", "再起動で復旧")
+
+	// 再起動後も同じ内容を読み込み、IDは続きから採番する
+	reloaded, err := newMemoryStore(path)
+	if err != nil {
+		t.Fatalf("newMemoryStore() error = %v", err)
+	}
+	incident, err := reloaded.GetIncident(id)
+	if err != nil {
+		t.Fatalf("GetIncident() error = %v", err)
+	}
+	if incident.HandlerID != "U010" || incident.Status != StatusResolved || incident.ResolutionNote != "再起動で復旧" || incident.ResolvedAt == nil {
+		t.Errorf("読み込んだインシデント = %+v", incident)
+	}
+	if history, _ := reloaded.StatusHistory(id, 10); len(history) != 2 {
+		t.Errorf("ステータス履歴の件数 = %d, want 2", len(history))
+	}
+	if next, _ := reloaded.CreateIncident(newTestIncident("API遅延", "low", "C002")); next != id+1 {
+		t.Errorf("次のID = %d, want %d", next, id+1)
+	}
+}
+
+func TestNewMemoryStoreInvalidFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "incidents.json")
+	if err := os.WriteFile(path, []byte("{invalid"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	_, err := newMemoryStore(path)
+	if err == nil || !strings.Contains(err.Error(), "インシデントファイル解析エラー") {
+		t.Errorf("newMemoryStore() error = %v, want 解析エラー", err)
+	}
+}
+
+func TestMemoryStoreAttachAlert(t *testing.T) {
+	s, _ := newMemoryStore("")
+	incidentID, _ := s.CreateIncident(newTestIncident("決済APIのエラー率上昇", "high", "C001"))
+	alert := IncidentAlert{IncidentID: incidentID, Fingerprint: "fp1", Source: "Alertmanager", Status: alertStatusFiring, Summary: "HighErrorRate", Service: "payments"}
+
+	// 初回は前回の状態が空で、再受信すると前回の状態を返して受信回数を増やす
+	if previous, err := s.AttachAlert(alert); err != nil || previous != "" {
+		t.Fatalf("AttachAlert() = %q, %v", previous, err)
+	}
+	alert.Status = alertStatusResolved
+	if previous, _ := s.AttachAlert(alert); previous != alertStatusFiring {
+		t.Errorf("AttachAlert() の前回の状態 = %q", previous)
+	}
+	alerts, _ := s.IncidentAlerts(incidentID)
+	if len(alerts) != 1 || alerts[0].Status != alertStatusResolved || alerts[0].ReceivedCount != 2 || alerts[0].LastReceivedAt.Before(alerts[0].FirstReceivedAt) {
+		t.Errorf("IncidentAlerts() = %+v", alerts)
+	}
+	if _, err := s.AttachAlert(IncidentAlert{IncidentID: 99, Fingerprint: "fp2"}); err == nil {
+		t.Error("存在しないインシデントへの紐付けはエラーになるべきです")
+	}
+
+	if incident, _ := s.FindIncidentByAlert("fp1"); incident == nil || incident.ID != incidentID {
+		t.Errorf("FindIncidentByAlert() = %+v", incident)
+	}
+	if incident, _ := s.FindIncidentByAlertService("payments", time.Now().Add(-time.Minute)); incident == nil || incident.ID != incidentID {
+		t.Errorf("FindIncidentByAlertService() = %+v", incident)
+	}
+	// 期間より前に受信したサービスのアラートはまとめない
+	if incident, _ := s.FindIncidentByAlertService("payments", time.Now().Add(time.Minute)); incident != nil {
+		t.Errorf("期間外のアラートのインシデントを取得しました: %+v", incident)
+	}
+
+	// 復旧済みのインシデントは対象外
+	s.ChangeStatus(incidentID, StatusResolved, "U001", "")
+	if incident, _ := s.FindIncidentByAlert("fp1"); incident != nil {
+		t.Errorf("復旧済みのインシデントを取得しました: %+v", incident)
+	}
+}
+
+func TestMemoryStoreWebhookDeliveries(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "incidents.json")
+	s, _ := newMemoryStore(path)
+	first, _ := s.CreateIncident(newTestIncident("決済APIのエラー率上昇", "high", "C001"))
+	second, _ := s.CreateIncident(newTestIncident("ログイン不可", "critical", "C002"))
+
+	for _, incidentID := range []int64{first, second, first} {
+		if _, err := s.CreateWebhookDelivery(WebhookDelivery{IncidentID: incidentID, Event: webhookEventCreated, Endpoint: "ticketing", Payload: "{}", Status: webhookDeliveryPending}); err != nil {
+			t.Fatalf("CreateWebhookDelivery() error = %v", err)
+		}
+	}
+	if _, err := s.CreateWebhookDelivery(WebhookDelivery{IncidentID: 99}); err == nil {
+		t.Error("存在しないインシデントの配信記録はエラーになるべきです")
+	}
+
+	now := time.Now()
+	if err := s.UpdateWebhookDelivery(WebhookDelivery{ID: 3, Status: webhookDeliverySucceeded, Attempts: 2, ResponseCode: 200, DeliveredAt: &now}); err != nil {
+		t.Fatalf("UpdateWebhookDelivery() error = %v", err)
+	}
+	if err := s.UpdateWebhookDelivery(WebhookDelivery{ID: 4}); err == nil {
+		t.Error("存在しない配信記録の更新はエラーになるべきです")
+	}
+
+	// ファイルから読み込み直しても配信記録を引き継ぐ
+	reloaded, _ := newMemoryStore(path)
+	deliveries, _ := reloaded.WebhookDeliveries(first, 0)
+	if len(deliveries) != 2 || deliveries[0].ID != 3 || deliveries[0].Status != webhookDeliverySucceeded || deliveries[0].Attempts != 2 || deliveries[0].Payload != "{}" || deliveries[1].ID != 1 {
+		t.Errorf("WebhookDeliveries() = %+v", deliveries)
+	}
+	if delivery, err := reloaded.GetWebhookDelivery(2); err != nil || delivery.IncidentID != second {
+		t.Errorf("GetWebhookDelivery() = %+v, %v", delivery, err)
+	}
+	if _, err := reloaded.GetWebhookDelivery(5); err == nil {
+		t.Error("存在しない配信記録の取得はエラーになるべきです")
+	}
+}
+
+func TestMemoryStoreActionItems(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "incidents.json")
+	s, _ := newMemoryStore(path)
+	first, _ := s.CreateIncident(newTestIncident("決済APIのエラー率上昇", "high", "C001"))
+	second, _ := s.CreateIncident(newTestIncident("ログイン不可", "critical", "C002"))
+
+	date := func(day int) *time.Time {
+		due := time.Date(2025, 1, day, 0, 0, 0, 0, time.UTC)
+		return &due
+	}
+	for _, item := range []ActionItem{
+		{IncidentID: first, Title: "閾値を見直す", OwnerID: "U2", DueDate: date(20), CreatedBy: "U1"},
+		{IncidentID: first, Title: "手順書を更新", OwnerID: "U1", CreatedBy: "U1"},
+		{IncidentID: first, Title: "監視を追加", OwnerID: "U1", DueDate: date(5), CreatedBy: "U1"},
+		{IncidentID: second, Title: "認証基盤を冗長化", OwnerID: "U1", DueDate: date(8), CreatedBy: "U1"},
+	} {
+		if _, err := s.AddActionItem(item); err != nil {
+			t.Fatalf("AddActionItem() error = %v", err)
+		}
+	}
+	if _, err := s.AddActionItem(ActionItem{IncidentID: 99, Title: "存在しない"}); err == nil {
+		t.Error("存在しないインシデントへの追加はエラーになるべきです")
+	}
+
+	item, err := s.CompleteActionItem(3, "U1")
+	if err != nil || item.IncidentID != first || item.Title != "監視を追加" || item.IncidentTitle != "決済APIのエラー率上昇" {
+		t.Fatalf("CompleteActionItem() = %+v, %v", item, err)
+	}
+	if _, err := s.CompleteActionItem(3, "U1"); err == nil {
+		t.Error("完了済みのアクションアイテムはエラーになるべきです")
+	}
+
+	// 未完了・期限の近い順（期限なしは後ろ）に並び、完了済みは最後
+	items, _ := s.ListActionItems(first, "", true, 0)
+	var ids []int64
+	for _, item := range items {
+		ids = append(ids, item.ID)
+	}
+	if len(ids) != 3 || ids[0] != 1 || ids[1] != 2 || ids[2] != 3 {
+		t.Errorf("ListActionItems() の順序 = %v, want [1 2 3]", ids)
+	}
+	if mine, _ := s.ListActionItems(0, "U1", false, 0); len(mine) != 2 || mine[0].ID != 4 || mine[0].IncidentTitle != "ログイン不可" {
+		t.Errorf("担当者で絞り込んだ ListActionItems() = %+v", mine)
+	}
+	if limited, _ := s.ListActionItems(0, "", true, 2); len(limited) != 2 {
+		t.Errorf("ListActionItems() の件数 = %d, want 2", len(limited))
+	}
+
+	// 期限切れで本日まだリマインドしていないものだけを担当者・期限の順に取得し、リマインド後は対象外
+	today := time.Date(2025, 1, 10, 0, 0, 0, 0, time.Local)
+	overdue, _ := s.OverdueActionItems(today)
+	if len(overdue) != 1 || overdue[0].ID != 4 {
+		t.Fatalf("OverdueActionItems() = %+v", overdue)
+	}
+	if err := s.MarkActionItemsReminded([]int64{4}); err != nil {
+		t.Fatalf("MarkActionItemsReminded() error = %v", err)
+	}
+	if overdue, _ := s.OverdueActionItems(today); len(overdue) != 0 {
+		t.Errorf("リマインド済みのアクションアイテムを取得しました: %+v", overdue)
+	}
+
+	// ファイルから読み込み直しても引き継ぐ
+	reloaded, _ := newMemoryStore(path)
+	if items, _ := reloaded.ListActionItems(first, "", true, 0); len(items) != 3 || items[2].Status != actionItemStatusDone || items[2].CompletedBy != "U1" {
+		t.Errorf("読み込んだアクションアイテム = %+v", items)
+	}
+}
+
+func TestMemoryStoreTimekeeperStates(t *testing.T) {
+	s, _ := newMemoryStore("")
+	first, _ := s.CreateIncident(newTestIncident("決済APIのエラー率上昇", "high", "C001"))
+	second, _ := s.CreateIncident(newTestIncident("ログイン不可", "critical", "C002"))
+
+	// 状態が保存されていない場合は動作中として扱う
+	if changed, err := s.TransitionTimekeeperState(first, []string{timekeeperStatePaused}, timekeeperStateRunning); err != nil || changed {
+		t.Errorf("一時停止中でないタイムキーパーを再開しました: %v, %v", changed, err)
+	}
+	if changed, _ := s.TransitionTimekeeperState(first, []string{timekeeperStateRunning}, timekeeperStatePaused); !changed {
+		t.Error("保存されていないタイムキーパーを一時停止できるべきです")
+	}
+
+	if err := s.SaveTimekeeperState(second, timekeeperStateRunning, 10); err != nil {
+		t.Fatalf("SaveTimekeeperState() error = %v", err)
+	}
+	posted := time.Now().Add(-time.Minute)
+	s.SaveTimekeeperProgress(second, timekeeperProgress{LastPostedAt: posted, LastMilestone: 30, PinnedTS: "1700000000.000100"})
+	s.SaveTimekeeperState(second, timekeeperStatePaused, 0)
+	s.SaveTimekeeperInterval(first, 20)
+
+	states, _ := s.TimekeeperStates([]int64{first, second, 99})
+	if len(states) != 2 || states[first].State != timekeeperStatePaused || states[first].IntervalMinutes != 20 {
+		t.Fatalf("TimekeeperStates() = %+v", states)
+	}
+	saved := states[second]
+	if saved.State != timekeeperStatePaused || saved.IntervalMinutes != 10 || saved.LastMilestone == nil || *saved.LastMilestone != 30 || saved.PinnedTS != "1700000000.000100" {
+		t.Errorf("保存した進捗が引き継がれていません: %+v", saved)
+	}
+
+	// 再開時は投稿の進捗をリセットする
+	if changed, _ := s.TransitionTimekeeperState(second, []string{timekeeperStatePaused}, timekeeperStateRunning); !changed {
+		t.Fatal("一時停止中のタイムキーパーを再開できるべきです")
+	}
+	states, _ = s.TimekeeperStates([]int64{second})
+	if resumed := states[second]; resumed.LastMilestone != nil || resumed.LastPostedAt == nil || !resumed.LastPostedAt.After(posted) {
+		t.Errorf("再開後の進捗 = %+v", resumed)
+	}
+}
+
+func TestMemoryStoreIncidentStats(t *testing.T) {
+	s, _ := newMemoryStore("")
+	until := time.Date(2025, 1, 15, 0, 0, 0, 0, time.UTC)
+	add := func(severity string, createdAt time.Time, assignAfter, resolveAfter time.Duration) {
+		incidentID, _ := s.CreateIncident(newTestIncident("障害", severity, "C001"))
+		incident := s.data.Incidents[incidentID]
+		incident.CreatedAt = createdAt
+		if assignAfter > 0 {
+			s.data.HandlerHistory[incidentID] = []HandlerChange{
+				{NewHandlerID: "U2", AssignedAt: createdAt.Add(assignAfter + time.Hour)},
+				{NewHandlerID: "U1", AssignedAt: createdAt.Add(assignAfter)},
+			}
+		}
+		if resolveAfter > 0 {
+			resolvedAt := createdAt.Add(resolveAfter)
+			incident.ResolvedAt = &resolvedAt
+		}
+	}
+	add("critical", until.Add(-24*time.Hour), 5*time.Minute, time.Hour)
+	add("critical", until.Add(-48*time.Hour), 15*time.Minute, 0)
+	add("low", until.Add(-72*time.Hour), 0, 3*time.Hour)
+	add("high", until.Add(-10*24*time.Hour), 10*time.Minute, 2*time.Hour)
+	add("high", until.Add(-30*24*time.Hour), time.Minute, time.Hour) // 集計期間外
+
+	current, previous, err := s.IncidentStats(until, 7*24*time.Hour)
+	if err != nil {
+		t.Fatalf("IncidentStats() error = %v", err)
+	}
+
+	overall := current[""]
+	if overall.Count != 3 || overall.TimeToAssign.Count != 2 || overall.TimeToResolve.Count != 2 {
+		t.Errorf("全体の統計 = %+v", overall)
+	}
+	if overall.TimeToAssign.Mean != 10*time.Minute || overall.TimeToAssign.Median != 10*time.Minute || overall.TimeToAssign.P90 != 14*time.Minute {
+		t.Errorf("MTTA = %+v", overall.TimeToAssign)
+	}
+	if critical := current["critical"]; critical.Count != 2 || critical.TimeToResolve.Median != time.Hour {
+		t.Errorf("重要度ごとの統計 = %+v", critical)
+	}
+	if len(previous) != 2 || previous["high"].Count != 1 || previous[""].TimeToResolve.Mean != 2*time.Hour {
+		t.Errorf("前の期間の統計 = %+v", previous)
+	}
+}
+
+func TestMemoryStoreProcessedEvents(t *testing.T) {
+	s, _ := newMemoryStore("")
+
+	if claimed, err := s.ClaimProcessedEvent("envelope:1", time.Hour); err != nil || !claimed {
+		t.Fatalf("ClaimProcessedEvent() = %v, %v", claimed, err)
+	}
+	if claimed, _ := s.ClaimProcessedEvent("envelope:1", time.Hour); claimed {
+		t.Error("処理済みのイベントを再び記録しました")
+	}
+
+	// 有効期限が切れたものは記録し直せ、削除の対象になる
+	s.data.ProcessedEvents["envelope:1"] = time.Now().Add(-time.Second)
+	s.data.ProcessedEvents["envelope:2"] = time.Now().Add(-time.Second)
+	if claimed, _ := s.ClaimProcessedEvent("envelope:1", time.Hour); !claimed {
+		t.Error("有効期限が切れたイベントは記録し直せるべきです")
+	}
+	if deleted, err := s.PurgeExpiredProcessedEvents(); err != nil || deleted != 1 {
+		t.Errorf("PurgeExpiredProcessedEvents() = %d, %v, want 1", deleted, err)
+	}
+}
+
+func TestMemoryStoreSearchIncidents(t *testing.T) {
+	s, _ := newMemoryStore("")
+	older, _ := s.CreateIncident(NewIncident{Title: "レプリカ遅延", Severity: "medium", Description: "DBのレプリケーションが遅延", ChannelID: "C001"})
+	titled, _ := s.CreateIncident(newTestIncident("DB接続エラー", "critical", "C002"))
+	newer, _ := s.CreateIncident(NewIncident{Title: "API遅延", Severity: "high", Impact: "db を使う全機能", ChannelID: "C003"})
+	s.CreateIncident(newTestIncident("ログイン不可", "high", "C004"))
+	s.ResolveIncident(older, "U001", "", "インデックスを追加")
+
+	// タイトルに含むものを優先し、同じ場合は新しい順（大文字・小文字は区別しない）
+	incidents, err := s.SearchIncidents([]string{"db"}, 10)
+	if err != nil {
+		t.Fatalf("SearchIncidents() error = %v", err)
+	}
+	var ids []int64
+	for _, incident := range incidents {
+		ids = append(ids, incident.ID)
+	}
+	if len(ids) != 3 || ids[0] != titled || ids[1] != newer || ids[2] != older {
+		t.Errorf("検索結果の順序 = %v, want [%d %d %d]", ids, titled, newer, older)
+	}
+
+	// すべてのキーワードを含むものだけを返し、復旧メモも検索対象
+	if incidents, _ := s.SearchIncidents([]string{"DB", "インデックス"}, 10); len(incidents) != 1 || incidents[0].ID != older {
+		t.Errorf("複数キーワードの検索結果 = %+v", incidents)
+	}
+	if incidents, _ := s.SearchIncidents([]string{"db"}, 2); len(incidents) != 2 {
+		t.Errorf("検索結果の件数 = %d, want 2", len(incidents))
+	}
+}
@@ -28,7 +28,7 @@ func GetIncidentGuidelines() string {
 
 *5️⃣ 事後対応*
 • インシデントレポートの作成
-• 再発防止策の検討
+• 再発防止策の検討（「📌 アクションアイテムを追加」ボタンで担当者・期限付きで登録）
 • ポストモーテムの実施
 
 ---
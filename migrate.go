@@ -0,0 +1,287 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log"
+	"strconv"
+	"time"
+)
+
+// migrationLockID は複数レプリカが同時にマイグレーションを適用しないためのアドバイザリロックのキー
+const migrationLockID int64 = 72_617_312
+
+// migrateUsage は migrate サブコマンドの使い方
+const migrateUsage = `使い方: incident-bot migrate <up|down|status>
+  up         未適用のマイグレーションをすべて適用
+  down [N]   適用済みのマイグレーションを新しい順に N 件取り消す（デフォルトは1件）
+  status     マイグレーションの適用状況を表示`
+
+// MigrationStatus はマイグレーションの適用状況
+type MigrationStatus struct {
+	Migration
+	AppliedAt *time.Time // 未適用の場合は nil
+}
+
+// ensureMigrationsTable は適用済みのマイグレーションを記録するテーブルを作成
+func ensureMigrationsTable(ctx context.Context, conn *sql.Conn) error {
+	query := `
+		CREATE TABLE IF NOT EXISTS schema_migrations (
+			version INTEGER PRIMARY KEY,
+			name VARCHAR(255) NOT NULL,
+			applied_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+		)
+	`
+	if _, err := conn.ExecContext(ctx, query); err != nil {
+		return fmt.Errorf("schema_migrationsテーブル作成エラー: %v", err)
+	}
+	return nil
+}
+
+// appliedMigrations は適用済みのマイグレーションのバージョンと適用日時を取得
+func appliedMigrations(ctx context.Context, conn *sql.Conn) (map[int]time.Time, error) {
+	rows, err := conn.QueryContext(ctx, `SELECT version, applied_at FROM schema_migrations`)
+	if err != nil {
+		return nil, fmt.Errorf("適用済みマイグレーション取得エラー: %v", err)
+	}
+	defer rows.Close()
+
+	applied := make(map[int]time.Time)
+	for rows.Next() {
+		var version int
+		var appliedAt sql.NullTime
+		if err := rows.Scan(&version, &appliedAt); err != nil {
+			return nil, fmt.Errorf("適用済みマイグレーション読み込みエラー: %v", err)
+		}
+		applied[version] = appliedAt.Time
+	}
+	return applied, rows.Err()
+}
+
+// withMigrationLock はアドバイザリロックを取得した専用のコネクションで fn を実行
+func withMigrationLock(fn func(ctx context.Context, conn *sql.Conn, applied map[int]bool) error) error {
+	if db == nil {
+		return fmt.Errorf("データベース接続が初期化されていません")
+	}
+
+	ctx := context.Background()
+	conn, err := db.Conn(ctx)
+	if err != nil {
+		return fmt.Errorf("マイグレーション用のコネクション取得エラー: %v", err)
+	}
+	defer conn.Close()
+
+	// ほかのレプリカがマイグレーション中の場合は終わるまで待つ
+	if _, err := conn.ExecContext(ctx, "SELECT pg_advisory_lock($1)", migrationLockID); err != nil {
+		return fmt.Errorf("マイグレーションのロック取得エラー: %v", err)
+	}
+	defer conn.ExecContext(ctx, "SELECT pg_advisory_unlock($1)", migrationLockID)
+
+	if err := ensureMigrationsTable(ctx, conn); err != nil {
+		return err
+	}
+	appliedAt, err := appliedMigrations(ctx, conn)
+	if err != nil {
+		return err
+	}
+
+	applied := make(map[int]bool, len(appliedAt))
+	for version := range appliedAt {
+		applied[version] = true
+	}
+	return fn(ctx, conn, applied)
+}
+
+// runMigration はマイグレーションのSQLと schema_migrations の更新を1つのトランザクションで実行
+func runMigration(ctx context.Context, conn *sql.Conn, migration Migration, up bool) error {
+	tx, err := conn.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("トランザクション開始エラー: %v", err)
+	}
+	defer tx.Rollback()
+
+	statements := migration.Down
+	record := `DELETE FROM schema_migrations WHERE version = $1`
+	args := []interface{}{migration.Version}
+	if up {
+		statements = migration.Up
+		record = `INSERT INTO schema_migrations (version, name) VALUES ($1, $2)`
+		args = append(args, migration.Name)
+	}
+
+	if _, err := tx.ExecContext(ctx, statements); err != nil {
+		return fmt.Errorf("マイグレーション %04d_%s の実行エラー: %v", migration.Version, migration.Name, err)
+	}
+	if _, err := tx.ExecContext(ctx, record, args...); err != nil {
+		return fmt.Errorf("マイグレーション %04d_%s の記録エラー: %v", migration.Version, migration.Name, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("マイグレーション %04d_%s のコミットエラー: %v", migration.Version, migration.Name, err)
+	}
+	return nil
+}
+
+// migrateUp は未適用のマイグレーションをすべて適用し、適用したものを返す
+// マイグレーションは IF NOT EXISTS で書かれているため、schema.sql で作成した既存のデータベースにも適用できる
+func migrateUp() ([]Migration, error) {
+	migrations, err := embeddedMigrations()
+	if err != nil {
+		return nil, err
+	}
+
+	var done []Migration
+	err = withMigrationLock(func(ctx context.Context, conn *sql.Conn, applied map[int]bool) error {
+		for _, migration := range pendingMigrations(migrations, applied) {
+			if err := runMigration(ctx, conn, migration, true); err != nil {
+				return err
+			}
+			log.Printf("マイグレーション %04d_%s を適用しました", migration.Version, migration.Name)
+			done = append(done, migration)
+		}
+		return nil
+	})
+	return done, err
+}
+
+// migrateDown は適用済みのマイグレーションを新しい順に steps 件取り消し、取り消したものを返す
+func migrateDown(steps int) ([]Migration, error) {
+	migrations, err := embeddedMigrations()
+	if err != nil {
+		return nil, err
+	}
+
+	var done []Migration
+	err = withMigrationLock(func(ctx context.Context, conn *sql.Conn, applied map[int]bool) error {
+		for _, migration := range rollbackMigrations(migrations, applied, steps) {
+			if err := runMigration(ctx, conn, migration, false); err != nil {
+				return err
+			}
+			log.Printf("マイグレーション %04d_%s を取り消しました", migration.Version, migration.Name)
+			done = append(done, migration)
+		}
+		return nil
+	})
+	return done, err
+}
+
+// migrationStatus はすべてのマイグレーションの適用状況を取得
+func migrationStatus() ([]MigrationStatus, error) {
+	if db == nil {
+		return nil, fmt.Errorf("データベース接続が初期化されていません")
+	}
+
+	migrations, err := embeddedMigrations()
+	if err != nil {
+		return nil, err
+	}
+
+	ctx := context.Background()
+	conn, err := db.Conn(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("マイグレーション用のコネクション取得エラー: %v", err)
+	}
+	defer conn.Close()
+
+	if err := ensureMigrationsTable(ctx, conn); err != nil {
+		return nil, err
+	}
+	appliedAt, err := appliedMigrations(ctx, conn)
+	if err != nil {
+		return nil, err
+	}
+
+	statuses := make([]MigrationStatus, 0, len(migrations))
+	for _, migration := range migrations {
+		status := MigrationStatus{Migration: migration}
+		if at, ok := appliedAt[migration.Version]; ok {
+			at := at
+			status.AppliedAt = &at
+		}
+		statuses = append(statuses, status)
+	}
+	return statuses, nil
+}
+
+// formatMigrationStatus はマイグレーションの適用状況を表示用に整形
+func formatMigrationStatus(statuses []MigrationStatus) string {
+	result := "バージョン  状態    適用日時             名前\n"
+	for _, status := range statuses {
+		state, appliedAt := "未適用", "-"
+		if status.AppliedAt != nil {
+			state, appliedAt = "適用済み", status.AppliedAt.Format("2006-01-02 15:04:05")
+		}
+		result += fmt.Sprintf("%04d        %s  %-19s  %s\n", status.Version, state, appliedAt, status.Name)
+	}
+	return result
+}
+
+// parseMigrateArgs は migrate サブコマンドの引数を解析
+func parseMigrateArgs(args []string) (string, int, error) {
+	if len(args) == 0 {
+		return "", 0, fmt.Errorf("サブコマンドを指定してください")
+	}
+
+	switch args[0] {
+	case "up", "status":
+		if len(args) > 1 {
+			return "", 0, fmt.Errorf("%s には引数を指定できません", args[0])
+		}
+		return args[0], 0, nil
+	case "down":
+		steps := 1
+		if len(args) > 2 {
+			return "", 0, fmt.Errorf("down の引数が多すぎます")
+		}
+		if len(args) == 2 {
+			n, err := strconv.Atoi(args[1])
+			if err != nil || n <= 0 {
+				return "", 0, fmt.Errorf("取り消す件数が不正です: %s", args[1])
+			}
+			steps = n
+		}
+		return "down", steps, nil
+	}
+	return "", 0, fmt.Errorf("不明なサブコマンドです: %s", args[0])
+}
+
+// runMigrateCommand は migrate サブコマンドを実行し、終了コードを返す
+func runMigrateCommand(args []string) int {
+	action, steps, err := parseMigrateArgs(args)
+	if err != nil {
+		fmt.Printf("%v\n%s\n", err, migrateUsage)
+		return 2
+	}
+
+	if err := initDB(); err != nil {
+		fmt.Printf("データベース接続エラー: %v\n", err)
+		return 1
+	}
+	defer db.Close()
+
+	switch action {
+	case "up":
+		done, err := migrateUp()
+		if err != nil {
+			fmt.Printf("マイグレーションエラー: %v\n", err)
+			return 1
+		}
+		fmt.Printf("%d 件のマイグレーションを適用しました\n", len(done))
+	case "down":
+		done, err := migrateDown(steps)
+		if err != nil {
+			fmt.Printf("マイグレーションエラー: %v\n", err)
+			return 1
+		}
+		fmt.Printf("%d 件のマイグレーションを取り消しました\n", len(done))
+	case "status":
+		statuses, err := migrationStatus()
+		if err != nil {
+			fmt.Printf("マイグレーション状況取得エラー: %v\n", err)
+			return 1
+		}
+		fmt.Print(formatMigrationStatus(statuses))
+	}
+	return 0
+}
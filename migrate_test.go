@@ -0,0 +1,62 @@
+package main
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseMigrateArgs(t *testing.T) {
+	tests := []struct {
+		args   []string
+		action string
+		steps  int
+	}{
+		{[]string{"up"}, "up", 0},
+		{[]string{"status"}, "status", 0},
+		{[]string{"down"}, "down", 1},
+		{[]string{"down", "3"}, "down", 3},
+	}
+	for _, tt := range tests {
+		action, steps, err := parseMigrateArgs(tt.args)
+		if err != nil || action != tt.action || steps != tt.steps {
+			t.Errorf("parseMigrateArgs(%q) = %q, %d, %v", tt.args, action, steps, err)
+		}
+	}
+
+	for _, args := range [][]string{nil, {"sideways"}, {"down", "0"}, {"down", "x"}, {"up", "1"}, {"down", "1", "2"}} {
+		if _, _, err := parseMigrateArgs(args); err == nil {
+			t.Errorf("parseMigrateArgs(%q) はエラーを返すべきです", args)
+		}
+	}
+}
+
+func TestFormatMigrationStatus(t *testing.T) {
+	appliedAt := time.Date(2025, 1, 1, 10, 0, 0, 0, time.UTC)
+	output := formatMigrationStatus([]MigrationStatus{
+		{Migration: Migration{Version: 1, Name: "create_incidents"}, AppliedAt: &appliedAt},
+		{Migration: Migration{Version: 2, Name: "add_incident_search"}},
+	})
+
+	for _, expected := range []string{"0001        適用済み  2025-01-01 10:00:00  create_incidents", "0002        未適用", "add_incident_search"} {
+		if !strings.Contains(output, expected) {
+			t.Errorf("%q が含まれていません:\n%s", expected, output)
+		}
+	}
+}
+
+func TestMigrateWithoutDatabase(t *testing.T) {
+	originalDB := db
+	db = nil
+	defer func() { db = originalDB }()
+
+	if _, err := migrateUp(); err == nil {
+		t.Error("データベースがnilの場合、migrateUpはエラーを返すべきです")
+	}
+	if _, err := migrateDown(1); err == nil {
+		t.Error("データベースがnilの場合、migrateDownはエラーを返すべきです")
+	}
+	if _, err := migrationStatus(); err == nil {
+		t.Error("データベースがnilの場合、migrationStatusはエラーを返すべきです")
+	}
+}
@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS incident_update_history;
+DROP TABLE IF EXISTS incident_handler_history;
+DROP TABLE IF EXISTS incident_status_history;
+DROP TABLE IF EXISTS incidents;
@@ -5,18 +5,28 @@ CREATE TABLE IF NOT EXISTS incidents (
     severity VARCHAR(50) NOT NULL,
     description TEXT,
     impact TEXT,
-    status VARCHAR(50) DEFAULT 'open',
+    status VARCHAR(50) DEFAULT 'investigating',
     channel_id VARCHAR(100) NOT NULL,
     channel_name VARCHAR(100) NOT NULL,
     reporter_id VARCHAR(100) NOT NULL,
     reporter_name VARCHAR(255),
     handler_id VARCHAR(100),
     handler_name VARCHAR(255),
+    source_permalink TEXT,
+    resolution_note TEXT,
     created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
     updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
     resolved_at TIMESTAMP
 );
 
+-- 既存環境向けの列追加
+ALTER TABLE incidents ADD COLUMN IF NOT EXISTS source_permalink TEXT;
+ALTER TABLE incidents ADD COLUMN IF NOT EXISTS resolution_note TEXT;
+
+-- 旧ステータス 'open' を 'investigating' に移行
+ALTER TABLE incidents ALTER COLUMN status SET DEFAULT 'investigating';
+UPDATE incidents SET status = 'investigating' WHERE status = 'open';
+
 -- インデックス
 CREATE INDEX IF NOT EXISTS idx_incidents_status ON incidents(status);
 CREATE INDEX IF NOT EXISTS idx_incidents_channel_id ON incidents(channel_id);
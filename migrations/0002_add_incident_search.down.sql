@@ -0,0 +1,5 @@
+-- pg_trgm 拡張はほかで使われている可能性があるため削除しない
+DROP INDEX IF EXISTS idx_incidents_search_text_trgm;
+DROP INDEX IF EXISTS idx_incidents_search_vector;
+ALTER TABLE incidents DROP COLUMN IF EXISTS search_vector;
+ALTER TABLE incidents DROP COLUMN IF EXISTS search_text;
@@ -0,0 +1,11 @@
+-- 全文検索（タイトル・詳細説明・影響範囲・復旧メモ）
+-- 日本語は空白で単語が区切られないため、pg_trgm による部分一致・類似度検索を併用する
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+ALTER TABLE incidents ADD COLUMN IF NOT EXISTS search_text TEXT GENERATED ALWAYS AS (
+    coalesce(title, '') || ' ' || coalesce(description, '') || ' ' || coalesce(impact, '') || ' ' || coalesce(resolution_note, '')
+) STORED;
+ALTER TABLE incidents ADD COLUMN IF NOT EXISTS search_vector tsvector GENERATED ALWAYS AS (
+    to_tsvector('simple', coalesce(title, '') || ' ' || coalesce(description, '') || ' ' || coalesce(impact, '') || ' ' || coalesce(resolution_note, ''))
+) STORED;
+CREATE INDEX IF NOT EXISTS idx_incidents_search_vector ON incidents USING GIN(search_vector);
+CREATE INDEX IF NOT EXISTS idx_incidents_search_text_trgm ON incidents USING GIN(search_text gin_trgm_ops);
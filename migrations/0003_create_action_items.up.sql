@@ -0,0 +1,21 @@
+-- 再発防止策などのアクションアイテムテーブル
+CREATE TABLE IF NOT EXISTS incident_action_items (
+    id SERIAL PRIMARY KEY,
+    incident_id INTEGER REFERENCES incidents(id) ON DELETE CASCADE,
+    title TEXT NOT NULL,
+    owner_id VARCHAR(100),
+    owner_name VARCHAR(255),
+    due_date DATE,
+    status VARCHAR(50) DEFAULT 'open',
+    link TEXT,
+    created_by VARCHAR(100) NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    completed_by VARCHAR(100),
+    completed_at TIMESTAMP,
+    last_reminded_at TIMESTAMP
+);
+
+-- インデックス
+CREATE INDEX IF NOT EXISTS idx_action_items_incident_id ON incident_action_items(incident_id);
+CREATE INDEX IF NOT EXISTS idx_action_items_status_due_date ON incident_action_items(status, due_date);
+CREATE INDEX IF NOT EXISTS idx_action_items_owner_id ON incident_action_items(owner_id);
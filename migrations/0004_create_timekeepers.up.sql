@@ -0,0 +1,10 @@
+-- タイムキーパーの状態テーブル（再起動後に停止・一時停止の状態と投稿の進捗を引き継ぐ）
+CREATE TABLE IF NOT EXISTS incident_timekeepers (
+    incident_id INTEGER PRIMARY KEY REFERENCES incidents(id) ON DELETE CASCADE,
+    state VARCHAR(20) NOT NULL DEFAULT 'running',
+    interval_minutes INTEGER,
+    last_posted_at TIMESTAMP,
+    last_milestone INTEGER DEFAULT 0,
+    pinned_ts VARCHAR(50),
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
@@ -0,0 +1,9 @@
+-- 処理済みイベントテーブル（Slack の再送や再接続による再配信を検出して二重に処理しない）
+CREATE TABLE IF NOT EXISTS processed_events (
+    event_key VARCHAR(255) PRIMARY KEY,
+    processed_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    expires_at TIMESTAMP NOT NULL
+);
+
+-- インデックス
+CREATE INDEX IF NOT EXISTS idx_processed_events_expires_at ON processed_events(expires_at);
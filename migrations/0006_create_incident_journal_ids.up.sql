@@ -0,0 +1,10 @@
+-- ジャーナルから登録したインシデントの仮IDテーブル
+-- データベースに接続できない間に仮ID（負の値）で作成したインシデントを、復旧後に登録したIDに対応付ける
+CREATE TABLE IF NOT EXISTS incident_journal_ids (
+    journal_id BIGINT PRIMARY KEY,
+    incident_id INTEGER NOT NULL REFERENCES incidents(id) ON DELETE CASCADE,
+    imported_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+-- インデックス
+CREATE INDEX IF NOT EXISTS idx_incident_journal_ids_incident_id ON incident_journal_ids(incident_id);
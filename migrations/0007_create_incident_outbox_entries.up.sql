@@ -0,0 +1,12 @@
+-- アウトボックスから登録した操作のテーブル
+-- データベースに接続できない間に記録した既存インシデントへの変更を、再接続後に二重に登録しないよう記録する
+CREATE TABLE IF NOT EXISTS incident_outbox_entries (
+    entry_id VARCHAR(255) PRIMARY KEY,
+    incident_id INTEGER NOT NULL REFERENCES incidents(id) ON DELETE CASCADE,
+    operation VARCHAR(50) NOT NULL,
+    recorded_at TIMESTAMP NOT NULL,
+    replayed_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+-- インデックス
+CREATE INDEX IF NOT EXISTS idx_incident_outbox_entries_incident_id ON incident_outbox_entries(incident_id);
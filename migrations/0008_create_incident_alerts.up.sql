@@ -0,0 +1,22 @@
+-- インシデントに紐付けたアラートのテーブル
+-- 同じアラート（フィンガープリント）を再受信した場合は新しいインシデントを作成せず、紐付け済みの行の状態を更新する
+CREATE TABLE IF NOT EXISTS incident_alerts (
+    incident_id INTEGER NOT NULL REFERENCES incidents(id) ON DELETE CASCADE,
+    fingerprint VARCHAR(255) NOT NULL,
+    source VARCHAR(50) NOT NULL,
+    status VARCHAR(20) NOT NULL,
+    summary TEXT NOT NULL,
+    service VARCHAR(255),
+    labels JSONB NOT NULL DEFAULT '{}',
+    starts_at TIMESTAMP,
+    ends_at TIMESTAMP,
+    generator_url TEXT,
+    first_received_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    last_received_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    received_count INTEGER NOT NULL DEFAULT 1,
+    PRIMARY KEY (incident_id, fingerprint)
+);
+
+-- インデックス
+CREATE INDEX IF NOT EXISTS idx_incident_alerts_fingerprint ON incident_alerts(fingerprint);
+CREATE INDEX IF NOT EXISTS idx_incident_alerts_service ON incident_alerts(service, last_received_at);
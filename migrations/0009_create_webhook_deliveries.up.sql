@@ -0,0 +1,19 @@
+-- インシデントの変化を外部のシステムに通知する Webhook の配信ログ
+-- 送信した本文をそのまま保存し、再送では同じ本文を送る
+CREATE TABLE IF NOT EXISTS webhook_deliveries (
+    id SERIAL PRIMARY KEY,
+    incident_id INTEGER NOT NULL REFERENCES incidents(id) ON DELETE CASCADE,
+    event VARCHAR(50) NOT NULL,
+    endpoint VARCHAR(255) NOT NULL,
+    payload TEXT NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'pending',
+    attempts INTEGER NOT NULL DEFAULT 0,
+    response_code INTEGER,
+    last_error TEXT,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    delivered_at TIMESTAMP
+);
+
+-- インデックス
+CREATE INDEX IF NOT EXISTS idx_webhook_deliveries_incident_id ON webhook_deliveries(incident_id, created_at);
@@ -0,0 +1,8 @@
+-- App Home を開いたユーザー（インシデントの状態が変化した時の再描画対象）
+-- 再起動後や別のレプリカでも再描画できるよう記録し、しばらく開いていないユーザーは削除する
+CREATE TABLE IF NOT EXISTS home_viewers (
+    user_id VARCHAR(50) PRIMARY KEY,
+    last_opened_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_home_viewers_last_opened_at ON home_viewers(last_opened_at);
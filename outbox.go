@@ -0,0 +1,186 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// defaultOutboxFile はデータベースに接続できない間の既存インシデントへの変更を記録するファイル
+const defaultOutboxFile = "incident-outbox.json"
+
+// アウトボックスに記録する操作の種類
+const (
+	outboxOpHandler        = "handler"         // 担当者の変更
+	outboxOpStatus         = "status"          // ステータスの変更（復旧と復旧メモを含む）
+	outboxOpResolutionNote = "resolution_note" // 復旧メモの保存（以前のバージョンで記録した操作の登録にのみ使用）
+	outboxOpField          = "field"           // タイトル・重要度などの更新
+)
+
+// outboxEntry はアウトボックスに記録した操作1件
+// RecordedAt は操作した日時で、再登録時も変更履歴の日時として使用する
+type outboxEntry struct {
+	ID         string    `json:"id"` // 再登録済みかの判定に使用（レプリカ間で重ならないようホスト名を含める）
+	Op         string    `json:"op"`
+	IncidentID int64     `json:"incident_id"`
+	ChangedBy  string    `json:"changed_by"`
+	RecordedAt time.Time `json:"recorded_at"`
+
+	HandlerID      string `json:"handler_id,omitempty"`
+	HandlerName    string `json:"handler_name,omitempty"`
+	NewStatus      string `json:"new_status,omitempty"`
+	Note           string `json:"note,omitempty"`            // ステータス変更のメモ（以前のバージョンでは復旧メモ）
+	ResolutionNote string `json:"resolution_note,omitempty"` // 復旧時に同時に保存する復旧メモ
+	Field          string `json:"field,omitempty"`
+	OldValue       string `json:"old_value,omitempty"`
+	NewValue       string `json:"new_value,omitempty"`
+	ChangedName    string `json:"changed_name,omitempty"` // 詳細情報の更新者名
+}
+
+// incidentOutbox は既存インシデントへの変更を記録順に保存する先行書き込みログ
+// 変更のたびにファイルに書き出すため、再起動しても再接続後に登録できる
+type incidentOutbox struct {
+	path    string // 空の場合はファイルに保存しない
+	host    string
+	mu      sync.Mutex
+	entries []outboxEntry
+	lastSeq int64
+}
+
+// outboxReplayer はアウトボックスの操作を登録する保存先（postgresStore）
+type outboxReplayer interface {
+	replayOutboxEntry(entry outboxEntry) error
+}
+
+// openIncidentOutbox はアウトボックスを開く（path が空の場合はデフォルトのファイル）
+func openIncidentOutbox(path string) (*incidentOutbox, error) {
+	if path == "" {
+		path = defaultOutboxFile
+	}
+	return newIncidentOutbox(path)
+}
+
+// newIncidentOutbox はアウトボックスを作成（path が空でない場合はファイルから読み込む）
+func newIncidentOutbox(path string) (*incidentOutbox, error) {
+	host, err := os.Hostname()
+	if err != nil || host == "" {
+		host = "incident-bot"
+	}
+	o := &incidentOutbox{path: path, host: host}
+	if path == "" {
+		return o, nil
+	}
+
+	content, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return o, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("アウトボックス読み込みエラー: %v", err)
+	}
+	if err := json.Unmarshal(content, &o.entries); err != nil {
+		return nil, fmt.Errorf("アウトボックス解析エラー: %v", err)
+	}
+	return o, nil
+}
+
+// save はファイルに書き出す（呼び出し元でロックを取得しておくこと）
+// 書き込み途中で停止しても壊れないよう、一時ファイルに書き出してから置き換える
+func (o *incidentOutbox) save() error {
+	if o.path == "" {
+		return nil
+	}
+
+	content, err := json.MarshalIndent(o.entries, "", "  ")
+	if err != nil {
+		return fmt.Errorf("アウトボックス書き込みエラー: %v", err)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(o.path), filepath.Base(o.path)+".tmp*")
+	if err != nil {
+		return fmt.Errorf("アウトボックス書き込みエラー: %v", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(content); err != nil {
+		tmp.Close()
+		return fmt.Errorf("アウトボックス書き込みエラー: %v", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("アウトボックス書き込みエラー: %v", err)
+	}
+	if err := os.Rename(tmp.Name(), o.path); err != nil {
+		return fmt.Errorf("アウトボックス書き込みエラー: %v", err)
+	}
+	return nil
+}
+
+// append は操作を末尾に記録（ID と日時が未設定の場合は採番する）
+func (o *incidentOutbox) append(entry outboxEntry) error {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	if entry.RecordedAt.IsZero() {
+		entry.RecordedAt = time.Now()
+	}
+	if entry.ID == "" {
+		// 同じナノ秒に記録しても重ならないよう、前回より必ず大きい値にする
+		seq := entry.RecordedAt.UnixNano()
+		if seq <= o.lastSeq {
+			seq = o.lastSeq + 1
+		}
+		o.lastSeq = seq
+		entry.ID = fmt.Sprintf("%s-%d", o.host, seq)
+	}
+
+	o.entries = append(o.entries, entry)
+	if err := o.save(); err != nil {
+		o.entries = o.entries[:len(o.entries)-1]
+		return err
+	}
+	return nil
+}
+
+// pending は未登録の操作を記録順に取得
+func (o *incidentOutbox) pending() []outboxEntry {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	return append([]outboxEntry(nil), o.entries...)
+}
+
+// remove は登録済みの操作を削除
+func (o *incidentOutbox) remove(entryID string) error {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	for i, entry := range o.entries {
+		if entry.ID == entryID {
+			o.entries = append(o.entries[:i], o.entries[i+1:]...)
+			return o.save()
+		}
+	}
+	return nil
+}
+
+// replayIncidentOutbox はアウトボックスの操作を記録順にデータベースに登録し、登録したものをアウトボックスから削除
+// 途中で失敗した場合は順序を保つためそこで止め、残りはアウトボックスに残す（次回の登録時に続きから登録する）
+func replayIncidentOutbox(replayer outboxReplayer, outbox *incidentOutbox) ([]outboxEntry, error) {
+	var replayed []outboxEntry
+	for _, entry := range outbox.pending() {
+		if err := replayer.replayOutboxEntry(entry); err != nil {
+			return replayed, fmt.Errorf("インシデント %d の操作 %s (%s) の登録エラー: %v", entry.IncidentID, entry.Op, entry.ID, err)
+		}
+
+		replayed = append(replayed, entry)
+		log.Printf("アウトボックスの操作 %s (インシデント %d, %s) を登録しました", entry.Op, entry.IncidentID, entry.RecordedAt.Format("2006-01-02 15:04:05"))
+
+		if err := outbox.remove(entry.ID); err != nil {
+			return replayed, err
+		}
+	}
+	return replayed, nil
+}
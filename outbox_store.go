@@ -0,0 +1,629 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"sync"
+	"time"
+)
+
+// outboxStore は PostgreSQL に書き込めない間も操作を失わないようにする IncidentStore
+// データベースに接続できている間は PostgreSQL に保存し、接続できなくなった場合は縮退運転に切り替えて
+// 新しいインシデントをジャーナル（仮ID）に、既存のインシデントへの変更をアウトボックスに記録する
+// どちらも再接続後に記録した順に PostgreSQL に登録する（recoverFromDegradedMode）
+type outboxStore struct {
+	mu      sync.RWMutex
+	primary IncidentStore // nil の場合は縮退運転中
+
+	journal  *memoryStore
+	outbox   *incidentOutbox
+	snapshot *memoryStore // PostgreSQL から最後に取得したインシデント（縮退運転中の参照と変更の検証に使用）
+
+	// unavailable は書き込みの失敗がデータベースに接続できないためかを判定する
+	unavailable func(err error) bool
+	// onUnavailable は縮退運転に切り替えたときに呼び出す（再接続の開始）
+	onUnavailable func()
+}
+
+// newOutboxStore は outboxStore を作成（primary が nil の場合は縮退運転から開始する）
+func newOutboxStore(primary IncidentStore, journal *memoryStore, outbox *incidentOutbox) *outboxStore {
+	snapshot, _ := newMemoryStore("")
+	return &outboxStore{
+		primary:     primary,
+		journal:     journal,
+		outbox:      outbox,
+		snapshot:    snapshot,
+		unavailable: databaseUnavailable,
+	}
+}
+
+// databaseUnavailable はデータベースに接続できない状態かチェック
+// 存在しないインシデントや許可されていない遷移などのエラーと区別するため、接続を確認する
+func databaseUnavailable(err error) bool {
+	return db == nil || db.Ping() != nil
+}
+
+// current は PostgreSQL の保存先を取得（縮退運転中は nil）
+func (s *outboxStore) current() IncidentStore {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.primary
+}
+
+// fallback は書き込みに失敗した原因がデータベースに接続できないためであれば縮退運転に切り替え、true を返す
+func (s *outboxStore) fallback(err error) bool {
+	if !s.unavailable(err) {
+		return false
+	}
+
+	s.mu.Lock()
+	wasHealthy := s.primary != nil
+	s.primary = nil
+	s.mu.Unlock()
+
+	if wasHealthy {
+		log.Printf("データベースに書き込めないため縮退運転に切り替えます: %v", err)
+		dbHealth.markDegraded(time.Now())
+		if s.onUnavailable != nil {
+			s.onUnavailable()
+		}
+	}
+	return true
+}
+
+// remember は PostgreSQL から取得したインシデントを縮退運転中に参照できるよう保持する
+func (s *outboxStore) remember(incidents ...Incident) {
+	for _, incident := range incidents {
+		s.snapshot.putIncident(incident)
+	}
+}
+
+// refresh は書き込み後のインシデントを PostgreSQL から取得し直して保持する
+func (s *outboxStore) refresh(primary IncidentStore, incidentID int64) {
+	if incident, err := primary.GetIncident(incidentID); err == nil {
+		s.remember(*incident)
+	}
+}
+
+// CreateIncident はインシデントを保存（縮退運転中はジャーナルに仮IDで保存）
+func (s *outboxStore) CreateIncident(input NewIncident) (int64, error) {
+	if primary := s.current(); primary != nil {
+		incidentID, err := primary.CreateIncident(input)
+		if err == nil || !s.fallback(err) {
+			return incidentID, err
+		}
+	}
+	return s.journal.CreateIncident(input)
+}
+
+// GetIncident はインシデントを取得（縮退運転中は最後に取得した内容に記録済みの変更を反映したもの）
+func (s *outboxStore) GetIncident(incidentID int64) (*Incident, error) {
+	if incidentID < 0 {
+		return s.journal.GetIncident(incidentID)
+	}
+	if primary := s.current(); primary != nil {
+		incident, err := primary.GetIncident(incidentID)
+		if err == nil {
+			s.remember(*incident)
+			return incident, nil
+		}
+		if !s.fallback(err) {
+			return nil, err
+		}
+	}
+	return s.snapshot.GetIncident(incidentID)
+}
+
+// findByChannel はチャンネルのインシデントを取得（縮退運転中はジャーナルを優先）
+func (s *outboxStore) findByChannel(channelID string, find func(IncidentStore) (*Incident, error)) (*Incident, error) {
+	if primary := s.current(); primary != nil {
+		incident, err := find(primary)
+		if err == nil {
+			if incident != nil {
+				s.remember(*incident)
+			}
+			return incident, nil
+		}
+		if !s.fallback(err) {
+			return nil, err
+		}
+	}
+
+	if incident, err := find(s.journal); err != nil || incident != nil {
+		return incident, err
+	}
+	return find(s.snapshot)
+}
+
+// FindActiveIncidentByChannel はチャンネルの対応中のインシデントのうち最新のものを取得
+func (s *outboxStore) FindActiveIncidentByChannel(channelID string) (*Incident, error) {
+	return s.findByChannel(channelID, func(target IncidentStore) (*Incident, error) {
+		return target.FindActiveIncidentByChannel(channelID)
+	})
+}
+
+// FindLatestIncidentByChannel はチャンネルの最新のインシデントを取得（復旧済み・クローズ済みも含む）
+func (s *outboxStore) FindLatestIncidentByChannel(channelID string) (*Incident, error) {
+	return s.findByChannel(channelID, func(target IncidentStore) (*Incident, error) {
+		return target.FindLatestIncidentByChannel(channelID)
+	})
+}
+
+// ListIncidents はフィルター条件に一致するインシデントの1ページ分と全件数を取得
+// 縮退運転中はジャーナルと最後に取得したインシデントを合わせて一覧にする
+func (s *outboxStore) ListIncidents(filter IncidentListFilter, limit, offset int) ([]Incident, int, error) {
+	if primary := s.current(); primary != nil {
+		incidents, total, err := primary.ListIncidents(filter, limit, offset)
+		if err == nil {
+			s.remember(incidents...)
+			return incidents, total, nil
+		}
+		if !s.fallback(err) {
+			return nil, 0, err
+		}
+	}
+
+	journaled, _, _ := s.journal.ListIncidents(filter, 0, 0)
+	remembered, _, _ := s.snapshot.ListIncidents(filter, 0, 0)
+	return paginateIncidents(append(journaled, remembered...), filter.SortBy, limit, offset), len(journaled) + len(remembered), nil
+}
+
+// SearchIncidents はキーワードに一致する過去のインシデントを関連度順に取得
+// 縮退運転中はジャーナルと最後に取得したインシデントから部分一致で検索する
+func (s *outboxStore) SearchIncidents(terms []string, limit int) ([]Incident, error) {
+	if primary := s.current(); primary != nil {
+		incidents, err := primary.SearchIncidents(terms, limit)
+		if err == nil || !s.fallback(err) {
+			return incidents, err
+		}
+	}
+
+	journaled, _ := s.journal.SearchIncidents(terms, 0)
+	remembered, _ := s.snapshot.SearchIncidents(terms, 0)
+	incidents := append(journaled, remembered...)
+	if limit > 0 && len(incidents) > limit {
+		incidents = incidents[:limit]
+	}
+	return incidents, nil
+}
+
+// write は PostgreSQL に書き込み、接続できない場合はアウトボックスに記録する
+// 縮退運転中は最後に取得した内容に変更を反映して検証してから記録する（仮IDのインシデントはジャーナルに保存）
+func (s *outboxStore) write(incidentID int64, apply func(IncidentStore) error, entry outboxEntry) error {
+	if incidentID < 0 {
+		return apply(s.journal)
+	}
+
+	// 縮退運転中の記録が終わるまで復旧（restore）を待たせ、記録した操作を取りこぼさないようにする
+	for {
+		s.mu.RLock()
+		primary := s.primary
+		if primary == nil {
+			break
+		}
+		s.mu.RUnlock()
+
+		err := apply(primary)
+		if err == nil {
+			s.refresh(primary, incidentID)
+			return nil
+		}
+		if !s.fallback(err) {
+			return err
+		}
+	}
+	defer s.mu.RUnlock()
+
+	if _, err := s.snapshot.GetIncident(incidentID); err != nil {
+		return fmt.Errorf("データベースに接続できないため、インシデント %d を変更できません（縮退運転前に取得していないインシデントです）", incidentID)
+	}
+	if err := apply(s.snapshot); err != nil {
+		return err
+	}
+
+	entry.IncidentID = incidentID
+	if err := s.outbox.append(entry); err != nil {
+		return err
+	}
+	log.Printf("データベースに接続できないため、インシデント %d の操作 %s をアウトボックスに記録しました", incidentID, entry.Op)
+	return nil
+}
+
+// UpdateIncidentField はタイトル・重要度・詳細説明・影響範囲のいずれかを更新し、更新履歴を記録
+func (s *outboxStore) UpdateIncidentField(incidentID int64, field, oldValue, newValue, updatedBy, updatedByName string) error {
+	return s.write(incidentID, func(target IncidentStore) error {
+		return target.UpdateIncidentField(incidentID, field, oldValue, newValue, updatedBy, updatedByName)
+	}, outboxEntry{Op: outboxOpField, Field: field, OldValue: oldValue, NewValue: newValue, ChangedBy: updatedBy, ChangedName: updatedByName})
+}
+
+// ChangeHandler は担当者を変更し、担当者の変更履歴を記録
+func (s *outboxStore) ChangeHandler(incidentID int64, handlerID, handlerName, changedBy string) error {
+	return s.write(incidentID, func(target IncidentStore) error {
+		return target.ChangeHandler(incidentID, handlerID, handlerName, changedBy)
+	}, outboxEntry{Op: outboxOpHandler, HandlerID: handlerID, HandlerName: handlerName, ChangedBy: changedBy})
+}
+
+// ChangeStatus はステータスを変更し、変更前のステータスを返す
+func (s *outboxStore) ChangeStatus(incidentID int64, newStatus, changedBy, note string) (string, error) {
+	var oldStatus string
+	err := s.write(incidentID, func(target IncidentStore) error {
+		var err error
+		oldStatus, err = target.ChangeStatus(incidentID, newStatus, changedBy, note)
+		return err
+	}, outboxEntry{Op: outboxOpStatus, NewStatus: newStatus, ChangedBy: changedBy, Note: note})
+	if err != nil {
+		return "", err
+	}
+	return oldStatus, nil
+}
+
+// ResolveIncident はステータスを復旧済みに変更し、復旧メモ（空でない場合）と同時に保存して変更前のステータスを返す
+func (s *outboxStore) ResolveIncident(incidentID int64, changedBy, note, resolutionNote string) (string, error) {
+	var oldStatus string
+	err := s.write(incidentID, func(target IncidentStore) error {
+		var err error
+		oldStatus, err = target.ResolveIncident(incidentID, changedBy, note, resolutionNote)
+		return err
+	}, outboxEntry{Op: outboxOpStatus, NewStatus: StatusResolved, ChangedBy: changedBy, Note: note, ResolutionNote: resolutionNote})
+	if err != nil {
+		return "", err
+	}
+	return oldStatus, nil
+}
+
+// outboxHistory は変更履歴を取得（縮退運転中は縮退運転中に記録した分のみ）
+func outboxHistory[T any](s *outboxStore, incidentID int64, get func(IncidentStore) ([]T, error)) ([]T, error) {
+	if incidentID < 0 {
+		return get(s.journal)
+	}
+	if primary := s.current(); primary != nil {
+		records, err := get(primary)
+		if err == nil || !s.fallback(err) {
+			return records, err
+		}
+	}
+	return get(s.snapshot)
+}
+
+// StatusHistory はステータスの変更履歴を新しい順に取得
+func (s *outboxStore) StatusHistory(incidentID int64, limit int) ([]StatusChange, error) {
+	return outboxHistory(s, incidentID, func(target IncidentStore) ([]StatusChange, error) {
+		return target.StatusHistory(incidentID, limit)
+	})
+}
+
+// HandlerHistory は担当者の変更履歴を新しい順に取得
+func (s *outboxStore) HandlerHistory(incidentID int64, limit int) ([]HandlerChange, error) {
+	return outboxHistory(s, incidentID, func(target IncidentStore) ([]HandlerChange, error) {
+		return target.HandlerHistory(incidentID, limit)
+	})
+}
+
+// UpdateHistory は詳細情報の更新履歴を新しい順に取得
+func (s *outboxStore) UpdateHistory(incidentID int64, limit int) ([]FieldUpdate, error) {
+	return outboxHistory(s, incidentID, func(target IncidentStore) ([]FieldUpdate, error) {
+		return target.UpdateHistory(incidentID, limit)
+	})
+}
+
+// AttachAlert はアラートをインシデントに紐付け、前回受信したときの状態を返す
+// アラートはアウトボックスに記録しないため、縮退運転中は仮IDのインシデントにのみ紐付けられる（Webhook の送信元の再送で紐付ける）
+func (s *outboxStore) AttachAlert(alert IncidentAlert) (string, error) {
+	if alert.IncidentID < 0 {
+		return s.journal.AttachAlert(alert)
+	}
+	if primary := s.current(); primary != nil {
+		previous, err := primary.AttachAlert(alert)
+		if err == nil || !s.fallback(err) {
+			return previous, err
+		}
+	}
+	return "", fmt.Errorf("データベースに接続できないため、アラートをインシデント %d に紐付けできません", alert.IncidentID)
+}
+
+// findByAlert はアラートを紐付けたインシデントを取得（縮退運転中はジャーナルのインシデントのみ）
+func (s *outboxStore) findByAlert(find func(IncidentStore) (*Incident, error)) (*Incident, error) {
+	if primary := s.current(); primary != nil {
+		incident, err := find(primary)
+		if err == nil {
+			if incident != nil {
+				s.remember(*incident)
+			}
+			return incident, nil
+		}
+		if !s.fallback(err) {
+			return nil, err
+		}
+	}
+	return find(s.journal)
+}
+
+// FindIncidentByAlert はフィンガープリントのアラートを紐付けた対応中のインシデントを取得
+func (s *outboxStore) FindIncidentByAlert(fingerprint string) (*Incident, error) {
+	return s.findByAlert(func(target IncidentStore) (*Incident, error) {
+		return target.FindIncidentByAlert(fingerprint)
+	})
+}
+
+// FindIncidentByAlertService は since 以降にサービスのアラートを受信した対応中のインシデントのうち最新のものを取得
+func (s *outboxStore) FindIncidentByAlertService(service string, since time.Time) (*Incident, error) {
+	return s.findByAlert(func(target IncidentStore) (*Incident, error) {
+		return target.FindIncidentByAlertService(service, since)
+	})
+}
+
+// IncidentAlerts はインシデントに紐付けたアラートを最初に受信した順に取得
+func (s *outboxStore) IncidentAlerts(incidentID int64) ([]IncidentAlert, error) {
+	if incidentID < 0 {
+		return s.journal.IncidentAlerts(incidentID)
+	}
+	if primary := s.current(); primary != nil {
+		alerts, err := primary.IncidentAlerts(incidentID)
+		if err == nil || !s.fallback(err) {
+			return alerts, err
+		}
+	}
+	return nil, fmt.Errorf("データベースに接続できないため、インシデント %d のアラートを取得できません", incidentID)
+}
+
+// CreateWebhookDelivery は Webhook の配信記録を保存し、配信IDを返す
+// 配信記録はアウトボックスに記録しないため、縮退運転中は保存できない（送信は配信記録なしで行う）
+func (s *outboxStore) CreateWebhookDelivery(delivery WebhookDelivery) (int64, error) {
+	if primary := s.current(); primary != nil {
+		deliveryID, err := primary.CreateWebhookDelivery(delivery)
+		if err == nil || !s.fallback(err) {
+			return deliveryID, err
+		}
+	}
+	return 0, fmt.Errorf("データベースに接続できないため、配信記録を保存できません")
+}
+
+// UpdateWebhookDelivery は配信記録の状態・送信回数・最後の結果を更新
+func (s *outboxStore) UpdateWebhookDelivery(delivery WebhookDelivery) error {
+	if primary := s.current(); primary != nil {
+		err := primary.UpdateWebhookDelivery(delivery)
+		if err == nil || !s.fallback(err) {
+			return err
+		}
+	}
+	return fmt.Errorf("データベースに接続できないため、配信ID %d の記録を更新できません", delivery.ID)
+}
+
+// GetWebhookDelivery は配信記録を取得
+func (s *outboxStore) GetWebhookDelivery(deliveryID int64) (*WebhookDelivery, error) {
+	if primary := s.current(); primary != nil {
+		delivery, err := primary.GetWebhookDelivery(deliveryID)
+		if err == nil || !s.fallback(err) {
+			return delivery, err
+		}
+	}
+	return nil, fmt.Errorf("データベースに接続できないため、配信ID %d の記録を取得できません", deliveryID)
+}
+
+// WebhookDeliveries はインシデントの配信記録を新しい順に取得
+func (s *outboxStore) WebhookDeliveries(incidentID int64, limit int) ([]WebhookDelivery, error) {
+	if primary := s.current(); primary != nil {
+		deliveries, err := primary.WebhookDeliveries(incidentID, limit)
+		if err == nil || !s.fallback(err) {
+			return deliveries, err
+		}
+	}
+	return nil, fmt.Errorf("データベースに接続できないため、インシデント %d の配信記録を取得できません", incidentID)
+}
+
+// AddActionItem はアクションアイテムを保存し、アクションアイテムIDを返す
+// アクションアイテムはアウトボックスに記録しないため、縮退運転中は追加できない
+func (s *outboxStore) AddActionItem(item ActionItem) (int64, error) {
+	if primary := s.current(); primary != nil {
+		itemID, err := primary.AddActionItem(item)
+		if err == nil || !s.fallback(err) {
+			return itemID, err
+		}
+	}
+	return 0, fmt.Errorf("データベースに接続できないため、アクションアイテムを追加できません")
+}
+
+// ListActionItems はアクションアイテムを未完了・期限の近い順に最大 limit 件取得
+func (s *outboxStore) ListActionItems(incidentID int64, ownerID string, includeDone bool, limit int) ([]ActionItem, error) {
+	if primary := s.current(); primary != nil {
+		items, err := primary.ListActionItems(incidentID, ownerID, includeDone, limit)
+		if err == nil || !s.fallback(err) {
+			return items, err
+		}
+	}
+	return nil, fmt.Errorf("データベースに接続できないため、アクションアイテムを取得できません")
+}
+
+// OverdueActionItems は期限切れで、today 以降にまだリマインドしていない未完了のアクションアイテムを取得
+func (s *outboxStore) OverdueActionItems(today time.Time) ([]ActionItem, error) {
+	if primary := s.current(); primary != nil {
+		items, err := primary.OverdueActionItems(today)
+		if err == nil || !s.fallback(err) {
+			return items, err
+		}
+	}
+	return nil, fmt.Errorf("データベースに接続できないため、アクションアイテムを取得できません")
+}
+
+// CompleteActionItem は未完了のアクションアイテムを完了にし、完了にしたアクションアイテムを返す
+func (s *outboxStore) CompleteActionItem(itemID int64, completedBy string) (*ActionItem, error) {
+	if primary := s.current(); primary != nil {
+		item, err := primary.CompleteActionItem(itemID, completedBy)
+		if err == nil || !s.fallback(err) {
+			return item, err
+		}
+	}
+	return nil, fmt.Errorf("データベースに接続できないため、アクションアイテム #%d を完了にできません", itemID)
+}
+
+// MarkActionItemsReminded はアクションアイテムのリマインド日時を記録
+func (s *outboxStore) MarkActionItemsReminded(itemIDs []int64) error {
+	if primary := s.current(); primary != nil {
+		err := primary.MarkActionItemsReminded(itemIDs)
+		if err == nil || !s.fallback(err) {
+			return err
+		}
+	}
+	return fmt.Errorf("データベースに接続できないため、リマインド日時を記録できません")
+}
+
+// timekeeperTarget はタイムキーパーの状態の保存先を取得（仮IDのインシデントはジャーナル、縮退運転中は nil）
+func (s *outboxStore) timekeeperTarget(incidentID int64) IncidentStore {
+	if incidentID < 0 {
+		return s.journal
+	}
+	return s.current()
+}
+
+// writeTimekeeper はタイムキーパーの状態を保存（縮退運転中は保存できない）
+func (s *outboxStore) writeTimekeeper(incidentID int64, apply func(IncidentStore) error) error {
+	if target := s.timekeeperTarget(incidentID); target != nil {
+		err := apply(target)
+		if err == nil || !s.fallback(err) {
+			return err
+		}
+	}
+	return fmt.Errorf("データベースに接続できないため、インシデント %d のタイムキーパーの状態を保存できません", incidentID)
+}
+
+// SaveTimekeeperState はタイムキーパーの状態と投稿間隔を保存
+func (s *outboxStore) SaveTimekeeperState(incidentID int64, state string, intervalMinutes int) error {
+	return s.writeTimekeeper(incidentID, func(target IncidentStore) error {
+		return target.SaveTimekeeperState(incidentID, state, intervalMinutes)
+	})
+}
+
+// SaveTimekeeperProgress はタイムキーパーの投稿の進捗を保存
+func (s *outboxStore) SaveTimekeeperProgress(incidentID int64, progress timekeeperProgress) error {
+	return s.writeTimekeeper(incidentID, func(target IncidentStore) error {
+		return target.SaveTimekeeperProgress(incidentID, progress)
+	})
+}
+
+// SaveTimekeeperInterval はタイムキーパーの投稿間隔を保存
+func (s *outboxStore) SaveTimekeeperInterval(incidentID int64, intervalMinutes int) error {
+	return s.writeTimekeeper(incidentID, func(target IncidentStore) error {
+		return target.SaveTimekeeperInterval(incidentID, intervalMinutes)
+	})
+}
+
+// TransitionTimekeeperState はタイムキーパーの状態が from のいずれかの場合のみ to に変更し、変更したかを返す
+func (s *outboxStore) TransitionTimekeeperState(incidentID int64, from []string, to string) (bool, error) {
+	var changed bool
+	err := s.writeTimekeeper(incidentID, func(target IncidentStore) error {
+		var err error
+		changed, err = target.TransitionTimekeeperState(incidentID, from, to)
+		return err
+	})
+	return changed, err
+}
+
+// TimekeeperStates は複数のインシデントのタイムキーパーの状態を取得（仮IDのインシデントはジャーナルから取得）
+func (s *outboxStore) TimekeeperStates(incidentIDs []int64) (map[int64]TimekeeperState, error) {
+	var journaled, registered []int64
+	for _, incidentID := range incidentIDs {
+		if incidentID < 0 {
+			journaled = append(journaled, incidentID)
+		} else {
+			registered = append(registered, incidentID)
+		}
+	}
+
+	states, _ := s.journal.TimekeeperStates(journaled)
+	if len(registered) == 0 {
+		return states, nil
+	}
+
+	if primary := s.current(); primary != nil {
+		saved, err := primary.TimekeeperStates(registered)
+		if err == nil {
+			for incidentID, state := range saved {
+				states[incidentID] = state
+			}
+			return states, nil
+		}
+		if !s.fallback(err) {
+			return nil, err
+		}
+	}
+	return nil, fmt.Errorf("データベースに接続できないため、タイムキーパーの状態を取得できません")
+}
+
+// IncidentStats は集計期間とその直前の同じ長さの期間の重要度ごとの統計を取得（縮退運転中は集計できない）
+func (s *outboxStore) IncidentStats(until time.Time, period time.Duration) (map[string]IncidentStats, map[string]IncidentStats, error) {
+	if primary := s.current(); primary != nil {
+		current, previous, err := primary.IncidentStats(until, period)
+		if err == nil || !s.fallback(err) {
+			return current, previous, err
+		}
+	}
+	return nil, nil, fmt.Errorf("データベースに接続できないため、統計を集計できません")
+}
+
+// ClaimProcessedEvent はイベントを処理済みとして記録し、初めて記録した場合は true を返す
+// 縮退運転中はエラーを返す（呼び出し元でメモリ上で重複を確認する）
+func (s *outboxStore) ClaimProcessedEvent(eventKey string, ttl time.Duration) (bool, error) {
+	if primary := s.current(); primary != nil {
+		claimed, err := primary.ClaimProcessedEvent(eventKey, ttl)
+		if err == nil || !s.fallback(err) {
+			return claimed, err
+		}
+	}
+	return false, fmt.Errorf("データベースに接続できないため、処理済みイベントを記録できません")
+}
+
+// PurgeExpiredProcessedEvents は有効期限が切れた処理済みイベントを削除し、削除した件数を返す
+func (s *outboxStore) PurgeExpiredProcessedEvents() (int64, error) {
+	if primary := s.current(); primary != nil {
+		deleted, err := primary.PurgeExpiredProcessedEvents()
+		if err == nil || !s.fallback(err) {
+			return deleted, err
+		}
+	}
+	return 0, fmt.Errorf("データベースに接続できないため、処理済みイベントを削除できません")
+}
+
+// LookupJournalIncidentID はジャーナルの仮IDに対応する登録後のインシデントIDを取得（未登録の場合は0）
+func (s *outboxStore) LookupJournalIncidentID(journalID int64) (int64, error) {
+	if primary := s.current(); primary != nil {
+		incidentID, err := primary.LookupJournalIncidentID(journalID)
+		if err == nil || !s.fallback(err) {
+			return incidentID, err
+		}
+	}
+	return 0, fmt.Errorf("データベースに接続できないため、仮ID %d の登録後のIDを取得できません", journalID)
+}
+
+// AddHomeViewer はApp Homeを開いたユーザーを再描画対象として記録
+// 縮退運転中はエラーを返す（呼び出し元でメモリ上に保持し、次に開いた時に記録し直す）
+func (s *outboxStore) AddHomeViewer(userID string) error {
+	if primary := s.current(); primary != nil {
+		err := primary.AddHomeViewer(userID)
+		if err == nil || !s.fallback(err) {
+			return err
+		}
+	}
+	return fmt.Errorf("データベースに接続できないため、App Homeの閲覧者を記録できません")
+}
+
+// HomeViewers は openedSince 以降にApp Homeを開いたユーザーの一覧を取得
+func (s *outboxStore) HomeViewers(openedSince time.Time) ([]string, error) {
+	if primary := s.current(); primary != nil {
+		userIDs, err := primary.HomeViewers(openedSince)
+		if err == nil || !s.fallback(err) {
+			return userIDs, err
+		}
+	}
+	return nil, fmt.Errorf("データベースに接続できないため、App Homeの閲覧者を取得できません")
+}
+
+// PurgeHomeViewers は openedBefore より前に最後にApp Homeを開いたユーザーを削除し、削除した件数を返す
+func (s *outboxStore) PurgeHomeViewers(openedBefore time.Time) (int64, error) {
+	if primary := s.current(); primary != nil {
+		deleted, err := primary.PurgeHomeViewers(openedBefore)
+		if err == nil || !s.fallback(err) {
+			return deleted, err
+		}
+	}
+	return 0, fmt.Errorf("データベースに接続できないため、App Homeの閲覧者を削除できません")
+}
@@ -0,0 +1,195 @@
+package main
+
+import (
+	"fmt"
+	"path/filepath"
+	"testing"
+)
+
+// flakyStore は down の間すべての操作が失敗する IncidentStore（データベースの障害を再現する）
+type flakyStore struct {
+	*memoryStore
+	down bool
+}
+
+func (s *flakyStore) err() error {
+	return fmt.Errorf("データベースに接続できません")
+}
+
+func (s *flakyStore) CreateIncident(input NewIncident) (int64, error) {
+	if s.down {
+		return 0, s.err()
+	}
+	return s.memoryStore.CreateIncident(input)
+}
+
+func (s *flakyStore) GetIncident(incidentID int64) (*Incident, error) {
+	if s.down {
+		return nil, s.err()
+	}
+	return s.memoryStore.GetIncident(incidentID)
+}
+
+func (s *flakyStore) FindActiveIncidentByChannel(channelID string) (*Incident, error) {
+	if s.down {
+		return nil, s.err()
+	}
+	return s.memoryStore.FindActiveIncidentByChannel(channelID)
+}
+
+func (s *flakyStore) ListIncidents(filter IncidentListFilter, limit, offset int) ([]Incident, int, error) {
+	if s.down {
+		return nil, 0, s.err()
+	}
+	return s.memoryStore.ListIncidents(filter, limit, offset)
+}
+
+func (s *flakyStore) SearchIncidents(terms []string, limit int) ([]Incident, error) {
+	if s.down {
+		return nil, s.err()
+	}
+	return s.memoryStore.SearchIncidents(terms, limit)
+}
+
+func (s *flakyStore) ChangeHandler(incidentID int64, handlerID, handlerName, changedBy string) error {
+	if s.down {
+		return s.err()
+	}
+	return s.memoryStore.ChangeHandler(incidentID, handlerID, handlerName, changedBy)
+}
+
+func (s *flakyStore) ChangeStatus(incidentID int64, newStatus, changedBy, note string) (string, error) {
+	if s.down {
+		return "", s.err()
+	}
+	return s.memoryStore.ChangeStatus(incidentID, newStatus, changedBy, note)
+}
+
+func (s *flakyStore) ResolveIncident(incidentID int64, changedBy, note, resolutionNote string) (string, error) {
+	if s.down {
+		return "", s.err()
+	}
+	return s.memoryStore.ResolveIncident(incidentID, changedBy, note, resolutionNote)
+}
+
+// newTestOutboxStore は flakyStore を PostgreSQL の代わりにした outboxStore を作成
+func newTestOutboxStore(t *testing.T) (*outboxStore, *flakyStore) {
+	t.Helper()
+	original := dbHealth
+	dbHealth = &databaseHealth{}
+	t.Cleanup(func() { dbHealth = original })
+
+	memory, _ := newMemoryStore("")
+	primary := &flakyStore{memoryStore: memory}
+	journal, err := openIncidentJournal(filepath.Join(t.TempDir(), "journal.json"))
+	if err != nil {
+		t.Fatalf("openIncidentJournal() error = %v", err)
+	}
+	outbox, err := openIncidentOutbox(filepath.Join(t.TempDir(), "outbox.json"))
+	if err != nil {
+		t.Fatalf("openIncidentOutbox() error = %v", err)
+	}
+
+	s := newOutboxStore(primary, journal, outbox)
+	s.unavailable = func(error) bool { return primary.down }
+	return s, primary
+}
+
+func TestOutboxStoreFallsBackWhenDatabaseIsDown(t *testing.T) {
+	s, primary := newTestOutboxStore(t)
+	unavailableCalls := 0
+	s.onUnavailable = func() { unavailableCalls++ }
+
+	existing, err := s.CreateIncident(newTestIncident("DB障害", "high", "C001"))
+	if err != nil || existing != 1 {
+		t.Fatalf("CreateIncident() = %d, %v", existing, err)
+	}
+	// 縮退運転中に参照できるよう、取得したインシデントを保持する
+	if _, err := s.GetIncident(existing); err != nil {
+		t.Fatalf("GetIncident() error = %v", err)
+	}
+
+	primary.down = true
+
+	// 新しいインシデントはジャーナルに仮IDで保存する
+	journaled, err := s.CreateIncident(newTestIncident("API遅延", "medium", "C002"))
+	if err != nil {
+		t.Fatalf("CreateIncident() error = %v", err)
+	}
+	if journaled >= 0 {
+		t.Errorf("縮退運転中のインシデントには仮IDを採番するべきです: %d", journaled)
+	}
+	if !isDegraded() || unavailableCalls != 1 {
+		t.Errorf("縮退運転に切り替えて再接続を開始するべきです: degraded=%v, calls=%d", isDegraded(), unavailableCalls)
+	}
+	if _, err := s.ChangeStatus(journaled, StatusIdentified, "U002", ""); err != nil {
+		t.Errorf("仮IDのインシデントはジャーナルで変更できるべきです: %v", err)
+	}
+
+	// 既存のインシデントへの変更はアウトボックスに記録し、参照には反映する
+	if err := s.ChangeHandler(existing, "U002", "担当者", "U002"); err != nil {
+		t.Fatalf("ChangeHandler() error = %v", err)
+	}
+	oldStatus, err := s.ResolveIncident(existing, "U002", "担当者 により復旧完了", "再起動で復旧")
+	if err != nil || oldStatus != StatusInvestigating {
+		t.Fatalf("ResolveIncident() = %q, %v", oldStatus, err)
+	}
+	incident, err := s.GetIncident(existing)
+	if err != nil || incident.HandlerID != "U002" || incident.Status != StatusResolved || incident.ResolutionNote != "再起動で復旧" {
+		t.Errorf("GetIncident() = %+v, %v", incident, err)
+	}
+
+	// 復旧と復旧メモは1件の操作として記録する
+	pending := s.outbox.pending()
+	if len(pending) != 2 || pending[0].Op != outboxOpHandler || pending[1].Op != outboxOpStatus || pending[1].IncidentID != existing {
+		t.Fatalf("アウトボックスに記録した順に残すべきです: %+v", pending)
+	}
+	if pending[1].NewStatus != StatusResolved || pending[1].ResolutionNote != "再起動で復旧" {
+		t.Errorf("復旧の操作に復旧メモが含まれていません: %+v", pending[1])
+	}
+
+	// 許可されていない遷移は記録しない
+	if _, err := s.ChangeStatus(existing, StatusInvestigating, "U002", ""); err == nil {
+		t.Error("許可されていない遷移はエラーになるべきです")
+	}
+	// 縮退運転前に取得していないインシデントは変更できない
+	if err := s.ChangeHandler(99, "U002", "担当者", "U002"); err == nil {
+		t.Error("保持していないインシデントの変更はエラーになるべきです")
+	}
+	if pending := s.outbox.pending(); len(pending) != 2 {
+		t.Errorf("失敗した操作はアウトボックスに記録しないべきです: %+v", pending)
+	}
+
+	// 一覧はジャーナルと保持しているインシデントを合わせる
+	incidents, total, err := s.ListIncidents(IncidentListFilter{Statuses: append(activeStatuses, finishedStatuses...)}, 10, 0)
+	if err != nil || total != 2 || len(incidents) != 2 {
+		t.Errorf("ListIncidents() = %+v, %d, %v", incidents, total, err)
+	}
+	if found, err := s.SearchIncidents([]string{"遅延"}, 10); err != nil || len(found) != 1 || found[0].ID != journaled {
+		t.Errorf("SearchIncidents() = %+v, %v", found, err)
+	}
+	if found, _ := s.SearchIncidents([]string{"障害"}, 10); len(found) != 1 || found[0].ID != existing {
+		t.Errorf("保持しているインシデントも検索するべきです: %+v", found)
+	}
+	if found, _ := s.FindActiveIncidentByChannel("C002"); found == nil || found.ID != journaled {
+		t.Errorf("FindActiveIncidentByChannel() = %+v", found)
+	}
+
+	// 縮退運転中は PostgreSQL を使わず、再接続も一度だけ開始する
+	s.CreateIncident(newTestIncident("ログイン不可", "critical", "C003"))
+	if unavailableCalls != 1 {
+		t.Errorf("再接続は一度だけ開始するべきです: %d", unavailableCalls)
+	}
+}
+
+func TestOutboxStoreKeepsErrorsUnrelatedToConnection(t *testing.T) {
+	s, _ := newTestOutboxStore(t)
+
+	// データベースに接続できている場合のエラーはそのまま返し、アウトボックスに記録しない
+	if err := s.ChangeHandler(99, "U002", "担当者", "U002"); err == nil {
+		t.Error("存在しないインシデントの変更はエラーになるべきです")
+	}
+	if isDegraded() || len(s.outbox.pending()) != 0 {
+		t.Error("接続に関係しないエラーで縮退運転に切り替えないべきです")
+	}
+}
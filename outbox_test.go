@@ -0,0 +1,96 @@
+package main
+
+import (
+	"fmt"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// testOutboxReplayer はアウトボックスの操作を記録する outboxReplayer
+type testOutboxReplayer struct {
+	failOn   string // この操作の登録を失敗させる
+	replayed []outboxEntry
+}
+
+func (r *testOutboxReplayer) replayOutboxEntry(entry outboxEntry) error {
+	if entry.Op == r.failOn {
+		return fmt.Errorf("接続が切れました")
+	}
+	r.replayed = append(r.replayed, entry)
+	return nil
+}
+
+func TestIncidentOutboxPersistence(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "outbox.json")
+	outbox, err := openIncidentOutbox(path)
+	if err != nil {
+		t.Fatalf("openIncidentOutbox() error = %v", err)
+	}
+
+	recordedAt := time.Date(2025, 1, 1, 10, 0, 0, 0, time.UTC)
+	entries := []outboxEntry{
+		{Op: outboxOpHandler, IncidentID: 1, HandlerID: "U002", HandlerName: "担当者", ChangedBy: "U002", RecordedAt: recordedAt},
+		{Op: outboxOpStatus, IncidentID: 1, NewStatus: StatusResolved, ChangedBy: "U002", RecordedAt: recordedAt},
+		{Op: outboxOpResolutionNote, IncidentID: 1, Note: "再起動で復旧", ChangedBy: "U002", RecordedAt: recordedAt},
+	}
+	for _, entry := range entries {
+		if err := outbox.append(entry); err != nil {
+			t.Fatalf("append() error = %v", err)
+		}
+	}
+
+	// 再起動後も記録した順に読み込める
+	reopened, err := openIncidentOutbox(path)
+	if err != nil {
+		t.Fatalf("openIncidentOutbox() error = %v", err)
+	}
+	pending := reopened.pending()
+	if len(pending) != len(entries) {
+		t.Fatalf("pending() = %+v", pending)
+	}
+	seen := make(map[string]bool)
+	for i, entry := range pending {
+		if entry.Op != entries[i].Op || !entry.RecordedAt.Equal(recordedAt) {
+			t.Errorf("%d件目 = %+v, want %+v", i+1, entry, entries[i])
+		}
+		// 同じ日時に記録しても操作のIDは重ならない
+		if entry.ID == "" || seen[entry.ID] {
+			t.Errorf("操作のIDが重複しています: %q", entry.ID)
+		}
+		seen[entry.ID] = true
+	}
+}
+
+func TestReplayIncidentOutbox(t *testing.T) {
+	outbox, _ := newIncidentOutbox(filepath.Join(t.TempDir(), "outbox.json"))
+	outbox.append(outboxEntry{Op: outboxOpHandler, IncidentID: 1})
+	outbox.append(outboxEntry{Op: outboxOpStatus, IncidentID: 1, NewStatus: StatusResolved})
+	outbox.append(outboxEntry{Op: outboxOpHandler, IncidentID: 2})
+
+	// 途中で失敗した場合は順序を保つためそこで止め、残りはアウトボックスに残す
+	replayer := &testOutboxReplayer{failOn: outboxOpStatus}
+	replayed, err := replayIncidentOutbox(replayer, outbox)
+	if err == nil {
+		t.Error("登録に失敗した場合はエラーを返すべきです")
+	}
+	if len(replayed) != 1 || replayed[0].IncidentID != 1 || replayed[0].Op != outboxOpHandler {
+		t.Fatalf("replayIncidentOutbox() = %+v", replayed)
+	}
+	if pending := outbox.pending(); len(pending) != 2 || pending[0].Op != outboxOpStatus {
+		t.Errorf("登録できなかった操作以降はアウトボックスに残すべきです: %+v", pending)
+	}
+
+	// 次回は残りを記録した順に登録する
+	replayer.failOn = ""
+	replayed, err = replayIncidentOutbox(replayer, outbox)
+	if err != nil {
+		t.Fatalf("replayIncidentOutbox() error = %v", err)
+	}
+	if len(replayed) != 2 || replayed[0].Op != outboxOpStatus || replayed[1].IncidentID != 2 {
+		t.Errorf("replayIncidentOutbox() = %+v", replayed)
+	}
+	if pending := outbox.pending(); len(pending) != 0 {
+		t.Errorf("すべて登録した後はアウトボックスが空になるべきです: %+v", pending)
+	}
+}
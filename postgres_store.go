@@ -0,0 +1,1398 @@
+package main
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+// postgresStore は PostgreSQL にインシデントを保存する IncidentStore
+type postgresStore struct {
+	db *sql.DB
+}
+
+// newPostgresStore は PostgreSQL の IncidentStore を作成
+func newPostgresStore(database *sql.DB) *postgresStore {
+	return &postgresStore{db: database}
+}
+
+// incidentColumns はインシデントを取得する際の列（scanIncident と同じ順序）
+const incidentColumns = `id, title, severity, description, impact, status, channel_id, channel_name,
+		       reporter_id, reporter_name, handler_id, handler_name, source_permalink, resolution_note, created_at, updated_at, resolved_at`
+
+// rowScanner は *sql.Row と *sql.Rows の共通部分
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+// rowQuerier は *sql.DB と *sql.Tx の共通部分
+type rowQuerier interface {
+	QueryRow(query string, args ...interface{}) *sql.Row
+}
+
+// scanIncident は incidentColumns の順序で取得した行をインシデントに変換
+func scanIncident(row rowScanner, extra ...interface{}) (*Incident, error) {
+	var incident Incident
+	var description, impact, handlerID, handlerName, sourcePermalink, resolutionNote sql.NullString
+	var resolvedAt sql.NullTime
+
+	dest := []interface{}{
+		&incident.ID, &incident.Title, &incident.Severity, &description, &impact, &incident.Status, &incident.ChannelID, &incident.ChannelName,
+		&incident.ReporterID, &incident.ReporterName, &handlerID, &handlerName, &sourcePermalink, &resolutionNote, &incident.CreatedAt, &incident.UpdatedAt, &resolvedAt,
+	}
+	if err := row.Scan(append(dest, extra...)...); err != nil {
+		return nil, err
+	}
+
+	incident.Status = normalizeStatus(incident.Status)
+	incident.Description = description.String
+	incident.Impact = impact.String
+	incident.HandlerID = handlerID.String
+	incident.HandlerName = handlerName.String
+	incident.SourcePermalink = sourcePermalink.String
+	incident.ResolutionNote = resolutionNote.String
+	if resolvedAt.Valid {
+		incident.ResolvedAt = &resolvedAt.Time
+	}
+	return &incident, nil
+}
+
+// CreateIncident はインシデントを保存し、初期ステータスを履歴に記録
+func (s *postgresStore) CreateIncident(input NewIncident) (int64, error) {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return 0, fmt.Errorf("トランザクション開始エラー: %v", err)
+	}
+	defer tx.Rollback()
+
+	query := `
+		INSERT INTO incidents (title, severity, description, impact, channel_id, channel_name, reporter_id, reporter_name, source_permalink, status)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, NULLIF($9, ''), $10)
+		RETURNING id
+	`
+
+	var incidentID int64
+	err = tx.QueryRow(query, input.Title, input.Severity, input.Description, input.Impact, input.ChannelID, input.ChannelName,
+		input.ReporterID, input.ReporterName, input.SourcePermalink, StatusInvestigating).Scan(&incidentID)
+	if err != nil {
+		return 0, fmt.Errorf("インシデント保存エラー: %v", err)
+	}
+
+	// 初期ステータスを履歴に記録
+	historyQuery := `
+		INSERT INTO incident_status_history (incident_id, old_status, new_status, changed_by, note)
+		VALUES ($1, NULL, $2, $3, $4)
+	`
+	_, err = tx.Exec(historyQuery, incidentID, StatusInvestigating, input.ReporterID, "インシデント報告")
+	if err != nil {
+		return 0, fmt.Errorf("ステータス履歴記録エラー: %v", err)
+	}
+
+	if err = tx.Commit(); err != nil {
+		return 0, fmt.Errorf("トランザクションコミットエラー: %v", err)
+	}
+	return incidentID, nil
+}
+
+// GetIncident はインシデントを取得
+func (s *postgresStore) GetIncident(incidentID int64) (*Incident, error) {
+	row := s.db.QueryRow(`SELECT `+incidentColumns+` FROM incidents WHERE id = $1`, incidentID)
+	incident, err := scanIncident(row)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, errIncidentNotFound(incidentID)
+		}
+		return nil, fmt.Errorf("インシデント詳細取得エラー: %v", err)
+	}
+	return incident, nil
+}
+
+// FindActiveIncidentByChannel はチャンネルの対応中のインシデントのうち最新のものを取得
+func (s *postgresStore) FindActiveIncidentByChannel(channelID string) (*Incident, error) {
+	query := `
+		SELECT ` + incidentColumns + `
+		FROM incidents
+		WHERE channel_id = $1 AND status = ANY($2)
+		ORDER BY created_at DESC
+		LIMIT 1
+	`
+
+	incident, err := scanIncident(s.db.QueryRow(query, channelID, pq.Array(activeStatuses)))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("インシデント取得エラー: %v", err)
+	}
+	return incident, nil
+}
+
+// FindLatestIncidentByChannel はチャンネルの最新のインシデントを取得（復旧済み・クローズ済みも含む）
+func (s *postgresStore) FindLatestIncidentByChannel(channelID string) (*Incident, error) {
+	query := `SELECT ` + incidentColumns + ` FROM incidents WHERE channel_id = $1 ORDER BY created_at DESC LIMIT 1`
+
+	incident, err := scanIncident(s.db.QueryRow(query, channelID))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("インシデント取得エラー: %v", err)
+	}
+	return incident, nil
+}
+
+// buildIncidentListQuery はフィルター条件からインシデント一覧（1ページ分）と全件数を取得するクエリと引数を構築
+// 全件数のクエリは引数のうち最後の2つ（LIMIT・OFFSET）を除いたものを使う
+func buildIncidentListQuery(filter IncidentListFilter, limit, offset int) (string, string, []interface{}) {
+	statuses := filter.Statuses
+	if len(statuses) == 0 {
+		statuses = activeStatuses
+	}
+
+	conditions := []string{"status = ANY($1)"}
+	args := []interface{}{pq.Array(statuses)}
+
+	addCondition := func(format string, value interface{}) {
+		args = append(args, value)
+		conditions = append(conditions, fmt.Sprintf(format, len(args)))
+	}
+
+	if len(filter.Severities) > 0 {
+		addCondition("severity = ANY($%d)", pq.Array(filter.Severities))
+	}
+	if filter.Unassigned {
+		conditions = append(conditions, "(handler_id IS NULL OR handler_id = '')")
+	} else if filter.HandlerID != "" {
+		addCondition("handler_id = $%d", filter.HandlerID)
+	}
+	if filter.ReporterID != "" {
+		addCondition("reporter_id = $%d", filter.ReporterID)
+	}
+	if !filter.Since.IsZero() {
+		addCondition("created_at >= $%d", filter.Since)
+	}
+
+	// 並び順（重要度順の場合は重要度の高い順、同じ重要度内は新しい順）
+	orderBy := "created_at DESC"
+	switch filter.SortBy {
+	case incidentListSortSeverity:
+		var cases []string
+		for i, severity := range severityOrder {
+			cases = append(cases, fmt.Sprintf("WHEN '%s' THEN %d", severity, i))
+		}
+		orderBy = fmt.Sprintf("CASE severity %s ELSE %d END, created_at DESC", strings.Join(cases, " "), len(severityOrder))
+	case incidentListSortOldest:
+		orderBy = "created_at ASC"
+	case incidentListSortRecent:
+		orderBy = "COALESCE(resolved_at, created_at) DESC"
+	}
+
+	// limit が0以下の場合は全件（LIMIT NULL）
+	var limitArg interface{}
+	if limit > 0 {
+		limitArg = limit
+	}
+
+	where := strings.Join(conditions, " AND ")
+	countQuery := `SELECT COUNT(*) FROM incidents WHERE ` + where
+
+	args = append(args, limitArg, offset)
+	query := fmt.Sprintf(`
+		SELECT %s
+		FROM incidents
+		WHERE %s
+		ORDER BY %s
+		LIMIT $%d OFFSET $%d
+	`, incidentColumns, where, orderBy, len(args)-1, len(args))
+
+	return query, countQuery, args
+}
+
+// ListIncidents はフィルター条件に一致するインシデントの1ページ分と全件数を取得
+// 全件数は別のクエリで数えるため、範囲外のページを指定した場合も正しい件数を返す
+func (s *postgresStore) ListIncidents(filter IncidentListFilter, limit, offset int) ([]Incident, int, error) {
+	query, countQuery, args := buildIncidentListQuery(filter, limit, offset)
+
+	rows, err := s.db.Query(query, args...)
+	if err != nil {
+		return nil, 0, fmt.Errorf("インシデント一覧取得エラー: %v", err)
+	}
+	defer rows.Close()
+
+	var incidents []Incident
+	for rows.Next() {
+		incident, err := scanIncident(rows)
+		if err != nil {
+			return nil, 0, fmt.Errorf("インシデント情報スキャンエラー: %v", err)
+		}
+		incidents = append(incidents, *incident)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, 0, fmt.Errorf("インシデント一覧取得エラー: %v", err)
+	}
+
+	var total int
+	if err := s.db.QueryRow(countQuery, args[:len(args)-2]...).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("インシデント件数取得エラー: %v", err)
+	}
+
+	return incidents, total, nil
+}
+
+// SearchIncidents はキーワードに一致する過去のインシデントを関連度順に取得
+// tsvector による全文検索に加え、日本語など空白で区切られない文章向けに
+// pg_trgm による部分一致・類似度検索を併用する
+func (s *postgresStore) SearchIncidents(terms []string, limit int) ([]Incident, error) {
+	query := `
+		SELECT ` + incidentColumns + `
+		FROM incidents
+		WHERE search_vector @@ plainto_tsquery('simple', $1)
+		   OR search_text ILIKE ALL($2)
+		   OR $1 <% search_text
+		ORDER BY ts_rank(search_vector, plainto_tsquery('simple', $1)) + word_similarity($1, search_text) DESC,
+		         created_at DESC
+		LIMIT $3
+	`
+
+	rows, err := s.db.Query(query, strings.Join(terms, " "), pq.Array(buildLikePatterns(terms)), limit)
+	if err != nil {
+		return nil, fmt.Errorf("インシデント検索エラー: %v", err)
+	}
+	defer rows.Close()
+
+	var incidents []Incident
+	for rows.Next() {
+		incident, err := scanIncident(rows)
+		if err != nil {
+			log.Printf("インシデント情報スキャンエラー: %v", err)
+			continue
+		}
+		incidents = append(incidents, *incident)
+	}
+
+	return incidents, nil
+}
+
+// buildLikePatterns はキーワードから部分一致検索用のILIKEパターンを生成
+func buildLikePatterns(terms []string) []string {
+	escaper := strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+	patterns := make([]string, 0, len(terms))
+	for _, term := range terms {
+		patterns = append(patterns, "%"+escaper.Replace(term)+"%")
+	}
+	return patterns
+}
+
+// UpdateIncidentField はタイトル・重要度・詳細説明・影響範囲のいずれかを更新し、更新履歴を記録
+func (s *postgresStore) UpdateIncidentField(incidentID int64, field, oldValue, newValue, updatedBy, updatedByName string) error {
+	// 列名はクエリに埋め込むため、更新できるフィールドに限定する
+	if !updatableIncidentFields[field] {
+		return fmt.Errorf("更新できないフィールド: %s", field)
+	}
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return fmt.Errorf("トランザクション開始エラー: %v", err)
+	}
+	defer tx.Rollback()
+
+	updateQuery := fmt.Sprintf("UPDATE incidents SET %s = $1, updated_at = CURRENT_TIMESTAMP WHERE id = $2", field)
+	_, err = tx.Exec(updateQuery, newValue, incidentID)
+	if err != nil {
+		return fmt.Errorf("インシデント更新エラー: %v", err)
+	}
+
+	// 更新履歴を記録
+	historyQuery := `
+		INSERT INTO incident_update_history (incident_id, field_name, old_value, new_value, updated_by, updated_by_name)
+		VALUES ($1, $2, $3, $4, $5, $6)
+	`
+	_, err = tx.Exec(historyQuery, incidentID, field, oldValue, newValue, updatedBy, updatedByName)
+	if err != nil {
+		return fmt.Errorf("更新履歴記録エラー: %v", err)
+	}
+
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("トランザクションコミットエラー: %v", err)
+	}
+	return nil
+}
+
+// ChangeHandler は担当者を変更し、担当者の変更履歴を記録
+func (s *postgresStore) ChangeHandler(incidentID int64, handlerID, handlerName, changedBy string) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return fmt.Errorf("トランザクション開始エラー: %v", err)
+	}
+	defer tx.Rollback()
+
+	// 現在のハンドラーを取得
+	var oldHandlerID sql.NullString
+	err = tx.QueryRow("SELECT handler_id FROM incidents WHERE id = $1 FOR UPDATE", incidentID).Scan(&oldHandlerID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return errIncidentNotFound(incidentID)
+		}
+		return fmt.Errorf("現在のハンドラー取得エラー: %v", err)
+	}
+
+	// ハンドラーを更新
+	updateQuery := `
+		UPDATE incidents
+		SET handler_id = $1, handler_name = $2, updated_at = CURRENT_TIMESTAMP
+		WHERE id = $3
+	`
+	_, err = tx.Exec(updateQuery, handlerID, handlerName, incidentID)
+	if err != nil {
+		return fmt.Errorf("ハンドラー更新エラー: %v", err)
+	}
+
+	// ハンドラー履歴を記録
+	historyQuery := `
+		INSERT INTO incident_handler_history (incident_id, old_handler_id, new_handler_id, assigned_by)
+		VALUES ($1, NULLIF($2, ''), $3, $4)
+	`
+	_, err = tx.Exec(historyQuery, incidentID, oldHandlerID.String, handlerID, changedBy)
+	if err != nil {
+		return fmt.Errorf("ハンドラー履歴記録エラー: %v", err)
+	}
+
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("トランザクションコミットエラー: %v", err)
+	}
+	return nil
+}
+
+// ChangeStatus はステータスを変更し、変更前のステータスを返す
+func (s *postgresStore) ChangeStatus(incidentID int64, newStatus, changedBy, note string) (string, error) {
+	return s.changeStatus(incidentID, newStatus, changedBy, note, "")
+}
+
+// ResolveIncident はステータスを復旧済みに変更し、復旧メモ（空でない場合）と同時に保存して変更前のステータスを返す
+func (s *postgresStore) ResolveIncident(incidentID int64, changedBy, note, resolutionNote string) (string, error) {
+	return s.changeStatus(incidentID, StatusResolved, changedBy, note, resolutionNote)
+}
+
+// changeStatus はステータスの変更と復旧メモ（空でない場合）の保存を1つのトランザクションで行う
+func (s *postgresStore) changeStatus(incidentID int64, newStatus, changedBy, note, resolutionNote string) (string, error) {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return "", fmt.Errorf("トランザクション開始エラー: %v", err)
+	}
+	defer tx.Rollback()
+
+	// 現在のステータスを取得（同時更新を防ぐため行ロック）
+	var oldStatus string
+	err = tx.QueryRow("SELECT status FROM incidents WHERE id = $1 FOR UPDATE", incidentID).Scan(&oldStatus)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return "", errIncidentNotFound(incidentID)
+		}
+		return "", fmt.Errorf("現在のステータス取得エラー: %v", err)
+	}
+
+	if err := validateTransition(oldStatus, newStatus); err != nil {
+		return "", err
+	}
+
+	// インシデントのステータスを更新（復旧時は復旧日時も記録）
+	updateQuery := `
+		UPDATE incidents
+		SET status = $1,
+		    resolved_at = CASE WHEN $2 THEN CURRENT_TIMESTAMP ELSE resolved_at END,
+		    resolution_note = COALESCE(NULLIF($3, ''), resolution_note),
+		    updated_at = CURRENT_TIMESTAMP
+		WHERE id = $4
+	`
+	_, err = tx.Exec(updateQuery, newStatus, newStatus == StatusResolved, resolutionNote, incidentID)
+	if err != nil {
+		return "", fmt.Errorf("ステータス更新エラー: %v", err)
+	}
+
+	// ステータス変更履歴を記録
+	historyQuery := `
+		INSERT INTO incident_status_history (incident_id, old_status, new_status, changed_by, note)
+		VALUES ($1, $2, $3, $4, $5)
+	`
+	_, err = tx.Exec(historyQuery, incidentID, normalizeStatus(oldStatus), newStatus, changedBy, note)
+	if err != nil {
+		return "", fmt.Errorf("ステータス履歴記録エラー: %v", err)
+	}
+
+	if err = tx.Commit(); err != nil {
+		return "", fmt.Errorf("トランザクションコミットエラー: %v", err)
+	}
+	return normalizeStatus(oldStatus), nil
+}
+
+// StatusHistory はステータスの変更履歴を新しい順に取得
+func (s *postgresStore) StatusHistory(incidentID int64, limit int) ([]StatusChange, error) {
+	query := `
+		SELECT old_status, new_status, changed_by, changed_at, note
+		FROM incident_status_history
+		WHERE incident_id = $1
+		ORDER BY changed_at DESC, id DESC
+		LIMIT NULLIF($2, 0)
+	`
+
+	rows, err := s.db.Query(query, incidentID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("ステータス履歴取得エラー: %v", err)
+	}
+	defer rows.Close()
+
+	var history []StatusChange
+	for rows.Next() {
+		var change StatusChange
+		var oldStatus, changedBy, note sql.NullString
+
+		if err := rows.Scan(&oldStatus, &change.NewStatus, &changedBy, &change.ChangedAt, &note); err != nil {
+			log.Printf("履歴スキャンエラー: %v", err)
+			continue
+		}
+		change.OldStatus = oldStatus.String
+		change.ChangedBy = changedBy.String
+		change.Note = note.String
+		history = append(history, change)
+	}
+
+	return history, nil
+}
+
+// HandlerHistory は担当者の変更履歴を新しい順に取得
+func (s *postgresStore) HandlerHistory(incidentID int64, limit int) ([]HandlerChange, error) {
+	query := `
+		SELECT old_handler_id, new_handler_id, assigned_by, assigned_at
+		FROM incident_handler_history
+		WHERE incident_id = $1
+		ORDER BY assigned_at DESC, id DESC
+		LIMIT NULLIF($2, 0)
+	`
+
+	rows, err := s.db.Query(query, incidentID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("担当者履歴取得エラー: %v", err)
+	}
+	defer rows.Close()
+
+	var history []HandlerChange
+	for rows.Next() {
+		var change HandlerChange
+		var oldHandlerID, newHandlerID sql.NullString
+
+		if err := rows.Scan(&oldHandlerID, &newHandlerID, &change.AssignedBy, &change.AssignedAt); err != nil {
+			log.Printf("履歴スキャンエラー: %v", err)
+			continue
+		}
+		change.OldHandlerID = oldHandlerID.String
+		change.NewHandlerID = newHandlerID.String
+		history = append(history, change)
+	}
+
+	return history, nil
+}
+
+// UpdateHistory は詳細情報の更新履歴を新しい順に取得
+func (s *postgresStore) UpdateHistory(incidentID int64, limit int) ([]FieldUpdate, error) {
+	query := `
+		SELECT field_name, old_value, new_value, updated_by, updated_by_name, updated_at, note
+		FROM incident_update_history
+		WHERE incident_id = $1
+		ORDER BY updated_at DESC, id DESC
+		LIMIT NULLIF($2, 0)
+	`
+
+	rows, err := s.db.Query(query, incidentID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("更新履歴取得エラー: %v", err)
+	}
+	defer rows.Close()
+
+	var history []FieldUpdate
+	for rows.Next() {
+		var update FieldUpdate
+		var oldValue, newValue, updatedByName, note sql.NullString
+
+		if err := rows.Scan(&update.FieldName, &oldValue, &newValue, &update.UpdatedBy, &updatedByName, &update.UpdatedAt, &note); err != nil {
+			log.Printf("履歴スキャンエラー: %v", err)
+			continue
+		}
+		update.OldValue = oldValue.String
+		update.NewValue = newValue.String
+		update.UpdatedByName = updatedByName.String
+		update.Note = note.String
+		history = append(history, update)
+	}
+
+	return history, nil
+}
+
+// alertColumns はアラートを取得する際の列（scanIncidentAlert と同じ順序）
+const alertColumns = `incident_id, fingerprint, source, status, summary, service, labels, starts_at, ends_at, generator_url,
+		       first_received_at, last_received_at, received_count`
+
+// scanIncidentAlert は alertColumns の順序で取得した行をアラートに変換
+func scanIncidentAlert(row rowScanner) (*IncidentAlert, error) {
+	var alert IncidentAlert
+	var service, generatorURL sql.NullString
+	var labels []byte
+	var startsAt, endsAt sql.NullTime
+
+	err := row.Scan(&alert.IncidentID, &alert.Fingerprint, &alert.Source, &alert.Status, &alert.Summary, &service, &labels,
+		&startsAt, &endsAt, &generatorURL, &alert.FirstReceivedAt, &alert.LastReceivedAt, &alert.ReceivedCount)
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(labels, &alert.Labels); err != nil {
+		return nil, fmt.Errorf("アラートのラベル解析エラー: %v", err)
+	}
+	alert.Service = service.String
+	alert.GeneratorURL = generatorURL.String
+	alert.StartsAt = startsAt.Time
+	alert.EndsAt = endsAt.Time
+	return &alert, nil
+}
+
+// alertTime はアラートの日時を登録する値に変換（未設定の場合は NULL）
+func alertTime(t time.Time) sql.NullTime {
+	return sql.NullTime{Time: t, Valid: !t.IsZero()}
+}
+
+// insertIncidentAlert はアラートを紐付け（紐付け済みの場合は状態を更新）、前回受信したときの状態を返す
+// 受信日時と受信回数は alert の値を初回の値として使用する（ジャーナルからの登録時に元の日時を引き継ぐため）
+func insertIncidentAlert(q rowQuerier, alert IncidentAlert) (string, error) {
+	labels, err := json.Marshal(alert.Labels)
+	if err != nil {
+		return "", fmt.Errorf("アラートのラベル変換エラー: %v", err)
+	}
+
+	query := `
+		WITH previous AS (
+			SELECT status FROM incident_alerts WHERE incident_id = $1 AND fingerprint = $2
+		)
+		INSERT INTO incident_alerts (incident_id, fingerprint, source, status, summary, service, labels, starts_at, ends_at, generator_url,
+		                             first_received_at, last_received_at, received_count)
+		VALUES ($1, $2, $3, $4, $5, NULLIF($6, ''), $7, $8, $9, NULLIF($10, ''), $11, $12, $13)
+		ON CONFLICT (incident_id, fingerprint) DO UPDATE
+		SET source = EXCLUDED.source,
+		    status = EXCLUDED.status,
+		    summary = EXCLUDED.summary,
+		    service = EXCLUDED.service,
+		    labels = EXCLUDED.labels,
+		    starts_at = EXCLUDED.starts_at,
+		    ends_at = EXCLUDED.ends_at,
+		    generator_url = EXCLUDED.generator_url,
+		    last_received_at = EXCLUDED.last_received_at,
+		    received_count = incident_alerts.received_count + 1
+		RETURNING (SELECT status FROM previous)
+	`
+
+	now := time.Now()
+	firstReceivedAt, lastReceivedAt, receivedCount := alert.FirstReceivedAt, alert.LastReceivedAt, alert.ReceivedCount
+	if firstReceivedAt.IsZero() {
+		firstReceivedAt = now
+	}
+	if lastReceivedAt.IsZero() {
+		lastReceivedAt = now
+	}
+	if receivedCount <= 0 {
+		receivedCount = 1
+	}
+
+	var previous sql.NullString
+	err = q.QueryRow(query, alert.IncidentID, alert.Fingerprint, alert.Source, alert.Status, alert.Summary, alert.Service, labels,
+		alertTime(alert.StartsAt), alertTime(alert.EndsAt), alert.GeneratorURL, firstReceivedAt, lastReceivedAt, receivedCount).Scan(&previous)
+	if err != nil {
+		return "", fmt.Errorf("アラート保存エラー: %v", err)
+	}
+	return previous.String, nil
+}
+
+// AttachAlert はアラートをインシデントに紐付け、前回受信したときの状態を返す
+func (s *postgresStore) AttachAlert(alert IncidentAlert) (string, error) {
+	alert.FirstReceivedAt, alert.LastReceivedAt, alert.ReceivedCount = time.Time{}, time.Time{}, 0
+	return insertIncidentAlert(s.db, alert)
+}
+
+// findIncidentByAlert はアラートの条件に一致する対応中のインシデントのうち最新のものを取得
+func (s *postgresStore) findIncidentByAlert(condition string, args ...interface{}) (*Incident, error) {
+	query := `
+		SELECT ` + incidentColumns + `
+		FROM incidents
+		WHERE status = ANY($1) AND EXISTS (
+			SELECT 1 FROM incident_alerts a WHERE a.incident_id = incidents.id AND ` + condition + `
+		)
+		ORDER BY created_at DESC, id DESC
+		LIMIT 1
+	`
+
+	incident, err := scanIncident(s.db.QueryRow(query, append([]interface{}{pq.Array(activeStatuses)}, args...)...))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("アラートのインシデント取得エラー: %v", err)
+	}
+	return incident, nil
+}
+
+// FindIncidentByAlert はフィンガープリントのアラートを紐付けた対応中のインシデントを取得
+func (s *postgresStore) FindIncidentByAlert(fingerprint string) (*Incident, error) {
+	return s.findIncidentByAlert("a.fingerprint = $2", fingerprint)
+}
+
+// FindIncidentByAlertService は since 以降にサービスのアラートを受信した対応中のインシデントのうち最新のものを取得
+func (s *postgresStore) FindIncidentByAlertService(service string, since time.Time) (*Incident, error) {
+	return s.findIncidentByAlert("a.service = $2 AND a.last_received_at >= $3", service, since)
+}
+
+// IncidentAlerts はインシデントに紐付けたアラートを最初に受信した順に取得
+func (s *postgresStore) IncidentAlerts(incidentID int64) ([]IncidentAlert, error) {
+	rows, err := s.db.Query(`SELECT `+alertColumns+` FROM incident_alerts WHERE incident_id = $1 ORDER BY first_received_at, fingerprint`, incidentID)
+	if err != nil {
+		return nil, fmt.Errorf("アラート取得エラー: %v", err)
+	}
+	defer rows.Close()
+
+	var alerts []IncidentAlert
+	for rows.Next() {
+		alert, err := scanIncidentAlert(rows)
+		if err != nil {
+			log.Printf("アラートスキャンエラー: %v", err)
+			continue
+		}
+		alerts = append(alerts, *alert)
+	}
+	return alerts, nil
+}
+
+// deliveryColumns は配信記録を取得する際の列（scanWebhookDelivery と同じ順序）
+const deliveryColumns = `id, incident_id, event, endpoint, payload, status, attempts, response_code, last_error, created_at, updated_at, delivered_at`
+
+// scanWebhookDelivery は deliveryColumns の順序で取得した行を配信記録に変換
+func scanWebhookDelivery(row rowScanner) (*WebhookDelivery, error) {
+	var delivery WebhookDelivery
+	var responseCode sql.NullInt64
+	var lastError sql.NullString
+	var deliveredAt sql.NullTime
+
+	err := row.Scan(&delivery.ID, &delivery.IncidentID, &delivery.Event, &delivery.Endpoint, &delivery.Payload, &delivery.Status, &delivery.Attempts,
+		&responseCode, &lastError, &delivery.CreatedAt, &delivery.UpdatedAt, &deliveredAt)
+	if err != nil {
+		return nil, err
+	}
+	delivery.ResponseCode = int(responseCode.Int64)
+	delivery.LastError = lastError.String
+	if deliveredAt.Valid {
+		delivery.DeliveredAt = &deliveredAt.Time
+	}
+	return &delivery, nil
+}
+
+// CreateWebhookDelivery は Webhook の配信記録を保存し、配信IDを返す
+func (s *postgresStore) CreateWebhookDelivery(delivery WebhookDelivery) (int64, error) {
+	query := `
+		INSERT INTO webhook_deliveries (incident_id, event, endpoint, payload, status, attempts)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING id
+	`
+
+	var deliveryID int64
+	err := s.db.QueryRow(query, delivery.IncidentID, delivery.Event, delivery.Endpoint, delivery.Payload, delivery.Status, delivery.Attempts).Scan(&deliveryID)
+	if err != nil {
+		return 0, fmt.Errorf("配信記録保存エラー: %v", err)
+	}
+	return deliveryID, nil
+}
+
+// UpdateWebhookDelivery は配信記録の状態・送信回数・最後の結果を更新
+func (s *postgresStore) UpdateWebhookDelivery(delivery WebhookDelivery) error {
+	var deliveredAt sql.NullTime
+	if delivery.DeliveredAt != nil {
+		deliveredAt = sql.NullTime{Time: *delivery.DeliveredAt, Valid: true}
+	}
+
+	query := `
+		UPDATE webhook_deliveries
+		SET status = $1, attempts = $2, response_code = NULLIF($3, 0), last_error = NULLIF($4, ''),
+		    delivered_at = $5, updated_at = CURRENT_TIMESTAMP
+		WHERE id = $6
+	`
+	result, err := s.db.Exec(query, delivery.Status, delivery.Attempts, delivery.ResponseCode, delivery.LastError, deliveredAt, delivery.ID)
+	if err != nil {
+		return fmt.Errorf("配信記録更新エラー: %v", err)
+	}
+	if rows, err := result.RowsAffected(); err == nil && rows == 0 {
+		return errWebhookDeliveryNotFound(delivery.ID)
+	}
+	return nil
+}
+
+// GetWebhookDelivery は配信記録を取得
+func (s *postgresStore) GetWebhookDelivery(deliveryID int64) (*WebhookDelivery, error) {
+	delivery, err := scanWebhookDelivery(s.db.QueryRow(`SELECT `+deliveryColumns+` FROM webhook_deliveries WHERE id = $1`, deliveryID))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, errWebhookDeliveryNotFound(deliveryID)
+		}
+		return nil, fmt.Errorf("配信記録取得エラー: %v", err)
+	}
+	return delivery, nil
+}
+
+// WebhookDeliveries はインシデントの配信記録を新しい順に取得
+func (s *postgresStore) WebhookDeliveries(incidentID int64, limit int) ([]WebhookDelivery, error) {
+	query := `SELECT ` + deliveryColumns + ` FROM webhook_deliveries WHERE incident_id = $1 ORDER BY id DESC LIMIT NULLIF($2, 0)`
+
+	rows, err := s.db.Query(query, incidentID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("配信記録取得エラー: %v", err)
+	}
+	defer rows.Close()
+
+	var deliveries []WebhookDelivery
+	for rows.Next() {
+		delivery, err := scanWebhookDelivery(rows)
+		if err != nil {
+			log.Printf("配信記録スキャンエラー: %v", err)
+			continue
+		}
+		deliveries = append(deliveries, *delivery)
+	}
+	return deliveries, nil
+}
+
+// actionItemColumns はアクションアイテムを取得する際の列（scanActionItem と同じ順序）
+const actionItemColumns = `a.id, a.incident_id, i.title, a.title, a.owner_id, a.owner_name, a.due_date, a.status, a.link,
+		       a.created_by, a.created_at, a.completed_by, a.completed_at, a.last_reminded_at`
+
+// scanActionItem は actionItemColumns の順序で取得した行をアクションアイテムに変換
+func scanActionItem(row rowScanner) (*ActionItem, error) {
+	var item ActionItem
+	var ownerID, ownerName, link, completedBy sql.NullString
+	var dueDate, completedAt, lastRemindedAt sql.NullTime
+
+	err := row.Scan(&item.ID, &item.IncidentID, &item.IncidentTitle, &item.Title, &ownerID, &ownerName, &dueDate, &item.Status, &link,
+		&item.CreatedBy, &item.CreatedAt, &completedBy, &completedAt, &lastRemindedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	item.OwnerID = ownerID.String
+	item.OwnerName = ownerName.String
+	item.Link = link.String
+	item.CompletedBy = completedBy.String
+	if dueDate.Valid {
+		item.DueDate = &dueDate.Time
+	}
+	if completedAt.Valid {
+		item.CompletedAt = &completedAt.Time
+	}
+	if lastRemindedAt.Valid {
+		item.LastRemindedAt = &lastRemindedAt.Time
+	}
+	return &item, nil
+}
+
+// queryActionItems はアクションアイテムを検索
+func (s *postgresStore) queryActionItems(query string, args ...interface{}) ([]ActionItem, error) {
+	rows, err := s.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var items []ActionItem
+	for rows.Next() {
+		item, err := scanActionItem(rows)
+		if err != nil {
+			log.Printf("アクションアイテムスキャンエラー: %v", err)
+			continue
+		}
+		items = append(items, *item)
+	}
+	return items, nil
+}
+
+// AddActionItem はアクションアイテムを未完了として保存し、アクションアイテムIDを返す
+func (s *postgresStore) AddActionItem(item ActionItem) (int64, error) {
+	var dueDate interface{}
+	if item.DueDate != nil {
+		dueDate = item.DueDate.Format("2006-01-02")
+	}
+
+	query := `
+		INSERT INTO incident_action_items (incident_id, title, owner_id, owner_name, due_date, link, created_by, status)
+		VALUES ($1, $2, NULLIF($3, ''), NULLIF($4, ''), $5::date, NULLIF($6, ''), $7, $8)
+		RETURNING id
+	`
+
+	var itemID int64
+	err := s.db.QueryRow(query, item.IncidentID, item.Title, item.OwnerID, item.OwnerName, dueDate, item.Link, item.CreatedBy, actionItemStatusOpen).Scan(&itemID)
+	if err != nil {
+		return 0, fmt.Errorf("アクションアイテム保存エラー: %v", err)
+	}
+	return itemID, nil
+}
+
+// ListActionItems はアクションアイテムを未完了・期限の近い順に最大 limit 件取得
+func (s *postgresStore) ListActionItems(incidentID int64, ownerID string, includeDone bool, limit int) ([]ActionItem, error) {
+	query := `
+		SELECT ` + actionItemColumns + `
+		FROM incident_action_items a
+		JOIN incidents i ON i.id = a.incident_id
+		WHERE ($1 = 0 OR a.incident_id = $1)
+		  AND ($2 = '' OR a.owner_id = $2)
+		  AND ($3 OR a.status = $4)
+		ORDER BY a.status = $4 DESC, a.due_date ASC NULLS LAST, a.id ASC
+		LIMIT NULLIF($5, 0)
+	`
+
+	items, err := s.queryActionItems(query, incidentID, ownerID, includeDone, actionItemStatusOpen, limit)
+	if err != nil {
+		return nil, fmt.Errorf("アクションアイテム取得エラー: %v", err)
+	}
+	return items, nil
+}
+
+// OverdueActionItems は期限切れで、today 以降にまだリマインドしていない未完了のアクションアイテムを取得
+func (s *postgresStore) OverdueActionItems(today time.Time) ([]ActionItem, error) {
+	query := `
+		SELECT ` + actionItemColumns + `
+		FROM incident_action_items a
+		JOIN incidents i ON i.id = a.incident_id
+		WHERE a.status = $1
+		  AND a.owner_id IS NOT NULL
+		  AND a.due_date < $2::date
+		  AND (a.last_reminded_at IS NULL OR a.last_reminded_at < $3)
+		ORDER BY a.owner_id, a.due_date
+	`
+
+	items, err := s.queryActionItems(query, actionItemStatusOpen, today.Format("2006-01-02"), today)
+	if err != nil {
+		return nil, fmt.Errorf("期限切れアクションアイテム取得エラー: %v", err)
+	}
+	return items, nil
+}
+
+// CompleteActionItem は未完了のアクションアイテムを完了にし、完了にしたアクションアイテムを返す
+func (s *postgresStore) CompleteActionItem(itemID int64, completedBy string) (*ActionItem, error) {
+	query := `
+		WITH completed AS (
+			UPDATE incident_action_items
+			SET status = $1, completed_by = $2, completed_at = CURRENT_TIMESTAMP
+			WHERE id = $3 AND status = $4
+			RETURNING *
+		)
+		SELECT ` + actionItemColumns + `
+		FROM completed a
+		JOIN incidents i ON i.id = a.incident_id
+	`
+
+	item, err := scanActionItem(s.db.QueryRow(query, actionItemStatusDone, completedBy, itemID, actionItemStatusOpen))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, errActionItemNotCompletable(itemID)
+		}
+		return nil, fmt.Errorf("アクションアイテム更新エラー: %v", err)
+	}
+	return item, nil
+}
+
+// MarkActionItemsReminded はアクションアイテムのリマインド日時を記録
+func (s *postgresStore) MarkActionItemsReminded(itemIDs []int64) error {
+	_, err := s.db.Exec("UPDATE incident_action_items SET last_reminded_at = CURRENT_TIMESTAMP WHERE id = ANY($1)", pq.Array(itemIDs))
+	if err != nil {
+		return fmt.Errorf("リマインド日時更新エラー: %v", err)
+	}
+	return nil
+}
+
+// SaveTimekeeperState はタイムキーパーの状態と投稿間隔を保存
+func (s *postgresStore) SaveTimekeeperState(incidentID int64, state string, intervalMinutes int) error {
+	query := `
+		INSERT INTO incident_timekeepers (incident_id, state, interval_minutes, updated_at)
+		VALUES ($1, $2, NULLIF($3, 0), CURRENT_TIMESTAMP)
+		ON CONFLICT (incident_id) DO UPDATE
+		SET state = EXCLUDED.state,
+		    interval_minutes = COALESCE(EXCLUDED.interval_minutes, incident_timekeepers.interval_minutes),
+		    updated_at = CURRENT_TIMESTAMP
+	`
+
+	if _, err := s.db.Exec(query, incidentID, state, intervalMinutes); err != nil {
+		return fmt.Errorf("タイムキーパー状態保存エラー: %v", err)
+	}
+	return nil
+}
+
+// SaveTimekeeperProgress はタイムキーパーの投稿の進捗を保存
+func (s *postgresStore) SaveTimekeeperProgress(incidentID int64, progress timekeeperProgress) error {
+	var lastPostedAt sql.NullTime
+	if !progress.LastPostedAt.IsZero() {
+		lastPostedAt = sql.NullTime{Time: progress.LastPostedAt, Valid: true}
+	}
+
+	query := `
+		UPDATE incident_timekeepers
+		SET last_posted_at = $2, last_milestone = $3, pinned_ts = NULLIF($4, ''), updated_at = CURRENT_TIMESTAMP
+		WHERE incident_id = $1
+	`
+
+	if _, err := s.db.Exec(query, incidentID, lastPostedAt, progress.LastMilestone, progress.PinnedTS); err != nil {
+		return fmt.Errorf("タイムキーパー進捗保存エラー: %v", err)
+	}
+	return nil
+}
+
+// SaveTimekeeperInterval はタイムキーパーの投稿間隔を保存
+func (s *postgresStore) SaveTimekeeperInterval(incidentID int64, intervalMinutes int) error {
+	_, err := s.db.Exec("UPDATE incident_timekeepers SET interval_minutes = $2, updated_at = CURRENT_TIMESTAMP WHERE incident_id = $1", incidentID, intervalMinutes)
+	if err != nil {
+		return fmt.Errorf("タイムキーパー投稿間隔保存エラー: %v", err)
+	}
+	return nil
+}
+
+// TimekeeperStates は複数のインシデントのタイムキーパーの状態を取得
+func (s *postgresStore) TimekeeperStates(incidentIDs []int64) (map[int64]TimekeeperState, error) {
+	query := `
+		SELECT incident_id, state, interval_minutes, last_posted_at, last_milestone, pinned_ts, updated_at
+		FROM incident_timekeepers
+		WHERE incident_id = ANY($1)
+	`
+
+	rows, err := s.db.Query(query, pq.Array(incidentIDs))
+	if err != nil {
+		return nil, fmt.Errorf("タイムキーパー状態取得エラー: %v", err)
+	}
+	defer rows.Close()
+
+	states := make(map[int64]TimekeeperState)
+	for rows.Next() {
+		var incidentID int64
+		var state TimekeeperState
+		var intervalMinutes, lastMilestone sql.NullInt64
+		var lastPostedAt, updatedAt sql.NullTime
+		var pinnedTS sql.NullString
+
+		if err := rows.Scan(&incidentID, &state.State, &intervalMinutes, &lastPostedAt, &lastMilestone, &pinnedTS, &updatedAt); err != nil {
+			log.Printf("タイムキーパー状態スキャンエラー: %v", err)
+			continue
+		}
+
+		state.IntervalMinutes = int(intervalMinutes.Int64)
+		state.PinnedTS = pinnedTS.String
+		state.UpdatedAt = updatedAt.Time
+		if lastPostedAt.Valid {
+			state.LastPostedAt = &lastPostedAt.Time
+		}
+		if lastMilestone.Valid {
+			milestone := int(lastMilestone.Int64)
+			state.LastMilestone = &milestone
+		}
+		states[incidentID] = state
+	}
+	return states, nil
+}
+
+// TransitionTimekeeperState はタイムキーパーの状態が from のいずれかの場合のみ to に変更し、変更したかを返す
+func (s *postgresStore) TransitionTimekeeperState(incidentID int64, from []string, to string) (bool, error) {
+	query := `
+		INSERT INTO incident_timekeepers (incident_id, state, updated_at)
+		SELECT $1::integer, $3::varchar, CURRENT_TIMESTAMP
+		WHERE $4 = ANY($2)
+		ON CONFLICT (incident_id) DO UPDATE
+		SET state = EXCLUDED.state,
+		    last_posted_at = CASE WHEN EXCLUDED.state = $4 THEN CURRENT_TIMESTAMP ELSE incident_timekeepers.last_posted_at END,
+		    last_milestone = CASE WHEN EXCLUDED.state = $4 THEN NULL ELSE incident_timekeepers.last_milestone END,
+		    updated_at = CURRENT_TIMESTAMP
+		WHERE incident_timekeepers.state = ANY($2)
+	`
+
+	result, err := s.db.Exec(query, incidentID, pq.Array(from), to, timekeeperStateRunning)
+	if err != nil {
+		return false, fmt.Errorf("タイムキーパー状態更新エラー: %v", err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("タイムキーパー状態更新エラー: %v", err)
+	}
+	return affected > 0, nil
+}
+
+// IncidentStats は集計期間とその直前の同じ長さの期間の重要度ごとの統計を取得
+func (s *postgresStore) IncidentStats(until time.Time, period time.Duration) (current, previous map[string]IncidentStats, err error) {
+	query := `
+		WITH first_assignment AS (
+			SELECT incident_id, MIN(assigned_at) AS assigned_at
+			FROM incident_handler_history
+			GROUP BY incident_id
+		), scoped AS (
+			SELECT i.severity,
+			       CASE WHEN i.created_at >= $2 THEN 'current' ELSE 'previous' END AS period,
+			       EXTRACT(EPOCH FROM fa.assigned_at - i.created_at)::double precision AS tta,
+			       EXTRACT(EPOCH FROM i.resolved_at - i.created_at)::double precision AS ttr
+			FROM incidents i
+			LEFT JOIN first_assignment fa ON fa.incident_id = i.id
+			WHERE i.created_at >= $1 AND i.created_at < $3
+		)
+		SELECT period, severity, COUNT(*),
+		       COUNT(tta), AVG(tta),
+		       percentile_cont(0.5) WITHIN GROUP (ORDER BY tta),
+		       percentile_cont(0.9) WITHIN GROUP (ORDER BY tta),
+		       COUNT(ttr), AVG(ttr),
+		       percentile_cont(0.5) WITHIN GROUP (ORDER BY ttr),
+		       percentile_cont(0.9) WITHIN GROUP (ORDER BY ttr)
+		FROM scoped
+		GROUP BY GROUPING SETS ((period, severity), (period))
+	`
+
+	currentFrom := until.Add(-period)
+	rows, err := s.db.Query(query, currentFrom.Add(-period), currentFrom, until)
+	if err != nil {
+		return nil, nil, fmt.Errorf("インシデント統計取得エラー: %v", err)
+	}
+	defer rows.Close()
+
+	current = make(map[string]IncidentStats)
+	previous = make(map[string]IncidentStats)
+	for rows.Next() {
+		var periodName string
+		var severity sql.NullString
+		var stats IncidentStats
+		var ttaMean, ttaMedian, ttaP90, ttrMean, ttrMedian, ttrP90 sql.NullFloat64
+
+		err := rows.Scan(&periodName, &severity, &stats.Count,
+			&stats.TimeToAssign.Count, &ttaMean, &ttaMedian, &ttaP90,
+			&stats.TimeToResolve.Count, &ttrMean, &ttrMedian, &ttrP90,
+		)
+		if err != nil {
+			log.Printf("インシデント統計スキャンエラー: %v", err)
+			continue
+		}
+
+		stats.TimeToAssign.Mean = secondsToDuration(ttaMean)
+		stats.TimeToAssign.Median = secondsToDuration(ttaMedian)
+		stats.TimeToAssign.P90 = secondsToDuration(ttaP90)
+		stats.TimeToResolve.Mean = secondsToDuration(ttrMean)
+		stats.TimeToResolve.Median = secondsToDuration(ttrMedian)
+		stats.TimeToResolve.P90 = secondsToDuration(ttrP90)
+
+		if periodName == "current" {
+			current[severity.String] = stats
+		} else {
+			previous[severity.String] = stats
+		}
+	}
+
+	return current, previous, nil
+}
+
+// secondsToDuration は秒数（NULL可）をtime.Durationに変換
+func secondsToDuration(seconds sql.NullFloat64) time.Duration {
+	if !seconds.Valid {
+		return 0
+	}
+	return time.Duration(seconds.Float64 * float64(time.Second))
+}
+
+// ClaimProcessedEvent はイベントを処理済みとして記録し、初めて記録した場合は true を返す
+func (s *postgresStore) ClaimProcessedEvent(eventKey string, ttl time.Duration) (bool, error) {
+	query := `
+		INSERT INTO processed_events (event_key, processed_at, expires_at)
+		VALUES ($1, CURRENT_TIMESTAMP, CURRENT_TIMESTAMP + $2 * INTERVAL '1 second')
+		ON CONFLICT (event_key) DO UPDATE
+		SET processed_at = EXCLUDED.processed_at,
+		    expires_at = EXCLUDED.expires_at
+		WHERE processed_events.expires_at <= CURRENT_TIMESTAMP
+	`
+
+	result, err := s.db.Exec(query, eventKey, int64(ttl.Seconds()))
+	if err != nil {
+		return false, fmt.Errorf("処理済みイベント記録エラー: %v", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("処理済みイベント記録エラー: %v", err)
+	}
+	return rows > 0, nil
+}
+
+// PurgeExpiredProcessedEvents は有効期限が切れた処理済みイベントを削除し、削除した件数を返す
+func (s *postgresStore) PurgeExpiredProcessedEvents() (int64, error) {
+	result, err := s.db.Exec(`DELETE FROM processed_events WHERE expires_at <= CURRENT_TIMESTAMP`)
+	if err != nil {
+		return 0, fmt.Errorf("処理済みイベント削除エラー: %v", err)
+	}
+
+	deleted, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("処理済みイベント削除エラー: %v", err)
+	}
+	return deleted, nil
+}
+
+// LookupJournalIncidentID はジャーナルの仮IDに対応する登録後のインシデントIDを取得（未登録の場合は0）
+func (s *postgresStore) LookupJournalIncidentID(journalID int64) (int64, error) {
+	var incidentID int64
+	err := s.db.QueryRow("SELECT incident_id FROM incident_journal_ids WHERE journal_id = $1", journalID).Scan(&incidentID)
+	if err == sql.ErrNoRows {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, fmt.Errorf("仮ID取得エラー: %v", err)
+	}
+	return incidentID, nil
+}
+
+// importJournalRecord はジャーナルのインシデントを変更履歴・日時とともに登録し、登録後のIDを返す
+// 仮IDとの対応を incident_journal_ids に記録するため、同じ仮IDを二重に登録することはない
+func (s *postgresStore) importJournalRecord(record journalRecord) (int64, error) {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return 0, fmt.Errorf("トランザクション開始エラー: %v", err)
+	}
+	defer tx.Rollback()
+
+	// 登録済みの場合（前回の登録がジャーナルの削除前に中断した場合など）はそのIDを返す
+	var incidentID int64
+	err = tx.QueryRow("SELECT incident_id FROM incident_journal_ids WHERE journal_id = $1", record.Incident.ID).Scan(&incidentID)
+	if err == nil {
+		return incidentID, nil
+	}
+	if err != sql.ErrNoRows {
+		return 0, fmt.Errorf("仮ID取得エラー: %v", err)
+	}
+
+	incident := record.Incident
+	var resolvedAt sql.NullTime
+	if incident.ResolvedAt != nil {
+		resolvedAt = sql.NullTime{Time: *incident.ResolvedAt, Valid: true}
+	}
+
+	query := `
+		INSERT INTO incidents (title, severity, description, impact, status, channel_id, channel_name, reporter_id, reporter_name,
+		                       handler_id, handler_name, source_permalink, resolution_note, created_at, updated_at, resolved_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, NULLIF($10, ''), NULLIF($11, ''), NULLIF($12, ''), NULLIF($13, ''), $14, $15, $16)
+		RETURNING id
+	`
+	err = tx.QueryRow(query, incident.Title, incident.Severity, incident.Description, incident.Impact, incident.Status,
+		incident.ChannelID, incident.ChannelName, incident.ReporterID, incident.ReporterName,
+		incident.HandlerID, incident.HandlerName, incident.SourcePermalink, incident.ResolutionNote,
+		incident.CreatedAt, incident.UpdatedAt, resolvedAt).Scan(&incidentID)
+	if err != nil {
+		return 0, fmt.Errorf("インシデント保存エラー: %v", err)
+	}
+
+	for _, change := range record.StatusHistory {
+		_, err = tx.Exec(`
+			INSERT INTO incident_status_history (incident_id, old_status, new_status, changed_by, changed_at, note)
+			VALUES ($1, NULLIF($2, ''), $3, $4, $5, $6)
+		`, incidentID, change.OldStatus, change.NewStatus, change.ChangedBy, change.ChangedAt, change.Note)
+		if err != nil {
+			return 0, fmt.Errorf("ステータス履歴記録エラー: %v", err)
+		}
+	}
+	for _, change := range record.HandlerHistory {
+		_, err = tx.Exec(`
+			INSERT INTO incident_handler_history (incident_id, old_handler_id, new_handler_id, assigned_by, assigned_at)
+			VALUES ($1, NULLIF($2, ''), $3, $4, $5)
+		`, incidentID, change.OldHandlerID, change.NewHandlerID, change.AssignedBy, change.AssignedAt)
+		if err != nil {
+			return 0, fmt.Errorf("ハンドラー履歴記録エラー: %v", err)
+		}
+	}
+	for _, update := range record.UpdateHistory {
+		_, err = tx.Exec(`
+			INSERT INTO incident_update_history (incident_id, field_name, old_value, new_value, updated_by, updated_by_name, updated_at, note)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, NULLIF($8, ''))
+		`, incidentID, update.FieldName, update.OldValue, update.NewValue, update.UpdatedBy, update.UpdatedByName, update.UpdatedAt, update.Note)
+		if err != nil {
+			return 0, fmt.Errorf("更新履歴記録エラー: %v", err)
+		}
+	}
+
+	for _, alert := range record.Alerts {
+		alert.IncidentID = incidentID
+		if _, err := insertIncidentAlert(tx, alert); err != nil {
+			return 0, err
+		}
+	}
+
+	_, err = tx.Exec("INSERT INTO incident_journal_ids (journal_id, incident_id) VALUES ($1, $2)", record.Incident.ID, incidentID)
+	if err != nil {
+		return 0, fmt.Errorf("仮ID記録エラー: %v", err)
+	}
+
+	if err = tx.Commit(); err != nil {
+		return 0, fmt.Errorf("トランザクションコミットエラー: %v", err)
+	}
+	return incidentID, nil
+}
+
+// replayOutboxEntry はアウトボックスの操作を記録した日時で登録する
+// 登録済みの操作を incident_outbox_entries に記録するため、同じ操作を二重に登録することはない
+// 縮退運転中にほかのレプリカが変更したなどで許可されていない遷移になったステータス変更は登録せずに破棄する
+func (s *postgresStore) replayOutboxEntry(entry outboxEntry) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return fmt.Errorf("トランザクション開始エラー: %v", err)
+	}
+	defer tx.Rollback()
+
+	var replayed bool
+	err = tx.QueryRow("SELECT EXISTS (SELECT 1 FROM incident_outbox_entries WHERE entry_id = $1)", entry.ID).Scan(&replayed)
+	if err != nil {
+		return fmt.Errorf("登録済みの操作の確認エラー: %v", err)
+	}
+	if replayed {
+		return nil
+	}
+
+	// 変更するインシデントをロック（削除された場合は破棄する）
+	var oldStatus string
+	var oldHandlerID sql.NullString
+	err = tx.QueryRow("SELECT status, handler_id FROM incidents WHERE id = $1 FOR UPDATE", entry.IncidentID).Scan(&oldStatus, &oldHandlerID)
+	if err == sql.ErrNoRows {
+		log.Printf("インシデント %d が見つからないため、アウトボックスの操作 %s を破棄します", entry.IncidentID, entry.ID)
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("インシデント取得エラー: %v", err)
+	}
+
+	switch entry.Op {
+	case outboxOpHandler:
+		_, err = tx.Exec("UPDATE incidents SET handler_id = $1, handler_name = $2, updated_at = $3 WHERE id = $4",
+			entry.HandlerID, entry.HandlerName, entry.RecordedAt, entry.IncidentID)
+		if err != nil {
+			return fmt.Errorf("ハンドラー更新エラー: %v", err)
+		}
+		_, err = tx.Exec(`
+			INSERT INTO incident_handler_history (incident_id, old_handler_id, new_handler_id, assigned_by, assigned_at)
+			VALUES ($1, NULLIF($2, ''), $3, $4, $5)
+		`, entry.IncidentID, oldHandlerID.String, entry.HandlerID, entry.ChangedBy, entry.RecordedAt)
+		if err != nil {
+			return fmt.Errorf("ハンドラー履歴記録エラー: %v", err)
+		}
+
+	case outboxOpStatus:
+		if err := validateTransition(oldStatus, entry.NewStatus); err != nil {
+			log.Printf("インシデント %d のアウトボックスの操作 %s を破棄します: %v", entry.IncidentID, entry.ID, err)
+			return nil
+		}
+		_, err = tx.Exec(`
+			UPDATE incidents
+			SET status = $1,
+			    resolved_at = CASE WHEN $2 THEN $3 ELSE resolved_at END,
+			    resolution_note = COALESCE(NULLIF($4, ''), resolution_note),
+			    updated_at = $3
+			WHERE id = $5
+		`, entry.NewStatus, entry.NewStatus == StatusResolved, entry.RecordedAt, entry.ResolutionNote, entry.IncidentID)
+		if err != nil {
+			return fmt.Errorf("ステータス更新エラー: %v", err)
+		}
+		_, err = tx.Exec(`
+			INSERT INTO incident_status_history (incident_id, old_status, new_status, changed_by, changed_at, note)
+			VALUES ($1, $2, $3, $4, $5, $6)
+		`, entry.IncidentID, normalizeStatus(oldStatus), entry.NewStatus, entry.ChangedBy, entry.RecordedAt, entry.Note)
+		if err != nil {
+			return fmt.Errorf("ステータス履歴記録エラー: %v", err)
+		}
+
+	case outboxOpResolutionNote:
+		// 復旧メモを復旧と別に記録していた以前のバージョンの操作
+		_, err = tx.Exec("UPDATE incidents SET resolution_note = $1, updated_at = $2 WHERE id = $3", entry.Note, entry.RecordedAt, entry.IncidentID)
+		if err != nil {
+			return fmt.Errorf("復旧メモ保存エラー: %v", err)
+		}
+
+	case outboxOpField:
+		// 列名はクエリに埋め込むため、更新できるフィールドに限定する
+		if !updatableIncidentFields[entry.Field] {
+			return fmt.Errorf("更新できないフィールド: %s", entry.Field)
+		}
+		updateQuery := fmt.Sprintf("UPDATE incidents SET %s = $1, updated_at = $2 WHERE id = $3", entry.Field)
+		if _, err = tx.Exec(updateQuery, entry.NewValue, entry.RecordedAt, entry.IncidentID); err != nil {
+			return fmt.Errorf("インシデント更新エラー: %v", err)
+		}
+		_, err = tx.Exec(`
+			INSERT INTO incident_update_history (incident_id, field_name, old_value, new_value, updated_by, updated_by_name, updated_at)
+			VALUES ($1, $2, $3, $4, $5, $6, $7)
+		`, entry.IncidentID, entry.Field, entry.OldValue, entry.NewValue, entry.ChangedBy, entry.ChangedName, entry.RecordedAt)
+		if err != nil {
+			return fmt.Errorf("更新履歴記録エラー: %v", err)
+		}
+
+	default:
+		log.Printf("不明な操作のため、アウトボックスの操作 %s (%s) を破棄します", entry.ID, entry.Op)
+		return nil
+	}
+
+	_, err = tx.Exec("INSERT INTO incident_outbox_entries (entry_id, incident_id, operation, recorded_at) VALUES ($1, $2, $3, $4)",
+		entry.ID, entry.IncidentID, entry.Op, entry.RecordedAt)
+	if err != nil {
+		return fmt.Errorf("登録済みの操作の記録エラー: %v", err)
+	}
+
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("トランザクションコミットエラー: %v", err)
+	}
+	return nil
+}
+
+// AddHomeViewer はApp Homeを開いたユーザーを再描画対象として記録（記録済みの場合は最後に開いた日時を更新）
+func (s *postgresStore) AddHomeViewer(userID string) error {
+	_, err := s.db.Exec(`
+		INSERT INTO home_viewers (user_id) VALUES ($1)
+		ON CONFLICT (user_id) DO UPDATE SET last_opened_at = CURRENT_TIMESTAMP
+	`, userID)
+	if err != nil {
+		return fmt.Errorf("App Homeの閲覧者記録エラー: %v", err)
+	}
+	return nil
+}
+
+// HomeViewers は openedSince 以降にApp Homeを開いたユーザーの一覧を取得
+func (s *postgresStore) HomeViewers(openedSince time.Time) ([]string, error) {
+	rows, err := s.db.Query(`SELECT user_id FROM home_viewers WHERE last_opened_at >= $1 ORDER BY user_id`, openedSince)
+	if err != nil {
+		return nil, fmt.Errorf("App Homeの閲覧者取得エラー: %v", err)
+	}
+	defer rows.Close()
+
+	var userIDs []string
+	for rows.Next() {
+		var userID string
+		if err := rows.Scan(&userID); err != nil {
+			return nil, fmt.Errorf("App Homeの閲覧者スキャンエラー: %v", err)
+		}
+		userIDs = append(userIDs, userID)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("App Homeの閲覧者取得エラー: %v", err)
+	}
+	return userIDs, nil
+}
+
+// PurgeHomeViewers は openedBefore より前に最後にApp Homeを開いたユーザーを削除し、削除した件数を返す
+func (s *postgresStore) PurgeHomeViewers(openedBefore time.Time) (int64, error) {
+	result, err := s.db.Exec(`DELETE FROM home_viewers WHERE last_opened_at < $1`, openedBefore)
+	if err != nil {
+		return 0, fmt.Errorf("App Homeの閲覧者削除エラー: %v", err)
+	}
+
+	deleted, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("App Homeの閲覧者削除エラー: %v", err)
+	}
+	return deleted, nil
+}
@@ -0,0 +1,383 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"log"
+	"os"
+	"regexp"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/slack-go/slack"
+)
+
+// defaultPostmortemTemplate は組み込みのポストモーテムテンプレート（Markdown）
+// config.toml の [postmortem] template_file で独自のテンプレートに差し替えられる
+const defaultPostmortemTemplate = `# ポストモーテム: {{.Title}}
+
+> このドキュメントはインシデント #{{.ID}} の記録から自動生成された下書きです（{{.GeneratedAt}} 生成）。
+
+## 概要
+
+| 項目 | 内容 |
+|---|---|
+| インシデントID | #{{.ID}} |
+| 重要度 | {{.Severity}} |
+| ステータス | {{.Status}} |
+| 報告日時 | {{.CreatedAt}} |
+| 復旧日時 | {{if .ResolvedAt}}{{.ResolvedAt}}{{else}}未復旧{{end}} |
+| 対応時間 | {{.Duration}} |
+| 報告者 | {{.Reporter}} |
+| 担当者 | {{if .Handler}}{{.Handler}}{{else}}未割り当て{{end}} |
+| チャンネル | #{{.ChannelName}} |
+
+## 詳細
+
+{{.Description}}
+
+## 影響範囲
+
+{{.Impact}}
+
+## 復旧対応
+
+{{if .ResolutionNote}}{{.ResolutionNote}}{{else}}<!-- 復旧のために実施した対応を記入してください -->{{end}}
+
+## タイムライン
+
+| 日時 | 出来事 |
+|---|---|
+{{range .Timeline}}| {{.Time}} | {{cell .Text}} |
+{{end}}
+## 対応メンバー
+
+{{range .Contributors}}- {{.}}
+{{else}}- （記録なし）
+{{end}}
+## 根本原因
+
+<!-- なぜ発生したのかを記入してください（5 Whys など） -->
+
+## アクションアイテム
+
+| ID | 内容 | 担当者 | 期限 | 状態 |
+|---|---|---|---|---|
+{{range .ActionItems}}| #{{.ID}} | {{cell .Title}}{{if .Link}} ([リンク]({{.Link}})){{end}} | {{.Owner}} | {{.DueDate}} | {{.Status}} |
+{{else}}|  |  |  |  |  |
+{{end}}
+## 学んだこと
+
+### うまくいったこと
+
+### 改善が必要なこと
+`
+
+// PostmortemData はポストモーテムテンプレートに渡すデータ
+type PostmortemData struct {
+	ID             int64
+	Title          string
+	Severity       string
+	Status         string
+	Description    string
+	Impact         string
+	ResolutionNote string
+	Reporter       string
+	Handler        string
+	ChannelName    string
+	CreatedAt      string
+	ResolvedAt     string // 未復旧の場合は空文字列
+	Duration       string
+	Timeline       []PostmortemTimelineEntry
+	Contributors   []string
+	ActionItems    []PostmortemActionItem
+	GeneratedAt    string
+}
+
+// PostmortemActionItem はポストモーテムのアクションアイテムの1行
+type PostmortemActionItem struct {
+	ID      int64
+	Title   string
+	Owner   string
+	DueDate string
+	Status  string
+	Link    string
+}
+
+// PostmortemTimelineEntry はポストモーテムのタイムラインの1行
+type PostmortemTimelineEntry struct {
+	Time string
+	Text string
+}
+
+// postmortemTemplateFuncs はテンプレートで利用できる関数
+var postmortemTemplateFuncs = template.FuncMap{
+	"cell": escapeMarkdownTableCell,
+}
+
+// mentionPattern はSlackのユーザーメンション（<@U123> / <@U123|name>）
+var mentionPattern = regexp.MustCompile(`<@([A-Z0-9]+)(?:\|[^>]*)?>`)
+
+// handleGeneratePostmortem は「ポストモーテムを作成」ボタンがクリックされた時の処理
+func handleGeneratePostmortem(api SlackAPI, callback slack.InteractionCallback) {
+	log.Println("ポストモーテム作成ボタンがクリックされました")
+
+	incidentID, err := parseIncidentActionValue(callback.ActionCallback.BlockActions[0].Value)
+	if err != nil {
+		log.Printf("インシデントID解析エラー: %v", err)
+		return
+	}
+
+	if err := postPostmortem(api, incidentID); err != nil {
+		api.PostEphemeral(
+			callbackChannelID(callback, incidentID),
+			callback.User.ID,
+			slack.MsgOptionText(fmt.Sprintf("❌ %v", err), false),
+		)
+	}
+}
+
+// showPostmortem はポストモーテムを（再）生成してインシデントチャンネルに投稿するコマンド
+func showPostmortem(ctx *CommandContext) {
+	incidentID, err := resolveTargetIncident(ctx)
+	if err != nil {
+		ctx.reply(fmt.Sprintf("❌ %v", err), true)
+		return
+	}
+
+	if err := postPostmortem(ctx.API, incidentID); err != nil {
+		ctx.reply(fmt.Sprintf("❌ %v", err), true)
+		return
+	}
+
+	ctx.reply(fmt.Sprintf("📝 インシデント #%d のポストモーテムの下書きをインシデントチャンネルに投稿しました", incidentID), true)
+}
+
+// postPostmortem はポストモーテムの下書きを生成し、インシデントチャンネルにファイルとして投稿
+func postPostmortem(api SlackAPI, incidentID int64) error {
+	incident, err := getIncidentDetails(incidentID)
+	if err != nil {
+		log.Printf("インシデント詳細取得エラー: %v", err)
+		return fmt.Errorf("インシデント情報の取得に失敗しました: %v", err)
+	}
+	channelID := incident.ChannelID
+
+	tmpl, err := loadPostmortemTemplate()
+	if err != nil {
+		log.Printf("ポストモーテムテンプレート読み込みエラー: %v", err)
+		return fmt.Errorf("ポストモーテムのテンプレートを読み込めませんでした: %v", err)
+	}
+
+	// ポストモーテムは対応の完全な記録として残すため、詳細表示と異なり履歴を件数で打ち切らずにすべて取得する
+	updates, err := getUpdateHistory(incidentID, 0)
+	if err != nil {
+		log.Printf("更新履歴取得エラー: %v", err)
+	}
+	handlers, err := getHandlerHistory(incidentID, 0)
+	if err != nil {
+		log.Printf("担当者履歴取得エラー: %v", err)
+	}
+	statuses, err := getStatusHistory(incidentID, 0)
+	if err != nil {
+		log.Printf("ステータス履歴取得エラー: %v", err)
+	}
+	timeline := buildIncidentTimeline(updates, handlers, statuses)
+
+	contributors, err := getChannelContributors(api, channelID)
+	if err != nil {
+		log.Printf("対応メンバー取得エラー: %v", err)
+	}
+
+	actionItems, err := listActionItems(incidentID, "", true)
+	if err != nil {
+		log.Printf("アクションアイテム取得エラー: %v", err)
+	}
+
+	// ファイル内ではメンションが展開されないため、ユーザー名に置き換える
+	names := newUserNameResolver(api)
+	data := buildPostmortemData(incident, timeline, splitMentions(contributors), time.Now())
+	data.ActionItems = buildPostmortemActionItems(actionItems)
+	for i := range data.Timeline {
+		data.Timeline[i].Text = names.replaceMentions(data.Timeline[i].Text)
+	}
+	for i := range data.Contributors {
+		data.Contributors[i] = names.replaceMentions(data.Contributors[i])
+	}
+	for i := range data.ActionItems {
+		data.ActionItems[i].Owner = names.replaceMentions(data.ActionItems[i].Owner)
+	}
+	data.Reporter = names.replaceMentions(data.Reporter)
+	data.Handler = names.replaceMentions(data.Handler)
+
+	content, err := renderPostmortem(tmpl, data)
+	if err != nil {
+		log.Printf("ポストモーテム生成エラー: %v", err)
+		return fmt.Errorf("ポストモーテムの生成に失敗しました: %v", err)
+	}
+
+	_, err = api.UploadFileV2(slack.UploadFileV2Parameters{
+		Channel:        channelID,
+		Content:        content,
+		FileSize:       len(content),
+		Filename:       fmt.Sprintf("postmortem-incident-%d.md", incidentID),
+		Title:          fmt.Sprintf("ポストモーテム: %s", incident.Title),
+		InitialComment: fmt.Sprintf("📝 インシデント #%d のポストモーテムの下書きを作成しました。根本原因とアクションアイテムを記入してください。", incidentID),
+	})
+	if err != nil {
+		log.Printf("ポストモーテム投稿エラー: %v", err)
+		return fmt.Errorf("ポストモーテムの投稿に失敗しました: %v", err)
+	}
+
+	log.Printf("インシデント %d のポストモーテムを投稿しました", incidentID)
+	return nil
+}
+
+// loadPostmortemTemplate は設定されたテンプレート（未設定の場合は組み込みテンプレート）を読み込む
+// テンプレートファイルの変更を再起動なしで反映するため、生成のたびに読み込む
+func loadPostmortemTemplate() (*template.Template, error) {
+	text := defaultPostmortemTemplate
+	if config.Postmortem.TemplateFile != "" {
+		content, err := os.ReadFile(config.Postmortem.TemplateFile)
+		if err != nil {
+			return nil, err
+		}
+		text = string(content)
+	}
+
+	return template.New("postmortem").Funcs(postmortemTemplateFuncs).Parse(text)
+}
+
+// renderPostmortem はテンプレートにデータを適用してMarkdownを生成
+func renderPostmortem(tmpl *template.Template, data PostmortemData) (string, error) {
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
+
+// buildPostmortemData はインシデント詳細とタイムラインからテンプレート用のデータを構築
+func buildPostmortemData(incident *Incident, timeline []timelineEntry, contributors []string, now time.Time) PostmortemData {
+	const timeFormat = "2006-01-02 15:04"
+	createdAt := incident.CreatedAt
+
+	data := PostmortemData{
+		ID:             incident.ID,
+		Title:          incident.Title,
+		Severity:       fmt.Sprintf("%s %s", severityEmojis[incident.Severity], incident.Severity),
+		Status:         statusLabel(incident.Status),
+		Description:    incident.Description,
+		Impact:         incident.Impact,
+		Reporter:       fmt.Sprintf("<@%s>", incident.ReporterID),
+		ChannelName:    incident.ChannelName,
+		ResolutionNote: incident.ResolutionNote,
+		CreatedAt:      createdAt.Format(timeFormat),
+		Duration:       fmt.Sprintf("%s（対応中）", formatElapsed(now.Sub(createdAt))),
+		Contributors:   contributors,
+		GeneratedAt:    now.Format(timeFormat),
+	}
+
+	if incident.HandlerID != "" {
+		data.Handler = fmt.Sprintf("<@%s>", incident.HandlerID)
+	}
+	if incident.ResolvedAt != nil && !isActiveStatus(incident.Status) {
+		data.ResolvedAt = incident.ResolvedAt.Format(timeFormat)
+		data.Duration = formatElapsed(incident.ResolvedAt.Sub(createdAt))
+	}
+
+	for _, entry := range timeline {
+		data.Timeline = append(data.Timeline, PostmortemTimelineEntry{
+			Time: entry.At.Format(timeFormat),
+			Text: entry.Text,
+		})
+	}
+
+	return data
+}
+
+// buildPostmortemActionItems はアクションアイテムをテンプレート用のデータに変換
+func buildPostmortemActionItems(items []ActionItem) []PostmortemActionItem {
+	var result []PostmortemActionItem
+	for _, item := range items {
+		actionItem := PostmortemActionItem{
+			ID:     item.ID,
+			Title:  item.Title,
+			Status: "未完了",
+			Link:   item.Link,
+		}
+		if item.Status == actionItemStatusDone {
+			actionItem.Status = "完了"
+		}
+		if item.OwnerID != "" {
+			actionItem.Owner = fmt.Sprintf("<@%s>", item.OwnerID)
+		}
+		if item.DueDate != nil {
+			actionItem.DueDate = item.DueDate.Format("2006-01-02")
+		}
+		result = append(result, actionItem)
+	}
+	return result
+}
+
+// splitMentions は getChannelContributors が返すメンションの連結文字列を分割
+func splitMentions(mentions string) []string {
+	var result []string
+	for _, mention := range strings.Split(mentions, ",") {
+		if mention = strings.TrimSpace(mention); mention != "" {
+			result = append(result, mention)
+		}
+	}
+	return result
+}
+
+// escapeMarkdownTableCell はMarkdownの表のセルに入れられるように文字列をエスケープ
+func escapeMarkdownTableCell(s string) string {
+	s = strings.ReplaceAll(s, "|", `\|`)
+	return strings.Join(strings.Fields(s), " ")
+}
+
+// userNameResolver はユーザーIDから表示名への変換結果をキャッシュする
+type userNameResolver struct {
+	api   SlackAPI
+	names map[string]string
+}
+
+// newUserNameResolver はユーザー名の変換器を作成
+func newUserNameResolver(api SlackAPI) *userNameResolver {
+	return &userNameResolver{api: api, names: make(map[string]string)}
+}
+
+// replaceMentions は文字列中のメンションを @表示名 に置き換える
+func (r *userNameResolver) replaceMentions(s string) string {
+	return mentionPattern.ReplaceAllStringFunc(s, func(mention string) string {
+		userID := mentionPattern.FindStringSubmatch(mention)[1]
+		return "@" + r.lookup(userID)
+	})
+}
+
+// lookup はユーザーの表示名を取得（取得できない場合はユーザーID）
+func (r *userNameResolver) lookup(userID string) string {
+	if name, ok := r.names[userID]; ok {
+		return name
+	}
+
+	name := userID
+	if r.api != nil {
+		if user, err := r.api.GetUserInfo(userID); err == nil {
+			switch {
+			case user.Profile.DisplayName != "":
+				name = user.Profile.DisplayName
+			case user.RealName != "":
+				name = user.RealName
+			default:
+				name = user.Name
+			}
+		} else {
+			log.Printf("ユーザー情報取得エラー (%s): %v", userID, err)
+		}
+	}
+
+	r.names[userID] = name
+	return name
+}
@@ -0,0 +1,184 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func testPostmortemDetails() *Incident {
+	resolvedAt := time.Date(2025, 1, 1, 12, 15, 0, 0, time.UTC)
+	return &Incident{
+		ID:             42,
+		Title:          "APIエラー率上昇",
+		Severity:       "critical",
+		Description:    "5xxが増加",
+		Impact:         "全ユーザー",
+		Status:         StatusResolved,
+		ChannelName:    "incident-20250101",
+		ReporterID:     "U1",
+		HandlerID:      "U2",
+		ResolutionNote: "DBをフェイルオーバー",
+		CreatedAt:      time.Date(2025, 1, 1, 10, 0, 0, 0, time.UTC),
+		ResolvedAt:     &resolvedAt,
+	}
+}
+
+func TestBuildPostmortemData(t *testing.T) {
+	timeline := []timelineEntry{
+		{At: time.Date(2025, 1, 1, 10, 0, 0, 0, time.UTC), Text: "🚨 <@U1> がインシデントを報告"},
+	}
+	now := time.Date(2025, 1, 2, 9, 0, 0, 0, time.UTC)
+
+	data := buildPostmortemData(testPostmortemDetails(), timeline, []string{"<@U1>", "<@U2>"}, now)
+
+	if data.ResolvedAt != "2025-01-01 12:15" || data.Duration != "2時間15分" {
+		t.Errorf("復旧日時・対応時間が間違っています: %s / %s", data.ResolvedAt, data.Duration)
+	}
+	if data.Handler != "<@U2>" || data.Reporter != "<@U1>" {
+		t.Errorf("報告者・担当者が間違っています: %s / %s", data.Reporter, data.Handler)
+	}
+	if data.ResolutionNote != "DBをフェイルオーバー" {
+		t.Errorf("復旧メモが間違っています: %s", data.ResolutionNote)
+	}
+	if len(data.Timeline) != 1 || data.Timeline[0].Time != "2025-01-01 10:00" {
+		t.Errorf("タイムラインが間違っています: %+v", data.Timeline)
+	}
+
+	// 未復旧の場合は経過時間を表示
+	details := testPostmortemDetails()
+	details.Status = StatusMonitoring
+	details.ResolvedAt = nil
+	data = buildPostmortemData(details, nil, nil, time.Date(2025, 1, 1, 10, 30, 0, 0, time.UTC))
+	if data.ResolvedAt != "" || data.Duration != "30分（対応中）" {
+		t.Errorf("未復旧の場合の対応時間が間違っています: %s / %s", data.ResolvedAt, data.Duration)
+	}
+}
+
+func TestRenderDefaultPostmortemTemplate(t *testing.T) {
+	originalConfig := config
+	config.Postmortem.TemplateFile = ""
+	defer func() { config = originalConfig }()
+
+	tmpl, err := loadPostmortemTemplate()
+	if err != nil {
+		t.Fatalf("組み込みテンプレートの読み込みに失敗しました: %v", err)
+	}
+
+	data := buildPostmortemData(testPostmortemDetails(), []timelineEntry{
+		{At: time.Date(2025, 1, 1, 10, 5, 0, 0, time.UTC), Text: "タイトルを変更: a|b"},
+	}, []string{"@tanaka"}, time.Now())
+	dueDate := time.Date(2025, 1, 10, 0, 0, 0, 0, time.UTC)
+	data.ActionItems = buildPostmortemActionItems([]ActionItem{
+		{ID: 3, Title: "閾値を見直す", Status: actionItemStatusOpen, OwnerID: "U1", DueDate: &dueDate, Link: "https://example.com/3"},
+		{ID: 4, Title: "手順書を更新", Status: actionItemStatusDone},
+	})
+
+	content, err := renderPostmortem(tmpl, data)
+	if err != nil {
+		t.Fatalf("ポストモーテムの生成に失敗しました: %v", err)
+	}
+
+	for _, expected := range []string{
+		"# ポストモーテム: APIエラー率上昇",
+		"| 対応時間 | 2時間15分 |",
+		"## 影響範囲\n\n全ユーザー",
+		"DBをフェイルオーバー",
+		`| 2025-01-01 10:05 | タイトルを変更: a\|b |`,
+		"- @tanaka",
+		"## 根本原因",
+		"## アクションアイテム",
+		"| #3 | 閾値を見直す ([リンク](https://example.com/3)) | <@U1> | 2025-01-10 | 未完了 |",
+		"| #4 | 手順書を更新 |  |  | 完了 |",
+	} {
+		if !strings.Contains(content, expected) {
+			t.Errorf("ポストモーテムに %q が含まれていません:\n%s", expected, content)
+		}
+	}
+}
+
+func TestLoadPostmortemTemplateFromFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "postmortem.md.tmpl")
+	if err := os.WriteFile(path, []byte("# {{.Title}} ({{len .Timeline}}件)"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	originalConfig := config
+	config.Postmortem.TemplateFile = path
+	defer func() { config = originalConfig }()
+
+	tmpl, err := loadPostmortemTemplate()
+	if err != nil {
+		t.Fatalf("テンプレートファイルの読み込みに失敗しました: %v", err)
+	}
+
+	content, err := renderPostmortem(tmpl, PostmortemData{Title: "障害"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if content != "# 障害 (0件)" {
+		t.Errorf("独自テンプレートが適用されていません: %s", content)
+	}
+
+	config.Postmortem.TemplateFile = filepath.Join(t.TempDir(), "missing.tmpl")
+	if _, err := loadPostmortemTemplate(); err == nil {
+		t.Error("テンプレートファイルが存在しない場合はエラーになるべきです")
+	}
+}
+
+func TestSplitMentions(t *testing.T) {
+	if got := splitMentions("<@U1>, <@U2>"); !reflect.DeepEqual(got, []string{"<@U1>", "<@U2>"}) {
+		t.Errorf("splitMentions() = %v", got)
+	}
+	if got := splitMentions(""); len(got) != 0 {
+		t.Errorf("空文字列の場合は空であるべきです: %v", got)
+	}
+}
+
+func TestUserNameResolverWithoutAPI(t *testing.T) {
+	resolver := newUserNameResolver(nil)
+	resolver.names["U1"] = "tanaka"
+
+	got := resolver.replaceMentions("<@U1> が担当者を <@U2|suzuki> に設定")
+	if got != "@tanaka が担当者を @U2 に設定" {
+		t.Errorf("replaceMentions() = %q", got)
+	}
+}
+
+func TestE2EPostmortemIncludesFullHistory(t *testing.T) {
+	fake, api := setupE2E(t)
+	fake.addChannel("CINC", "incident-1")
+	incidentID, err := saveIncident("決済APIの障害", "high", "初回の説明", "決済", "CINC", "incident-1", "U001", "報告 太郎", "")
+	if err != nil {
+		t.Fatalf("saveIncident() error = %v", err)
+	}
+
+	// 詳細表示の取得件数（incidentHistoryFetchLimit）を超える更新も、最初の更新からすべてタイムラインに含める
+	previous := "初回の説明"
+	for i := 1; i <= incidentHistoryFetchLimit+10; i++ {
+		next := fmt.Sprintf("説明 %d", i)
+		if err := updateIncident(incidentID, "description", previous, next, "U001", "報告 太郎"); err != nil {
+			t.Fatal(err)
+		}
+		previous = next
+	}
+
+	if err := postPostmortem(api, incidentID); err != nil {
+		t.Fatalf("postPostmortem() error = %v", err)
+	}
+	uploads := fake.callsTo("file_upload")
+	if len(uploads) != 1 {
+		t.Fatalf("アップロード = %+v", uploads)
+	}
+	content := uploads[0].Params.Get("content")
+	if !strings.Contains(content, "`初回の説明` → `説明 1`") || !strings.Contains(content, fmt.Sprintf("説明 %d", incidentHistoryFetchLimit+10)) {
+		t.Errorf("タイムラインに最初と最後の更新が含まれるべきです:\n%s", content)
+	}
+	if got := strings.Count(content, "詳細説明を変更"); got != incidentHistoryFetchLimit+10 {
+		t.Errorf("タイムラインの更新 = %d件", got)
+	}
+}
@@ -0,0 +1,183 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/slack-go/slack"
+)
+
+// データベースへの再接続を試みる間隔（失敗するごとに2倍にし、最大間隔で頭打ちにする）
+const (
+	initialReconnectInterval = 5 * time.Second
+	maxReconnectInterval     = 5 * time.Minute
+)
+
+// databaseHealth はデータベースに接続できているかの状態
+// 起動時に接続できなかった場合は縮退運転とし、インシデントをジャーナルに一時保存する
+type databaseHealth struct {
+	mu       sync.RWMutex
+	degraded bool
+	since    time.Time
+}
+
+var dbHealth = &databaseHealth{}
+
+// markDegraded は縮退運転に切り替える（既に縮退運転中の場合は開始時刻を変更しない）
+func (h *databaseHealth) markDegraded(now time.Time) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if !h.degraded {
+		h.degraded = true
+		h.since = now
+	}
+}
+
+// markHealthy は縮退運転を終了
+func (h *databaseHealth) markHealthy() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.degraded = false
+	h.since = time.Time{}
+}
+
+// status は縮退運転中かどうかと、縮退運転を開始した時刻を取得
+func (h *databaseHealth) status() (degraded bool, since time.Time) {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	return h.degraded, h.since
+}
+
+// isDegraded は縮退運転中かチェック
+func isDegraded() bool {
+	degraded, _ := dbHealth.status()
+	return degraded
+}
+
+// degradedNotice は縮退運転中であることを利用者に知らせるメッセージ（縮退運転中でない場合は空）
+func degradedNotice() string {
+	degraded, since := dbHealth.status()
+	if !degraded {
+		return ""
+	}
+	return fmt.Sprintf("⚠️ *データベースに接続できないため、%s から縮退運転中です*\n"+
+		"新しいインシデントと担当者・ステータスなどの変更はBotに一時保存し、データベースの復旧後に記録した順に自動で登録します。"+
+		"アクションアイテム・検索・統計は復旧までご利用いただけません。",
+		since.Format("2006-01-02 15:04"))
+}
+
+// nextReconnectInterval は再接続に失敗した後の次の待ち時間を計算
+func nextReconnectInterval(current time.Duration) time.Duration {
+	next := current * 2
+	if next > maxReconnectInterval {
+		return maxReconnectInterval
+	}
+	return next
+}
+
+// connectAndMigrate はデータベースに接続し、未適用のマイグレーションを適用（失敗した場合は接続を閉じる）
+func connectAndMigrate() error {
+	if err := initDB(); err != nil {
+		return err
+	}
+	if config.Database.DisableAutoMigrate {
+		return nil
+	}
+
+	migrations, err := migrateUp()
+	if err != nil {
+		db.Close()
+		db = nil
+		return fmt.Errorf("マイグレーションエラー: %v", err)
+	}
+	log.Printf("マイグレーションを %d 件適用しました", len(migrations))
+	return nil
+}
+
+// reconnectDatabase はデータベースに接続できるまで間隔を空けながら再接続を試み、接続できたら縮退運転から復旧する
+func reconnectDatabase(ctx context.Context, api SlackAPI, incidents *outboxStore) {
+	interval := initialReconnectInterval
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(interval):
+		}
+
+		if err := reconnect(); err != nil {
+			interval = nextReconnectInterval(interval)
+			log.Printf("データベースへの再接続に失敗しました（%s後に再試行します）: %v", interval, err)
+			continue
+		}
+
+		log.Println("データベースに再接続しました")
+		if err := recoverFromDegradedMode(api, incidents); err != nil {
+			interval = nextReconnectInterval(interval)
+			log.Printf("縮退運転中の記録の登録に失敗しました（%s後に再試行します）: %v", interval, err)
+			continue
+		}
+		return
+	}
+}
+
+// reconnect はデータベースへの接続を確認する
+// 稼働中に接続できなくなった場合は既存の接続プールが再接続するため、接続を確認するだけにする
+func reconnect() error {
+	if db != nil {
+		return db.Ping()
+	}
+	return connectAndMigrate()
+}
+
+// recoverFromDegradedMode はアウトボックスの操作を記録順に登録してからインシデントの保存先をデータベースに戻し、
+// ジャーナルのインシデントを登録する
+// アウトボックスの登録に失敗した場合は縮退運転を続ける（操作の順序を保つため、保存先を戻さない）
+func recoverFromDegradedMode(api SlackAPI, incidents *outboxStore) error {
+	postgres := newPostgresStore(db)
+
+	replayed, err := replayIncidentOutbox(postgres, incidents.outbox)
+	if err != nil {
+		return err
+	}
+
+	// 登録中に記録された操作も取りこぼさないよう、記録を止めてから残りを登録して保存先を戻す
+	incidents.mu.Lock()
+	remaining, err := replayIncidentOutbox(postgres, incidents.outbox)
+	if err == nil {
+		incidents.primary = postgres
+	}
+	incidents.mu.Unlock()
+	if err != nil {
+		return err
+	}
+	replayed = append(replayed, remaining...)
+
+	// 保存先を切り替えてから登録するため、切り替えの直前にジャーナルに保存されたインシデントも登録される
+	imported, err := backfillIncidentJournal(postgres, incidents.journal)
+	if err != nil {
+		// 登録できなかったものはジャーナルに残り、次回の起動時に登録する
+		log.Printf("ジャーナルのインシデント登録エラー: %v", err)
+	}
+
+	for _, entry := range imported {
+		timekeeperManager.handOver(entry.JournalID, entry.IncidentID)
+		emitIncidentWebhook(webhookEventCreated, entry.IncidentID, "system", nil)
+
+		message := fmt.Sprintf("✅ データベースが復旧したため、このインシデントを ID #%d として登録しました。", entry.IncidentID)
+		if _, _, err := api.PostMessage(entry.ChannelID, slack.MsgOptionText(message, false)); err != nil {
+			log.Printf("復旧の通知エラー (チャンネル: %s): %v", entry.ChannelID, err)
+		}
+	}
+
+	dbHealth.markHealthy()
+	log.Printf("縮退運転から復旧しました（ジャーナルから %d 件のインシデント、アウトボックスから %d 件の操作を登録）", len(imported), len(replayed))
+
+	startBackgroundWork(api)
+	refreshAppHomes(api)
+	return nil
+}
@@ -0,0 +1,55 @@
+package main
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestNextReconnectInterval(t *testing.T) {
+	interval := initialReconnectInterval
+	var got []time.Duration
+	for i := 0; i < 8; i++ {
+		interval = nextReconnectInterval(interval)
+		got = append(got, interval)
+	}
+
+	want := []time.Duration{
+		10 * time.Second, 20 * time.Second, 40 * time.Second, 80 * time.Second,
+		160 * time.Second, maxReconnectInterval, maxReconnectInterval, maxReconnectInterval,
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("%d回目の待ち時間 = %s, want %s", i+1, got[i], want[i])
+		}
+	}
+}
+
+func TestDatabaseHealth(t *testing.T) {
+	original := dbHealth
+	dbHealth = &databaseHealth{}
+	defer func() { dbHealth = original }()
+
+	if isDegraded() || degradedNotice() != "" {
+		t.Error("接続できている場合は縮退運転のお知らせを表示しないべきです")
+	}
+
+	since := time.Date(2025, 1, 1, 10, 30, 0, 0, time.Local)
+	dbHealth.markDegraded(since)
+	// 再接続に失敗しても開始時刻は変わらない
+	dbHealth.markDegraded(since.Add(time.Minute))
+
+	degraded, gotSince := dbHealth.status()
+	if !degraded || !gotSince.Equal(since) {
+		t.Errorf("status() = %v, %s", degraded, gotSince)
+	}
+	notice := degradedNotice()
+	if !strings.Contains(notice, "縮退運転中") || !strings.Contains(notice, "2025-01-01 10:30") {
+		t.Errorf("縮退運転のお知らせ = %q", notice)
+	}
+
+	dbHealth.markHealthy()
+	if isDegraded() || degradedNotice() != "" {
+		t.Error("復旧後は縮退運転のお知らせを表示しないべきです")
+	}
+}
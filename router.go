@@ -0,0 +1,382 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"strings"
+	"unicode"
+
+	"github.com/slack-go/slack"
+)
+
+// CommandContext はコマンド実行時のコンテキスト
+type CommandContext struct {
+	API         SlackAPI
+	ChannelID   string
+	UserID      string
+	UserName    string   // ユーザー名（スラッシュコマンドのみ）
+	TriggerID   string   // モーダルを開くためのトリガーID（スラッシュコマンドのみ）
+	ResponseURL string   // 応答用URL（スラッシュコマンドのみ）
+	Name        string   // 入力されたコマンド名（エイリアスを含む）
+	Args        []string // コマンド名以降の引数
+}
+
+// reply はコマンドの実行結果を応答
+// スラッシュコマンドの場合はresponse_url経由で応答するため、Botが参加していないチャンネルでも利用できる
+func (ctx *CommandContext) reply(message string, ephemeral bool) {
+	var err error
+	switch {
+	case ctx.ResponseURL != "":
+		responseType := slack.ResponseTypeInChannel
+		if ephemeral {
+			responseType = slack.ResponseTypeEphemeral
+		}
+		err = slack.PostWebhook(ctx.ResponseURL, &slack.WebhookMessage{
+			Text:         message,
+			ResponseType: responseType,
+		})
+	case ephemeral && ctx.UserID != "":
+		_, err = ctx.API.PostEphemeral(
+			ctx.ChannelID,
+			ctx.UserID,
+			slack.MsgOptionText(message, false),
+		)
+	default:
+		_, _, err = ctx.API.PostMessage(
+			ctx.ChannelID,
+			slack.MsgOptionText(message, false),
+			slack.MsgOptionBlocks(
+				slack.NewSectionBlock(
+					slack.NewTextBlockObject("mrkdwn", message, false, false),
+					nil, nil,
+				),
+			),
+		)
+	}
+
+	if err != nil {
+		log.Printf("コマンド応答の投稿エラー: %v", err)
+	}
+}
+
+// replyBlocks はBlock Kitのブロックでコマンドの実行結果を応答
+// text はブロックを表示できないクライアント（通知など）向けのテキスト
+func (ctx *CommandContext) replyBlocks(text string, blocks []slack.Block, ephemeral bool) {
+	var err error
+	switch {
+	case ctx.ResponseURL != "":
+		responseType := slack.ResponseTypeInChannel
+		if ephemeral {
+			responseType = slack.ResponseTypeEphemeral
+		}
+		err = slack.PostWebhook(ctx.ResponseURL, &slack.WebhookMessage{
+			Text:         text,
+			Blocks:       &slack.Blocks{BlockSet: blocks},
+			ResponseType: responseType,
+		})
+	case ephemeral && ctx.UserID != "":
+		_, err = ctx.API.PostEphemeral(
+			ctx.ChannelID,
+			ctx.UserID,
+			slack.MsgOptionText(text, false),
+			slack.MsgOptionBlocks(blocks...),
+		)
+	default:
+		_, _, err = ctx.API.PostMessage(
+			ctx.ChannelID,
+			slack.MsgOptionText(text, false),
+			slack.MsgOptionBlocks(blocks...),
+		)
+	}
+
+	if err != nil {
+		log.Printf("コマンド応答の投稿エラー: %v", err)
+	}
+}
+
+// Command はメンションで呼び出せるサブコマンドの定義
+type Command struct {
+	Name        string   // 正式なコマンド名
+	Aliases     []string // 別名（日本語など）
+	Usage       string   // 引数の書式（例: "[id]"）
+	Description string   // ヘルプに表示する説明
+	Handler     func(ctx *CommandContext)
+}
+
+// CommandRouter はコマンド名からハンドラーへの振り分けを行う
+type CommandRouter struct {
+	prefix   string              // ヘルプに表示する呼び出し方（例: "@bot"）
+	commands []*Command          // 登録順（ヘルプの表示順）
+	index    map[string]*Command // コマンド名・エイリアス -> コマンド
+}
+
+// newCommandRouter は空のコマンドルーターを作成
+func newCommandRouter(prefix string) *CommandRouter {
+	return &CommandRouter{
+		prefix: prefix,
+		index:  make(map[string]*Command),
+	}
+}
+
+// register はコマンドを登録（名前・エイリアスが重複している場合はpanic）
+func (r *CommandRouter) register(cmd *Command) {
+	names := append([]string{cmd.Name}, cmd.Aliases...)
+	for _, name := range names {
+		key := strings.ToLower(name)
+		if _, exists := r.index[key]; exists {
+			panic(fmt.Sprintf("コマンド名が重複しています: %s", name))
+		}
+		r.index[key] = cmd
+	}
+	r.commands = append(r.commands, cmd)
+}
+
+// lookup はコマンド名またはエイリアスからコマンドを検索
+func (r *CommandRouter) lookup(name string) (*Command, bool) {
+	cmd, ok := r.index[strings.ToLower(name)]
+	return cmd, ok
+}
+
+// dispatch はトークン列を解析してコマンドを実行
+// コマンドが指定されていない場合（先頭の単語がコマンドでない場合）は false を返す
+// 登録済みのコマンドの打ち間違いと思われる場合は不明なコマンドとして応答し、true を返す
+func (r *CommandRouter) dispatch(ctx *CommandContext, tokens []string) bool {
+	if len(tokens) == 0 {
+		return false
+	}
+
+	ctx.Name = tokens[0]
+	ctx.Args = tokens[1:]
+
+	cmd, ok := r.lookup(ctx.Name)
+	if !ok {
+		suggestion, ok := r.suggest(ctx.Name)
+		if !ok {
+			return false
+		}
+		log.Printf("不明なコマンドです: %s (候補: %s)", ctx.Name, suggestion)
+		ctx.reply(r.unknownCommandText(ctx.Name, suggestion), true)
+		return true
+	}
+
+	log.Printf("コマンド %s を実行します (引数: %v)", cmd.Name, ctx.Args)
+	cmd.Handler(ctx)
+	return true
+}
+
+// suggest は打ち間違いと思われるコマンド名に最も近い登録済みのコマンド名・エイリアスを返す
+// 短い名前ほど許容する編集距離を小さくし、通常の文章の単語をコマンドと誤認しないようにする
+func (r *CommandRouter) suggest(name string) (string, bool) {
+	typed := strings.ToLower(name)
+
+	best, bestDistance := "", -1
+	for key := range r.index {
+		distance := editDistance(typed, key)
+		if distance == 0 || distance > commandTypoTolerance(min(len([]rune(typed)), len([]rune(key)))) {
+			continue
+		}
+		if bestDistance < 0 || distance < bestDistance || (distance == bestDistance && key < best) {
+			best, bestDistance = key, distance
+		}
+	}
+	return best, bestDistance > 0
+}
+
+// commandTypoTolerance は名前の長さ（入力とコマンド名の短い方）に応じて打ち間違いとみなす編集距離の上限を返す
+func commandTypoTolerance(length int) int {
+	switch {
+	case length <= 3:
+		return 0
+	case length <= 5:
+		return 1
+	default:
+		return 2
+	}
+}
+
+// editDistance は2つの文字列の編集距離（隣接する文字の入れ替えも1回の操作とみなす）を返す
+func editDistance(a, b string) int {
+	s, t := []rune(a), []rune(b)
+
+	// prev2・prev・curr はそれぞれ2つ前・1つ前・現在の行
+	prev2 := make([]int, len(t)+1)
+	prev := make([]int, len(t)+1)
+	curr := make([]int, len(t)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+
+	for i := 1; i <= len(s); i++ {
+		curr[0] = i
+		for j := 1; j <= len(t); j++ {
+			cost := 1
+			if s[i-1] == t[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+			if i > 1 && j > 1 && s[i-1] == t[j-2] && s[i-2] == t[j-1] {
+				curr[j] = min(curr[j], prev2[j-2]+1)
+			}
+		}
+		prev2, prev, curr = prev, curr, prev2
+	}
+	return prev[len(t)]
+}
+
+// helpText は登録済みコマンドのヘルプ文字列を生成
+func (r *CommandRouter) helpText() string {
+	var sb strings.Builder
+	for _, cmd := range r.commands {
+		sb.WriteString("• `" + r.prefix + " " + cmd.Name)
+		if cmd.Usage != "" {
+			sb.WriteString(" " + cmd.Usage)
+		}
+		sb.WriteString("`")
+		for _, alias := range cmd.Aliases {
+			sb.WriteString(" または `" + r.prefix + " " + alias + "`")
+		}
+		sb.WriteString("\n  " + cmd.Description + "\n\n")
+	}
+	return sb.String()
+}
+
+// tokenizeCommand はメンション本文をトークンに分割
+// 先頭のメンション（<@U...>）は取り除き、ダブルクォートで囲まれた部分は1トークンとして扱う
+func tokenizeCommand(text string) []string {
+	var tokens []string
+	var current strings.Builder
+	inQuote := false
+	hasToken := false
+
+	flush := func() {
+		if hasToken {
+			tokens = append(tokens, current.String())
+		}
+		current.Reset()
+		hasToken = false
+	}
+
+	for _, r := range text {
+		switch {
+		case r == '"' || r == '“' || r == '”':
+			inQuote = !inQuote
+			hasToken = true
+		case unicode.IsSpace(r) && !inQuote: // 全角スペースも区切りとして扱う
+			flush()
+		default:
+			current.WriteRune(r)
+			hasToken = true
+		}
+	}
+	flush()
+
+	// 先頭のメンションを除去
+	for len(tokens) > 0 && strings.HasPrefix(tokens[0], "<@") && strings.HasSuffix(tokens[0], ">") {
+		tokens = tokens[1:]
+	}
+
+	return tokens
+}
+
+// unknownCommandText は不明なコマンドへの応答メッセージを生成
+func (r *CommandRouter) unknownCommandText(name, suggestion string) string {
+	return fmt.Sprintf("❓ 不明なコマンドです: `%s`（`%s %s` のことですか？）\n`%s help` で利用可能なコマンドを確認できます。", name, r.prefix, suggestion, r.prefix)
+}
+
+// mentionRouter はメンションコマンドのルーター
+var mentionRouter = newCommandRouter("@bot")
+
+func init() {
+	mentionRouter.register(&Command{
+		Name:        "help",
+		Aliases:     []string{"ヘルプ"},
+		Description: "このヘルプメッセージを表示",
+		Handler: func(ctx *CommandContext) {
+			showHelp(ctx.API, ctx.ChannelID)
+		},
+	})
+	mentionRouter.register(&Command{
+		Name:        "report",
+		Aliases:     []string{"報告"},
+		Description: "インシデント報告ボタンを表示",
+		Handler: func(ctx *CommandContext) {
+			postReportButton(ctx.API, ctx.ChannelID)
+		},
+	})
+	mentionRouter.register(&Command{
+		Name:        "handler",
+		Aliases:     []string{"ハンドラー", "担当"},
+		Description: "このチャンネルのインシデントハンドラーを確認",
+		Handler: func(ctx *CommandContext) {
+			showHandler(ctx.API, ctx.ChannelID)
+		},
+	})
+	mentionRouter.register(&Command{
+		Name:        "status",
+		Aliases:     []string{"状況", "詳細"},
+		Usage:       "[id]",
+		Description: "インシデントの詳細と変更履歴を表示（IDを省略するとこのチャンネルのインシデント）",
+		Handler: func(ctx *CommandContext) {
+			showIncidentDetail(ctx, false)
+		},
+	})
+	mentionRouter.register(&Command{
+		Name:        "alerts",
+		Aliases:     []string{"アラート"},
+		Usage:       "[id]",
+		Description: "インシデントにまとめたアラートと発生中・解消済みの状態を表示（IDを省略するとこのチャンネルのインシデント）",
+		Handler: func(ctx *CommandContext) {
+			showIncidentAlerts(ctx, false)
+		},
+	})
+	mentionRouter.register(&Command{
+		Name:        "webhooks",
+		Aliases:     []string{"配信"},
+		Usage:       "[id] | redeliver <配信ID>",
+		Description: "Webhook の配信記録を表示（`redeliver <配信ID>` で再送、IDを省略するとこのチャンネルのインシデント）",
+		Handler: func(ctx *CommandContext) {
+			handleWebhooksCommand(ctx, false)
+		},
+	})
+	mentionRouter.register(&Command{
+		Name:        "search",
+		Aliases:     []string{"検索"},
+		Usage:       "<キーワード>",
+		Description: "過去のインシデントをタイトル・詳細・影響範囲・復旧メモから検索",
+		Handler: func(ctx *CommandContext) {
+			showIncidentSearch(ctx, false)
+		},
+	})
+	mentionRouter.register(&Command{
+		Name:        "stats",
+		Aliases:     []string{"統計"},
+		Usage:       "[期間]",
+		Description: "インシデント件数・MTTA・MTTRを重要度別に表示（期間の例: `7d`、`30d`、デフォルトは7日間で前週比を表示）",
+		Handler: func(ctx *CommandContext) {
+			showIncidentStats(ctx, false)
+		},
+	})
+	mentionRouter.register(&Command{
+		Name:        "postmortem",
+		Aliases:     []string{"ポストモーテム"},
+		Usage:       "[id]",
+		Description: "ポストモーテムの下書き（Markdown）を生成してインシデントチャンネルに投稿",
+		Handler:     showPostmortem,
+	})
+	mentionRouter.register(&Command{
+		Name:        "action",
+		Aliases:     []string{"アクション"},
+		Usage:       "<add|list|done> ...",
+		Description: "再発防止策などのアクションアイテムを担当者・期限付きで管理（`@bot action help` で詳細）",
+		Handler:     handleActionCommand,
+	})
+	mentionRouter.register(&Command{
+		Name:        "list",
+		Aliases:     []string{"一覧", "リスト"},
+		Usage:       "[条件...]",
+		Description: "インシデント一覧を表示（例: `severity:critical handler:@me since:7d`）",
+		Handler: func(ctx *CommandContext) {
+			showIncidentList(ctx, false)
+		},
+	})
+}
@@ -0,0 +1,145 @@
+package main
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestTokenizeCommand(t *testing.T) {
+	tests := []struct {
+		name     string
+		text     string
+		expected []string
+	}{
+		{"メンションのみ", "<@U123>", nil},
+		{"コマンドのみ", "<@U123> list", []string{"list"}},
+		{"引数付き", "<@U123> status 42", []string{"status", "42"}},
+		{"連続した空白", "<@U123>   list    severity:high", []string{"list", "severity:high"}},
+		{"全角スペース", "<@U123>　一覧　重要度", []string{"一覧", "重要度"}},
+		{"ダブルクォート", `<@U123> search "api timeout" db`, []string{"search", "api timeout", "db"}},
+		{"空のクォート", `<@U123> search ""`, []string{"search", ""}},
+		{"引数中のメンションは残す", "<@U123> list reporter:<@U456>", []string{"list", "reporter:<@U456>"}},
+		{"メンションなし", "help", []string{"help"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tokens := tokenizeCommand(tt.text)
+			if len(tokens) == 0 && len(tt.expected) == 0 {
+				return
+			}
+			if !reflect.DeepEqual(tokens, tt.expected) {
+				t.Errorf("トークンが間違っています: %q, 期待値: %q", tokens, tt.expected)
+			}
+		})
+	}
+}
+
+func TestCommandRouterDispatch(t *testing.T) {
+	router := newCommandRouter("@bot")
+
+	var called string
+	var args []string
+	router.register(&Command{
+		Name:    "status",
+		Aliases: []string{"状況"},
+		Handler: func(ctx *CommandContext) {
+			called = ctx.Name
+			args = ctx.Args
+		},
+	})
+
+	// コマンドなしの場合は処理されない
+	if router.dispatch(&CommandContext{}, nil) {
+		t.Error("コマンドなしでdispatchがtrueを返しました")
+	}
+
+	// エイリアスで呼び出せることを確認
+	if !router.dispatch(&CommandContext{}, []string{"状況", "42"}) {
+		t.Fatal("dispatchがfalseを返しました")
+	}
+	if called != "状況" {
+		t.Errorf("入力されたコマンド名が間違っています: %s", called)
+	}
+	if !reflect.DeepEqual(args, []string{"42"}) {
+		t.Errorf("引数が間違っています: %v", args)
+	}
+}
+
+func TestCommandRouterDuplicateRegistration(t *testing.T) {
+	router := newCommandRouter("@bot")
+	router.register(&Command{Name: "list", Handler: func(*CommandContext) {}})
+
+	defer func() {
+		if recover() == nil {
+			t.Error("重複登録でpanicが発生しませんでした")
+		}
+	}()
+	router.register(&Command{Name: "other", Aliases: []string{"LIST"}, Handler: func(*CommandContext) {}})
+}
+
+func TestMentionRouterHelpText(t *testing.T) {
+	help := mentionRouter.helpText()
+
+	// 登録済みのコマンドとエイリアスがすべてヘルプに含まれることを確認
+	for _, cmd := range mentionRouter.commands {
+		if !strings.Contains(help, "`@bot "+cmd.Name) {
+			t.Errorf("ヘルプにコマンド %s が含まれていません", cmd.Name)
+		}
+		for _, alias := range cmd.Aliases {
+			if !strings.Contains(help, "`@bot "+alias+"`") {
+				t.Errorf("ヘルプにエイリアス %s が含まれていません", alias)
+			}
+		}
+		if !strings.Contains(help, cmd.Description) {
+			t.Errorf("ヘルプにコマンド %s の説明が含まれていません", cmd.Name)
+		}
+	}
+}
+
+func TestCommandRouterSuggest(t *testing.T) {
+	tests := []struct {
+		name     string
+		expected string // 空文字列は打ち間違いとみなさないことを表す
+	}{
+		{"satus", "status"},
+		{"STSTUS", "status"},
+		{"statz", "stats"},
+		{"hepl", "help"},
+		{"lisst", "list"},
+		{"hello", ""},
+		{"blacklist", ""},
+		{"lst", ""},
+		{"担当者", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			suggestion, _ := mentionRouter.suggest(tt.name)
+			if suggestion != tt.expected {
+				t.Errorf("suggest(%q) = %q, 期待値: %q", tt.name, suggestion, tt.expected)
+			}
+		})
+	}
+}
+
+func TestEditDistance(t *testing.T) {
+	tests := []struct {
+		a, b     string
+		expected int
+	}{
+		{"status", "status", 0},
+		{"stauts", "status", 1},
+		{"lisst", "list", 1},
+		{"hello", "help", 2},
+		{"", "list", 4},
+		{"一覧", "一欄", 1},
+	}
+
+	for _, tt := range tests {
+		if got := editDistance(tt.a, tt.b); got != tt.expected {
+			t.Errorf("editDistance(%q, %q) = %d, 期待値: %d", tt.a, tt.b, got, tt.expected)
+		}
+	}
+}
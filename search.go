@@ -0,0 +1,69 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"strings"
+	"time"
+)
+
+// searchResultLimit は検索結果の最大表示件数
+const searchResultLimit = 10
+
+// showIncidentSearch はキーワードに一致する過去のインシデントを表示
+func showIncidentSearch(ctx *CommandContext, ephemeral bool) {
+	if len(ctx.Args) == 0 {
+		ctx.reply("❌ 検索キーワードを指定してください（例: `search DB 接続エラー`）", true)
+		return
+	}
+
+	if store == nil {
+		ctx.reply("⚠️ データベース機能が無効のため、インシデントを検索できません。", true)
+		return
+	}
+
+	incidents, err := searchIncidentsByText(ctx.Args, searchResultLimit)
+	if err != nil {
+		log.Printf("インシデント検索エラー: %v", err)
+		ctx.reply(fmt.Sprintf("❌ インシデントの検索に失敗しました: %v", err), true)
+		return
+	}
+
+	ctx.reply(buildSearchResultMessage(ctx.Args, incidents, time.Now()), ephemeral)
+	log.Printf("インシデントを検索しました: %q (%d件)", strings.Join(ctx.Args, " "), len(incidents))
+}
+
+// buildSearchResultMessage は検索結果のメッセージを生成
+func buildSearchResultMessage(terms []string, incidents []Incident, now time.Time) string {
+	keywords := strings.Join(terms, " ")
+	if len(incidents) == 0 {
+		return fmt.Sprintf("🔍 「%s」に一致する過去のインシデントは見つかりませんでした。", keywords)
+	}
+
+	var lines []string
+	for _, incident := range incidents {
+		duration := fmt.Sprintf("経過: %s", formatElapsed(now.Sub(incident.CreatedAt)))
+		if incident.ResolvedAt != nil {
+			duration = fmt.Sprintf("対応時間: %s", formatElapsed(incident.ResolvedAt.Sub(incident.CreatedAt)))
+		}
+
+		lines = append(lines, fmt.Sprintf(
+			"%s *#%d* <#%s> %s\n  %s | 報告: %s | %s",
+			severityEmojis[incident.Severity],
+			incident.ID,
+			incident.ChannelID,
+			truncateRunes(incident.Title, 80),
+			statusLabel(incident.Status),
+			incident.CreatedAt.Format("2006-01-02"),
+			duration,
+		))
+	}
+
+	message := fmt.Sprintf("🔎 *「%s」の検索結果* (%d件)\n\n%s", keywords, len(incidents), strings.Join(lines, "\n\n"))
+	if len(incidents) >= searchResultLimit {
+		message += fmt.Sprintf("\n\n_関連度の高い上位%d件を表示しています。キーワードを追加すると絞り込めます。_", searchResultLimit)
+	}
+	message += "\n\n💡 詳細は `status <id>` で確認できます。"
+
+	return message
+}
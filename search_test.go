@@ -0,0 +1,83 @@
+package main
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestBuildSearchResultMessage(t *testing.T) {
+	now := time.Date(2025, 1, 10, 12, 0, 0, 0, time.UTC)
+	resolvedAt := time.Date(2025, 1, 1, 11, 30, 0, 0, time.UTC)
+	incidents := []Incident{
+		{
+			ID:         3,
+			Title:      "DB接続エラー",
+			Severity:   "critical",
+			Status:     StatusClosed,
+			ChannelID:  "C3",
+			CreatedAt:  time.Date(2025, 1, 1, 10, 0, 0, 0, time.UTC),
+			ResolvedAt: &resolvedAt,
+		},
+		{
+			ID:        7,
+			Title:     "DBレプリカ遅延",
+			Severity:  "medium",
+			Status:    StatusMonitoring,
+			ChannelID: "C7",
+			CreatedAt: time.Date(2025, 1, 10, 11, 15, 0, 0, time.UTC),
+		},
+	}
+
+	message := buildSearchResultMessage([]string{"DB"}, incidents, now)
+
+	for _, expected := range []string{
+		"「DB」の検索結果* (2件)",
+		"🔴 *#3* <#C3> DB接続エラー",
+		"対応時間: 1時間30分",
+		"🟡 *#7* <#C7> DBレプリカ遅延",
+		"経過: 45分",
+		statusLabel(StatusMonitoring),
+	} {
+		if !strings.Contains(message, expected) {
+			t.Errorf("検索結果に %q が含まれていません:\n%s", expected, message)
+		}
+	}
+	if strings.Contains(message, "上位") {
+		t.Error("上限未満の場合は件数制限の注記は不要です")
+	}
+}
+
+func TestBuildSearchResultMessageEmpty(t *testing.T) {
+	message := buildSearchResultMessage([]string{"存在しない", "障害"}, nil, time.Now())
+	if !strings.Contains(message, "「存在しない 障害」に一致する過去のインシデントは見つかりませんでした") {
+		t.Errorf("0件の場合のメッセージが間違っています: %s", message)
+	}
+}
+
+func TestBuildLikePatterns(t *testing.T) {
+	patterns := buildLikePatterns([]string{"DB", "100%", "a_b", `c\d`})
+	expected := []string{"%DB%", `%100\%%`, `%a\_b%`, `%c\\d%`}
+
+	for i, pattern := range patterns {
+		if pattern != expected[i] {
+			t.Errorf("buildLikePatterns[%d] = %q, want %q", i, pattern, expected[i])
+		}
+	}
+}
+
+func TestE2ESearchWithMemoryStore(t *testing.T) {
+	fake, api := setupE2E(t)
+	if _, err := saveIncident("決済DBの接続エラー", "critical", "コネクションプールが枯渇", "決済", "CINC1", "incident-1", "U001", "報告 太郎", ""); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := saveIncident("ログイン不可", "high", "認証APIのタイムアウト", "全ユーザー", "CINC2", "incident-2", "U001", "報告 太郎", ""); err != nil {
+		t.Fatal(err)
+	}
+
+	handleEventsAPIEvent(api, mentionEvent("CGENERAL", "U001", "<@UBOT> search db 枯渇"))
+
+	if !fake.hasMessage("CGENERAL", "決済DBの接続エラー") || !fake.hasMessage("CGENERAL", "(1件)") {
+		t.Errorf("検索結果 = %+v", fake.messagesTo("CGENERAL"))
+	}
+}
@@ -0,0 +1,93 @@
+package main
+
+import (
+	"encoding/json"
+	"log"
+
+	"github.com/slack-go/slack"
+)
+
+// reportMessageShortcutID はメッセージショートカット「インシデントとして報告」のコールバックID
+const reportMessageShortcutID = "report_as_incident"
+
+// maxPrefillDescriptionLength はモーダルの詳細説明に事前入力する最大文字数
+const maxPrefillDescriptionLength = 3000
+
+// incidentReportMetadata はインシデント報告モーダルのPrivateMetadataに保存する報告元情報
+type incidentReportMetadata struct {
+	ChannelID string `json:"channel_id"`
+	ThreadTS  string `json:"thread_ts,omitempty"` // 報告元メッセージのスレッド
+	Permalink string `json:"permalink,omitempty"` // 報告元メッセージのリンク
+}
+
+// parseIncidentReportMetadata はPrivateMetadataから報告元情報を取得
+// 従来のチャンネルIDのみの形式にも対応する
+func parseIncidentReportMetadata(privateMetadata string) incidentReportMetadata {
+	var metadata incidentReportMetadata
+	if err := json.Unmarshal([]byte(privateMetadata), &metadata); err != nil {
+		return incidentReportMetadata{ChannelID: privateMetadata}
+	}
+	return metadata
+}
+
+// createIncidentModalFromMessage はメッセージ本文を詳細説明に事前入力したインシデント報告モーダルを作成
+func createIncidentModalFromMessage(metadata incidentReportMetadata, messageText string) slack.ModalViewRequest {
+	modal := createIncidentModal(metadata.ChannelID)
+
+	// 詳細説明にメッセージ本文を事前入力
+	for _, block := range modal.Blocks.BlockSet {
+		inputBlock, ok := block.(*slack.InputBlock)
+		if !ok || inputBlock.BlockID != "description_block" {
+			continue
+		}
+		if input, ok := inputBlock.Element.(*slack.PlainTextInputBlockElement); ok {
+			input.InitialValue = truncateRunes(messageText, maxPrefillDescriptionLength)
+		}
+	}
+
+	metadataJSON, err := json.Marshal(metadata)
+	if err != nil {
+		log.Printf("報告元情報のエンコードエラー: %v", err)
+		return modal
+	}
+	modal.PrivateMetadata = string(metadataJSON)
+
+	return modal
+}
+
+// handleReportMessageShortcut はメッセージショートカット「インシデントとして報告」の処理
+func handleReportMessageShortcut(api SlackAPI, callback slack.InteractionCallback) {
+	log.Printf("メッセージショートカットを受信しました: チャンネル=%s, ts=%s", callback.Channel.ID, callback.Message.Timestamp)
+
+	// 報告元メッセージがスレッド内の場合はそのスレッドに返信する
+	threadTS := callback.Message.ThreadTimestamp
+	if threadTS == "" {
+		threadTS = callback.Message.Timestamp
+	}
+
+	metadata := incidentReportMetadata{
+		ChannelID: callback.Channel.ID,
+		ThreadTS:  threadTS,
+	}
+
+	// 報告元メッセージのリンクを取得
+	permalink, err := api.GetPermalink(&slack.PermalinkParameters{
+		Channel: callback.Channel.ID,
+		Ts:      callback.Message.Timestamp,
+	})
+	if err != nil {
+		log.Printf("メッセージリンク取得エラー: %v", err)
+	} else {
+		metadata.Permalink = permalink
+	}
+
+	modalView := createIncidentModalFromMessage(metadata, callback.Message.Text)
+
+	_, err = api.OpenView(callback.TriggerID, modalView)
+	if err != nil {
+		log.Printf("モーダル表示エラー: %v", err)
+		return
+	}
+
+	log.Println("メッセージからインシデント報告モーダルを表示しました")
+}
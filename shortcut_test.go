@@ -0,0 +1,85 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/slack-go/slack"
+)
+
+func TestParseIncidentReportMetadata(t *testing.T) {
+	tests := []struct {
+		name     string
+		metadata string
+		expected incidentReportMetadata
+	}{
+		{"従来のチャンネルID形式", "C12345", incidentReportMetadata{ChannelID: "C12345"}},
+		{"空文字列", "", incidentReportMetadata{}},
+		{
+			"JSON形式",
+			`{"channel_id":"C12345","thread_ts":"1700000000.000100","permalink":"https://example.slack.com/archives/C12345/p1700000000000100"}`,
+			incidentReportMetadata{
+				ChannelID: "C12345",
+				ThreadTS:  "1700000000.000100",
+				Permalink: "https://example.slack.com/archives/C12345/p1700000000000100",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			actual := parseIncidentReportMetadata(tt.metadata)
+			if actual != tt.expected {
+				t.Errorf("報告元情報が間違っています: %+v, 期待値: %+v", actual, tt.expected)
+			}
+		})
+	}
+}
+
+func TestCreateIncidentModalFromMessage(t *testing.T) {
+	metadata := incidentReportMetadata{
+		ChannelID: "C12345",
+		ThreadTS:  "1700000000.000100",
+		Permalink: "https://example.slack.com/archives/C12345/p1700000000000100",
+	}
+	modal := createIncidentModalFromMessage(metadata, "APIが500エラーを返しています")
+
+	if modal.CallbackID != "incident_report_modal" {
+		t.Errorf("CallbackIDが間違っています: %s", modal.CallbackID)
+	}
+
+	// PrivateMetadataから報告元情報を復元できることを確認
+	if parsed := parseIncidentReportMetadata(modal.PrivateMetadata); parsed != metadata {
+		t.Errorf("PrivateMetadataが間違っています: %+v, 期待値: %+v", parsed, metadata)
+	}
+
+	// 詳細説明にメッセージ本文が事前入力されていることを確認
+	descriptionBlock, ok := modal.Blocks.BlockSet[2].(*slack.InputBlock)
+	if !ok {
+		t.Fatal("詳細説明ブロックがInputBlockではありません")
+	}
+	input, ok := descriptionBlock.Element.(*slack.PlainTextInputBlockElement)
+	if !ok {
+		t.Fatal("詳細説明ブロックのElementがPlainTextInputBlockElementではありません")
+	}
+	if input.InitialValue != "APIが500エラーを返しています" {
+		t.Errorf("詳細説明の初期値が間違っています: %s", input.InitialValue)
+	}
+
+	// 元のモーダルには影響しないことを確認
+	plain := createIncidentModal("C12345")
+	plainDescription := plain.Blocks.BlockSet[2].(*slack.InputBlock).Element.(*slack.PlainTextInputBlockElement)
+	if plainDescription.InitialValue != "" {
+		t.Errorf("通常のモーダルに初期値が設定されています: %s", plainDescription.InitialValue)
+	}
+}
+
+func TestCreateIncidentModalFromLongMessage(t *testing.T) {
+	longText := strings.Repeat("あ", maxPrefillDescriptionLength+100)
+	modal := createIncidentModalFromMessage(incidentReportMetadata{ChannelID: "C12345"}, longText)
+
+	input := modal.Blocks.BlockSet[2].(*slack.InputBlock).Element.(*slack.PlainTextInputBlockElement)
+	if len([]rune(input.InitialValue)) != maxPrefillDescriptionLength {
+		t.Errorf("詳細説明の初期値の文字数が間違っています: %d, 期待値: %d", len([]rune(input.InitialValue)), maxPrefillDescriptionLength)
+	}
+}
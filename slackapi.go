@@ -0,0 +1,33 @@
+package main
+
+import "github.com/slack-go/slack"
+
+// SlackAPI はハンドラーが使用する Slack Web API のメソッド
+// 本番では *slack.Client を渡し、テストでは slack.OptionAPIURL で偽の Slack サーバーに向けたクライアントを渡す
+type SlackAPI interface {
+	// メッセージ
+	PostMessage(channelID string, options ...slack.MsgOption) (string, string, error)
+	PostEphemeral(channelID, userID string, options ...slack.MsgOption) (string, error)
+	UpdateMessage(channelID, timestamp string, options ...slack.MsgOption) (string, string, string, error)
+	GetPermalink(params *slack.PermalinkParameters) (string, error)
+	AddPin(channel string, item slack.ItemRef) error
+	RemovePin(channel string, item slack.ItemRef) error
+	UploadFileV2(params slack.UploadFileV2Parameters) (*slack.FileSummary, error)
+
+	// チャンネル
+	CreateConversation(params slack.CreateConversationParams) (*slack.Channel, error)
+	GetConversationInfo(input *slack.GetConversationInfoInput) (*slack.Channel, error)
+	GetConversationHistory(params *slack.GetConversationHistoryParameters) (*slack.GetConversationHistoryResponse, error)
+	InviteUsersToConversation(channelID string, users ...string) (*slack.Channel, error)
+	SetTopicOfConversation(channelID, topic string) (*slack.Channel, error)
+
+	// モーダル・App Home
+	OpenView(triggerID string, view slack.ModalViewRequest) (*slack.ViewResponse, error)
+	PublishView(userID string, view slack.HomeTabViewRequest, hash string) (*slack.ViewResponse, error)
+
+	// ユーザー
+	GetUserInfo(user string) (*slack.User, error)
+}
+
+// *slack.Client が SlackAPI を満たしていることをコンパイル時に確認
+var _ SlackAPI = (*slack.Client)(nil)
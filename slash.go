@@ -0,0 +1,182 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/slack-go/slack"
+)
+
+// slashRouter は /incident スラッシュコマンドのルーター
+var slashRouter = newCommandRouter("/incident")
+
+// handleSlashCommand は /incident スラッシュコマンド受信時の処理
+func handleSlashCommand(api SlackAPI, cmd slack.SlashCommand) {
+	log.Printf("スラッシュコマンドを受信しました: %s %s (チャンネル: %s)", cmd.Command, cmd.Text, cmd.ChannelID)
+
+	ctx := &CommandContext{
+		API:         api,
+		ChannelID:   cmd.ChannelID,
+		UserID:      cmd.UserID,
+		UserName:    cmd.UserName,
+		TriggerID:   cmd.TriggerID,
+		ResponseURL: cmd.ResponseURL,
+	}
+
+	// サブコマンドなしの場合はヘルプを表示
+	if !slashRouter.dispatch(ctx, tokenizeCommand(cmd.Text)) {
+		ctx.reply(buildSlashHelpMessage(), true)
+	}
+}
+
+// buildSlashHelpMessage はスラッシュコマンドのヘルプメッセージを生成
+func buildSlashHelpMessage() string {
+	return "📚 *`/incident` コマンド - ヘルプ*\n\n" +
+		"Botが参加していないチャンネルからも利用できます。\n\n" +
+		slashRouter.helpText()
+}
+
+// resolveTargetIncident は引数またはチャンネルから対象のインシデントIDを決定
+func resolveTargetIncident(ctx *CommandContext) (int64, error) {
+	if len(ctx.Args) > 0 {
+		return parseIncidentID(ctx.Args[0])
+	}
+
+	incidentID, _, err := getIncidentByChannelID(ctx.ChannelID)
+	if err != nil {
+		return 0, fmt.Errorf("このチャンネルにはオープンなインシデントがありません。インシデントIDを指定してください")
+	}
+	return incidentID, nil
+}
+
+// resolveTargetIncidentWithText は先頭の引数がインシデントIDであれば対象とし、残りの引数を本文として返す
+// 先頭がIDでない場合はチャンネルのインシデントを対象とし、すべての引数を本文とする
+func resolveTargetIncidentWithText(ctx *CommandContext) (int64, string, error) {
+	if len(ctx.Args) > 0 {
+		if incidentID, err := parseIncidentID(ctx.Args[0]); err == nil {
+			return incidentID, strings.Join(ctx.Args[1:], " "), nil
+		}
+	}
+
+	incidentID, _, err := getIncidentByChannelID(ctx.ChannelID)
+	if err != nil {
+		return 0, "", fmt.Errorf("このチャンネルにはオープンなインシデントがありません。インシデントIDを指定してください")
+	}
+	return incidentID, strings.Join(ctx.Args, " "), nil
+}
+
+// slashNew はインシデント報告モーダルを開く
+func slashNew(ctx *CommandContext) {
+	modalView := createIncidentModal(ctx.ChannelID)
+
+	_, err := ctx.API.OpenView(ctx.TriggerID, modalView)
+	if err != nil {
+		log.Printf("モーダル表示エラー: %v", err)
+		ctx.reply(fmt.Sprintf("❌ インシデント報告モーダルを開けませんでした: %v", err), true)
+		return
+	}
+
+	log.Println("スラッシュコマンドからインシデント報告モーダルを表示しました")
+}
+
+// slashResolve はインシデントを復旧済みにする
+func slashResolve(ctx *CommandContext) {
+	incidentID, resolutionNote, err := resolveTargetIncidentWithText(ctx)
+	if err != nil {
+		ctx.reply(fmt.Sprintf("❌ %v", err), true)
+		return
+	}
+
+	if err := resolveAndAnnounce(ctx.API, incidentID, ctx.UserID, ctx.UserName, resolutionNote); err != nil {
+		ctx.reply(fmt.Sprintf("❌ %v", err), true)
+		return
+	}
+
+	ctx.reply(fmt.Sprintf("✅ インシデント #%d を復旧済みにしました", incidentID), true)
+}
+
+func init() {
+	slashRouter.register(&Command{
+		Name:        "new",
+		Aliases:     []string{"報告", "新規"},
+		Description: "インシデント報告モーダルを開く",
+		Handler:     slashNew,
+	})
+	slashRouter.register(&Command{
+		Name:        "list",
+		Aliases:     []string{"一覧"},
+		Usage:       "[条件...]",
+		Description: "インシデント一覧を表示（自分にだけ表示、例: `severity:critical status:resolved`）",
+		Handler: func(ctx *CommandContext) {
+			showIncidentList(ctx, true)
+		},
+	})
+	slashRouter.register(&Command{
+		Name:        "search",
+		Aliases:     []string{"検索"},
+		Usage:       "<キーワード>",
+		Description: "過去のインシデントを検索（自分にだけ表示）",
+		Handler: func(ctx *CommandContext) {
+			showIncidentSearch(ctx, true)
+		},
+	})
+	slashRouter.register(&Command{
+		Name:        "stats",
+		Aliases:     []string{"統計"},
+		Usage:       "[期間]",
+		Description: "インシデント件数・MTTA・MTTRを重要度別に表示（自分にだけ表示）",
+		Handler: func(ctx *CommandContext) {
+			showIncidentStats(ctx, true)
+		},
+	})
+	slashRouter.register(&Command{
+		Name:        "status",
+		Aliases:     []string{"状況"},
+		Usage:       "[id]",
+		Description: "インシデントの詳細と変更履歴を表示（IDを省略するとこのチャンネルのインシデント）",
+		Handler: func(ctx *CommandContext) {
+			showIncidentDetail(ctx, true)
+		},
+	})
+	slashRouter.register(&Command{
+		Name:        "alerts",
+		Aliases:     []string{"アラート"},
+		Usage:       "[id]",
+		Description: "インシデントにまとめたアラートと発生中・解消済みの状態を表示（自分にだけ表示）",
+		Handler: func(ctx *CommandContext) {
+			showIncidentAlerts(ctx, true)
+		},
+	})
+	slashRouter.register(&Command{
+		Name:        "webhooks",
+		Aliases:     []string{"配信"},
+		Usage:       "[id] | redeliver <配信ID>",
+		Description: "Webhook の配信記録を表示（`redeliver <配信ID>` で再送、自分にだけ表示）",
+		Handler: func(ctx *CommandContext) {
+			handleWebhooksCommand(ctx, true)
+		},
+	})
+	slashRouter.register(&Command{
+		Name:        "resolve",
+		Aliases:     []string{"復旧"},
+		Usage:       "[id] [復旧メモ]",
+		Description: "インシデントを復旧済みにする（IDを省略するとこのチャンネルのインシデント、復旧メモは検索対象になります）",
+		Handler:     slashResolve,
+	})
+	slashRouter.register(&Command{
+		Name:        "postmortem",
+		Aliases:     []string{"ポストモーテム"},
+		Usage:       "[id]",
+		Description: "ポストモーテムの下書き（Markdown）を生成してインシデントチャンネルに投稿",
+		Handler:     showPostmortem,
+	})
+	slashRouter.register(&Command{
+		Name:        "help",
+		Aliases:     []string{"ヘルプ"},
+		Description: "このヘルプメッセージを表示",
+		Handler: func(ctx *CommandContext) {
+			ctx.reply(buildSlashHelpMessage(), true)
+		},
+	})
+}
@@ -0,0 +1,100 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSlashRouterCommands(t *testing.T) {
+	// スラッシュコマンドのサブコマンドとエイリアスが登録されていることを確認
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"new", "new"},
+		{"報告", "new"},
+		{"list", "list"},
+		{"一覧", "list"},
+		{"status", "status"},
+		{"状況", "status"},
+		{"resolve", "resolve"},
+		{"search", "search"},
+		{"検索", "search"},
+		{"復旧", "resolve"},
+		{"help", "help"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			cmd, ok := slashRouter.lookup(tt.input)
+			if !ok {
+				t.Fatalf("サブコマンド %s が登録されていません", tt.input)
+			}
+			if cmd.Name != tt.expected {
+				t.Errorf("サブコマンド名が間違っています: %s, 期待値: %s", cmd.Name, tt.expected)
+			}
+		})
+	}
+}
+
+func TestSlashHelpMessage(t *testing.T) {
+	help := buildSlashHelpMessage()
+
+	for _, expected := range []string{"`/incident new`", "`/incident status [id]`", "`/incident resolve [id] [復旧メモ]`", "`/incident search <キーワード>`"} {
+		if !strings.Contains(help, expected) {
+			t.Errorf("ヘルプに %s が含まれていません", expected)
+		}
+	}
+
+	if strings.Contains(help, "@bot") {
+		t.Error("スラッシュコマンドのヘルプにメンション形式が含まれています")
+	}
+}
+
+func TestResolveTargetIncidentWithArgument(t *testing.T) {
+	ctx := &CommandContext{Args: []string{"#12"}}
+
+	incidentID, err := resolveTargetIncident(ctx)
+	if err != nil {
+		t.Fatalf("予期しないエラー: %v", err)
+	}
+	if incidentID != 12 {
+		t.Errorf("インシデントIDが間違っています: %d, 期待値: 12", incidentID)
+	}
+}
+
+func TestResolveTargetIncidentWithoutDatabase(t *testing.T) {
+	originalDB := db
+	db = nil
+	defer func() { db = originalDB }()
+
+	// 引数がなく、データベースも無効な場合はエラー
+	_, err := resolveTargetIncident(&CommandContext{ChannelID: "C123"})
+	if err == nil {
+		t.Error("データベースが無効な場合、resolveTargetIncidentはエラーを返すべきです")
+	}
+}
+
+func TestResolveTargetIncidentWithText(t *testing.T) {
+	ctx := &CommandContext{Args: []string{"#12", "DBの", "フェイルオーバーで復旧"}}
+
+	incidentID, text, err := resolveTargetIncidentWithText(ctx)
+	if err != nil {
+		t.Fatalf("予期しないエラー: %v", err)
+	}
+	if incidentID != 12 {
+		t.Errorf("インシデントIDが間違っています: %d, 期待値: 12", incidentID)
+	}
+	if text != "DBの フェイルオーバーで復旧" {
+		t.Errorf("本文が間違っています: %q", text)
+	}
+
+	// 先頭がIDでなく、データベースも無効な場合はエラー
+	originalDB := db
+	db = nil
+	defer func() { db = originalDB }()
+
+	if _, _, err := resolveTargetIncidentWithText(&CommandContext{Args: []string{"再起動で復旧"}}); err == nil {
+		t.Error("チャンネルのインシデントが取得できない場合はエラーになるべきです")
+	}
+}
@@ -0,0 +1,146 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"strings"
+	"time"
+)
+
+// defaultStatsPeriod は stats コマンドのデフォルトの集計期間（前週比を表示）
+const defaultStatsPeriod = "7d"
+
+// showIncidentStats はインシデントの統計（件数・MTTA・MTTR）を表示
+func showIncidentStats(ctx *CommandContext, ephemeral bool) {
+	periodArg := defaultStatsPeriod
+	if len(ctx.Args) > 0 {
+		periodArg = ctx.Args[0]
+	}
+
+	period, err := parseSinceDuration(periodArg)
+	if err != nil {
+		ctx.reply(fmt.Sprintf("❌ %v", err), true)
+		return
+	}
+
+	if store == nil {
+		ctx.reply("⚠️ データベース機能が無効のため、統計を取得できません。", true)
+		return
+	}
+
+	now := time.Now()
+	current, previous, err := getIncidentStats(now, period)
+	if err != nil {
+		log.Printf("インシデント統計取得エラー: %v", err)
+		ctx.reply(fmt.Sprintf("❌ 統計の取得に失敗しました: %v", err), true)
+		return
+	}
+
+	ctx.reply(buildStatsMessage(period, now, current, previous), ephemeral)
+	log.Printf("インシデント統計を表示しました (期間: %s)", periodArg)
+}
+
+// buildStatsMessage はインシデント統計のメッセージを生成
+func buildStatsMessage(period time.Duration, until time.Time, current, previous map[string]IncidentStats) string {
+	from := until.Add(-period)
+
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("📊 *インシデント統計（直近%s）*\n", formatPeriod(period)))
+	sb.WriteString(fmt.Sprintf("期間: %s 〜 %s（前期間: %s 〜）\n\n",
+		from.Format("2006-01-02 15:04"),
+		until.Format("2006-01-02 15:04"),
+		from.Add(-period).Format("2006-01-02 15:04"),
+	))
+
+	total := current[""]
+	if total.Count == 0 && previous[""].Count == 0 {
+		sb.WriteString("✅ この期間に報告されたインシデントはありません。")
+		return sb.String()
+	}
+
+	sb.WriteString(formatStatsSection("*全体*", total, previous[""]))
+
+	for _, severity := range severityOrder {
+		cur, prev := current[severity], previous[severity]
+		if cur.Count == 0 && prev.Count == 0 {
+			continue
+		}
+		sb.WriteString("\n")
+		sb.WriteString(formatStatsSection(fmt.Sprintf("%s *%s*", severityEmojis[severity], severity), cur, prev))
+	}
+
+	sb.WriteString("\n_MTTA: 報告から最初の担当者割り当てまで / MTTR: 報告から復旧まで_")
+	return sb.String()
+}
+
+// formatStatsSection は1つの重要度（または全体）の統計を整形
+func formatStatsSection(title string, current, previous IncidentStats) string {
+	return fmt.Sprintf("%s: %d件（前期間比 %s）\n• MTTA: %s\n• MTTR: %s\n",
+		title,
+		current.Count,
+		formatCountChange(current.Count, previous.Count),
+		formatDurationStats(current.TimeToAssign, previous.TimeToAssign),
+		formatDurationStats(current.TimeToResolve, previous.TimeToResolve),
+	)
+}
+
+// formatCountChange は前期間からの件数の変化を整形
+func formatCountChange(current, previous int) string {
+	diff := current - previous
+	switch {
+	case previous == 0 && current == 0:
+		return "±0件"
+	case previous == 0:
+		return fmt.Sprintf("+%d件", diff)
+	case diff == 0:
+		return "±0件"
+	default:
+		return fmt.Sprintf("%+d件 / %+.0f%%", diff, float64(diff)/float64(previous)*100)
+	}
+}
+
+// formatDurationStats は所要時間の統計と前期間の平均との比較を整形
+func formatDurationStats(current, previous DurationStats) string {
+	if current.Count == 0 {
+		return "データなし"
+	}
+
+	text := fmt.Sprintf("平均 %s / 中央値 %s / p90 %s（%d件）",
+		formatStatDuration(current.Mean),
+		formatStatDuration(current.Median),
+		formatStatDuration(current.P90),
+		current.Count,
+	)
+
+	if previous.Count > 0 {
+		trend := "→"
+		if current.Mean > previous.Mean {
+			trend = "↑"
+		} else if current.Mean < previous.Mean {
+			trend = "↓"
+		}
+		text += fmt.Sprintf(" 前期間 平均 %s %s", formatStatDuration(previous.Mean), trend)
+	}
+
+	return text
+}
+
+// formatStatDuration は統計用に所要時間を整形（1分未満も表示する）
+func formatStatDuration(d time.Duration) string {
+	if d < time.Minute {
+		return "1分未満"
+	}
+	return formatElapsed(d)
+}
+
+// formatPeriod は集計期間を表示用に整形
+func formatPeriod(period time.Duration) string {
+	switch {
+	case period%(24*time.Hour) == 0:
+		return fmt.Sprintf("%d日間", int(period/(24*time.Hour)))
+	case period%time.Hour == 0:
+		return fmt.Sprintf("%d時間", int(period/time.Hour))
+	default:
+		return fmt.Sprintf("%d分間", int(period/time.Minute))
+	}
+}
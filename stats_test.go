@@ -0,0 +1,99 @@
+package main
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestFormatCountChange(t *testing.T) {
+	tests := []struct {
+		current  int
+		previous int
+		expected string
+	}{
+		{0, 0, "±0件"},
+		{3, 0, "+3件"},
+		{4, 4, "±0件"},
+		{6, 4, "+2件 / +50%"},
+		{1, 4, "-3件 / -75%"},
+	}
+
+	for _, tt := range tests {
+		if got := formatCountChange(tt.current, tt.previous); got != tt.expected {
+			t.Errorf("formatCountChange(%d, %d) = %q, want %q", tt.current, tt.previous, got, tt.expected)
+		}
+	}
+}
+
+func TestFormatDurationStats(t *testing.T) {
+	if got := formatDurationStats(DurationStats{}, DurationStats{}); got != "データなし" {
+		t.Errorf("データがない場合の表示が間違っています: %s", got)
+	}
+
+	current := DurationStats{Count: 3, Mean: 90 * time.Minute, Median: time.Hour, P90: 3 * time.Hour}
+	previous := DurationStats{Count: 2, Mean: 2 * time.Hour}
+
+	got := formatDurationStats(current, previous)
+	expected := "平均 1時間30分 / 中央値 1時間0分 / p90 3時間0分（3件） 前期間 平均 2時間0分 ↓"
+	if got != expected {
+		t.Errorf("formatDurationStats() = %q, want %q", got, expected)
+	}
+
+	if got := formatDurationStats(DurationStats{Count: 1, Mean: 30 * time.Second}, DurationStats{}); !strings.HasPrefix(got, "平均 1分未満") {
+		t.Errorf("1分未満の表示が間違っています: %s", got)
+	}
+}
+
+func TestFormatPeriod(t *testing.T) {
+	tests := map[time.Duration]string{
+		7 * 24 * time.Hour: "7日間",
+		12 * time.Hour:     "12時間",
+		30 * time.Minute:   "30分間",
+	}
+
+	for period, expected := range tests {
+		if got := formatPeriod(period); got != expected {
+			t.Errorf("formatPeriod(%v) = %q, want %q", period, got, expected)
+		}
+	}
+}
+
+func TestBuildStatsMessage(t *testing.T) {
+	until := time.Date(2025, 1, 15, 9, 0, 0, 0, time.UTC)
+	current := map[string]IncidentStats{
+		"":         {Count: 3, TimeToAssign: DurationStats{Count: 2, Mean: 5 * time.Minute, Median: 5 * time.Minute, P90: 8 * time.Minute}},
+		"critical": {Count: 1},
+		"low":      {Count: 2},
+	}
+	previous := map[string]IncidentStats{
+		"":     {Count: 2},
+		"high": {Count: 2},
+	}
+
+	message := buildStatsMessage(7*24*time.Hour, until, current, previous)
+
+	for _, expected := range []string{
+		"直近7日間",
+		"2025-01-08 09:00 〜 2025-01-15 09:00",
+		"*全体*: 3件（前期間比 +1件 / +50%）",
+		"• MTTA: 平均 5分",
+		"🔴 *critical*: 1件（前期間比 +1件）",
+		"🟠 *high*: 0件（前期間比 -2件 / -100%）",
+		"🟢 *low*: 2件",
+	} {
+		if !strings.Contains(message, expected) {
+			t.Errorf("統計メッセージに %q が含まれていません:\n%s", expected, message)
+		}
+	}
+	if strings.Contains(message, "*medium*") {
+		t.Error("両期間とも0件の重要度は表示しないべきです")
+	}
+}
+
+func TestBuildStatsMessageEmpty(t *testing.T) {
+	message := buildStatsMessage(24*time.Hour, time.Now(), map[string]IncidentStats{}, map[string]IncidentStats{})
+	if !strings.Contains(message, "報告されたインシデントはありません") {
+		t.Errorf("0件の場合のメッセージが間違っています: %s", message)
+	}
+}
@@ -0,0 +1,254 @@
+package main
+
+import (
+	"fmt"
+	"time"
+)
+
+// Incident はインシデントの記録
+type Incident struct {
+	ID              int64      `json:"id"`
+	Title           string     `json:"title"`
+	Severity        string     `json:"severity"`
+	Description     string     `json:"description"`
+	Impact          string     `json:"impact"`
+	Status          string     `json:"status"`
+	ChannelID       string     `json:"channel_id"`
+	ChannelName     string     `json:"channel_name"`
+	ReporterID      string     `json:"reporter_id"`
+	ReporterName    string     `json:"reporter_name"`
+	HandlerID       string     `json:"handler_id,omitempty"`
+	HandlerName     string     `json:"handler_name,omitempty"`
+	SourcePermalink string     `json:"source_permalink,omitempty"` // メッセージショートカットから報告された場合の元メッセージのリンク
+	ResolutionNote  string     `json:"resolution_note,omitempty"`
+	CreatedAt       time.Time  `json:"created_at"`
+	UpdatedAt       time.Time  `json:"updated_at"`
+	ResolvedAt      *time.Time `json:"resolved_at,omitempty"`
+}
+
+// NewIncident は報告されたインシデントの入力内容
+type NewIncident struct {
+	Title           string
+	Severity        string
+	Description     string
+	Impact          string
+	ChannelID       string
+	ChannelName     string
+	ReporterID      string
+	ReporterName    string
+	SourcePermalink string
+}
+
+// StatusChange はステータスの変更履歴
+type StatusChange struct {
+	OldStatus string    `json:"old_status,omitempty"` // 報告時の記録では空
+	NewStatus string    `json:"new_status"`
+	ChangedBy string    `json:"changed_by"`
+	ChangedAt time.Time `json:"changed_at"`
+	Note      string    `json:"note,omitempty"`
+}
+
+// HandlerChange は担当者の変更履歴
+type HandlerChange struct {
+	OldHandlerID string    `json:"old_handler_id,omitempty"`
+	NewHandlerID string    `json:"new_handler_id"`
+	AssignedBy   string    `json:"assigned_by"`
+	AssignedAt   time.Time `json:"assigned_at"`
+}
+
+// FieldUpdate はタイトル・重要度などの詳細情報の更新履歴
+type FieldUpdate struct {
+	FieldName     string    `json:"field_name"`
+	OldValue      string    `json:"old_value"`
+	NewValue      string    `json:"new_value"`
+	UpdatedBy     string    `json:"updated_by"`
+	UpdatedByName string    `json:"updated_by_name"`
+	UpdatedAt     time.Time `json:"updated_at"`
+	Note          string    `json:"note,omitempty"`
+}
+
+// IncidentAlert はインシデントに紐付けたアラート（フィンガープリントごとに1件）
+type IncidentAlert struct {
+	IncidentID      int64             `json:"incident_id"`
+	Fingerprint     string            `json:"fingerprint"`
+	Source          string            `json:"source"`
+	Status          string            `json:"status"` // firing または resolved（最後に受信した状態）
+	Summary         string            `json:"summary"`
+	Service         string            `json:"service,omitempty"`
+	Labels          map[string]string `json:"labels,omitempty"`
+	StartsAt        time.Time         `json:"starts_at"`
+	EndsAt          time.Time         `json:"ends_at,omitempty"`
+	GeneratorURL    string            `json:"generator_url,omitempty"`
+	FirstReceivedAt time.Time         `json:"first_received_at"`
+	LastReceivedAt  time.Time         `json:"last_received_at"`
+	ReceivedCount   int               `json:"received_count"`
+}
+
+// WebhookDelivery はインシデントの変化を通知する Webhook の配信記録（送信先ごとに1件）
+type WebhookDelivery struct {
+	ID           int64      `json:"id"`
+	IncidentID   int64      `json:"incident_id"`
+	Event        string     `json:"event"`
+	Endpoint     string     `json:"endpoint"` // 送信先の名前
+	Payload      string     `json:"payload"`  // 送信する本文（再送でも同じ本文を送る）
+	Status       string     `json:"status"`   // pending・succeeded・failed のいずれか
+	Attempts     int        `json:"attempts"`
+	ResponseCode int        `json:"response_code,omitempty"`
+	LastError    string     `json:"last_error,omitempty"`
+	CreatedAt    time.Time  `json:"created_at"`
+	UpdatedAt    time.Time  `json:"updated_at"`
+	DeliveredAt  *time.Time `json:"delivered_at,omitempty"`
+}
+
+// ActionItem は再発防止策などのフォローアップ作業
+type ActionItem struct {
+	ID             int64      `json:"id"`
+	IncidentID     int64      `json:"incident_id"`
+	IncidentTitle  string     `json:"-"` // 取得時にインシデントのタイトルを設定する
+	Title          string     `json:"title"`
+	OwnerID        string     `json:"owner_id,omitempty"`
+	OwnerName      string     `json:"owner_name,omitempty"`
+	DueDate        *time.Time `json:"due_date,omitempty"` // 日付のみ（時刻とタイムゾーンは使用しない）
+	Status         string     `json:"status"`             // open または done
+	Link           string     `json:"link,omitempty"`
+	CreatedBy      string     `json:"created_by"`
+	CreatedAt      time.Time  `json:"created_at"`
+	CompletedBy    string     `json:"completed_by,omitempty"`
+	CompletedAt    *time.Time `json:"completed_at,omitempty"`
+	LastRemindedAt *time.Time `json:"last_reminded_at,omitempty"`
+}
+
+// TimekeeperState はタイムキーパーの保存された状態（再起動後やほかのレプリカで引き継ぐために使用）
+type TimekeeperState struct {
+	State           string     `json:"state"`                      // running・paused・stopped のいずれか
+	IntervalMinutes int        `json:"interval_minutes,omitempty"` // 0の場合は重要度ごとの投稿間隔
+	LastPostedAt    *time.Time `json:"last_posted_at,omitempty"`
+	LastMilestone   *int       `json:"last_milestone,omitempty"` // nil の場合は再開直後（現在時刻までのマイルストーンを投稿済みとして扱う）
+	PinnedTS        string     `json:"pinned_ts,omitempty"`
+	UpdatedAt       time.Time  `json:"updated_at"`
+}
+
+// IncidentStore はインシデントと変更履歴の保存先
+// PostgreSQL（postgresStore）とメモリ上・ファイル（memoryStore）の実装がある
+type IncidentStore interface {
+	// CreateIncident はインシデントを保存し、初期ステータスを履歴に記録
+	CreateIncident(input NewIncident) (int64, error)
+	// GetIncident はインシデントを取得
+	GetIncident(incidentID int64) (*Incident, error)
+	// FindActiveIncidentByChannel はチャンネルの対応中のインシデントのうち最新のものを取得（ない場合は nil）
+	FindActiveIncidentByChannel(channelID string) (*Incident, error)
+	// FindLatestIncidentByChannel はチャンネルの最新のインシデントを取得（復旧済み・クローズ済みも含む、ない場合は nil）
+	FindLatestIncidentByChannel(channelID string) (*Incident, error)
+	// ListIncidents はフィルター条件に一致するインシデントの1ページ分と全件数を取得（limit が0以下の場合は全件）
+	ListIncidents(filter IncidentListFilter, limit, offset int) ([]Incident, int, error)
+	// SearchIncidents はすべてのキーワードを含むインシデントを関連度の高い順に最大 limit 件取得
+	SearchIncidents(terms []string, limit int) ([]Incident, error)
+
+	// UpdateIncidentField はタイトル・重要度・詳細説明・影響範囲のいずれかを更新し、更新履歴を記録
+	UpdateIncidentField(incidentID int64, field, oldValue, newValue, updatedBy, updatedByName string) error
+	// ChangeHandler は担当者を変更し、担当者の変更履歴を記録
+	ChangeHandler(incidentID int64, handlerID, handlerName, changedBy string) error
+	// ChangeStatus はステータスを変更し、変更前のステータスを返す（許可されていない遷移の場合はエラー）
+	ChangeStatus(incidentID int64, newStatus, changedBy, note string) (string, error)
+	// ResolveIncident はステータスを復旧済みに変更し、復旧メモ（空でない場合）と同時に保存して変更前のステータスを返す
+	ResolveIncident(incidentID int64, changedBy, note, resolutionNote string) (string, error)
+
+	// StatusHistory はステータスの変更履歴を新しい順に取得（limit が0の場合は全件）
+	StatusHistory(incidentID int64, limit int) ([]StatusChange, error)
+	// HandlerHistory は担当者の変更履歴を新しい順に取得（limit が0の場合は全件）
+	HandlerHistory(incidentID int64, limit int) ([]HandlerChange, error)
+	// UpdateHistory は詳細情報の更新履歴を新しい順に取得（limit が0の場合は全件）
+	UpdateHistory(incidentID int64, limit int) ([]FieldUpdate, error)
+
+	// AttachAlert はアラートをインシデントに紐付け（紐付け済みの場合は状態を更新）、前回受信したときの状態を返す（初回は空）
+	AttachAlert(alert IncidentAlert) (string, error)
+	// FindIncidentByAlert はフィンガープリントのアラートを紐付けた対応中のインシデントを取得（ない場合は nil）
+	FindIncidentByAlert(fingerprint string) (*Incident, error)
+	// FindIncidentByAlertService は since 以降にサービスのアラートを受信した対応中のインシデントのうち最新のものを取得（ない場合は nil）
+	FindIncidentByAlertService(service string, since time.Time) (*Incident, error)
+	// IncidentAlerts はインシデントに紐付けたアラートを最初に受信した順に取得
+	IncidentAlerts(incidentID int64) ([]IncidentAlert, error)
+
+	// CreateWebhookDelivery は Webhook の配信記録を保存し、配信IDを返す
+	CreateWebhookDelivery(delivery WebhookDelivery) (int64, error)
+	// UpdateWebhookDelivery は配信記録の状態・送信回数・最後の結果を更新
+	UpdateWebhookDelivery(delivery WebhookDelivery) error
+	// GetWebhookDelivery は配信記録を取得
+	GetWebhookDelivery(deliveryID int64) (*WebhookDelivery, error)
+	// WebhookDeliveries はインシデントの配信記録を新しい順に取得（limit が0の場合は全件）
+	WebhookDeliveries(incidentID int64, limit int) ([]WebhookDelivery, error)
+
+	// AddActionItem はアクションアイテムを未完了として保存し、アクションアイテムIDを返す
+	AddActionItem(item ActionItem) (int64, error)
+	// ListActionItems はアクションアイテムを未完了・期限の近い順に最大 limit 件取得
+	// incidentID が0の場合はすべてのインシデント、ownerID が空の場合はすべての担当者が対象
+	ListActionItems(incidentID int64, ownerID string, includeDone bool, limit int) ([]ActionItem, error)
+	// OverdueActionItems は期限が today より前で、today 以降にまだリマインドしていない未完了のアクションアイテムを担当者・期限の順に取得
+	OverdueActionItems(today time.Time) ([]ActionItem, error)
+	// CompleteActionItem は未完了のアクションアイテムを完了にし、完了にしたアクションアイテムを返す
+	CompleteActionItem(itemID int64, completedBy string) (*ActionItem, error)
+	// MarkActionItemsReminded はアクションアイテムのリマインド日時を記録
+	MarkActionItemsReminded(itemIDs []int64) error
+
+	// SaveTimekeeperState はタイムキーパーの状態と投稿間隔を保存（intervalMinutes が0の場合は保存済みの投稿間隔を変更しない）
+	SaveTimekeeperState(incidentID int64, state string, intervalMinutes int) error
+	// SaveTimekeeperProgress はタイムキーパーの投稿の進捗を保存（状態が保存されていない場合は何もしない）
+	SaveTimekeeperProgress(incidentID int64, progress timekeeperProgress) error
+	// SaveTimekeeperInterval はタイムキーパーの投稿間隔を保存（状態が保存されていない場合は何もしない）
+	SaveTimekeeperInterval(incidentID int64, intervalMinutes int) error
+	// TimekeeperStates は複数のインシデントのタイムキーパーの状態を取得（状態が保存されていないものは含めない）
+	TimekeeperStates(incidentIDs []int64) (map[int64]TimekeeperState, error)
+	// TransitionTimekeeperState はタイムキーパーの状態が from のいずれかの場合のみ to に変更し、変更したかを返す
+	// 状態が保存されていない場合は動作中として扱い、再開（running への変更）時は投稿の進捗をリセットする
+	TransitionTimekeeperState(incidentID int64, from []string, to string) (bool, error)
+
+	// IncidentStats は集計期間（until までの period）とその直前の同じ長さの期間の重要度ごとの統計を取得（空文字列は全体）
+	IncidentStats(until time.Time, period time.Duration) (current, previous map[string]IncidentStats, err error)
+
+	// ClaimProcessedEvent はイベントを処理済みとして記録し、初めて記録した場合（有効期限切れを含む）は true を返す
+	ClaimProcessedEvent(eventKey string, ttl time.Duration) (bool, error)
+	// PurgeExpiredProcessedEvents は有効期限が切れた処理済みイベントを削除し、削除した件数を返す
+	PurgeExpiredProcessedEvents() (int64, error)
+
+	// LookupJournalIncidentID はジャーナルの仮IDに対応する登録後のインシデントIDを取得（未登録の場合は0）
+	LookupJournalIncidentID(journalID int64) (int64, error)
+
+	// AddHomeViewer はApp Homeを開いたユーザーを再描画対象として記録（記録済みの場合は最後に開いた日時を更新）
+	AddHomeViewer(userID string) error
+	// HomeViewers は openedSince 以降にApp Homeを開いたユーザーの一覧を取得
+	HomeViewers(openedSince time.Time) ([]string, error)
+	// PurgeHomeViewers は openedBefore より前に最後にApp Homeを開いたユーザーを削除し、削除した件数を返す
+	PurgeHomeViewers(openedBefore time.Time) (int64, error)
+}
+
+// store はインシデントの保存先（nil の場合はインシデントの記録に関する機能が無効）
+var store IncidentStore
+
+// 保存先の種類
+const (
+	storageBackendPostgres = "postgres"
+	storageBackendMemory   = "memory"
+)
+
+// updatableIncidentFields は UpdateIncidentField で更新できるフィールド
+var updatableIncidentFields = map[string]bool{
+	"title":       true,
+	"severity":    true,
+	"description": true,
+	"impact":      true,
+}
+
+// errIncidentNotFound はインシデントが見つからない場合のエラー
+func errIncidentNotFound(incidentID int64) error {
+	return fmt.Errorf("インシデントID %d が見つかりません", incidentID)
+}
+
+// errWebhookDeliveryNotFound は配信記録が見つからない場合のエラー
+func errWebhookDeliveryNotFound(deliveryID int64) error {
+	return fmt.Errorf("配信ID %d が見つかりません", deliveryID)
+}
+
+// errActionItemNotCompletable は完了にできるアクションアイテムが見つからない場合のエラー
+func errActionItemNotCompletable(itemID int64) error {
+	return fmt.Errorf("アクションアイテム #%d が見つからないか、既に完了しています", itemID)
+}
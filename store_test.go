@@ -0,0 +1,58 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestIncidentFunctionsWithMemoryStore(t *testing.T) {
+	originalStore := store
+	originalDB := db
+	defer func() {
+		store = originalStore
+		db = originalDB
+	}()
+	db = nil
+	store, _ = newMemoryStore("")
+
+	incidentID, err := saveIncident("DB障害", "high", "説明", "影響", "C001", "incident-c001", "U001", "reporter", "")
+	if err != nil {
+		t.Fatalf("saveIncident() error = %v", err)
+	}
+	if err := assignHandler(incidentID, "U010", "alice", "U001"); err != nil {
+		t.Fatalf("assignHandler() error = %v", err)
+	}
+
+	id, title, err := getIncidentByChannelID("C001")
+	if err != nil || id != incidentID || title != "DB障害" {
+		t.Errorf("getIncidentByChannelID() = %d, %s, %v", id, title, err)
+	}
+
+	message := buildHandlerMessage("C001")
+	if !strings.Contains(message, "DB障害") || !strings.Contains(message, "U010") {
+		t.Errorf("buildHandlerMessage() = %s", message)
+	}
+	if message := buildHandlerMessage("C999"); !strings.Contains(message, "オープンなインシデントがありません") {
+		t.Errorf("buildHandlerMessage() = %s", message)
+	}
+
+	// データベースがなくても対応中のインシデント一覧を取得できる（タイムキーパーの状態は含まれない）
+	open, _, err := getOpenIncidents()
+	if err != nil || len(open) != 1 || open[0].HandlerID != "U010" {
+		t.Errorf("getOpenIncidents() = %v, %v", open, err)
+	}
+
+	if err := resolveIncident(incidentID, "U010", "alice", "再起動で復旧"); err != nil {
+		t.Fatalf("resolveIncident() error = %v", err)
+	}
+	details, err := getIncidentDetails(incidentID)
+	if err != nil {
+		t.Fatalf("getIncidentDetails() error = %v", err)
+	}
+	if details.Status != StatusResolved || details.ResolutionNote != "再起動で復旧" {
+		t.Errorf("getIncidentDetails() = %+v", details)
+	}
+	if _, _, err := getIncidentByChannelID("C001"); err == nil {
+		t.Error("復旧済みのインシデントしかないチャンネルはエラーになるべきです")
+	}
+}
@@ -1,8 +1,10 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"log"
+	"sort"
 	"strings"
 	"sync"
 	"time"
@@ -10,137 +12,510 @@ import (
 	"github.com/slack-go/slack"
 )
 
+// タイムキーパーの表示モード
+const (
+	timekeeperModePost   = "post"   // 経過時間を新しいメッセージとして投稿
+	timekeeperModeUpdate = "update" // ピン留めした1つのメッセージを更新
+)
+
+// defaultTimekeeperIntervals は重要度ごとの経過時間の投稿間隔（分）のデフォルト
+var defaultTimekeeperIntervals = map[string]int{
+	"critical": 5,
+	"high":     10,
+	"medium":   15,
+	"low":      30,
+}
+
+// defaultTimekeeperInterval は重要度が不明な場合の投稿間隔（分）
+const defaultTimekeeperInterval = 15
+
+// defaultTimekeeperMilestones はエスカレーションを促すマイルストーン（分）のデフォルト
+var defaultTimekeeperMilestones = []int{15, 30, 60, 120}
+
+// timekeeperMilestonePrompts はマイルストーンに応じたエスカレーションの呼びかけ（経過分の昇順）
+// 設定で独自のマイルストーンを指定した場合は、経過時間以下で最大のものを使用する
+var timekeeperMilestonePrompts = []struct {
+	Minutes int
+	Prompt  string
+}{
+	{15, "影響範囲と暫定対応の方針を確認し、チャンネルで共有してください。"},
+	{30, "解決の見通しが立っていない場合は、関係チームや上長へのエスカレーションを検討してください。"},
+	{60, "カスタマーサポートなど関係部署への状況共有と、対応体制の見直しを検討してください。"},
+	{120, "長時間の対応になっています。交代要員の手配と、ステータスページの更新を検討してください。"},
+}
+
+// タイムキーパーの状態（incident_timekeepers テーブルに保存し、再起動後の復元に使用）
+const (
+	timekeeperStateRunning = "running"
+	timekeeperStatePaused  = "paused"
+	timekeeperStateStopped = "stopped"
+)
+
+// timekeeperProgress はタイムキーパーの投稿の進捗（再起動後に続きから再開するために保存する）
+type timekeeperProgress struct {
+	LastPostedAt  time.Time // 最後に経過時間またはマイルストーンを投稿した時刻
+	LastMilestone int       // 最後に投稿したマイルストーン（分）
+	PinnedTS      string    // ピン留めした経過時間メッセージのタイムスタンプ（更新モードのみ）
+}
+
+// timekeeperEntry は動作中または一時停止中のタイムキーパーの情報
+type timekeeperEntry struct {
+	channelID string
+	severity  string
+	interval  int // 経過時間の投稿間隔（分）
+	startTime time.Time
+	progress  timekeeperProgress
+}
+
+// timekeeperStopHandover はリーダーでなくなったためにタイムキーパーのゴルーチンを止める場合の停止理由
+// 状態は変更せず、ピン留めした経過時間メッセージもそのままにする
+const timekeeperStopHandover = "handover"
+
+// timekeeperSyncInterval はリーダーがデータベースに保存されたタイムキーパーの状態を反映する間隔
+const timekeeperSyncInterval = 10 * time.Second
+
 // TimekeeperManager はタイムキーパーのゴルーチンを管理
+// タイムキーパーの状態はデータベースに保存し、ゴルーチンはリーダーに選出されたレプリカだけで動作させる
 type TimekeeperManager struct {
-	timekeepers map[int64]chan bool // incidentID -> stop channel
+	timekeepers map[int64]chan bool        // incidentID -> stop channel（動作中のもの）
+	entries     map[int64]*timekeeperEntry // incidentID -> 動作中・一時停止中のタイムキーパーの情報
+	stopReasons map[chan bool]string       // stop channel -> 停止理由（stopped/paused/handover）
 	mu          sync.RWMutex
 }
 
 var timekeeperManager = &TimekeeperManager{
 	timekeepers: make(map[int64]chan bool),
+	entries:     make(map[int64]*timekeeperEntry),
+	stopReasons: make(map[chan bool]string),
 }
 
 // startTimekeeper はインシデントのタイムキーパーを開始
-func (tm *TimekeeperManager) startTimekeeper(api *slack.Client, incidentID int64, channelID string, startTime time.Time) {
+// リーダーでないレプリカでは状態の保存だけを行い、リーダーが次の同期で開始する
+func (tm *TimekeeperManager) startTimekeeper(api SlackAPI, incidentID int64, channelID string, severity string, startTime time.Time) {
+	entry := &timekeeperEntry{
+		channelID: channelID,
+		severity:  severity,
+		interval:  timekeeperInterval(severity),
+		startTime: startTime,
+		progress:  initialTimekeeperProgress(startTime, time.Now(), timekeeperMilestones()),
+	}
+
+	persistTimekeeperState(incidentID, timekeeperStateRunning, entry.interval)
+
+	if !isLeader() {
+		log.Printf("インシデント %d のタイムキーパーはリーダーのレプリカで開始されます", incidentID)
+		return
+	}
+	tm.launch(api, incidentID, entry)
+}
+
+// launch はタイムキーパーのゴルーチンを開始（既に動いている場合は false）
+func (tm *TimekeeperManager) launch(api SlackAPI, incidentID int64, entry *timekeeperEntry) bool {
 	tm.mu.Lock()
 	defer tm.mu.Unlock()
 
 	// 既に動いている場合は何もしない
 	if _, exists := tm.timekeepers[incidentID]; exists {
 		log.Printf("インシデント %d のタイムキーパーは既に動作中です", incidentID)
-		return
+		return false
 	}
 
 	// 停止用チャネルを作成
 	stopChan := make(chan bool)
 	tm.timekeepers[incidentID] = stopChan
+	if tm.entries == nil {
+		tm.entries = make(map[int64]*timekeeperEntry)
+	}
+	tm.entries[incidentID] = entry
 
-	log.Printf("インシデント %d のタイムキーパーを開始します", incidentID)
+	log.Printf("インシデント %d のタイムキーパーを開始します (重要度: %s, 投稿間隔: %d分)", incidentID, entry.severity, entry.interval)
 
 	// ゴルーチンでタイムキーパーを開始
-	go func() {
-		ticker := time.NewTicker(1 * time.Minute)
-		defer ticker.Stop()
-
-		for {
-			select {
-			case <-stopChan:
-				log.Printf("インシデント %d のタイムキーパーを停止しました", incidentID)
-				return
-			case <-ticker.C:
-				// 経過時間を計算
-				elapsed := time.Since(startTime)
-				minutes := int(elapsed.Minutes())
-				hours := minutes / 60
-				mins := minutes % 60
-
-				var elapsedStr string
-				if hours > 0 {
-					elapsedStr = fmt.Sprintf("%d時間%d分", hours, mins)
-				} else {
-					elapsedStr = fmt.Sprintf("%d分", mins)
-				}
+	go tm.run(api, incidentID, *entry, stopChan)
+	return true
+}
+
+// run はタイムキーパーの本体
+// 1分ごとに経過時間を確認し、投稿間隔ごとに経過時間を、マイルストーンでエスカレーションの呼びかけを投稿する
+func (tm *TimekeeperManager) run(api SlackAPI, incidentID int64, entry timekeeperEntry, stopChan chan bool) {
+	ticker := time.NewTicker(1 * time.Minute)
+	defer ticker.Stop()
+
+	channelID := entry.channelID
+	startTime := entry.startTime
+	milestones := timekeeperMilestones()
+	progress := entry.progress
+	lastPostedMinutes := int(progress.LastPostedAt.Sub(startTime).Minutes())
+
+	// 更新モードの場合は経過時間のメッセージを1つ投稿してピン留めする（復元・再開時は既存のメッセージを使う）
+	if config.Timekeeper.Mode == timekeeperModeUpdate {
+		if progress.PinnedTS == "" || updatePinnedElapsedMessage(api, incidentID, channelID, progress.PinnedTS, time.Since(startTime)) != nil {
+			progress.PinnedTS = postPinnedElapsedMessage(api, incidentID, channelID, time.Since(startTime))
+			tm.recordProgress(incidentID, progress)
+		}
+	} else {
+		progress.PinnedTS = ""
+	}
+	pinnedTS := progress.PinnedTS
 
-				// 経過時間メッセージを投稿
-				message := fmt.Sprintf("⏱️ *インシデント経過時間:* %s", elapsedStr)
-
-				// 停止ボタンを作成
-				stopButton := slack.NewButtonBlockElement(
-					"stop_timekeeper",
-					fmt.Sprintf("incident_%d", incidentID),
-					slack.NewTextBlockObject("plain_text", "⏹️ タイムキーパーを止める", true, false),
-				)
-				stopButton.Style = "danger"
-
-				// メッセージブロックを作成（テキストとボタンを横並び）
-				textSection := slack.NewSectionBlock(
-					slack.NewTextBlockObject("mrkdwn", message, false, false),
-					nil,
-					slack.NewAccessory(stopButton),
-				)
-
-				_, _, err := api.PostMessage(
-					channelID,
-					slack.MsgOptionText(message, false),
-					slack.MsgOptionBlocks(textSection),
-				)
-
-				if err != nil {
-					log.Printf("タイムキーパーメッセージ投稿エラー: %v", err)
-
-					// チャンネルがアーカイブされている場合は自動停止
-					if strings.Contains(err.Error(), "is_archived") || strings.Contains(err.Error(), "channel_not_found") {
-						log.Printf("チャンネル %s がアーカイブまたは削除されています。インシデント %d のタイムキーパーを自動停止します", channelID, incidentID)
-
-						// タイムキーパーを停止
-						tm.mu.Lock()
-						if stopCh, exists := tm.timekeepers[incidentID]; exists {
-							close(stopCh)
-							delete(tm.timekeepers, incidentID)
-							log.Printf("インシデント %d のタイムキーパーを自動停止しました", incidentID)
-						}
-						tm.mu.Unlock()
-
-						// インシデントを自動的に復旧済みにする
-						if db != nil {
-							err := resolveIncident(incidentID, "system", "システム（チャンネルアーカイブ）")
-							if err != nil {
-								log.Printf("インシデント %d の自動復旧エラー: %v", incidentID, err)
-							} else {
-								log.Printf("インシデント %d を自動的に復旧済みにしました", incidentID)
-							}
-						}
-						return
-					}
-				} else {
-					log.Printf("インシデント %d の経過時間を投稿しました: %s", incidentID, elapsedStr)
+	for {
+		select {
+		case <-stopChan:
+			reason := tm.takeStopReason(stopChan)
+			if pinnedTS != "" {
+				switch reason {
+				case timekeeperStatePaused:
+					pausePinnedElapsedMessage(api, incidentID, channelID, pinnedTS, time.Since(startTime))
+				case timekeeperStopHandover:
+					// 引き継ぎ先のレプリカが同じメッセージの更新を続ける
+				default:
+					finishPinnedElapsedMessage(api, channelID, pinnedTS, time.Since(startTime))
 				}
 			}
+			log.Printf("インシデント %d のタイムキーパーを停止しました (理由: %s)", incidentID, reason)
+			return
+		case <-ticker.C:
+			now := time.Now()
+			elapsed := now.Sub(startTime)
+			minutes := int(elapsed.Minutes())
+
+			// ピン留めしたメッセージの更新と投稿のエラーはそれぞれ確認する（片方のエラーで他方を上書きしない）
+			var updateErr, postErr error
+			if pinnedTS != "" {
+				updateErr = updatePinnedElapsedMessage(api, incidentID, channelID, pinnedTS, elapsed)
+			}
+
+			if milestone := reachedMilestone(minutes, progress.LastMilestone, milestones); milestone > 0 {
+				postErr = postMilestoneMessage(api, incidentID, channelID, tm.severity(incidentID), elapsed, milestone)
+				lastPostedMinutes = minutes
+				progress.LastMilestone = milestone
+				progress.LastPostedAt = now
+				tm.recordProgress(incidentID, progress)
+			} else if pinnedTS == "" && isTimekeeperPostDue(minutes, lastPostedMinutes, tm.interval(incidentID)) {
+				postErr = postElapsedMessage(api, incidentID, channelID, elapsed)
+				lastPostedMinutes = minutes
+				progress.LastPostedAt = now
+				tm.recordProgress(incidentID, progress)
+			}
+
+			if updateErr != nil {
+				log.Printf("タイムキーパーメッセージ更新エラー: %v", updateErr)
+			}
+			if postErr != nil {
+				log.Printf("タイムキーパーメッセージ投稿エラー: %v", postErr)
+			}
+
+			// チャンネルがアーカイブされている場合は自動停止
+			if (updateErr != nil && isChannelGoneError(updateErr)) || (postErr != nil && isChannelGoneError(postErr)) {
+				tm.stopForGoneChannel(api, incidentID, channelID, stopChan)
+				return
+			}
 		}
-	}()
+	}
 }
 
-// stopTimekeeper はインシデントのタイムキーパーを停止
-func (tm *TimekeeperManager) stopTimekeeper(incidentID int64) bool {
+// stopForGoneChannel はチャンネルがアーカイブまたは削除されたタイムキーパーを停止し、インシデントを復旧済みにする
+// run のゴルーチンから呼び出すため、停止シグナルを待たずに停止理由を削除する
+func (tm *TimekeeperManager) stopForGoneChannel(api SlackAPI, incidentID int64, channelID string, stopChan chan bool) {
+	log.Printf("チャンネル %s がアーカイブまたは削除されています。インシデント %d のタイムキーパーを自動停止します", channelID, incidentID)
+
+	// タイムキーパーを停止
+	if tm.stopTimekeeper(incidentID) {
+		log.Printf("インシデント %d のタイムキーパーを自動停止しました", incidentID)
+	}
+	tm.takeStopReason(stopChan)
+
+	// インシデントを自動的に復旧済みにする
+	if store != nil {
+		err := resolveIncident(incidentID, "system", "システム（チャンネルアーカイブ）", "")
+		if err != nil {
+			log.Printf("インシデント %d の自動復旧エラー: %v", incidentID, err)
+		} else {
+			log.Printf("インシデント %d を自動的に復旧済みにしました", incidentID)
+			refreshAppHomes(api)
+		}
+	}
+}
+
+// halt はこのレプリカで動作中のタイムキーパーのゴルーチンを止める（データベースの状態は変更しない）
+// reason が一時停止の場合は再開できるように情報を残す
+func (tm *TimekeeperManager) halt(incidentID int64, reason string) (wasRunning, wasRegistered bool) {
 	tm.mu.Lock()
 	defer tm.mu.Unlock()
 
-	stopChan, exists := tm.timekeepers[incidentID]
-	if !exists {
+	stopChan, wasRunning := tm.timekeepers[incidentID]
+	_, wasRegistered = tm.entries[incidentID]
+	if wasRunning {
+		// 停止理由を記録してから停止シグナルを送信
+		if tm.stopReasons == nil {
+			tm.stopReasons = make(map[chan bool]string)
+		}
+		tm.stopReasons[stopChan] = reason
+		close(stopChan)
+		delete(tm.timekeepers, incidentID)
+	}
+	if reason != timekeeperStatePaused {
+		delete(tm.entries, incidentID)
+	}
+	return wasRunning, wasRegistered
+}
+
+// haltAll はこのレプリカのすべてのタイムキーパーを止める（リーダーでなくなった時）
+func (tm *TimekeeperManager) haltAll() {
+	tm.mu.RLock()
+	incidentIDs := make([]int64, 0, len(tm.entries))
+	for incidentID := range tm.entries {
+		incidentIDs = append(incidentIDs, incidentID)
+	}
+	tm.mu.RUnlock()
+
+	for _, incidentID := range incidentIDs {
+		tm.halt(incidentID, timekeeperStopHandover)
+	}
+	log.Printf("このレプリカのタイムキーパー %d 件を停止しました（リーダーの交代）", len(incidentIDs))
+}
+
+// handOver は仮IDで動作しているタイムキーパーを止め、その状態と進捗を登録後のインシデントIDで保存
+// データベースの復旧後、タイムキーパーは保存した状態からリーダーが登録後のIDで再開する
+// 縮退運転中に停止したものも停止済みとして保存する（状態が未保存の場合は動作中として扱われるため）
+func (tm *TimekeeperManager) handOver(journalID, incidentID int64) {
+	tm.mu.RLock()
+	var entry timekeeperEntry
+	registeredEntry, registered := tm.entries[journalID]
+	if registered {
+		entry = *registeredEntry
+	}
+	_, running := tm.timekeepers[journalID]
+	tm.mu.RUnlock()
+
+	state := timekeeperStateStopped
+	switch {
+	case running:
+		state = timekeeperStateRunning
+	case registered:
+		state = timekeeperStatePaused
+	}
+
+	tm.halt(journalID, timekeeperStopHandover)
+	persistTimekeeperState(incidentID, state, entry.interval)
+	if registered && store != nil {
+		if err := saveTimekeeperProgress(incidentID, entry.progress); err != nil {
+			log.Printf("インシデント %d のタイムキーパー進捗保存エラー: %v", incidentID, err)
+		}
+	}
+	log.Printf("仮ID %d のタイムキーパーを ID %d に引き継ぎました (状態: %s)", journalID, incidentID, state)
+}
+
+// takeStopReason はゴルーチンの停止理由を取得して削除
+func (tm *TimekeeperManager) takeStopReason(stopChan chan bool) string {
+	tm.mu.Lock()
+	defer tm.mu.Unlock()
+
+	reason, ok := tm.stopReasons[stopChan]
+	if !ok {
+		return timekeeperStateStopped
+	}
+	delete(tm.stopReasons, stopChan)
+	return reason
+}
+
+// stopTimekeeper はインシデントのタイムキーパーを停止（一時停止中のものも停止済みにする）
+func (tm *TimekeeperManager) stopTimekeeper(incidentID int64) bool {
+	wasRunning, wasRegistered := tm.halt(incidentID, timekeeperStateStopped)
+	stopped := wasRunning || wasRegistered
+
+	// ほかのレプリカ（リーダー）で動作しているものは、保存した状態を同期して停止される
+	if store != nil {
+		changed, err := transitionTimekeeperState(incidentID, []string{timekeeperStateRunning, timekeeperStatePaused}, timekeeperStateStopped)
+		if err != nil {
+			log.Printf("インシデント %d のタイムキーパー状態保存エラー: %v", incidentID, err)
+		}
+		stopped = stopped || changed
+	}
+
+	if !stopped {
 		log.Printf("インシデント %d のタイムキーパーは動作していません", incidentID)
 		return false
 	}
 
-	// 停止シグナルを送信
-	close(stopChan)
+	log.Printf("インシデント %d のタイムキーパーに停止シグナルを送信しました", incidentID)
+	return true
+}
 
-	// マップから削除
-	delete(tm.timekeepers, incidentID)
+// pauseTimekeeper は動作中のタイムキーパーを一時停止
+func (tm *TimekeeperManager) pauseTimekeeper(incidentID int64) bool {
+	paused := false
+	if store != nil {
+		changed, err := transitionTimekeeperState(incidentID, []string{timekeeperStateRunning}, timekeeperStatePaused)
+		if err != nil {
+			log.Printf("インシデント %d のタイムキーパー状態保存エラー: %v", incidentID, err)
+		}
+		paused = changed
+	}
 
-	log.Printf("インシデント %d のタイムキーパーに停止シグナルを送信しました", incidentID)
+	// 情報は残したまま停止シグナルを送信
+	if wasRunning, _ := tm.halt(incidentID, timekeeperStatePaused); wasRunning {
+		paused = true
+	}
+
+	if !paused {
+		log.Printf("インシデント %d のタイムキーパーは動作していません", incidentID)
+		return false
+	}
+
+	log.Printf("インシデント %d のタイムキーパーを一時停止しました", incidentID)
 	return true
 }
 
+// resumeTimekeeper は一時停止中のタイムキーパーを再開
+// 一時停止中に過ぎたマイルストーンや投稿間隔の分はさかのぼって投稿しない
+func (tm *TimekeeperManager) resumeTimekeeper(api SlackAPI, incidentID int64) bool {
+	if store != nil {
+		changed, err := transitionTimekeeperState(incidentID, []string{timekeeperStatePaused}, timekeeperStateRunning)
+		if err != nil {
+			log.Printf("インシデント %d のタイムキーパー状態保存エラー: %v", incidentID, err)
+			return false
+		}
+		if !changed {
+			log.Printf("インシデント %d のタイムキーパーは一時停止中ではありません", incidentID)
+			return false
+		}
+
+		// リーダーでない場合は、リーダーが次の同期で再開する
+		if isLeader() {
+			tm.resumeLocal(api, incidentID)
+		}
+		log.Printf("インシデント %d のタイムキーパーを再開しました", incidentID)
+		return true
+	}
+
+	return tm.resumeLocal(api, incidentID)
+}
+
+// resumeLocal はこのレプリカで一時停止中のタイムキーパーのゴルーチンを再開
+func (tm *TimekeeperManager) resumeLocal(api SlackAPI, incidentID int64) bool {
+	tm.mu.Lock()
+	entry, registered := tm.entries[incidentID]
+	_, running := tm.timekeepers[incidentID]
+	if !registered || running {
+		tm.mu.Unlock()
+		log.Printf("インシデント %d のタイムキーパーは一時停止中ではありません", incidentID)
+		return false
+	}
+	resumed := *entry
+	tm.mu.Unlock()
+
+	pinnedTS := resumed.progress.PinnedTS
+	resumed.progress = initialTimekeeperProgress(resumed.startTime, time.Now(), timekeeperMilestones())
+	resumed.progress.PinnedTS = pinnedTS
+
+	return tm.launch(api, incidentID, &resumed)
+}
+
+// syncLoop はデータベースに保存されたタイムキーパーの状態をこのレプリカに反映し続ける（リーダーのみ）
+// 再起動前のタイムキーパーや、ほかのレプリカで開始・一時停止・再開・停止されたタイムキーパーを引き継ぐ
+func (tm *TimekeeperManager) syncLoop(ctx context.Context, api SlackAPI) {
+	tm.syncWithDatabase(api)
+
+	ticker := time.NewTicker(timekeeperSyncInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			tm.haltAll()
+			return
+		case <-ticker.C:
+			tm.syncWithDatabase(api)
+		}
+	}
+}
+
+// syncWithDatabase は対応中のインシデントの保存された状態に合わせてタイムキーパーを開始・一時停止・停止
+func (tm *TimekeeperManager) syncWithDatabase(api SlackAPI) {
+	incidents, states, err := getOpenIncidents()
+	if err != nil {
+		log.Printf("オープンなインシデント取得エラー: %v", err)
+		return
+	}
+
+	open := make(map[int64]bool, len(incidents))
+	for _, incident := range incidents {
+		open[incident.ID] = true
+		var saved *TimekeeperState
+		if state, exists := states[incident.ID]; exists {
+			saved = &state
+		}
+		tm.syncTimekeeper(api, incident.ID, incident.ChannelID, incident.Severity, incident.CreatedAt, saved)
+	}
+
+	// 対応中でなくなったインシデントのタイムキーパーを停止
+	tm.mu.RLock()
+	var finished []int64
+	for incidentID := range tm.entries {
+		if !open[incidentID] {
+			finished = append(finished, incidentID)
+		}
+	}
+	tm.mu.RUnlock()
+
+	for _, incidentID := range finished {
+		tm.halt(incidentID, timekeeperStateStopped)
+		log.Printf("インシデント %d は対応中でなくなったため、タイムキーパーを停止しました", incidentID)
+	}
+}
+
+// syncTimekeeper は保存された状態に合わせて1件のタイムキーパーを開始・一時停止・停止
+// 状態が保存されていない場合（状態の保存に対応する前のインシデント）は動作中として扱う
+func (tm *TimekeeperManager) syncTimekeeper(api SlackAPI, incidentID int64, channelID, severity string, startTime time.Time, saved *TimekeeperState) {
+	desired := timekeeperStateRunning
+	if saved != nil {
+		desired = saved.State
+	}
+	running := tm.isTimekeeperRunning(incidentID)
+	paused := tm.isPaused(incidentID)
+
+	switch desired {
+	case timekeeperStateStopped:
+		if running || paused {
+			tm.halt(incidentID, timekeeperStateStopped)
+			log.Printf("インシデント %d のタイムキーパーを停止しました（保存された状態を反映）", incidentID)
+		}
+	case timekeeperStatePaused:
+		if running {
+			tm.halt(incidentID, timekeeperStatePaused)
+			log.Printf("インシデント %d のタイムキーパーを一時停止しました（保存された状態を反映）", incidentID)
+		} else if !paused {
+			tm.mu.Lock()
+			if tm.entries == nil {
+				tm.entries = make(map[int64]*timekeeperEntry)
+			}
+			tm.entries[incidentID] = restoredTimekeeperEntry(saved, channelID, severity, startTime, time.Now())
+			tm.mu.Unlock()
+			log.Printf("インシデント %d のタイムキーパーを一時停止中として復元しました", incidentID)
+		}
+	default:
+		switch {
+		case running:
+			// ほかのレプリカでの重要度の変更を反映
+			interval := timekeeperInterval(severity)
+			if saved != nil && saved.IntervalMinutes > 0 {
+				interval = saved.IntervalMinutes
+			}
+			tm.mu.Lock()
+			if entry, exists := tm.entries[incidentID]; exists {
+				entry.severity = severity
+				entry.interval = interval
+			}
+			tm.mu.Unlock()
+		case saved == nil:
+			tm.startTimekeeper(api, incidentID, channelID, severity, startTime)
+		default:
+			tm.launch(api, incidentID, restoredTimekeeperEntry(saved, channelID, severity, startTime, time.Now()))
+		}
+	}
+}
+
 // isTimekeeperRunning はタイムキーパーが動作中かチェック
 func (tm *TimekeeperManager) isTimekeeperRunning(incidentID int64) bool {
 	tm.mu.RLock()
@@ -149,3 +524,380 @@ func (tm *TimekeeperManager) isTimekeeperRunning(incidentID int64) bool {
 	_, exists := tm.timekeepers[incidentID]
 	return exists
 }
+
+// isPaused はタイムキーパーが一時停止中かチェック
+func (tm *TimekeeperManager) isPaused(incidentID int64) bool {
+	tm.mu.RLock()
+	defer tm.mu.RUnlock()
+
+	_, registered := tm.entries[incidentID]
+	_, running := tm.timekeepers[incidentID]
+	return registered && !running
+}
+
+// currentState はタイムキーパーの状態を取得
+// 保存された状態（リーダー以外のレプリカでも正しい状態）を返し、取得できない場合はこのレプリカの状態を返す
+func (tm *TimekeeperManager) currentState(incidentID int64) string {
+	if store != nil {
+		saved, err := getTimekeeperState(incidentID)
+		if err == nil {
+			if saved == nil {
+				return timekeeperStateRunning
+			}
+			return saved.State
+		}
+		log.Printf("インシデント %d のタイムキーパー状態取得エラー: %v", incidentID, err)
+	}
+
+	switch {
+	case tm.isTimekeeperRunning(incidentID):
+		return timekeeperStateRunning
+	case tm.isPaused(incidentID):
+		return timekeeperStatePaused
+	}
+	return timekeeperStateStopped
+}
+
+// setSeverity はタイムキーパーの重要度と投稿間隔を変更（次回の投稿から新しい間隔になる）
+func (tm *TimekeeperManager) setSeverity(incidentID int64, severity string) {
+	interval := timekeeperInterval(severity)
+
+	tm.mu.Lock()
+	if entry, exists := tm.entries[incidentID]; exists {
+		entry.severity = severity
+		entry.interval = interval
+	}
+	tm.mu.Unlock()
+
+	if store != nil {
+		if err := saveTimekeeperInterval(incidentID, interval); err != nil {
+			log.Printf("インシデント %d のタイムキーパー投稿間隔保存エラー: %v", incidentID, err)
+		}
+	}
+	log.Printf("インシデント %d のタイムキーパーの重要度を %s に変更しました (投稿間隔: %d分)", incidentID, severity, interval)
+}
+
+// severity はタイムキーパーに設定されている重要度を取得
+func (tm *TimekeeperManager) severity(incidentID int64) string {
+	tm.mu.RLock()
+	defer tm.mu.RUnlock()
+
+	if entry, exists := tm.entries[incidentID]; exists {
+		return entry.severity
+	}
+	return ""
+}
+
+// interval はタイムキーパーの投稿間隔（分）を取得
+func (tm *TimekeeperManager) interval(incidentID int64) int {
+	tm.mu.RLock()
+	defer tm.mu.RUnlock()
+
+	if entry, exists := tm.entries[incidentID]; exists {
+		return entry.interval
+	}
+	return defaultTimekeeperInterval
+}
+
+// recordProgress は投稿の進捗を記録し、インシデントの保存先に保存
+func (tm *TimekeeperManager) recordProgress(incidentID int64, progress timekeeperProgress) {
+	tm.mu.Lock()
+	if entry, exists := tm.entries[incidentID]; exists {
+		entry.progress = progress
+	}
+	tm.mu.Unlock()
+
+	if store == nil {
+		return
+	}
+	if err := saveTimekeeperProgress(incidentID, progress); err != nil {
+		log.Printf("インシデント %d のタイムキーパー進捗保存エラー: %v", incidentID, err)
+	}
+}
+
+// persistTimekeeperState はタイムキーパーの状態をインシデントの保存先に保存（保存先がない場合は何もしない）
+// intervalMinutes が0の場合は保存済みの投稿間隔を変更しない
+func persistTimekeeperState(incidentID int64, state string, intervalMinutes int) {
+	if store == nil {
+		return
+	}
+	if err := saveTimekeeperState(incidentID, state, intervalMinutes); err != nil {
+		log.Printf("インシデント %d のタイムキーパー状態保存エラー: %v", incidentID, err)
+	}
+}
+
+// initialTimekeeperProgress は開始・再開時点の進捗を作成
+// 既に過ぎたマイルストーンや投稿間隔の分をさかのぼって投稿しないよう、現在時刻を基準にする
+func initialTimekeeperProgress(startTime, now time.Time, milestones []int) timekeeperProgress {
+	progress := timekeeperProgress{LastPostedAt: now}
+	minutes := int(now.Sub(startTime).Minutes())
+	for _, m := range milestones {
+		if m <= minutes {
+			progress.LastMilestone = m
+		}
+	}
+	return progress
+}
+
+// restoredTimekeeperEntry は保存された状態からタイムキーパーの情報を復元
+// 停止中に到達したマイルストーンは、再起動後の最初の確認時に投稿される
+// 再開直後（マイルストーンが未保存）の場合は、現在時刻までのマイルストーンを投稿済みとして扱う
+func restoredTimekeeperEntry(saved *TimekeeperState, channelID, severity string, startTime, now time.Time) *timekeeperEntry {
+	entry := &timekeeperEntry{
+		channelID: channelID,
+		severity:  severity,
+		interval:  timekeeperInterval(severity),
+		startTime: startTime,
+		progress:  initialTimekeeperProgress(startTime, now, timekeeperMilestones()),
+	}
+
+	if saved.IntervalMinutes > 0 {
+		entry.interval = saved.IntervalMinutes
+	}
+	if saved.LastPostedAt != nil {
+		entry.progress.LastPostedAt = *saved.LastPostedAt
+	}
+	if saved.LastMilestone != nil {
+		entry.progress.LastMilestone = *saved.LastMilestone
+	}
+	if saved.PinnedTS != "" {
+		entry.progress.PinnedTS = saved.PinnedTS
+	}
+	return entry
+}
+
+// timekeeperInterval は重要度ごとの経過時間の投稿間隔（分）を取得
+func timekeeperInterval(severity string) int {
+	if interval, ok := config.Timekeeper.IntervalMinutes[severity]; ok && interval > 0 {
+		return interval
+	}
+	if interval, ok := defaultTimekeeperIntervals[severity]; ok {
+		return interval
+	}
+	return defaultTimekeeperInterval
+}
+
+// timekeeperMilestones はマイルストーン（分）を昇順で取得
+// 設定されていない場合はデフォルト、空配列が設定されている場合はマイルストーンを投稿しない
+func timekeeperMilestones() []int {
+	if config.Timekeeper.Milestones == nil {
+		return defaultTimekeeperMilestones
+	}
+
+	milestones := make([]int, 0, len(config.Timekeeper.Milestones))
+	for _, m := range config.Timekeeper.Milestones {
+		if m > 0 {
+			milestones = append(milestones, m)
+		}
+	}
+	sort.Ints(milestones)
+	return milestones
+}
+
+// isTimekeeperPostDue は前回の投稿から次の投稿間隔の区切りを越えたかを判定
+func isTimekeeperPostDue(minutes, lastPostedMinutes, interval int) bool {
+	if interval <= 0 {
+		return false
+	}
+	return minutes/interval > lastPostedMinutes/interval
+}
+
+// reachedMilestone は前回のマイルストーン以降に到達した最大のマイルストーンを返す（ない場合は0）
+func reachedMilestone(minutes, lastMilestone int, milestones []int) int {
+	reached := 0
+	for _, m := range milestones {
+		if m > lastMilestone && m <= minutes {
+			reached = m
+		}
+	}
+	return reached
+}
+
+// milestonePrompt はマイルストーンに応じたエスカレーションの呼びかけを取得
+func milestonePrompt(minutes int) string {
+	prompt := timekeeperMilestonePrompts[0].Prompt
+	for _, p := range timekeeperMilestonePrompts {
+		if p.Minutes <= minutes {
+			prompt = p.Prompt
+		}
+	}
+	return prompt
+}
+
+// buildMilestoneMessage はマイルストーン到達時のメッセージを生成
+func buildMilestoneMessage(elapsed time.Duration, milestone int, severity string, handlerAssigned bool) string {
+	message := fmt.Sprintf("🔔 *インシデント発生から%sが経過しました*", formatElapsed(time.Duration(milestone)*time.Minute))
+	if severity != "" {
+		message += fmt.Sprintf("（重要度: %s %s）", severityEmojis[severity], severity)
+	}
+	message += "\n\n" + milestonePrompt(milestone)
+	if !handlerAssigned {
+		message += "\n⚠️ 担当者が未割り当てです。「🙋 担当者になる」ボタンで担当者を決めてください。"
+	}
+	message += fmt.Sprintf("\n\n⏱️ *経過時間:* %s", formatElapsed(elapsed))
+	return message
+}
+
+// postMilestoneMessage はマイルストーン到達のメッセージを投稿
+func postMilestoneMessage(api SlackAPI, incidentID int64, channelID, severity string, elapsed time.Duration, milestone int) error {
+	// 担当者が確認できない場合（データベース無効など）は未割り当ての警告を出さない
+	handlerAssigned := true
+	if incident, err := getIncidentDetails(incidentID); err == nil {
+		handlerAssigned = incident.HandlerID != ""
+	}
+
+	message := buildMilestoneMessage(elapsed, milestone, severity, handlerAssigned)
+	_, _, err := api.PostMessage(
+		channelID,
+		slack.MsgOptionText(message, false),
+		slack.MsgOptionBlocks(buildElapsedBlocks(incidentID, message)...),
+	)
+	if err == nil {
+		log.Printf("インシデント %d のマイルストーン（%d分）を投稿しました", incidentID, milestone)
+	}
+	return err
+}
+
+// postElapsedMessage は経過時間のメッセージを投稿
+func postElapsedMessage(api SlackAPI, incidentID int64, channelID string, elapsed time.Duration) error {
+	message := fmt.Sprintf("⏱️ *インシデント経過時間:* %s", formatElapsed(elapsed))
+	_, _, err := api.PostMessage(
+		channelID,
+		slack.MsgOptionText(message, false),
+		slack.MsgOptionBlocks(buildElapsedBlocks(incidentID, message)...),
+	)
+	if err == nil {
+		log.Printf("インシデント %d の経過時間を投稿しました: %s", incidentID, formatElapsed(elapsed))
+	}
+	return err
+}
+
+// pinnedElapsedText はピン留めする経過時間メッセージの本文を生成
+func pinnedElapsedText(elapsed time.Duration, now time.Time) string {
+	return fmt.Sprintf("⏱️ *インシデント経過時間:* %s\n_このメッセージは1分ごとに更新されます（最終更新: %s）_", formatElapsed(elapsed), now.Format("15:04"))
+}
+
+// postPinnedElapsedMessage は経過時間のメッセージを投稿してピン留めし、そのタイムスタンプを返す
+// 投稿に失敗した場合は空文字列を返す（経過時間を投稿するモードで動作する）
+func postPinnedElapsedMessage(api SlackAPI, incidentID int64, channelID string, elapsed time.Duration) string {
+	message := pinnedElapsedText(elapsed, time.Now())
+	_, ts, err := api.PostMessage(
+		channelID,
+		slack.MsgOptionText(message, false),
+		slack.MsgOptionBlocks(buildElapsedBlocks(incidentID, message)...),
+	)
+	if err != nil {
+		log.Printf("経過時間メッセージ投稿エラー（経過時間を投稿するモードで動作します）: %v", err)
+		return ""
+	}
+
+	if err := api.AddPin(channelID, slack.NewRefToMessage(channelID, ts)); err != nil {
+		log.Printf("経過時間メッセージのピン留めエラー: %v", err)
+	}
+	return ts
+}
+
+// updatePinnedElapsedMessage はピン留めした経過時間のメッセージを更新
+func updatePinnedElapsedMessage(api SlackAPI, incidentID int64, channelID, ts string, elapsed time.Duration) error {
+	message := pinnedElapsedText(elapsed, time.Now())
+	_, _, _, err := api.UpdateMessage(
+		channelID,
+		ts,
+		slack.MsgOptionText(message, false),
+		slack.MsgOptionBlocks(buildElapsedBlocks(incidentID, message)...),
+	)
+	return err
+}
+
+// finishPinnedElapsedMessage はタイムキーパー停止時にピン留めした経過時間のメッセージを最終状態にしてピン留めを外す
+func finishPinnedElapsedMessage(api SlackAPI, channelID, ts string, elapsed time.Duration) {
+	message := fmt.Sprintf("⏹️ *タイムキーパー停止* （停止時点の経過時間: %s）", formatElapsed(elapsed))
+	_, _, _, err := api.UpdateMessage(
+		channelID,
+		ts,
+		slack.MsgOptionText(message, false),
+		slack.MsgOptionBlocks(
+			slack.NewSectionBlock(
+				slack.NewTextBlockObject("mrkdwn", message, false, false),
+				nil, nil,
+			),
+		),
+	)
+	if err != nil {
+		log.Printf("経過時間メッセージの更新エラー: %v", err)
+	}
+
+	if err := api.RemovePin(channelID, slack.NewRefToMessage(channelID, ts)); err != nil {
+		log.Printf("経過時間メッセージのピン留め解除エラー: %v", err)
+	}
+}
+
+// pausePinnedElapsedMessage はタイムキーパー一時停止時にピン留めした経過時間のメッセージを一時停止中の表示にする
+// 再開時に同じメッセージの更新を続けるため、ピン留めは外さない
+func pausePinnedElapsedMessage(api SlackAPI, incidentID int64, channelID, ts string, elapsed time.Duration) {
+	message := fmt.Sprintf("⏸️ *タイムキーパー一時停止中* （一時停止時点の経過時間: %s）", formatElapsed(elapsed))
+	_, _, _, err := api.UpdateMessage(
+		channelID,
+		ts,
+		slack.MsgOptionText(message, false),
+		slack.MsgOptionBlocks(
+			slack.NewSectionBlock(
+				slack.NewTextBlockObject("mrkdwn", message, false, false),
+				nil,
+				slack.NewAccessory(newResumeTimekeeperButton(incidentID)),
+			),
+		),
+	)
+	if err != nil {
+		log.Printf("経過時間メッセージの更新エラー: %v", err)
+	}
+}
+
+// buildElapsedBlocks は一時停止・停止ボタン付きの経過時間メッセージのブロックを作成
+func buildElapsedBlocks(incidentID int64, message string) []slack.Block {
+	return []slack.Block{
+		slack.NewSectionBlock(
+			slack.NewTextBlockObject("mrkdwn", message, false, false),
+			nil, nil,
+		),
+		slack.NewActionBlock(
+			fmt.Sprintf("timekeeper_%d", incidentID),
+			newPauseTimekeeperButton(incidentID),
+			newStopTimekeeperButton(incidentID),
+		),
+	}
+}
+
+// newStopTimekeeperButton はタイムキーパー停止ボタンを作成
+func newStopTimekeeperButton(incidentID int64) *slack.ButtonBlockElement {
+	stopButton := slack.NewButtonBlockElement(
+		"stop_timekeeper",
+		fmt.Sprintf("incident_%d", incidentID),
+		slack.NewTextBlockObject("plain_text", "⏹️ タイムキーパーを止める", true, false),
+	)
+	stopButton.Style = "danger"
+	return stopButton
+}
+
+// newPauseTimekeeperButton はタイムキーパー一時停止ボタンを作成
+func newPauseTimekeeperButton(incidentID int64) *slack.ButtonBlockElement {
+	return slack.NewButtonBlockElement(
+		"pause_timekeeper",
+		fmt.Sprintf("incident_%d", incidentID),
+		slack.NewTextBlockObject("plain_text", "⏸️ 一時停止", true, false),
+	)
+}
+
+// newResumeTimekeeperButton はタイムキーパー再開ボタンを作成
+func newResumeTimekeeperButton(incidentID int64) *slack.ButtonBlockElement {
+	return slack.NewButtonBlockElement(
+		"resume_timekeeper",
+		fmt.Sprintf("incident_%d", incidentID),
+		slack.NewTextBlockObject("plain_text", "▶️ タイムキーパーを再開", true, false),
+	)
+}
+
+// isChannelGoneError はチャンネルがアーカイブまたは削除されたことを示すエラーかを判定
+func isChannelGoneError(err error) bool {
+	return strings.Contains(err.Error(), "is_archived") || strings.Contains(err.Error(), "channel_not_found")
+}
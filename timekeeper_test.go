@@ -1,6 +1,7 @@
 package main
 
 import (
+	"strings"
 	"testing"
 	"time"
 )
@@ -135,3 +136,305 @@ func TestTimekeeperConcurrency(t *testing.T) {
 	}
 	tm.mu.RUnlock()
 }
+
+func TestTimekeeperInterval(t *testing.T) {
+	originalConfig := config
+	defer func() { config = originalConfig }()
+
+	config.Timekeeper.IntervalMinutes = nil
+	if got := timekeeperInterval("critical"); got != 5 {
+		t.Errorf("critical のデフォルトの投稿間隔が間違っています: %d", got)
+	}
+	if got := timekeeperInterval("unknown"); got != defaultTimekeeperInterval {
+		t.Errorf("不明な重要度の投稿間隔が間違っています: %d", got)
+	}
+
+	config.Timekeeper.IntervalMinutes = map[string]int{"critical": 3, "low": 0}
+	if got := timekeeperInterval("critical"); got != 3 {
+		t.Errorf("設定した投稿間隔が反映されていません: %d", got)
+	}
+	if got := timekeeperInterval("low"); got != 30 {
+		t.Errorf("0以下の設定はデフォルトを使用するべきです: %d", got)
+	}
+}
+
+func TestTimekeeperMilestones(t *testing.T) {
+	originalConfig := config
+	defer func() { config = originalConfig }()
+
+	config.Timekeeper.Milestones = nil
+	if got := timekeeperMilestones(); len(got) != 4 || got[0] != 15 || got[3] != 120 {
+		t.Errorf("デフォルトのマイルストーンが間違っています: %v", got)
+	}
+
+	config.Timekeeper.Milestones = []int{90, 0, 45}
+	if got := timekeeperMilestones(); len(got) != 2 || got[0] != 45 || got[1] != 90 {
+		t.Errorf("設定したマイルストーンが昇順になっていません: %v", got)
+	}
+
+	config.Timekeeper.Milestones = []int{}
+	if got := timekeeperMilestones(); len(got) != 0 {
+		t.Errorf("空配列の場合はマイルストーンを無効にするべきです: %v", got)
+	}
+}
+
+func TestIsTimekeeperPostDue(t *testing.T) {
+	tests := []struct {
+		minutes, lastPosted, interval int
+		expected                      bool
+	}{
+		{4, 0, 5, false},
+		{5, 0, 5, true},
+		{6, 5, 5, false},
+		{10, 5, 5, true},
+		{31, 30, 5, false}, // マイルストーン投稿直後
+		{47, 45, 30, false},
+		{60, 45, 30, true},
+		{7, 3, 0, false},
+	}
+
+	for _, tt := range tests {
+		if got := isTimekeeperPostDue(tt.minutes, tt.lastPosted, tt.interval); got != tt.expected {
+			t.Errorf("isTimekeeperPostDue(%d, %d, %d) = %v, want %v", tt.minutes, tt.lastPosted, tt.interval, got, tt.expected)
+		}
+	}
+}
+
+func TestReachedMilestone(t *testing.T) {
+	milestones := []int{15, 30, 60, 120}
+
+	tests := []struct {
+		minutes, lastMilestone, expected int
+	}{
+		{14, 0, 0},
+		{15, 0, 15},
+		{16, 15, 0},
+		{30, 15, 30},
+		{65, 15, 60}, // 複数を飛び越えた場合は最大のもののみ
+		{200, 120, 0},
+	}
+
+	for _, tt := range tests {
+		if got := reachedMilestone(tt.minutes, tt.lastMilestone, milestones); got != tt.expected {
+			t.Errorf("reachedMilestone(%d, %d) = %d, want %d", tt.minutes, tt.lastMilestone, got, tt.expected)
+		}
+	}
+}
+
+func TestBuildMilestoneMessage(t *testing.T) {
+	message := buildMilestoneMessage(61*time.Minute, 60, "critical", false)
+
+	for _, expected := range []string{"1時間0分が経過しました", "🔴 critical", milestonePrompt(60), "担当者が未割り当て", "*経過時間:* 1時間1分"} {
+		if !strings.Contains(message, expected) {
+			t.Errorf("マイルストーンのメッセージに %q が含まれていません:\n%s", expected, message)
+		}
+	}
+
+	message = buildMilestoneMessage(45*time.Minute, 45, "", true)
+	if strings.Contains(message, "未割り当て") || strings.Contains(message, "重要度") {
+		t.Errorf("担当者割り当て済み・重要度不明の場合の表示が間違っています:\n%s", message)
+	}
+	if !strings.Contains(message, milestonePrompt(30)) {
+		t.Errorf("独自のマイルストーンには経過時間以下で最大の呼びかけを使用するべきです:\n%s", message)
+	}
+}
+
+func TestTimekeeperPauseAndStop(t *testing.T) {
+	tm := &TimekeeperManager{
+		timekeepers: make(map[int64]chan bool),
+		entries:     make(map[int64]*timekeeperEntry),
+	}
+
+	// タイムキーパーを手動で登録
+	stopChan := make(chan bool)
+	tm.timekeepers[1] = stopChan
+	tm.entries[1] = &timekeeperEntry{channelID: "C1", severity: "high", interval: 10}
+
+	if !tm.pauseTimekeeper(1) {
+		t.Fatal("動作中のタイムキーパーの一時停止に失敗しました")
+	}
+	select {
+	case <-stopChan:
+	default:
+		t.Error("一時停止でゴルーチンに停止シグナルが送信されていません")
+	}
+	if tm.isTimekeeperRunning(1) || !tm.isPaused(1) {
+		t.Error("一時停止後は一時停止中と報告されるべきです")
+	}
+	if tm.severity(1) != "high" || tm.interval(1) != 10 {
+		t.Error("一時停止中もタイムキーパーの情報は保持されるべきです")
+	}
+
+	// 一時停止中のものを再度一時停止することはできない
+	if tm.pauseTimekeeper(1) {
+		t.Error("一時停止中のタイムキーパーの一時停止が成功してしまいました")
+	}
+
+	// 一時停止中の重要度変更は投稿間隔に反映される
+	tm.setSeverity(1, "critical")
+	if tm.interval(1) != timekeeperInterval("critical") {
+		t.Errorf("重要度の変更が投稿間隔に反映されていません: %d", tm.interval(1))
+	}
+
+	// 一時停止中のものを停止すると停止済みになる
+	if !tm.stopTimekeeper(1) {
+		t.Error("一時停止中のタイムキーパーの停止に失敗しました")
+	}
+	if tm.isPaused(1) || tm.resumeTimekeeper(nil, 1) {
+		t.Error("停止済みのタイムキーパーは再開できないべきです")
+	}
+}
+
+func TestInitialTimekeeperProgress(t *testing.T) {
+	start := time.Date(2025, 1, 1, 10, 0, 0, 0, time.UTC)
+	now := start.Add(45 * time.Minute)
+
+	progress := initialTimekeeperProgress(start, now, []int{15, 30, 60})
+	if !progress.LastPostedAt.Equal(now) {
+		t.Errorf("最終投稿時刻は現在時刻であるべきです: %v", progress.LastPostedAt)
+	}
+	if progress.LastMilestone != 30 {
+		t.Errorf("過ぎたマイルストーンは投稿済みとして扱うべきです: %d", progress.LastMilestone)
+	}
+}
+
+func TestRestoredTimekeeperEntry(t *testing.T) {
+	originalConfig := config
+	config.Timekeeper.IntervalMinutes = nil
+	defer func() { config = originalConfig }()
+
+	start := time.Date(2025, 1, 1, 10, 0, 0, 0, time.UTC)
+	now := start.Add(90 * time.Minute)
+	lastPostedAt := start.Add(40 * time.Minute)
+
+	lastMilestone := 30
+	entry := restoredTimekeeperEntry(&TimekeeperState{
+		State:           timekeeperStateRunning,
+		IntervalMinutes: 20,
+		LastPostedAt:    &lastPostedAt,
+		LastMilestone:   &lastMilestone,
+		PinnedTS:        "1700000000.000100",
+	}, "C1", "critical", start, now)
+
+	if entry.interval != 20 {
+		t.Errorf("保存された投稿間隔を使用するべきです: %d", entry.interval)
+	}
+	if !entry.progress.LastPostedAt.Equal(lastPostedAt) || entry.progress.LastMilestone != 30 || entry.progress.PinnedTS != "1700000000.000100" {
+		t.Errorf("保存された進捗が復元されていません: %+v", entry.progress)
+	}
+
+	// 停止中に到達したマイルストーンは再起動後に投稿される
+	if got := reachedMilestone(90, entry.progress.LastMilestone, []int{15, 30, 60, 120}); got != 60 {
+		t.Errorf("停止中に到達したマイルストーンが投稿されません: %d", got)
+	}
+
+	// 投稿間隔が保存されていない場合は重要度から決める
+	noMilestone := 0
+	entry = restoredTimekeeperEntry(&TimekeeperState{State: timekeeperStatePaused, LastMilestone: &noMilestone}, "C1", "critical", start, now)
+	if entry.interval != 5 || !entry.progress.LastPostedAt.Equal(now) {
+		t.Errorf("保存されていない項目のデフォルトが間違っています: %+v", entry)
+	}
+
+	// 再開直後でマイルストーンが保存されていない場合は、現在時刻までのマイルストーンを投稿済みとして扱う
+	entry = restoredTimekeeperEntry(&TimekeeperState{State: timekeeperStateRunning, LastPostedAt: &lastPostedAt}, "C1", "critical", start, now)
+	if entry.progress.LastMilestone != 60 {
+		t.Errorf("再開前のマイルストーンが投稿済みになっていません: %d", entry.progress.LastMilestone)
+	}
+}
+
+func TestTimekeeperHandover(t *testing.T) {
+	tm := &TimekeeperManager{
+		timekeepers: make(map[int64]chan bool),
+		entries:     make(map[int64]*timekeeperEntry),
+	}
+
+	for _, id := range []int64{1, 2} {
+		stopChan := make(chan bool)
+		tm.timekeepers[id] = stopChan
+		tm.entries[id] = &timekeeperEntry{channelID: "C1", severity: "high", interval: 10}
+	}
+	// 一時停止中のもの
+	tm.entries[3] = &timekeeperEntry{channelID: "C1", severity: "low", interval: 30}
+
+	tm.haltAll()
+
+	for _, id := range []int64{1, 2, 3} {
+		if tm.isTimekeeperRunning(id) || tm.isPaused(id) {
+			t.Errorf("リーダーの交代時はインシデント %d のタイムキーパーをすべて止めるべきです", id)
+		}
+	}
+	if len(tm.stopReasons) != 2 {
+		t.Fatalf("動作中だったタイムキーパーの停止理由が記録されていません: %v", tm.stopReasons)
+	}
+	for stopChan, reason := range tm.stopReasons {
+		if reason != timekeeperStopHandover {
+			t.Errorf("停止理由が間違っています: %s", reason)
+		}
+		if tm.takeStopReason(stopChan) != timekeeperStopHandover {
+			t.Error("takeStopReasonが停止理由を返していません")
+		}
+	}
+
+	// 記録されていない場合は停止として扱う
+	if reason := tm.takeStopReason(make(chan bool)); reason != timekeeperStateStopped {
+		t.Errorf("停止理由が記録されていない場合はstoppedであるべきです: %s", reason)
+	}
+}
+
+func TestTimekeeperHandOverToRegisteredID(t *testing.T) {
+	originalDB := db
+	db = nil
+	defer func() { db = originalDB }()
+
+	tm := &TimekeeperManager{
+		timekeepers: make(map[int64]chan bool),
+		entries:     make(map[int64]*timekeeperEntry),
+	}
+
+	stopChan := make(chan bool)
+	tm.timekeepers[-100] = stopChan
+	tm.entries[-100] = &timekeeperEntry{channelID: "C1", severity: "high", interval: 10}
+	// 一時停止中のもの
+	tm.entries[-200] = &timekeeperEntry{channelID: "C2", severity: "low", interval: 30}
+
+	tm.handOver(-100, 1)
+	tm.handOver(-200, 2)
+
+	select {
+	case <-stopChan:
+	default:
+		t.Error("仮IDで動作中のタイムキーパーに停止シグナルが送信されていません")
+	}
+	if reason := tm.takeStopReason(stopChan); reason != timekeeperStopHandover {
+		t.Errorf("停止理由 = %s, want %s", reason, timekeeperStopHandover)
+	}
+	for _, id := range []int64{-100, -200} {
+		if tm.isTimekeeperRunning(id) || tm.isPaused(id) {
+			t.Errorf("仮ID %d のタイムキーパーは引き継ぎ後に残らないべきです", id)
+		}
+	}
+}
+
+func TestTimekeeperStopForGoneChannel(t *testing.T) {
+	originalStore := store
+	store = nil
+	defer func() { store = originalStore }()
+
+	tm := &TimekeeperManager{
+		timekeepers: make(map[int64]chan bool),
+		entries:     make(map[int64]*timekeeperEntry),
+	}
+	stopChan := make(chan bool)
+	tm.timekeepers[1] = stopChan
+	tm.entries[1] = &timekeeperEntry{channelID: "C1", severity: "high", interval: 10}
+
+	tm.stopForGoneChannel(nil, 1, "C1", stopChan)
+
+	if tm.isTimekeeperRunning(1) || tm.isPaused(1) {
+		t.Error("チャンネルがなくなったタイムキーパーが停止されていません")
+	}
+	// ゴルーチン自身が停止したため、停止理由は残さない
+	if len(tm.stopReasons) != 0 {
+		t.Errorf("停止理由が残っています: %v", tm.stopReasons)
+	}
+}
@@ -1,6 +1,23 @@
 package main
 
-import "math/rand"
+import (
+	"fmt"
+	"math/rand"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// severityOrder は重要度の表示順（重要度の高い順）
+var severityOrder = []string{"critical", "high", "medium", "low"}
+
+// severityEmojis は重要度に応じた絵文字
+var severityEmojis = map[string]string{
+	"critical": "🔴",
+	"high":     "🟠",
+	"medium":   "🟡",
+	"low":      "🟢",
+}
 
 // generateRandomString は指定された長さのランダムな英数字文字列を生成
 func generateRandomString(length int) string {
@@ -11,3 +28,42 @@ func generateRandomString(length int) string {
 	}
 	return string(result)
 }
+
+// isNotInChannelError はBotがチャンネルに投稿できないことを示すSlack APIエラーかどうかを判定
+func isNotInChannelError(err error) bool {
+	if err == nil {
+		return false
+	}
+	msg := err.Error()
+	return strings.Contains(msg, "not_in_channel") || strings.Contains(msg, "channel_not_found")
+}
+
+// parseIncidentID はコマンド引数からインシデントIDを解析（"42" と "#42" の両方を受け付ける）
+func parseIncidentID(arg string) (int64, error) {
+	id, err := strconv.ParseInt(strings.TrimPrefix(arg, "#"), 10, 64)
+	if err != nil || id <= 0 {
+		return 0, fmt.Errorf("インシデントIDが不正です: %s", arg)
+	}
+	return id, nil
+}
+
+// truncateRunes は文字列を指定した文字数（rune数）以内に切り詰める
+func truncateRunes(s string, max int) string {
+	runes := []rune(s)
+	if len(runes) <= max {
+		return s
+	}
+	return string(runes[:max])
+}
+
+// formatElapsed は経過時間を「X時間Y分」形式に整形
+func formatElapsed(elapsed time.Duration) string {
+	minutes := int(elapsed.Minutes())
+	hours := minutes / 60
+	mins := minutes % 60
+
+	if hours > 0 {
+		return fmt.Sprintf("%d時間%d分", hours, mins)
+	}
+	return fmt.Sprintf("%d分", mins)
+}
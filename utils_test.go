@@ -1,7 +1,9 @@
 package main
 
 import (
+	"errors"
 	"testing"
+	"time"
 )
 
 func TestGenerateRandomString(t *testing.T) {
@@ -54,3 +56,99 @@ func TestGenerateRandomStringUniqueness(t *testing.T) {
 		t.Errorf("ランダム文字列の一意性が低い: %d/%d (期待値: >=%d)", uniqueCount, iterations, expectedUnique)
 	}
 }
+
+func TestIsNotInChannelError(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		expected bool
+	}{
+		{"nil", nil, false},
+		{"not_in_channel", errors.New("not_in_channel"), true},
+		{"channel_not_found", errors.New("channel_not_found"), true},
+		{"その他のエラー", errors.New("ratelimited"), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if actual := isNotInChannelError(tt.err); actual != tt.expected {
+				t.Errorf("isNotInChannelError(%v) = %v, 期待値: %v", tt.err, actual, tt.expected)
+			}
+		})
+	}
+}
+
+func TestParseIncidentID(t *testing.T) {
+	tests := []struct {
+		arg         string
+		expected    int64
+		shouldError bool
+	}{
+		{"42", 42, false},
+		{"#42", 42, false},
+		{"0", 0, true},
+		{"-1", 0, true},
+		{"abc", 0, true},
+		{"", 0, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.arg, func(t *testing.T) {
+			id, err := parseIncidentID(tt.arg)
+			if tt.shouldError {
+				if err == nil {
+					t.Errorf("エラーが期待されましたが、成功しました: %d", id)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("解析エラー: %v", err)
+			}
+			if id != tt.expected {
+				t.Errorf("解析されたIDが間違っています: %d, 期待値: %d", id, tt.expected)
+			}
+		})
+	}
+}
+
+func TestTruncateRunes(t *testing.T) {
+	tests := []struct {
+		input    string
+		max      int
+		expected string
+	}{
+		{"abc", 5, "abc"},
+		{"abcdef", 3, "abc"},
+		{"インシデント", 3, "インシ"},
+		{"", 3, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			if actual := truncateRunes(tt.input, tt.max); actual != tt.expected {
+				t.Errorf("truncateRunes(%q, %d) = %q, 期待値: %q", tt.input, tt.max, actual, tt.expected)
+			}
+		})
+	}
+}
+
+func TestFormatElapsed(t *testing.T) {
+	tests := []struct {
+		elapsed  time.Duration
+		expected string
+	}{
+		{30 * time.Second, "0分"},
+		{5 * time.Minute, "5分"},
+		{59 * time.Minute, "59分"},
+		{60 * time.Minute, "1時間0分"},
+		{125 * time.Minute, "2時間5分"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expected, func(t *testing.T) {
+			if actual := formatElapsed(tt.elapsed); actual != tt.expected {
+				t.Errorf("formatElapsed(%v) = %s, 期待値: %s", tt.elapsed, actual, tt.expected)
+			}
+		})
+	}
+}
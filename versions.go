@@ -1 +1,128 @@
 package main
+
+import (
+	"embed"
+	"fmt"
+	"io/fs"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+)
+
+// migrationFiles はバイナリに埋め込んだスキーマのマイグレーション
+// ファイル名は "<バージョン>_<名前>.up.sql" と "<バージョン>_<名前>.down.sql" の組にする
+//
+//go:embed migrations/*.sql
+var migrationFiles embed.FS
+
+// migrationFilenamePattern はマイグレーションのファイル名の形式
+var migrationFilenamePattern = regexp.MustCompile(`^(\d+)_([a-z0-9_]+)\.(up|down)\.sql$`)
+
+// Migration はバージョンごとのスキーマ変更
+type Migration struct {
+	Version int
+	Name    string
+	Up      string // 適用するSQL
+	Down    string // 取り消すSQL
+}
+
+// parseMigrationFilename はマイグレーションのファイル名からバージョン・名前・方向（up/down）を取得
+func parseMigrationFilename(filename string) (int, string, string, error) {
+	matches := migrationFilenamePattern.FindStringSubmatch(filename)
+	if matches == nil {
+		return 0, "", "", fmt.Errorf("マイグレーションのファイル名が不正です: %s（<バージョン>_<名前>.up.sql の形式にしてください）", filename)
+	}
+
+	version, err := strconv.Atoi(matches[1])
+	if err != nil || version <= 0 {
+		return 0, "", "", fmt.Errorf("マイグレーションのバージョンが不正です: %s", filename)
+	}
+	return version, matches[2], matches[3], nil
+}
+
+// loadMigrations はマイグレーションを読み込み、バージョン順に並べて返す
+// すべてのバージョンに up と down の両方があり、バージョンが1から連番になっていることを確認する
+func loadMigrations(fsys fs.FS, dir string) ([]Migration, error) {
+	entries, err := fs.ReadDir(fsys, dir)
+	if err != nil {
+		return nil, fmt.Errorf("マイグレーション読み込みエラー: %v", err)
+	}
+
+	byVersion := make(map[int]*Migration)
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+
+		version, name, direction, err := parseMigrationFilename(entry.Name())
+		if err != nil {
+			return nil, err
+		}
+
+		content, err := fs.ReadFile(fsys, path.Join(dir, entry.Name()))
+		if err != nil {
+			return nil, fmt.Errorf("マイグレーション読み込みエラー: %v", err)
+		}
+
+		migration, exists := byVersion[version]
+		if !exists {
+			migration = &Migration{Version: version, Name: name}
+			byVersion[version] = migration
+		}
+		if migration.Name != name {
+			return nil, fmt.Errorf("バージョン %d のマイグレーションの名前が一致しません: %s, %s", version, migration.Name, name)
+		}
+
+		if direction == "up" {
+			migration.Up = string(content)
+		} else {
+			migration.Down = string(content)
+		}
+	}
+
+	migrations := make([]Migration, 0, len(byVersion))
+	for _, migration := range byVersion {
+		if migration.Up == "" || migration.Down == "" {
+			return nil, fmt.Errorf("バージョン %d のマイグレーションには up と down の両方が必要です", migration.Version)
+		}
+		migrations = append(migrations, *migration)
+	}
+	sort.Slice(migrations, func(i, j int) bool {
+		return migrations[i].Version < migrations[j].Version
+	})
+
+	for i, migration := range migrations {
+		if migration.Version != i+1 {
+			return nil, fmt.Errorf("マイグレーションのバージョンが連番になっていません: %d 番目のマイグレーションのバージョンが %d です（%d であるべきです）", i+1, migration.Version, i+1)
+		}
+	}
+	return migrations, nil
+}
+
+// embeddedMigrations はバイナリに埋め込んだマイグレーションを読み込む
+func embeddedMigrations() ([]Migration, error) {
+	return loadMigrations(migrationFiles, "migrations")
+}
+
+// pendingMigrations は未適用のマイグレーションをバージョン順に返す
+func pendingMigrations(migrations []Migration, applied map[int]bool) []Migration {
+	var pending []Migration
+	for _, migration := range migrations {
+		if !applied[migration.Version] {
+			pending = append(pending, migration)
+		}
+	}
+	return pending
+}
+
+// rollbackMigrations は取り消すマイグレーションを新しい順に最大 steps 件返す
+func rollbackMigrations(migrations []Migration, applied map[int]bool, steps int) []Migration {
+	var rollback []Migration
+	for i := len(migrations) - 1; i >= 0 && len(rollback) < steps; i-- {
+		if applied[migrations[i].Version] {
+			rollback = append(rollback, migrations[i])
+		}
+	}
+	return rollback
+}
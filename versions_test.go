@@ -0,0 +1,92 @@
+package main
+
+import (
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+func TestEmbeddedMigrations(t *testing.T) {
+	migrations, err := embeddedMigrations()
+	if err != nil {
+		t.Fatalf("埋め込んだマイグレーションの読み込みに失敗しました: %v", err)
+	}
+	if len(migrations) == 0 {
+		t.Fatal("マイグレーションが1件もありません")
+	}
+
+	for i, migration := range migrations {
+		if migration.Version != i+1 {
+			t.Errorf("バージョンが連番ではありません: %d", migration.Version)
+		}
+		if strings.TrimSpace(migration.Up) == "" || strings.TrimSpace(migration.Down) == "" {
+			t.Errorf("マイグレーション %d の up または down が空です", migration.Version)
+		}
+	}
+
+	// 既存のデータベースにも適用できるよう、最初のマイグレーションは IF NOT EXISTS で作成する
+	if !strings.Contains(migrations[0].Up, "CREATE TABLE IF NOT EXISTS incidents") {
+		t.Error("最初のマイグレーションで incidents テーブルを作成するべきです")
+	}
+}
+
+func TestParseMigrationFilename(t *testing.T) {
+	version, name, direction, err := parseMigrationFilename("0012_add_column.down.sql")
+	if err != nil || version != 12 || name != "add_column" || direction != "down" {
+		t.Errorf("parseMigrationFilename = %d, %q, %q, %v", version, name, direction, err)
+	}
+
+	for _, filename := range []string{"add_column.up.sql", "0001_add_column.sql", "0001_Add-Column.up.sql", "0000_zero.up.sql"} {
+		if _, _, _, err := parseMigrationFilename(filename); err == nil {
+			t.Errorf("parseMigrationFilename(%q) はエラーを返すべきです", filename)
+		}
+	}
+}
+
+func TestLoadMigrationsErrors(t *testing.T) {
+	tests := map[string]fstest.MapFS{
+		"downがない": {
+			"m/0001_init.up.sql": {Data: []byte("CREATE TABLE a (id INTEGER);")},
+		},
+		"連番ではない": {
+			"m/0001_init.up.sql":   {Data: []byte("CREATE TABLE a (id INTEGER);")},
+			"m/0001_init.down.sql": {Data: []byte("DROP TABLE a;")},
+			"m/0003_next.up.sql":   {Data: []byte("CREATE TABLE b (id INTEGER);")},
+			"m/0003_next.down.sql": {Data: []byte("DROP TABLE b;")},
+		},
+		"名前が一致しない": {
+			"m/0001_init.up.sql":    {Data: []byte("CREATE TABLE a (id INTEGER);")},
+			"m/0001_other.down.sql": {Data: []byte("DROP TABLE a;")},
+		},
+	}
+
+	for name, fsys := range tests {
+		if _, err := loadMigrations(fsys, "m"); err == nil {
+			t.Errorf("%s場合はエラーを返すべきです", name)
+		}
+	}
+
+	// 連番ではない場合は実際のバージョンをエラーに含める
+	_, err := loadMigrations(tests["連番ではない"], "m")
+	if err == nil || !strings.Contains(err.Error(), "2 番目のマイグレーションのバージョンが 3 です") {
+		t.Errorf("連番ではない場合のエラー = %v", err)
+	}
+}
+
+func TestPendingAndRollbackMigrations(t *testing.T) {
+	migrations := []Migration{{Version: 1, Name: "a"}, {Version: 2, Name: "b"}, {Version: 3, Name: "c"}}
+	applied := map[int]bool{1: true, 2: true}
+
+	pending := pendingMigrations(migrations, applied)
+	if len(pending) != 1 || pending[0].Version != 3 {
+		t.Errorf("未適用のマイグレーションが間違っています: %+v", pending)
+	}
+
+	rollback := rollbackMigrations(migrations, applied, 5)
+	if len(rollback) != 2 || rollback[0].Version != 2 || rollback[1].Version != 1 {
+		t.Errorf("取り消すマイグレーションは新しい順であるべきです: %+v", rollback)
+	}
+	if rollback := rollbackMigrations(migrations, applied, 1); len(rollback) != 1 || rollback[0].Version != 2 {
+		t.Errorf("取り消す件数が間違っています: %+v", rollback)
+	}
+}